@@ -0,0 +1,55 @@
+package console
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/spf13/cobra"
+)
+
+func jwtKeyCMD() *cobra.Command {
+	var (
+		alg string
+		dir string
+		kid string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "jwt-key",
+		Short: "Generate a JWT signing key",
+		Long:  "Write a new RS256 or EdDSA private key to <dir>/<kid>.pem. With JWT_KEY_ID unset the newest kid signs after a restart, while the older keys keep verifying tokens already issued; delete them once those have expired.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if kid == "" {
+				kid = time.Now().UTC().Format("20060102150405")
+			}
+			key, err := auth.GenerateKey(alg)
+			if err != nil {
+				return err
+			}
+
+			if err := os.MkdirAll(dir, 0o700); err != nil {
+				return fmt.Errorf("failed to create %s: %w", dir, err)
+			}
+			file := filepath.Join(dir, kid+".pem")
+			f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", file, err)
+			}
+			defer f.Close()
+			if _, err := f.Write(key); err != nil {
+				return fmt.Errorf("failed to write %s: %w", file, err)
+			}
+			cmd.Printf("%s key %s written to %s\n", alg, kid, file)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&alg, "alg", auth.AlgEdDSA, "Key algorithm: RS256 or EdDSA")
+	cmd.Flags().StringVar(&dir, "dir", "storage/jwt", "Directory of the signing keys (JWT_KEY_DIR)")
+	cmd.Flags().StringVar(&kid, "kid", "", "Key ID; defaults to the current UTC time")
+
+	return cmd
+}
@@ -8,5 +8,10 @@ func Commands() []*cobra.Command {
 	return []*cobra.Command{
 		helloCMD(),
 		formatSQLCMD(),
+		openapiCMD(),
+		jwtKeyCMD(),
+		roleGrantCMD(),
+		schoolMergeCMD(),
+		studentClassroomCMD(),
 	}
 }
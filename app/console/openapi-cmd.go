@@ -0,0 +1,54 @@
+// Package console implements CLI commands for the application
+package console
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/easy-attend-serviceV3/app/modules"
+	"github.com/easy-attend-serviceV3/routes"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cobra"
+)
+
+func openapiCMD() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "openapi",
+		Short: "Write the OpenAPI spec generated from the API routes",
+		Long:  "Build the OpenAPI 3.1 document from the registered Gin routes and controller structs, without connecting to the database, and write it to a file (or stdout with --output -).",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			gin.SetMode(gin.ReleaseMode)
+
+			mod, err := modules.Offline()
+			if err != nil {
+				return err
+			}
+			doc, err := json.MarshalIndent(routes.OpenAPI(mod), "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to encode spec: %w", err)
+			}
+			doc = append(doc, '\n')
+
+			if output == "-" {
+				_, err := cmd.OutOrStdout().Write(doc)
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(output), 0o755); err != nil {
+				return fmt.Errorf("failed to create %s: %w", filepath.Dir(output), err)
+			}
+			if err := os.WriteFile(output, doc, 0o644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", output, err)
+			}
+			cmd.Printf("OpenAPI spec written to %s\n", output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "docs/openapi.json", "File to write the spec to, or - for stdout")
+
+	return cmd
+}
@@ -0,0 +1,51 @@
+package console
+
+import (
+	"fmt"
+
+	"github.com/easy-attend-serviceV3/app/modules"
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/spf13/cobra"
+)
+
+func roleGrantCMD() *cobra.Command {
+	var (
+		email string
+		role  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "role-grant",
+		Short: "Grant a role to a teacher",
+		Long:  "Grant a role to the teacher with the given email, bypassing the API's permission checks. Use it to appoint the first super_admin, who can then assign roles through the API. The teacher picks the role up on the next token refresh.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			mod, err := modules.Get()
+			if err != nil {
+				return err
+			}
+			db := mod.ENT.Svc
+			ctx := cmd.Context()
+
+			teacher, err := db.GetTeacherByEmail(ctx, email)
+			if err != nil {
+				return fmt.Errorf("failed to find teacher %s: %w", email, err)
+			}
+			r, err := db.GetRoleByName(ctx, role)
+			if err != nil {
+				return fmt.Errorf("failed to find role %s: %w", role, err)
+			}
+			if err := db.AssignTeacherRole(ctx, &ent.TeacherRoleEntity{TeacherID: teacher.ID, RoleID: r.ID}); err != nil {
+				return err
+			}
+			cmd.Printf("Granted %s to %s\n", r.Name, teacher.Email)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&email, "email", "", "Email of the teacher")
+	cmd.Flags().StringVar(&role, "role", auth.RoleSuperAdmin, "Role to grant")
+	cmd.MarkFlagRequired("email")
+
+	return cmd
+}
@@ -0,0 +1,245 @@
+package console
+
+import (
+	"bufio"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/easy-attend-serviceV3/app/modules"
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+// schoolNamePrefixes are dropped from the front of school names before they
+// are compared, the longest first.
+var schoolNamePrefixes = []string{"โรงเรียน", "รร.", "รร"}
+
+func schoolMergeCMD() *cobra.Command {
+	var (
+		apply    bool
+		distance int
+		into     string
+		from     []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "school-merge",
+		Short: "Find and merge duplicate schools",
+		Long: "List the schools whose names differ only in spacing, punctuation, case, the โรงเรียน prefix or a few typos, as free-text registration created them. " +
+			"Every school of a group is near every other, and each group is listed with the --into and --from that merge it into its oldest school: teachers, classrooms, students, API keys, SSO domains and invitations move to it, the others are deleted and the moved teachers are signed out. " +
+			"With --apply each group is merged once confirmed; use --into and --from to merge the groups one by one, or schools the listing does not group.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			mod, err := modules.Get()
+			if err != nil {
+				return err
+			}
+			db := mod.ENT.Svc
+			ctx := cmd.Context()
+
+			if into != "" {
+				target, err := uuid.Parse(into)
+				if err != nil {
+					return fmt.Errorf("invalid --into: %w", err)
+				}
+				var ids []uuid.UUID
+				for _, f := range from {
+					id, err := uuid.Parse(f)
+					if err != nil {
+						return fmt.Errorf("invalid --from %s: %w", f, err)
+					}
+					if id != target && !slices.Contains(ids, id) {
+						ids = append(ids, id)
+					}
+				}
+				if len(ids) == 0 {
+					return fmt.Errorf("--from names no school besides --into")
+				}
+				if err := db.MergeSchools(ctx, target, ids); err != nil {
+					return err
+				}
+				cmd.Printf("Merged %d schools into %s\n", len(ids), target)
+				return nil
+			}
+
+			schools, err := db.GetListSchool(ctx)
+			if err != nil {
+				return err
+			}
+			groups := duplicateSchools(schools, distance)
+			if len(groups) == 0 {
+				cmd.Println("No duplicate schools found")
+				return nil
+			}
+
+			in := bufio.NewReader(cmd.InOrStdin())
+			merged := 0
+			for _, group := range groups {
+				cmd.Printf("%s (%s) <- ", group[0].Name, group[0].ID)
+				ids := make([]uuid.UUID, 0, len(group)-1)
+				for i, s := range group[1:] {
+					if i > 0 {
+						cmd.Print(", ")
+					}
+					cmd.Printf("%s (%s)", s.Name, s.ID)
+					ids = append(ids, s.ID)
+				}
+				cmd.Printf("\n  %s\n", schoolMergeFlags(group))
+				if !apply {
+					continue
+				}
+
+				cmd.Print("  Merge this group? [y/N] ")
+				answer, err := in.ReadString('\n')
+				if err != nil && answer == "" {
+					return fmt.Errorf("no answer, %d groups merged: %w", merged, err)
+				}
+				if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+					continue
+				}
+				if err := db.MergeSchools(ctx, group[0].ID, ids); err != nil {
+					return fmt.Errorf("failed to merge into %s: %w", group[0].ID, err)
+				}
+				merged++
+			}
+			if !apply {
+				cmd.Printf("Found %d groups; run again with --apply to merge them one by one\n", len(groups))
+				return nil
+			}
+			cmd.Printf("Merged %d of %d groups\n", merged, len(groups))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&apply, "apply", false, "Merge each group found once confirmed")
+	cmd.Flags().IntVar(&distance, "distance", 2, "Most typos between names of the same school")
+	cmd.Flags().StringVar(&into, "into", "", "ID of the school to keep")
+	cmd.Flags().StringSliceVar(&from, "from", nil, "IDs of the schools to merge into --into")
+	cmd.MarkFlagsRequiredTogether("into", "from")
+
+	return cmd
+}
+
+// schoolNameKey reduces a school name to the letters and digits that tell
+// schools apart.
+func schoolNameKey(name string) []rune {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, prefix := range schoolNamePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			name = name[len(prefix):]
+			break
+		}
+	}
+	var key []rune
+	for _, r := range name {
+		// Thai vowels and tone marks are combining marks
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) {
+			key = append(key, r)
+		}
+	}
+	return key
+}
+
+// schoolMergeFlags are the flags that merge group into its first school.
+func schoolMergeFlags(group []*ent.SchoolEntity) string {
+	from := make([]string, 0, len(group)-1)
+	for _, s := range group[1:] {
+		from = append(from, s.ID.String())
+	}
+	return "--into " + group[0].ID.String() + " --from " + strings.Join(from, ",")
+}
+
+// duplicateSchools groups the schools whose names are near duplicates: the
+// same once reduced by schoolNameKey, or at most maxDistance typos apart and
+// then only over a quarter of the name at most. Names with different numbers
+// never match, as in "สาธิต 1" and "สาธิต 2". Every school of a group is near
+// every other, so a chain of small differences never joins two schools far
+// apart; a school goes to the oldest group it fits. Each group is ordered
+// oldest first; schools without duplicates are left out.
+func duplicateSchools(schools []*ent.SchoolEntity, maxDistance int) [][]*ent.SchoolEntity {
+	schools = slices.Clone(schools)
+	slices.SortStableFunc(schools, func(a, b *ent.SchoolEntity) int { return a.CreatedAt.Compare(b.CreatedAt) })
+
+	keys := make([][]rune, len(schools))
+	for i, s := range schools {
+		keys[i] = schoolNameKey(s.Name)
+	}
+
+	var members [][]int
+	for i := range schools {
+		joined := false
+		for g, group := range members {
+			near := true
+			for _, j := range group {
+				near = near && nearSchoolNames(keys[i], keys[j], maxDistance)
+			}
+			if near {
+				members[g] = append(group, i)
+				joined = true
+				break
+			}
+		}
+		if !joined {
+			members = append(members, []int{i})
+		}
+	}
+
+	var groups [][]*ent.SchoolEntity
+	for _, group := range members {
+		if len(group) < 2 {
+			continue
+		}
+		list := make([]*ent.SchoolEntity, len(group))
+		for k, i := range group {
+			list[k] = schools[i]
+		}
+		groups = append(groups, list)
+	}
+	return groups
+}
+
+func nearSchoolNames(a, b []rune, maxDistance int) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return false
+	}
+	if !slices.Equal(digitsOf(a), digitsOf(b)) {
+		return false
+	}
+	d := nameDistance(a, b)
+	shorter, _ := minMax(len(a), len(b))
+	return d == 0 || (d <= maxDistance && d*4 <= shorter)
+}
+
+func digitsOf(key []rune) []rune {
+	var digits []rune
+	for _, r := range key {
+		if unicode.IsDigit(r) {
+			digits = append(digits, r)
+		}
+	}
+	return digits
+}
+
+// nameDistance is the Levenshtein distance between a and b in characters,
+// where levenshteinDistance counts bytes and so triples it for Thai.
+func nameDistance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
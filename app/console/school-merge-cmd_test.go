@@ -0,0 +1,59 @@
+package console
+
+import (
+	"testing"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/google/uuid"
+)
+
+func Test_duplicateSchools(t *testing.T) {
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	school := func(name string, age int) *ent.SchoolEntity {
+		return &ent.SchoolEntity{ID: uuid.New(), Name: name, CreatedAt: day.AddDate(0, 0, -age)}
+	}
+
+	tests := []struct {
+		name  string
+		names []string // the first is the oldest
+		want  [][]int  // indexes into names, oldest first
+	}{
+		{"Test spacing and prefix", []string{"โรงเรียนบ้านหนองบัว", "บ้าน หนองบัว", "รร.บ้านหนองบัว "}, [][]int{{0, 1, 2}}},
+		{"Test case and punctuation", []string{"St. Mary's School", "st marys school"}, [][]int{{0, 1}}},
+		{"Test typo", []string{"โรงเรียนวัดสุทธิวราราม", "โรงเรียนวัดสุทธิวรารม"}, [][]int{{0, 1}}},
+		{"Test different numbers", []string{"โรงเรียนสาธิต 1", "โรงเรียนสาธิต 2"}, nil},
+		{"Test short names", []string{"ABC", "ABD"}, nil},
+		{"Test chain of typos", []string{"Abcdefghij", "AbcdefghXY", "AbcdefWZXY"}, [][]int{{0, 1}}}, // the last is two typos from the second, four from the first
+		{"Test different schools", []string{"โรงเรียนบ้านหนองบัว", "โรงเรียนบ้านโคกสูง", "Bangkok Christian College"}, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			schools := make([]*ent.SchoolEntity, len(tc.names))
+			for i, name := range tc.names {
+				schools[i] = school(name, len(tc.names)-i)
+			}
+			// Listing order does not matter
+			reversed := make([]*ent.SchoolEntity, len(schools))
+			for i, s := range schools {
+				reversed[len(schools)-1-i] = s
+			}
+
+			groups := duplicateSchools(reversed, 2)
+			if len(groups) != len(tc.want) {
+				t.Fatalf("expected %d groups, got %d", len(tc.want), len(groups))
+			}
+			for g, want := range tc.want {
+				if len(groups[g]) != len(want) {
+					t.Fatalf("group %d: expected %d schools, got %d", g, len(want), len(groups[g]))
+				}
+				for i, idx := range want {
+					if groups[g][i] != schools[idx] {
+						t.Errorf("group %d school %d: expected %q, got %q", g, i, schools[idx].Name, groups[g][i].Name)
+					}
+				}
+			}
+		})
+	}
+}
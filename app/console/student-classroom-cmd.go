@@ -0,0 +1,75 @@
+package console
+
+import (
+	"github.com/easy-attend-serviceV3/app/modules"
+	entitiesdto "github.com/easy-attend-serviceV3/app/modules/entities/dto"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+func studentClassroomCMD() *cobra.Command {
+	var fix bool
+
+	cmd := &cobra.Command{
+		Use:   "student-classroom",
+		Short: "Check students.classroom_id against classroom memberships",
+		Long: "List the students whose classroom_id is not the classroom of their membership in effect today. " +
+			"Memberships are the source of truth; with --fix each student's classroom_id is set from them, " +
+			"except that a student with a classroom_id and no membership at all becomes a member of that classroom from the day they were created.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			mod, err := modules.Get()
+			if err != nil {
+				return err
+			}
+			db := mod.ENT.Svc
+			ctx := cmd.Context()
+
+			mismatches, err := db.GetStudentClassroomMismatches(ctx)
+			if err != nil {
+				return err
+			}
+			if len(mismatches) == 0 {
+				cmd.Println("No mismatches found")
+				return nil
+			}
+
+			for _, m := range mismatches {
+				cmd.Printf("%s (%s) of school %s: classroom_id %s, membership %s -> %s\n",
+					m.StudentCode, m.StudentID, m.SchoolID, classroomName(m.ClassroomID), classroomName(m.MemberClassroomID), studentClassroomFix(m))
+			}
+			if !fix {
+				cmd.Printf("Found %d mismatches; run again with --fix to resolve them\n", len(mismatches))
+				return nil
+			}
+
+			if err := db.FixStudentClassrooms(ctx, mismatches); err != nil {
+				return err
+			}
+			cmd.Printf("Fixed %d students\n", len(mismatches))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&fix, "fix", false, "Resolve the mismatches instead of listing them")
+
+	return cmd
+}
+
+func classroomName(id *uuid.UUID) string {
+	if id == nil {
+		return "none"
+	}
+	return id.String()
+}
+
+// studentClassroomFix describes what FixStudentClassrooms does to m.
+func studentClassroomFix(m *entitiesdto.StudentClassroomMismatch) string {
+	switch {
+	case m.ClassroomID != nil && !m.HasMembership:
+		return "add membership in " + m.ClassroomID.String()
+	case m.MemberClassroomID == nil:
+		return "clear classroom_id"
+	default:
+		return "set classroom_id to " + m.MemberClassroomID.String()
+	}
+}
@@ -0,0 +1,32 @@
+package console
+
+import (
+	"testing"
+
+	entitiesdto "github.com/easy-attend-serviceV3/app/modules/entities/dto"
+	"github.com/google/uuid"
+)
+
+func Test_studentClassroomFix(t *testing.T) {
+	a := uuid.MustParse("aaaaaaaa-0000-0000-0000-000000000000")
+	b := uuid.MustParse("bbbbbbbb-0000-0000-0000-000000000000")
+
+	tests := []struct {
+		name     string
+		mismatch entitiesdto.StudentClassroomMismatch
+		want     string
+	}{
+		{"Test never a member", entitiesdto.StudentClassroomMismatch{ClassroomID: &a}, "add membership in " + a.String()},
+		{"Test membership ended", entitiesdto.StudentClassroomMismatch{ClassroomID: &a, HasMembership: true}, "clear classroom_id"},
+		{"Test other classroom", entitiesdto.StudentClassroomMismatch{ClassroomID: &a, MemberClassroomID: &b, HasMembership: true}, "set classroom_id to " + b.String()},
+		{"Test no classroom_id", entitiesdto.StudentClassroomMismatch{MemberClassroomID: &b, HasMembership: true}, "set classroom_id to " + b.String()},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := studentClassroomFix(&tc.mismatch); got != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
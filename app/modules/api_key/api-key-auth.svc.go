@@ -0,0 +1,42 @@
+package apikey
+
+import (
+	"context"
+
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+)
+
+// keyStore answers the auth middleware's X-API-Key lookups.
+type keyStore struct {
+	svc *Service
+}
+
+// APIKeyClaims turns an active key into claims for its school, carrying its
+// scopes as permissions, and records the use.
+func (s keyStore) APIKeyClaims(ctx context.Context, key string) (*auth.TokenClaims, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`api_key.svc.auth.start`)
+
+	apiKey, err := s.svc.db.GetActiveAPIKeyByHash(ctx, auth.HashOpaqueToken(key))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.svc.db.TouchAPIKey(ctx, apiKey.ID); err != nil {
+		log.Error(err)
+	}
+
+	span.AddEvent(`api_key.svc.auth.end`)
+	return &auth.TokenClaims{
+		UserID:      apiKey.ID,
+		FirstName:   apiKey.Name,
+		UserType:    auth.UserTypeAPIKey,
+		SchoolID:    apiKey.SchoolID,
+		Permissions: apiKey.Scopes,
+	}, nil
+}
+
+// Keys is the API key check for auth.AuthMiddleware.
+func (s *Service) Keys() auth.APIKeyStore {
+	return keyStore{svc: s}
+}
@@ -0,0 +1,60 @@
+package apikey
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/config/i18n"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (c *Controller) CreateController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`api_key.create.ctl.start`)
+
+	var req CreateServiceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		base.ValidationFailed(ctx, err)
+		return
+	}
+	actorID, err := auth.GetUserID(ctx)
+	if err != nil {
+		base.Unauthorized(ctx, i18n.Unauthorized, nil)
+		return
+	}
+	// Keys are for the admin's own school unless a super admin names one
+	if req.SchoolID == uuid.Nil {
+		if req.SchoolID, err = auth.GetSchoolID(ctx); err != nil {
+			base.InvalidField(ctx, "school_id", "required")
+			return
+		}
+	}
+	req.ActorID = actorID
+	req.ActorPermissions = auth.GetPermissions(ctx)
+
+	data, err := c.svc.CreateService(ctx.Request.Context(), &req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`api_key.create.ctl.end`)
+	base.Success(ctx, data)
+}
+
+func handleError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrScopeUnknown):
+		base.InvalidField(ctx, "scopes", "oneof", strings.Join(auth.APIKeyScopes, " "))
+	case errors.Is(err, ErrExpiresInPast):
+		base.InvalidField(ctx, "expires_at", "gt", "now")
+	case errors.Is(err, ErrScopeNotGrantable):
+		base.Forbidden(ctx, i18n.Forbidden, nil)
+	default:
+		base.HandleCustomError(ctx, err)
+	}
+}
@@ -0,0 +1,58 @@
+package apikey
+
+import (
+	"context"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/google/uuid"
+)
+
+type CreateServiceRequest struct {
+	SchoolID  uuid.UUID  `json:"school_id"`
+	Name      string     `json:"name" binding:"required,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+
+	ActorID          uuid.UUID `json:"-"`
+	ActorPermissions []string  `json:"-"`
+}
+
+// CreateServiceResponse carries the key itself, which is shown only here.
+type CreateServiceResponse struct {
+	*ServiceResponse
+	Key string `json:"key"`
+}
+
+func (s *Service) CreateService(ctx context.Context, req *CreateServiceRequest) (*CreateServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`api_key.svc.create.start`)
+
+	if err := checkGrant(req.Scopes, req.ActorPermissions, req.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	key, prefix, hash, err := auth.NewAPIKey()
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	apiKey := &ent.APIKeyEntity{
+		SchoolID:  req.SchoolID,
+		Name:      req.Name,
+		Prefix:    prefix,
+		KeyHash:   hash,
+		Scopes:    compactScopes(req.Scopes),
+		CreatedBy: &req.ActorID,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := s.db.CreateAPIKey(ctx, apiKey); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	span.AddEvent(`api_key.svc.create.end`)
+	return &CreateServiceResponse{ServiceResponse: toResponse(apiKey), Key: key}, nil
+}
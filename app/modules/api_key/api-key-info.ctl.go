@@ -0,0 +1,28 @@
+package apikey
+
+import (
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (c *Controller) InfoController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`api_key.info.ctl.start`)
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		base.InvalidField(ctx, "id", "uuid")
+		return
+	}
+
+	data, err := c.svc.InfoService(ctx.Request.Context(), id)
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`api_key.info.ctl.end`)
+	base.Success(ctx, data)
+}
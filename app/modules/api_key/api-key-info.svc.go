@@ -0,0 +1,22 @@
+package apikey
+
+import (
+	"context"
+
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/google/uuid"
+)
+
+func (s *Service) InfoService(ctx context.Context, id uuid.UUID) (*ServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`api_key.svc.info.start`)
+
+	key, err := s.db.GetAPIKeyByID(ctx, id)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	span.AddEvent(`api_key.svc.info.end`)
+	return toResponse(key), nil
+}
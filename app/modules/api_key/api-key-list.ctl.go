@@ -0,0 +1,21 @@
+package apikey
+
+import (
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/gin-gonic/gin"
+)
+
+func (c *Controller) ListController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`api_key.list.ctl.start`)
+
+	data, err := c.svc.ListService(ctx.Request.Context())
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`api_key.list.ctl.end`)
+	base.Success(ctx, data)
+}
@@ -0,0 +1,26 @@
+package apikey
+
+import (
+	"context"
+
+	"github.com/easy-attend-serviceV3/app/utils"
+)
+
+func (s *Service) ListService(ctx context.Context) ([]*ServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`api_key.svc.list.start`)
+
+	keys, err := s.db.GetListAPIKey(ctx)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	resp := make([]*ServiceResponse, 0, len(keys))
+	for _, key := range keys {
+		resp = append(resp, toResponse(key))
+	}
+
+	span.AddEvent(`api_key.svc.list.end`)
+	return resp, nil
+}
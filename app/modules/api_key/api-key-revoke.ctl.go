@@ -0,0 +1,27 @@
+package apikey
+
+import (
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (c *Controller) RevokeController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`api_key.revoke.ctl.start`)
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		base.InvalidField(ctx, "id", "uuid")
+		return
+	}
+
+	if err := c.svc.RevokeService(ctx.Request.Context(), id); err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`api_key.revoke.ctl.end`)
+	base.Success(ctx, nil)
+}
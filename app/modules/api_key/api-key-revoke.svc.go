@@ -0,0 +1,22 @@
+package apikey
+
+import (
+	"context"
+
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/google/uuid"
+)
+
+// RevokeService stops a key from authenticating. Revoked keys stay listed.
+func (s *Service) RevokeService(ctx context.Context, id uuid.UUID) error {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`api_key.svc.revoke.start`)
+
+	if err := s.db.RevokeAPIKey(ctx, id); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	span.AddEvent(`api_key.svc.revoke.end`)
+	return nil
+}
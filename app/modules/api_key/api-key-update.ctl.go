@@ -0,0 +1,37 @@
+package apikey
+
+import (
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (c *Controller) UpdateController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`api_key.update.ctl.start`)
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		base.InvalidField(ctx, "id", "uuid")
+		return
+	}
+
+	var req UpdateServiceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		base.ValidationFailed(ctx, err)
+		return
+	}
+	req.ID = id
+	req.ActorPermissions = auth.GetPermissions(ctx)
+
+	data, err := c.svc.UpdateService(ctx.Request.Context(), &req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`api_key.update.ctl.end`)
+	base.Success(ctx, data)
+}
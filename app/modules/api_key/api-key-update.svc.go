@@ -0,0 +1,45 @@
+package apikey
+
+import (
+	"context"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/google/uuid"
+)
+
+type UpdateServiceRequest struct {
+	ID        uuid.UUID  `json:"-"`
+	Name      string     `json:"name" binding:"required,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+
+	ActorPermissions []string `json:"-"`
+}
+
+// UpdateService renames a key or changes its scopes or expiry. The key
+// itself stays the same; revoke it and create another to rotate.
+func (s *Service) UpdateService(ctx context.Context, req *UpdateServiceRequest) (*ServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`api_key.svc.update.start`)
+
+	if err := checkGrant(req.Scopes, req.ActorPermissions, req.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	key, err := s.db.GetAPIKeyByID(ctx, req.ID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	key.Name = req.Name
+	key.Scopes = compactScopes(req.Scopes)
+	key.ExpiresAt = req.ExpiresAt
+	if err := s.db.UpdateAPIKey(ctx, key); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	span.AddEvent(`api_key.svc.update.end`)
+	return toResponse(key), nil
+}
@@ -0,0 +1,55 @@
+package apikey
+
+import (
+	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Module struct {
+	Svc *Service
+	Ctl *Controller
+}
+
+type (
+	Service struct {
+		tracer trace.Tracer
+		db     entitiesinf.APIKeyEntity
+	}
+	Controller struct {
+		tracer trace.Tracer
+		svc    *Service
+	}
+)
+
+type Options struct {
+	tracer trace.Tracer
+	db     entitiesinf.APIKeyEntity
+}
+
+func New(db entitiesinf.APIKeyEntity) *Module {
+	tracer := otel.Tracer("easy-attend-serviceV3.modules.api_key")
+	svc := newService(&Options{
+		tracer: tracer,
+		db:     db,
+	})
+	return &Module{
+		Svc: svc,
+		Ctl: newController(tracer, svc),
+	}
+}
+
+func newService(opt *Options) *Service {
+	return &Service{
+		tracer: opt.tracer,
+		db:     opt.db,
+	}
+}
+
+func newController(trace trace.Tracer, svc *Service) *Controller {
+	return &Controller{
+		tracer: trace,
+		svc:    svc,
+	}
+}
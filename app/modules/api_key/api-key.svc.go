@@ -0,0 +1,71 @@
+package apikey
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrScopeUnknown      = errors.New("scope cannot be given to an api key")
+	ErrScopeNotGrantable = errors.New("scope grants a permission the caller does not hold")
+	ErrExpiresInPast     = errors.New("expiry is in the past")
+)
+
+type ServiceResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	SchoolID   uuid.UUID  `json:"school_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedBy  *uuid.UUID `json:"created_by"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func toResponse(key *ent.APIKeyEntity) *ServiceResponse {
+	return &ServiceResponse{
+		ID:         key.ID,
+		SchoolID:   key.SchoolID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		CreatedBy:  key.CreatedBy,
+		LastUsedAt: key.LastUsedAt,
+		ExpiresAt:  key.ExpiresAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+		UpdatedAt:  key.UpdatedAt,
+	}
+}
+
+// checkGrant allows scopes an API key may carry and the actor holds itself,
+// so no admin can mint a key stronger than their own account.
+func checkGrant(scopes, actorPermissions []string, expiresAt *time.Time) error {
+	for _, scope := range scopes {
+		if !slices.Contains(auth.APIKeyScopes, scope) {
+			return ErrScopeUnknown
+		}
+		if !auth.HasPermission(actorPermissions, scope) {
+			return ErrScopeNotGrantable
+		}
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return ErrExpiresInPast
+	}
+	return nil
+}
+
+// compactScopes drops repeated scopes and sorts them.
+func compactScopes(scopes []string) []string {
+	scopes = slices.Clone(scopes)
+	slices.Sort(scopes)
+	return slices.Compact(scopes)
+}
@@ -4,7 +4,11 @@ import (
 	"net/http"
 
 	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/config/i18n"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 func (c *Controller) CreateController(ctx *gin.Context) {
@@ -14,22 +18,27 @@ func (c *Controller) CreateController(ctx *gin.Context) {
 	var req CreateServiceRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		log.Error(err)
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"code":    "400",
-			"message": "Invalid request body",
-			"data":    nil,
-		})
+		base.ValidationFailed(ctx, err)
 		return
 	}
 
-	result, err := c.svc.CreateService(ctx, &req)
+	ov, err := auth.NewOwnershipVerifier(ctx, c.access)
+	if err == nil {
+		err = ov.VerifyAttendanceMarking(ctx.Request.Context(), req.ClassroomID, req.Date)
+	}
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+	if req.MarkedBy, err = markedBy(ctx); err != nil {
+		base.Unauthorized(ctx, i18n.Unauthorized, nil)
+		return
+	}
+
+	result, err := c.svc.CreateService(ctx.Request.Context(), &req)
 	if err != nil {
 		log.Error(err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"code":    "500",
-			"message": err.Error(),
-			"data":    nil,
-		})
+		base.HandleCustomError(ctx, err)
 		return
 	}
 
@@ -41,3 +50,16 @@ func (c *Controller) CreateController(ctx *gin.Context) {
 
 	span.AddEvent(`attendance.ctl.create.end`)
 }
+
+// markedBy returns the teacher recording attendance, or nil when an API key
+// does it for the whole school.
+func markedBy(ctx *gin.Context) (*uuid.UUID, error) {
+	if ctx.GetString("user_type") == auth.UserTypeAPIKey {
+		return nil, nil
+	}
+	id, err := auth.GetUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
@@ -2,35 +2,48 @@ package attendance
 
 import (
 	"context"
+	"errors"
 
 	entitiesdto "github.com/easy-attend-serviceV3/app/modules/entities/dto"
 	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/base"
 	"github.com/google/uuid"
 )
 
 type CreateServiceRequest struct {
-	ClassroomID uuid.UUID `json:"classroom_id" binding:"required,uuid"`
-	TeacherID   uuid.UUID `json:"teacher_id" binding:"required,uuid"`
-	StudentID   uuid.UUID `json:"student_id" binding:"required,uuid"`
-	Date        string    `json:"date" binding:"required"`   // YYYY-MM-DD format
-	Time        string    `json:"time" binding:"required"`   // HH:MM:SS format
-	Status      string    `json:"status" binding:"required"` // present, absent, late, excused
+	ClassroomID uuid.UUID  `json:"classroom_id" binding:"required,uuid"`
+	TeacherID   uuid.UUID  `json:"teacher_id" binding:"required,uuid"`
+	StudentID   uuid.UUID  `json:"student_id" binding:"required,uuid"`
+	Date        string     `json:"date" binding:"required,datetime=2006-01-02"` // YYYY-MM-DD format
+	Time        string     `json:"time" binding:"required"`                     // HH:MM:SS format
+	Status      string     `json:"status" binding:"required"`                   // present, absent, late, excused
+	Session     string     `json:"session" binding:"omitempty,max=20"`          // คาบ/รอบ ค่าว่างคือทั้งวัน
+	MarkedBy    *uuid.UUID `json:"-"`                                           // the teacher of the token; nil for API keys
 }
 
 type CreateServiceResponse struct {
-	ID          uuid.UUID `json:"id"`
-	ClassroomID uuid.UUID `json:"classroom_id"`
-	TeacherID   uuid.UUID `json:"teacher_id"`
-	StudentID   uuid.UUID `json:"student_id"`
-	Date        string    `json:"date"`
-	Time        string    `json:"time"`
-	Status      string    `json:"status"`
+	ID          uuid.UUID  `json:"id"`
+	ClassroomID uuid.UUID  `json:"classroom_id"`
+	TeacherID   uuid.UUID  `json:"teacher_id"`
+	StudentID   uuid.UUID  `json:"student_id"`
+	Date        string     `json:"date"`
+	Time        string     `json:"time"`
+	Status      string     `json:"status"`
+	Session     string     `json:"session,omitempty"`
+	MarkedBy    *uuid.UUID `json:"marked_by,omitempty"`
+	OnBehalfOf  *uuid.UUID `json:"on_behalf_of,omitempty"` // ครูที่ถูกสอนแทน
 }
 
 func (s *Service) CreateService(ctx context.Context, req *CreateServiceRequest) (*CreateServiceResponse, error) {
 	span, log := utils.LogSpanFromContext(ctx)
 	span.AddEvent(`attendance.svc.create.start`)
 
+	onBehalfOf, err := s.onBehalfOf(ctx, req.ClassroomID, req.MarkedBy, req.Date)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
 	attendance, err := s.db.CreateAttendance(ctx, &entitiesdto.AttendanceCreateRequest{
 		ClassroomID: req.ClassroomID,
 		TeacherID:   req.TeacherID,
@@ -38,6 +51,9 @@ func (s *Service) CreateService(ctx context.Context, req *CreateServiceRequest)
 		Date:        req.Date,
 		Time:        req.Time,
 		Status:      req.Status,
+		Session:     req.Session,
+		MarkedBy:    req.MarkedBy,
+		OnBehalfOf:  onBehalfOf,
 	})
 	if err != nil {
 		log.Error(err)
@@ -52,8 +68,29 @@ func (s *Service) CreateService(ctx context.Context, req *CreateServiceRequest)
 		Date:        attendance.Date,
 		Time:        attendance.Time,
 		Status:      attendance.Status,
+		Session:     attendance.Session,
+		MarkedBy:    attendance.MarkedBy,
+		OnBehalfOf:  attendance.OnBehalfOf,
 	}
 
 	span.AddEvent(`attendance.svc.create.end`)
 	return response, nil
 }
+
+// onBehalfOf returns the teacher that markedBy stands in for when recording
+// attendance in the classroom on date, or nil when they record it in their
+// own right.
+func (s *Service) onBehalfOf(ctx context.Context, classroomID uuid.UUID, markedBy *uuid.UUID, date string) (*uuid.UUID, error) {
+	if markedBy == nil {
+		return nil, nil
+	}
+	d, err := s.delegationDB.GetActiveClassroomDelegation(ctx, classroomID, *markedBy, date)
+	var notFound base.NotFoundError
+	if errors.As(err, &notFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d.DelegatorID, nil
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -16,26 +18,35 @@ func (c *Controller) DeleteController(ctx *gin.Context) {
 	id, err := uuid.Parse(idStr)
 	if err != nil {
 		log.Error(err)
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"code":    "400",
-			"message": "Invalid ID format",
-			"data":    nil,
-		})
+		base.InvalidField(ctx, "id", "uuid")
+		return
+	}
+
+	ov, err := auth.NewOwnershipVerifier(ctx, c.access)
+	if err == nil {
+		err = ov.VerifyAttendanceAccess(ctx.Request.Context(), id)
+	}
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	version, err := base.IfMatch(ctx)
+	if err != nil {
+		log.Error(err)
+		base.HandleCustomError(ctx, err)
 		return
 	}
 
 	req := &DeleteServiceRequest{
-		ID: id,
+		ID:      id,
+		Version: version,
 	}
 
-	result, err := c.svc.DeleteService(ctx, req)
+	result, err := c.svc.DeleteService(ctx.Request.Context(), req)
 	if err != nil {
 		log.Error(err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"code":    "500",
-			"message": err.Error(),
-			"data":    nil,
-		})
+		base.HandleCustomError(ctx, err)
 		return
 	}
 
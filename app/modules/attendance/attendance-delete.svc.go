@@ -2,13 +2,15 @@ package attendance
 
 import (
 	"context"
+	"time"
 
 	"github.com/easy-attend-serviceV3/app/utils"
 	"github.com/google/uuid"
 )
 
 type DeleteServiceRequest struct {
-	ID uuid.UUID `json:"id" binding:"required,uuid"`
+	ID      uuid.UUID  `json:"id" binding:"required,uuid"`
+	Version *time.Time `json:"-"` // from If-Match; nil writes unconditionally
 }
 
 type DeleteServiceResponse struct {
@@ -20,7 +22,7 @@ func (s *Service) DeleteService(ctx context.Context, req *DeleteServiceRequest)
 	span, log := utils.LogSpanFromContext(ctx)
 	span.AddEvent(`attendance.svc.delete.start`)
 
-	err := s.db.DeleteAttendance(ctx, req.ID)
+	err := s.db.DeleteAttendance(ctx, req.ID, req.Version)
 	if err != nil {
 		log.Error(err)
 		return nil, err
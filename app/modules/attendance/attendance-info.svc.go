@@ -2,6 +2,7 @@ package attendance
 
 import (
 	"context"
+	"time"
 
 	"github.com/easy-attend-serviceV3/app/utils"
 	"github.com/google/uuid"
@@ -12,13 +13,17 @@ type InfoServiceRequest struct {
 }
 
 type InfoServiceResponse struct {
-	ID        uuid.UUID       `json:"id"`
-	Date      string          `json:"date"`
-	Time      string          `json:"time"`
-	Status    string          `json:"status"`
-	Classroom ClassroomDetail `json:"classroom"`
-	Teacher   TeacherDetail   `json:"teacher"`
-	Student   StudentDetail   `json:"student"`
+	ID         uuid.UUID       `json:"id"`
+	Date       string          `json:"date"`
+	Time       string          `json:"time"`
+	Status     string          `json:"status"`
+	Session    string          `json:"session,omitempty"`
+	Classroom  ClassroomDetail `json:"classroom"`
+	Teacher    TeacherDetail   `json:"teacher"`
+	Student    StudentDetail   `json:"student"`
+	MarkedBy   *uuid.UUID      `json:"marked_by,omitempty"`
+	OnBehalfOf *uuid.UUID      `json:"on_behalf_of,omitempty"` // บันทึกโดย MarkedBy แทนครูคนนี้
+	UpdatedAt  time.Time       `json:"updated_at"`
 }
 
 type ClassroomDetail struct {
@@ -92,10 +97,14 @@ func (s *Service) InfoService(ctx context.Context, req *InfoServiceRequest) (*In
 	}
 
 	response := &InfoServiceResponse{
-		ID:     attendance.ID,
-		Date:   attendance.Date,
-		Time:   attendance.Time,
-		Status: attendance.Status,
+		ID:         attendance.ID,
+		Date:       attendance.Date,
+		Time:       attendance.Time,
+		Status:     attendance.Status,
+		Session:    attendance.Session,
+		MarkedBy:   attendance.MarkedBy,
+		OnBehalfOf: attendance.OnBehalfOf,
+		UpdatedAt:  attendance.UpdatedAt,
 		Classroom: ClassroomDetail{
 			ID:   classroom.ID,
 			Name: classroom.Name,
@@ -5,6 +5,8 @@ import (
 
 	"github.com/easy-attend-serviceV3/app/utils"
 	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/config/i18n"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -20,11 +22,7 @@ func (c *Controller) ListController(ctx *gin.Context) {
 		classroomID, err := uuid.Parse(classroomIDStr)
 		if err != nil {
 			log.Error(err)
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"code":    "400",
-				"message": "Invalid classroom_id format",
-				"data":    nil,
-			})
+			base.InvalidField(ctx, "classroom_id", "uuid")
 			return
 		}
 		req.ClassroomID = &classroomID
@@ -34,11 +32,7 @@ func (c *Controller) ListController(ctx *gin.Context) {
 		studentID, err := uuid.Parse(studentIDStr)
 		if err != nil {
 			log.Error(err)
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"code":    "400",
-				"message": "Invalid student_id format",
-				"data":    nil,
-			})
+			base.InvalidField(ctx, "student_id", "uuid")
 			return
 		}
 		req.StudentID = &studentID
@@ -52,23 +46,15 @@ func (c *Controller) ListController(ctx *gin.Context) {
 	userID, err := auth.GetUserID(ctx)
 	if err != nil {
 		log.Error(err)
-		ctx.JSON(http.StatusUnauthorized, gin.H{
-			"code":    "401",
-			"message": "User not authenticated",
-			"data":    nil,
-		})
+		base.Unauthorized(ctx, i18n.Unauthorized, nil)
 		return
 	}
 	req.UserID = userID
 
-	result, err := c.svc.ListService(ctx, &req)
+	result, err := c.svc.ListService(ctx.Request.Context(), &req)
 	if err != nil {
 		log.Error(err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"code":    "500",
-			"message": err.Error(),
-			"data":    nil,
-		})
+		base.HandleCustomError(ctx, err)
 		return
 	}
 
@@ -15,13 +15,16 @@ type ListServiceRequest struct {
 }
 
 type ListServiceResponse struct {
-	ID          uuid.UUID `json:"id"`
-	ClassroomID uuid.UUID `json:"classroom_id"`
-	TeacherID   uuid.UUID `json:"teacher_id"`
-	StudentID   uuid.UUID `json:"student_id"`
-	Date        string    `json:"date"`
-	Time        string    `json:"time"`
-	Status      string    `json:"status"`
+	ID          uuid.UUID  `json:"id"`
+	ClassroomID uuid.UUID  `json:"classroom_id"`
+	TeacherID   uuid.UUID  `json:"teacher_id"`
+	StudentID   uuid.UUID  `json:"student_id"`
+	Date        string     `json:"date"`
+	Time        string     `json:"time"`
+	Status      string     `json:"status"`
+	Session     string     `json:"session,omitempty"`
+	MarkedBy    *uuid.UUID `json:"marked_by,omitempty"`
+	OnBehalfOf  *uuid.UUID `json:"on_behalf_of,omitempty"`
 }
 
 func (s *Service) ListService(ctx context.Context, req *ListServiceRequest) ([]*ListServiceResponse, error) {
@@ -62,6 +65,9 @@ func (s *Service) ListService(ctx context.Context, req *ListServiceRequest) ([]*
 			Date:        attendance.Date,
 			Time:        attendance.Time,
 			Status:      attendance.Status,
+			Session:     attendance.Session,
+			MarkedBy:    attendance.MarkedBy,
+			OnBehalfOf:  attendance.OnBehalfOf,
 		})
 	}
 
@@ -4,6 +4,9 @@ import (
 	"net/http"
 
 	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/config/i18n"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -16,35 +19,50 @@ func (c *Controller) UpdateController(ctx *gin.Context) {
 	id, err := uuid.Parse(idStr)
 	if err != nil {
 		log.Error(err)
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"code":    "400",
-			"message": "Invalid ID format",
-			"data":    nil,
-		})
+		base.InvalidField(ctx, "id", "uuid")
+		return
+	}
+
+	ov, err := auth.NewOwnershipVerifier(ctx, c.access)
+	if err == nil {
+		err = ov.VerifyAttendanceAccess(ctx.Request.Context(), id)
+	}
+	if err != nil {
+		base.HandleCustomError(ctx, err)
 		return
 	}
 
 	var req UpdateServiceRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		log.Error(err)
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"code":    "400",
-			"message": "Invalid request body",
-			"data":    nil,
-		})
+		base.ValidationFailed(ctx, err)
+		return
+	}
+	// The record may move to another classroom or date, which must be one
+	// the teacher can mark too
+	if err := ov.VerifyAttendanceMarking(ctx.Request.Context(), req.ClassroomID, req.Date); err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	version, err := base.IfMatch(ctx)
+	if err != nil {
+		log.Error(err)
+		base.HandleCustomError(ctx, err)
 		return
 	}
 
 	req.ID = id
+	req.Version = version
+	if req.MarkedBy, err = markedBy(ctx); err != nil {
+		base.Unauthorized(ctx, i18n.Unauthorized, nil)
+		return
+	}
 
-	result, err := c.svc.UpdateService(ctx, &req)
+	result, err := c.svc.UpdateService(ctx.Request.Context(), &req)
 	if err != nil {
 		log.Error(err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"code":    "500",
-			"message": err.Error(),
-			"data":    nil,
-		})
+		base.HandleCustomError(ctx, err)
 		return
 	}
 
@@ -2,6 +2,7 @@ package attendance
 
 import (
 	"context"
+	"time"
 
 	entitiesdto "github.com/easy-attend-serviceV3/app/modules/entities/dto"
 	"github.com/easy-attend-serviceV3/app/utils"
@@ -9,29 +10,41 @@ import (
 )
 
 type UpdateServiceRequest struct {
-	ID          uuid.UUID `json:"id"`
-	ClassroomID uuid.UUID `json:"classroom_id" binding:"required,uuid"`
-	TeacherID   uuid.UUID `json:"teacher_id" binding:"required,uuid"`
-	StudentID   uuid.UUID `json:"student_id" binding:"required,uuid"`
-	Date        string    `json:"date" binding:"required"`
-	Time        string    `json:"time" binding:"required"`
-	Status      string    `json:"status" binding:"required"`
+	ID          uuid.UUID  `json:"id"`
+	ClassroomID uuid.UUID  `json:"classroom_id" binding:"required,uuid"`
+	TeacherID   uuid.UUID  `json:"teacher_id" binding:"required,uuid"`
+	StudentID   uuid.UUID  `json:"student_id" binding:"required,uuid"`
+	Date        string     `json:"date" binding:"required,datetime=2006-01-02"`
+	Time        string     `json:"time" binding:"required"`
+	Status      string     `json:"status" binding:"required"`
+	Session     string     `json:"session" binding:"omitempty,max=20"`
+	Version     *time.Time `json:"-"` // from If-Match; nil writes unconditionally
+	MarkedBy    *uuid.UUID `json:"-"` // the teacher of the token; nil for API keys
 }
 
 type UpdateServiceResponse struct {
-	ID          uuid.UUID `json:"id"`
-	ClassroomID uuid.UUID `json:"classroom_id"`
-	TeacherID   uuid.UUID `json:"teacher_id"`
-	StudentID   uuid.UUID `json:"student_id"`
-	Date        string    `json:"date"`
-	Time        string    `json:"time"`
-	Status      string    `json:"status"`
+	ID          uuid.UUID  `json:"id"`
+	ClassroomID uuid.UUID  `json:"classroom_id"`
+	TeacherID   uuid.UUID  `json:"teacher_id"`
+	StudentID   uuid.UUID  `json:"student_id"`
+	Date        string     `json:"date"`
+	Time        string     `json:"time"`
+	Status      string     `json:"status"`
+	Session     string     `json:"session,omitempty"`
+	MarkedBy    *uuid.UUID `json:"marked_by,omitempty"`
+	OnBehalfOf  *uuid.UUID `json:"on_behalf_of,omitempty"`
 }
 
 func (s *Service) UpdateService(ctx context.Context, req *UpdateServiceRequest) (*UpdateServiceResponse, error) {
 	span, log := utils.LogSpanFromContext(ctx)
 	span.AddEvent(`attendance.svc.update.start`)
 
+	onBehalfOf, err := s.onBehalfOf(ctx, req.ClassroomID, req.MarkedBy, req.Date)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
 	attendance, err := s.db.UpdateAttendance(ctx, req.ID, &entitiesdto.AttendanceUpdateRequest{
 		ID:          req.ID,
 		ClassroomID: req.ClassroomID,
@@ -40,7 +53,10 @@ func (s *Service) UpdateService(ctx context.Context, req *UpdateServiceRequest)
 		Date:        req.Date,
 		Time:        req.Time,
 		Status:      req.Status,
-	})
+		Session:     req.Session,
+		MarkedBy:    req.MarkedBy,
+		OnBehalfOf:  onBehalfOf,
+	}, req.Version)
 	if err != nil {
 		log.Error(err)
 		return nil, err
@@ -54,6 +70,9 @@ func (s *Service) UpdateService(ctx context.Context, req *UpdateServiceRequest)
 		Date:        attendance.Date,
 		Time:        attendance.Time,
 		Status:      attendance.Status,
+		Session:     attendance.Session,
+		MarkedBy:    attendance.MarkedBy,
+		OnBehalfOf:  attendance.OnBehalfOf,
 	}
 
 	span.AddEvent(`attendance.svc.update.end`)
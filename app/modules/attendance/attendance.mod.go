@@ -13,60 +13,66 @@ type Module struct {
 }
 type (
 	Service struct {
-		tracer      trace.Tracer
-		db          entitiesinf.AttendanceEntity
-		classroomDB entitiesinf.ClassroomEntity
-		schoolDB    entitiesinf.SchoolEntity
-		teacherDB   entitiesinf.TeacherEntity
-		studentDB   entitiesinf.StudentEntity
+		tracer       trace.Tracer
+		db           entitiesinf.AttendanceEntity
+		classroomDB  entitiesinf.ClassroomEntity
+		schoolDB     entitiesinf.SchoolEntity
+		teacherDB    entitiesinf.TeacherEntity
+		studentDB    entitiesinf.StudentEntity
+		delegationDB entitiesinf.ClassroomDelegationEntity
 	}
 	Controller struct {
 		tracer trace.Tracer
 		svc    *Service
+		access entitiesinf.AccessEntity
 	}
 )
 
 type Options struct {
 	// *configDTO.Config[Config]
-	tracer      trace.Tracer
-	db          entitiesinf.AttendanceEntity
-	classroomDB entitiesinf.ClassroomEntity
-	schoolDB    entitiesinf.SchoolEntity
-	teacherDB   entitiesinf.TeacherEntity
-	studentDB   entitiesinf.StudentEntity
+	tracer       trace.Tracer
+	db           entitiesinf.AttendanceEntity
+	classroomDB  entitiesinf.ClassroomEntity
+	schoolDB     entitiesinf.SchoolEntity
+	teacherDB    entitiesinf.TeacherEntity
+	studentDB    entitiesinf.StudentEntity
+	delegationDB entitiesinf.ClassroomDelegationEntity
 }
 
-func New(db entitiesinf.AttendanceEntity, classroomDB entitiesinf.ClassroomEntity, schoolDB entitiesinf.SchoolEntity, teacherDB entitiesinf.TeacherEntity, studentDB entitiesinf.StudentEntity) *Module {
+func New(db entitiesinf.AttendanceEntity, classroomDB entitiesinf.ClassroomEntity, schoolDB entitiesinf.SchoolEntity, teacherDB entitiesinf.TeacherEntity, studentDB entitiesinf.StudentEntity, delegationDB entitiesinf.ClassroomDelegationEntity, dbAccess entitiesinf.AccessEntity) *Module {
 	tracer := otel.Tracer("easy-attend-serviceV3.modules.attendance")
 	svc := newService(&Options{
 		// Config: conf,
-		tracer:      tracer,
-		db:          db,
-		classroomDB: classroomDB,
-		schoolDB:    schoolDB,
-		teacherDB:   teacherDB,
-		studentDB:   studentDB,
+		tracer:       tracer,
+		db:           db,
+		classroomDB:  classroomDB,
+		schoolDB:     schoolDB,
+		teacherDB:    teacherDB,
+		studentDB:    studentDB,
+		delegationDB: delegationDB,
 	})
 	return &Module{
 		Svc: svc,
-		Ctl: newController(tracer, svc),
+		Ctl: newController(tracer, svc, dbAccess),
 	}
 }
 
 func newService(opt *Options) *Service {
 	return &Service{
-		tracer:      opt.tracer,
-		db:          opt.db,
-		classroomDB: opt.classroomDB,
-		schoolDB:    opt.schoolDB,
-		teacherDB:   opt.teacherDB,
-		studentDB:   opt.studentDB,
+		tracer:       opt.tracer,
+		db:           opt.db,
+		classroomDB:  opt.classroomDB,
+		schoolDB:     opt.schoolDB,
+		teacherDB:    opt.teacherDB,
+		studentDB:    opt.studentDB,
+		delegationDB: opt.delegationDB,
 	}
 }
 
-func newController(trace trace.Tracer, svc *Service) *Controller {
+func newController(trace trace.Tracer, svc *Service, access entitiesinf.AccessEntity) *Controller {
 	return &Controller{
 		tracer: trace,
 		svc:    svc,
+		access: access,
 	}
 }
@@ -2,6 +2,7 @@ package classroom
 
 import (
 	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
 	"github.com/easy-attend-serviceV3/app/utils/base"
 	"github.com/easy-attend-serviceV3/config/i18n"
 	"github.com/gin-gonic/gin"
@@ -9,7 +10,7 @@ import (
 )
 
 type CreateControllerRequest struct {
-	SchoolID string `json:"school_id"`
+	SchoolID string `json:"school_id" binding:"required,uuid"`
 	Name     string `json:"name" binding:"required"`
 }
 
@@ -19,29 +20,36 @@ func (c *Controller) CreateController(ctx *gin.Context) {
 
 	var request CreateControllerRequest
 	if err := ctx.ShouldBindJSON(&request); err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.ValidationFailed(ctx, err)
 		return
 	}
 	span.AddEvent(`classroom.create.ctl.request`)
 
-	if request.Name == "" {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
-		return
-	}
+	schoolID := uuid.MustParse(request.SchoolID)
 
-	schoolID, err := uuid.Parse(request.SchoolID)
+	teacherID, err := actorTeacher(ctx)
 	if err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.Unauthorized(ctx, i18n.Unauthorized, nil)
 		return
 	}
 
 	if err := c.svc.CreateService(ctx.Request.Context(), &CreateServiceRequest{
-		SchoolID: schoolID,
-		Name:     request.Name,
+		SchoolID:  schoolID,
+		Name:      request.Name,
+		TeacherID: teacherID,
 	}); err != nil {
-		base.HandleError(ctx, err)
+		base.HandleCustomError(ctx, err)
 		return
 	}
 	span.AddEvent(`classroom.create.ctl.end`)
 	base.Success(ctx, nil)
 }
+
+// actorTeacher returns the teacher making the request, or uuid.Nil for an
+// API key, which acts for no teacher.
+func actorTeacher(ctx *gin.Context) (uuid.UUID, error) {
+	if ctx.GetString("user_type") == auth.UserTypeAPIKey {
+		return uuid.Nil, nil
+	}
+	return auth.GetUserID(ctx)
+}
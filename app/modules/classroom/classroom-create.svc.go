@@ -11,6 +11,7 @@ import (
 type CreateServiceRequest struct {
 	SchoolID  uuid.UUID
 	Name      string
+	TeacherID uuid.UUID // the creator, assigned as homeroom teacher; uuid.Nil for an API key
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -19,7 +20,7 @@ func (s *Service) CreateService(ctx context.Context, req *CreateServiceRequest)
 	span, log := utils.LogSpanFromContext(ctx)
 	span.AddEvent(`classroom.svc.create.start`)
 
-	_, err := s.db.CreateClassroom(ctx, req.SchoolID, req.Name)
+	_, err := s.db.CreateClassroom(ctx, req.SchoolID, req.Name, req.TeacherID)
 	if err != nil {
 		log.Error(err)
 		return err
@@ -0,0 +1,89 @@
+package classroom
+
+import (
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/config/i18n"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (c *Controller) DelegationListController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`classroom.delegation.list.ctl.start`)
+
+	classroomID, ok := c.pathClassroom(ctx)
+	if !ok {
+		return
+	}
+
+	data, err := c.svc.DelegationListService(ctx.Request.Context(), classroomID)
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`classroom.delegation.list.ctl.end`)
+	base.Success(ctx, data)
+}
+
+func (c *Controller) DelegationCreateController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`classroom.delegation.create.ctl.start`)
+
+	classroomID, ok := c.managedClassroom(ctx)
+	if !ok {
+		return
+	}
+
+	var req DelegationCreateServiceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		base.ValidationFailed(ctx, err)
+		return
+	}
+	actorID, err := auth.GetUserID(ctx)
+	if err != nil {
+		base.Unauthorized(ctx, i18n.Unauthorized, nil)
+		return
+	}
+	req.ClassroomID = classroomID
+	req.ActorID = actorID
+
+	data, err := c.svc.DelegationCreateService(ctx.Request.Context(), &req)
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`classroom.delegation.create.ctl.end`)
+	base.Success(ctx, data)
+}
+
+func (c *Controller) DelegationDeleteController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`classroom.delegation.delete.ctl.start`)
+
+	classroomID, ok := c.managedClassroom(ctx)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(ctx.Param("delegation_id"))
+	if err != nil {
+		base.InvalidField(ctx, "delegation_id", "uuid")
+		return
+	}
+	actorID, err := auth.GetUserID(ctx)
+	if err != nil {
+		base.Unauthorized(ctx, i18n.Unauthorized, nil)
+		return
+	}
+
+	if err := c.svc.DelegationDeleteService(ctx.Request.Context(), classroomID, id, actorID); err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`classroom.delegation.delete.ctl.end`)
+	base.Success(ctx, nil)
+}
@@ -2,14 +2,14 @@ package classroom
 
 import (
 	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
 	"github.com/easy-attend-serviceV3/app/utils/base"
-	"github.com/easy-attend-serviceV3/config/i18n"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 type DeleteControllerRequest struct {
-	ID string `uri:"id"`
+	ID string `uri:"id" binding:"required,uuid"`
 }
 
 func (c *Controller) DeleteController(ctx *gin.Context) {
@@ -18,20 +18,33 @@ func (c *Controller) DeleteController(ctx *gin.Context) {
 
 	var req DeleteControllerRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.ValidationFailed(ctx, err)
 		return
 	}
-	id, err := uuid.Parse(req.ID)
+	id := uuid.MustParse(req.ID)
+
+	ov, err := auth.NewOwnershipVerifier(ctx, c.access)
+	if err == nil {
+		err = ov.VerifyClassroomAccess(ctx.Request.Context(), id)
+	}
 	if err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.HandleCustomError(ctx, err)
 		return
 	}
+
 	span.AddEvent(`school.delete.ctl.request`)
 
+	version, err := base.IfMatch(ctx)
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
 	if err := c.svc.DeleteService(ctx.Request.Context(), &DeleteServiceRequest{
-		ID: id,
+		ID:      id,
+		Version: version,
 	}); err != nil {
-		base.HandleError(ctx, err)
+		base.HandleCustomError(ctx, err)
 		return
 	}
 
@@ -3,32 +3,22 @@ package classroom
 import (
 	"context"
 	"log/slog"
+	"time"
 
 	"github.com/easy-attend-serviceV3/app/utils"
 	"github.com/google/uuid"
 )
 
 type DeleteServiceRequest struct {
-	ID uuid.UUID
+	ID      uuid.UUID
+	Version *time.Time // from If-Match; nil writes unconditionally
 }
 
 func (s *Service) DeleteService(ctx context.Context, req *DeleteServiceRequest) error {
 	span, log := utils.LogSpanFromContext(ctx)
 	span.AddEvent(`school.svc.delete.start`)
 
-	// Check if school exists first
-	exists, err := s.db.CheckExistClassroom(ctx, req.ID)
-	if err != nil {
-		log.With(slog.Any(`body`, req)).Error(err)
-		return err
-	}
-
-	if !exists {
-		log.With(slog.Any(`body`, req)).Warnf("school not found")
-		return nil // School already doesn't exist, consider it successful
-	}
-
-	err = s.db.DeleteClassroom(ctx, req.ID)
+	err := s.db.DeleteClassroom(ctx, req.ID, req.Version)
 	if err != nil {
 		log.With(slog.Any(`body`, req)).Error(err)
 		return err
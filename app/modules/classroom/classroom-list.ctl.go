@@ -4,7 +4,6 @@ import (
 	"github.com/easy-attend-serviceV3/app/utils"
 	"github.com/easy-attend-serviceV3/app/utils/auth"
 	"github.com/easy-attend-serviceV3/app/utils/base"
-	"github.com/easy-attend-serviceV3/config/i18n"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -26,24 +25,24 @@ func (c *Controller) ListController(ctx *gin.Context) {
 
 	var req ListControllerRequest
 	if err := ctx.ShouldBind(&req); err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.ValidationFailed(ctx, err)
 		return
 	}
 	span.AddEvent(`prefix.ctl.list.request`)
 
-	// Get user ID from token context
-	userID, err := auth.GetUserID(ctx)
+	ov, err := auth.NewOwnershipVerifier(ctx, c.access)
 	if err != nil {
 		base.Unauthorized(ctx, "User not authenticated", nil)
 		return
 	}
 
-	data, _, err := c.svc.ListService(ctx, &ListServiceRequest{
+	data, _, err := c.svc.ListService(ctx.Request.Context(), &ListServiceRequest{
 		RequestPaginate: req.RequestPaginate,
-		UserID:          userID,
+		UserID:          ov.GetTeacherID(),
+		All:             ov.ReachesAll(),
 	})
 	if err != nil {
-		base.HandleError(ctx, err)
+		base.HandleCustomError(ctx, err)
 		return
 	}
 	span.AddEvent(`prefix.ctl.list.callsvc`)
@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
 	"github.com/easy-attend-serviceV3/app/utils"
 	"github.com/easy-attend-serviceV3/app/utils/base"
 	"github.com/google/uuid"
@@ -13,6 +14,7 @@ import (
 type ListServiceRequest struct {
 	base.RequestPaginate
 	UserID uuid.UUID `json:"-"` // Teacher ID from token context
+	All    bool      `json:"-"` // the user reaches every classroom, so the list is not narrowed to UserID's
 }
 
 type ListServiceResponse struct {
@@ -27,8 +29,14 @@ func (s *Service) ListService(ctx context.Context, request *ListServiceRequest)
 	span, log := utils.LogSpanFromContext(ctx)
 	span.AddEvent(`classroom.svc.list.start`)
 
-	// Get classrooms filtered by teacher ID (user from token)
-	data, err := s.db.GetClassroomsByTeacherID(ctx, request.UserID)
+	// Get the classrooms the user reaches, by the same rule as access checks
+	var data []*ent.ClassroomEntity
+	var err error
+	if request.All {
+		data, err = s.db.GetListClassroom(ctx)
+	} else {
+		data, err = s.db.GetClassroomsByTeacherID(ctx, request.UserID)
+	}
 	if err != nil {
 		log.With(slog.Any(`body`, request)).Errf(`internal: %s`, err)
 		return nil, nil, err
@@ -0,0 +1,127 @@
+package classroom
+
+import (
+	"errors"
+
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/config/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+func (c *Controller) MemberBulkAddController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`classroom.member_bulk_add.ctl.start`)
+
+	classroomID, ok := c.pathClassroom(ctx)
+	if !ok {
+		return
+	}
+
+	var req MemberBulkServiceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		base.ValidationFailed(ctx, err)
+		return
+	}
+	req.ClassroomID = classroomID
+	teacherID, err := actorTeacher(ctx)
+	if err != nil {
+		base.Unauthorized(ctx, i18n.Unauthorized, nil)
+		return
+	}
+	req.TeacherID = teacherID
+
+	data, err := c.svc.MemberBulkAddService(ctx.Request.Context(), &req)
+	if err != nil {
+		handleBulkError(ctx, data, err)
+		return
+	}
+
+	span.AddEvent(`classroom.member_bulk_add.ctl.end`)
+	base.Success(ctx, data)
+}
+
+func (c *Controller) MemberBulkRemoveController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`classroom.member_bulk_remove.ctl.start`)
+
+	classroomID, ok := c.pathClassroom(ctx)
+	if !ok {
+		return
+	}
+
+	var req MemberBulkServiceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		base.ValidationFailed(ctx, err)
+		return
+	}
+	req.ClassroomID = classroomID
+	teacherID, err := actorTeacher(ctx)
+	if err != nil {
+		base.Unauthorized(ctx, i18n.Unauthorized, nil)
+		return
+	}
+	req.TeacherID = teacherID
+
+	data, err := c.svc.MemberBulkRemoveService(ctx.Request.Context(), &req)
+	if err != nil {
+		handleBulkError(ctx, data, err)
+		return
+	}
+
+	span.AddEvent(`classroom.member_bulk_remove.ctl.end`)
+	base.Success(ctx, data)
+}
+
+func (c *Controller) MemberCopyController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`classroom.member_copy.ctl.start`)
+
+	classroomID, ok := c.pathClassroom(ctx)
+	if !ok {
+		return
+	}
+
+	var req MemberCopyServiceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		base.ValidationFailed(ctx, err)
+		return
+	}
+
+	// The roster copied is as private as the classroom it comes from
+	ov, err := auth.NewOwnershipVerifier(ctx, c.access)
+	if err == nil {
+		err = ov.VerifyClassroomAccess(ctx.Request.Context(), req.FromClassroomID)
+	}
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+	req.ClassroomID = classroomID
+	teacherID, err := actorTeacher(ctx)
+	if err != nil {
+		base.Unauthorized(ctx, i18n.Unauthorized, nil)
+		return
+	}
+	req.TeacherID = teacherID
+
+	data, err := c.svc.MemberCopyService(ctx.Request.Context(), &req)
+	if err != nil {
+		handleBulkError(ctx, data, err)
+		return
+	}
+
+	span.AddEvent(`classroom.member_copy.ctl.end`)
+	base.Success(ctx, data)
+}
+
+// handleBulkError answers a refused bulk operation with the result of each
+// item, so the caller can see which to correct.
+func handleBulkError(ctx *gin.Context, data []*MemberBulkServiceResponse, err error) {
+	if errors.Is(err, ErrBulkInvalid) {
+		base.UnprocessableEntity(ctx, i18n.BulkInvalid, data)
+		return
+	}
+	base.HandleCustomError(ctx, err)
+}
@@ -0,0 +1,136 @@
+package classroom
+
+import (
+	"context"
+	"errors"
+
+	entitiesdto "github.com/easy-attend-serviceV3/app/modules/entities/dto"
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/google/uuid"
+)
+
+// ErrBulkInvalid is returned, with the results, when a bulk operation names
+// students it cannot resolve; nothing was written.
+var ErrBulkInvalid = errors.New("bulk operation names unknown, duplicate or ambiguous students")
+
+type MemberBulkServiceRequest struct {
+	StudentIDs   []uuid.UUID `json:"student_ids" binding:"required_without=StudentCodes,omitempty,max=500"` // นักเรียนตาม ID
+	StudentCodes []string    `json:"student_codes" binding:"required_without=StudentIDs,omitempty,max=500"` // นักเรียนตามรหัสนักเรียนในโรงเรียนของห้อง
+	Date         string      `json:"date" binding:"omitempty,datetime=2006-01-02"`                          // วันที่มีผล ค่าเริ่มต้นวันนี้
+
+	ClassroomID uuid.UUID `json:"-"`
+	TeacherID   uuid.UUID `json:"-"` // the teacher making the request; uuid.Nil for API keys
+}
+
+type MemberCopyServiceRequest struct {
+	FromClassroomID uuid.UUID `json:"from_classroom_id" binding:"required"`              // ห้องที่คัดลอกรายชื่อมา
+	FromDate        string    `json:"from_date" binding:"omitempty,datetime=2006-01-02"` // วันของรายชื่อที่คัดลอก ค่าเริ่มต้นวันนี้
+	Date            string    `json:"date" binding:"omitempty,datetime=2006-01-02"`      // วันแรกในห้องใหม่ ค่าเริ่มต้นวันนี้
+
+	ClassroomID uuid.UUID `json:"-"`
+	TeacherID   uuid.UUID `json:"-"` // the teacher making the request; uuid.Nil for API keys
+}
+
+type MemberBulkServiceResponse struct {
+	StudentID   *uuid.UUID `json:"student_id"`
+	StudentCode string     `json:"student_code,omitempty"`
+	Status      string     `json:"status"`              // added, removed, skipped, not_found, duplicate, ambiguous
+	MemberID    *uuid.UUID `json:"member_id,omitempty"` // สมาชิกภาพที่เพิ่ม ลบ หรือมีอยู่แล้ว
+}
+
+func (r *MemberBulkServiceRequest) bulk() *entitiesdto.ClassroomMemberBulkRequest {
+	return &entitiesdto.ClassroomMemberBulkRequest{
+		ClassroomID:  r.ClassroomID,
+		TeacherID:    r.TeacherID,
+		Date:         r.Date,
+		StudentIDs:   r.StudentIDs,
+		StudentCodes: r.StudentCodes,
+	}
+}
+
+// bulkResponse converts the results of a bulk operation, failing with
+// ErrBulkInvalid if any item stopped it.
+func bulkResponse(results []*entitiesdto.ClassroomMemberBulkResult) ([]*MemberBulkServiceResponse, error) {
+	var err error
+	resp := make([]*MemberBulkServiceResponse, 0, len(results))
+	for _, r := range results {
+		if !r.Valid() {
+			err = ErrBulkInvalid
+		}
+		resp = append(resp, &MemberBulkServiceResponse{
+			StudentID:   r.StudentID,
+			StudentCode: r.StudentCode,
+			Status:      r.Status,
+			MemberID:    r.MemberID,
+		})
+	}
+	return resp, err
+}
+
+// MemberBulkAddService makes the students of req members of the classroom
+// from req.Date on, all or none.
+func (s *Service) MemberBulkAddService(ctx context.Context, req *MemberBulkServiceRequest) ([]*MemberBulkServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`classroom.svc.member_bulk_add.start`)
+
+	results, err := s.dbMember.BulkAddClassroomMembers(ctx, req.bulk())
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	span.AddEvent(`classroom.svc.member_bulk_add.end`)
+	return bulkResponse(results)
+}
+
+// MemberBulkRemoveService takes the students of req out of the classroom
+// from req.Date on, all or none.
+func (s *Service) MemberBulkRemoveService(ctx context.Context, req *MemberBulkServiceRequest) ([]*MemberBulkServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`classroom.svc.member_bulk_remove.start`)
+
+	results, err := s.dbMember.BulkRemoveClassroomMembers(ctx, req.bulk())
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	span.AddEvent(`classroom.svc.member_bulk_remove.end`)
+	return bulkResponse(results)
+}
+
+// MemberCopyService starts a classroom's roster for a new term from that of
+// another classroom of the same school, seats included.
+func (s *Service) MemberCopyService(ctx context.Context, req *MemberCopyServiceRequest) ([]*MemberBulkServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`classroom.svc.member_copy.start`)
+
+	from, err := s.db.GetByIDClassroom(ctx, req.FromClassroomID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	to, err := s.db.GetByIDClassroom(ctx, req.ClassroomID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	if from.ID == to.ID || from.SchoolID != to.SchoolID {
+		return nil, base.ValidationError{Field: "from_classroom_id", Rule: "invalid"}
+	}
+
+	results, err := s.dbMember.CopyClassroomMembers(ctx, req.FromClassroomID, req.FromDate, &entitiesdto.ClassroomMemberBulkRequest{
+		ClassroomID: req.ClassroomID,
+		TeacherID:   req.TeacherID,
+		Date:        req.Date,
+	})
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	log.Infof("copied %d members of classroom %s to classroom %s", len(results), req.FromClassroomID, req.ClassroomID)
+
+	span.AddEvent(`classroom.svc.member_copy.end`)
+	return bulkResponse(results)
+}
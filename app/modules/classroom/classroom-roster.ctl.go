@@ -0,0 +1,46 @@
+package classroom
+
+import (
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RosterController answers the roll-call screen. Substitutes who may only
+// mark attendance in the classroom may read it as well.
+func (c *Controller) RosterController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`classroom.roster.ctl.start`)
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		base.InvalidField(ctx, "id", "uuid")
+		return
+	}
+	var req RosterServiceRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		base.ValidationFailed(ctx, err)
+		return
+	}
+
+	ov, err := auth.NewOwnershipVerifier(ctx, c.access)
+	if err == nil {
+		err = ov.VerifyAttendanceMarking(ctx.Request.Context(), id, req.Date)
+	}
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+	req.ClassroomID = id
+
+	data, err := c.svc.RosterService(ctx.Request.Context(), &req)
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`classroom.roster.ctl.end`)
+	base.Success(ctx, data)
+}
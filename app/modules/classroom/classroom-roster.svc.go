@@ -0,0 +1,74 @@
+package classroom
+
+import (
+	"context"
+
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/google/uuid"
+)
+
+type RosterServiceRequest struct {
+	Date    string `form:"date" binding:"omitempty,datetime=2006-01-02"`      // ค่าเริ่มต้นวันนี้
+	Session string `form:"session" binding:"omitempty,max=20"`                // คาบ/รอบ ค่าว่างคือทั้งวัน
+	Order   string `form:"order" binding:"omitempty,oneof=seat student_code"` // ค่าเริ่มต้น seat
+
+	ClassroomID uuid.UUID `json:"-" form:"-"`
+}
+
+type RosterServiceResponse struct {
+	MemberID    uuid.UUID       `json:"member_id"`
+	SeatNo      *int            `json:"seat_no,omitempty"`
+	StudentID   uuid.UUID       `json:"student_id"`
+	StudentCode string          `json:"student_code"`
+	Prefix      string          `json:"prefix"`
+	FirstName   string          `json:"first_name"`
+	LastName    string          `json:"last_name"`
+	Gender      string          `json:"gender"`
+	PhotoURL    string          `json:"photo_url,omitempty"`
+	Mark        *RosterMarkInfo `json:"mark"` // null when not marked yet
+}
+
+type RosterMarkInfo struct {
+	AttendanceID uuid.UUID `json:"attendance_id"`
+	Status       string    `json:"status"`
+	Time         string    `json:"time"`
+}
+
+// RosterService lists the members of a classroom for roll call, each with
+// their mark for req.Date and req.Session.
+func (s *Service) RosterService(ctx context.Context, req *RosterServiceRequest) ([]*RosterServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`classroom.svc.roster.start`)
+
+	rows, err := s.dbMember.GetClassroomRoster(ctx, req.ClassroomID, req.Date, req.Session, req.Order)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	resp := make([]*RosterServiceResponse, 0, len(rows))
+	for _, row := range rows {
+		member := &RosterServiceResponse{
+			MemberID:    row.MemberID,
+			SeatNo:      row.SeatNo,
+			StudentID:   row.StudentID,
+			StudentCode: row.StudentCode,
+			Prefix:      row.Prefix,
+			FirstName:   row.FirstName,
+			LastName:    row.LastName,
+			Gender:      row.Gender,
+			PhotoURL:    row.PhotoURL,
+		}
+		if row.AttendanceID != nil {
+			member.Mark = &RosterMarkInfo{
+				AttendanceID: *row.AttendanceID,
+				Status:       row.Status,
+				Time:         row.Time,
+			}
+		}
+		resp = append(resp, member)
+	}
+
+	span.AddEvent(`classroom.svc.roster.end`)
+	return resp, nil
+}
@@ -0,0 +1,159 @@
+package classroom
+
+import (
+	"context"
+
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/config/i18n"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (c *Controller) TeacherListController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`classroom.teacher.list.ctl.start`)
+
+	classroomID, ok := c.pathClassroom(ctx)
+	if !ok {
+		return
+	}
+
+	data, err := c.svc.TeacherListService(ctx.Request.Context(), classroomID)
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`classroom.teacher.list.ctl.end`)
+	base.Success(ctx, data)
+}
+
+func (c *Controller) TeacherCreateController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`classroom.teacher.create.ctl.start`)
+
+	classroomID, ok := c.managedClassroom(ctx)
+	if !ok {
+		return
+	}
+
+	var req TeacherCreateServiceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		base.ValidationFailed(ctx, err)
+		return
+	}
+	actorID, err := auth.GetUserID(ctx)
+	if err != nil {
+		base.Unauthorized(ctx, i18n.Unauthorized, nil)
+		return
+	}
+	req.ClassroomID = classroomID
+	req.ActorID = actorID
+
+	data, err := c.svc.TeacherCreateService(ctx.Request.Context(), &req)
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`classroom.teacher.create.ctl.end`)
+	base.Success(ctx, data)
+}
+
+func (c *Controller) TeacherUpdateController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`classroom.teacher.update.ctl.start`)
+
+	classroomID, ok := c.managedClassroom(ctx)
+	if !ok {
+		return
+	}
+	teacherID, err := uuid.Parse(ctx.Param("teacher_id"))
+	if err != nil {
+		base.InvalidField(ctx, "teacher_id", "uuid")
+		return
+	}
+
+	var req TeacherUpdateServiceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		base.ValidationFailed(ctx, err)
+		return
+	}
+	actorID, err := auth.GetUserID(ctx)
+	if err != nil {
+		base.Unauthorized(ctx, i18n.Unauthorized, nil)
+		return
+	}
+	req.ClassroomID = classroomID
+	req.TeacherID = teacherID
+	req.ActorID = actorID
+
+	data, err := c.svc.TeacherUpdateService(ctx.Request.Context(), &req)
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`classroom.teacher.update.ctl.end`)
+	base.Success(ctx, data)
+}
+
+func (c *Controller) TeacherDeleteController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`classroom.teacher.delete.ctl.start`)
+
+	classroomID, ok := c.managedClassroom(ctx)
+	if !ok {
+		return
+	}
+	teacherID, err := uuid.Parse(ctx.Param("teacher_id"))
+	if err != nil {
+		base.InvalidField(ctx, "teacher_id", "uuid")
+		return
+	}
+	actorID, err := auth.GetUserID(ctx)
+	if err != nil {
+		base.Unauthorized(ctx, i18n.Unauthorized, nil)
+		return
+	}
+
+	if err := c.svc.TeacherDeleteService(ctx.Request.Context(), classroomID, teacherID, actorID); err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`classroom.teacher.delete.ctl.end`)
+	base.Success(ctx, nil)
+}
+
+// pathClassroom returns the classroom of the URL once the caller may access
+// it, answering the request otherwise.
+func (c *Controller) pathClassroom(ctx *gin.Context) (uuid.UUID, bool) {
+	return c.verifiedClassroom(ctx, (*auth.OwnershipVerifier).VerifyClassroomAccess)
+}
+
+// managedClassroom is pathClassroom for changing who teaches or stands in
+// there, which the caller must manage the classroom for.
+func (c *Controller) managedClassroom(ctx *gin.Context) (uuid.UUID, bool) {
+	return c.verifiedClassroom(ctx, (*auth.OwnershipVerifier).VerifyClassroomManagement)
+}
+
+func (c *Controller) verifiedClassroom(ctx *gin.Context, verify func(*auth.OwnershipVerifier, context.Context, uuid.UUID) error) (uuid.UUID, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		base.InvalidField(ctx, "id", "uuid")
+		return uuid.Nil, false
+	}
+
+	ov, err := auth.NewOwnershipVerifier(ctx, c.access)
+	if err == nil {
+		err = verify(ov, ctx.Request.Context(), id)
+	}
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,136 @@
+package classroom
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/google/uuid"
+)
+
+type TeacherServiceResponse struct {
+	ID          uuid.UUID `json:"id"`
+	ClassroomID uuid.UUID `json:"classroom_id"`
+	TeacherID   uuid.UUID `json:"teacher_id"`
+	Role        string    `json:"role"` // homeroom ครูประจำชั้น, subject ครูผู้สอน, assistant ผู้ช่วยสอน
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type TeacherCreateServiceRequest struct {
+	TeacherID uuid.UUID `json:"teacher_id" binding:"required"`
+	Role      string    `json:"role" binding:"omitempty,oneof=homeroom subject assistant"` // ค่าเริ่มต้น subject
+
+	ClassroomID uuid.UUID `json:"-"`
+	ActorID     uuid.UUID `json:"-"`
+}
+
+type TeacherUpdateServiceRequest struct {
+	Role string `json:"role" binding:"required,oneof=homeroom subject assistant"`
+
+	ClassroomID uuid.UUID `json:"-"`
+	TeacherID   uuid.UUID `json:"-"`
+	ActorID     uuid.UUID `json:"-"`
+}
+
+func toTeacherResponse(ct *ent.ClassroomTeacherEntity) *TeacherServiceResponse {
+	return &TeacherServiceResponse{
+		ID:          ct.ID,
+		ClassroomID: ct.ClassroomID,
+		TeacherID:   ct.TeacherID,
+		Role:        ct.Role,
+		CreatedAt:   ct.CreatedAt,
+		UpdatedAt:   ct.UpdatedAt,
+	}
+}
+
+func (s *Service) TeacherListService(ctx context.Context, classroomID uuid.UUID) ([]*TeacherServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`classroom.svc.teacher_list.start`)
+
+	teachers, err := s.dbClassroomTeacher.GetListClassroomTeacher(ctx, classroomID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	resp := make([]*TeacherServiceResponse, 0, len(teachers))
+	for _, ct := range teachers {
+		resp = append(resp, toTeacherResponse(ct))
+	}
+
+	span.AddEvent(`classroom.svc.teacher_list.end`)
+	return resp, nil
+}
+
+// TeacherCreateService assigns a teacher of the classroom's school to the
+// classroom, giving them access to it and its students.
+func (s *Service) TeacherCreateService(ctx context.Context, req *TeacherCreateServiceRequest) (*TeacherServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`classroom.svc.teacher_create.start`)
+
+	classroom, err := s.db.GetByIDClassroom(ctx, req.ClassroomID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	// A teacher outside the school is not found in a school-scoped context
+	var notFound base.NotFoundError
+	teacher, err := s.dbTeacher.GetByIDTeacher(ctx, req.TeacherID)
+	if errors.As(err, &notFound) || (err == nil && teacher.SchoolID != classroom.SchoolID) {
+		return nil, base.ReferenceError{Resource: "classroom_teacher", Reference: "teacher"}
+	} else if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	if req.Role == "" {
+		req.Role = ent.ClassroomRoleSubject
+	}
+	ct := &ent.ClassroomTeacherEntity{
+		ClassroomID: classroom.ID,
+		TeacherID:   teacher.ID,
+		Role:        req.Role,
+	}
+	if err := s.dbClassroomTeacher.CreateClassroomTeacher(ctx, ct); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	log.Infof("security: %s assigned teacher %s to classroom %s as %s", req.ActorID, teacher.ID, classroom.ID, ct.Role)
+
+	span.AddEvent(`classroom.svc.teacher_create.end`)
+	return toTeacherResponse(ct), nil
+}
+
+func (s *Service) TeacherUpdateService(ctx context.Context, req *TeacherUpdateServiceRequest) (*TeacherServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`classroom.svc.teacher_update.start`)
+
+	ct, err := s.dbClassroomTeacher.UpdateClassroomTeacherRole(ctx, req.ClassroomID, req.TeacherID, req.Role)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	log.Infof("security: %s made teacher %s %s of classroom %s", req.ActorID, ct.TeacherID, ct.Role, ct.ClassroomID)
+
+	span.AddEvent(`classroom.svc.teacher_update.end`)
+	return toTeacherResponse(ct), nil
+}
+
+// TeacherDeleteService removes a teacher from the classroom. The students
+// they added stay in it.
+func (s *Service) TeacherDeleteService(ctx context.Context, classroomID, teacherID, actorID uuid.UUID) error {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`classroom.svc.teacher_delete.start`)
+
+	if err := s.dbClassroomTeacher.DeleteClassroomTeacher(ctx, classroomID, teacherID); err != nil {
+		log.Error(err)
+		return err
+	}
+	log.Infof("security: %s removed teacher %s from classroom %s", actorID, teacherID, classroomID)
+
+	span.AddEvent(`classroom.svc.teacher_delete.end`)
+	return nil
+}
@@ -0,0 +1,74 @@
+package classroom
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+// delegateAccess reaches every classroom, as a full delegate of it does, but
+// manages none.
+type delegateAccess struct{ entitiesinf.AccessEntity }
+
+func (delegateAccess) TeacherCanAccessClassroom(context.Context, uuid.UUID, uuid.UUID) (bool, error) {
+	return true, nil
+}
+
+func (delegateAccess) TeacherCanManageClassroom(context.Context, uuid.UUID, uuid.UUID) (bool, error) {
+	return false, nil
+}
+
+// Test_ClassroomManagement checks that a delegate, who may otherwise work in
+// the classroom, cannot change who teaches or stands in there. The service is
+// nil, so a request let through would panic.
+func Test_ClassroomManagement(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctl := newController(otel.Tracer("test"), nil, delegateAccess{})
+	r := gin.New()
+	r.Use(func(ctx *gin.Context) {
+		ctx.Set("user_id", uuid.New())
+		ctx.Set("user_email", "delegate@school.ac.th")
+		ctx.Set("user_type", "teacher")
+		ctx.Set("user_claims", &auth.TokenClaims{Permissions: []string{auth.PermClassroomWrite}})
+	})
+	r.POST("/classroom/:id/teachers", ctl.TeacherCreateController)
+	r.PATCH("/classroom/:id/teachers/:teacher_id", ctl.TeacherUpdateController)
+	r.DELETE("/classroom/:id/teachers/:teacher_id", ctl.TeacherDeleteController)
+	r.POST("/classroom/:id/delegations", ctl.DelegationCreateController)
+	r.DELETE("/classroom/:id/delegations/:delegation_id", ctl.DelegationDeleteController)
+
+	classroom, other := uuid.NewString(), uuid.NewString()
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{"Test assign themselves", http.MethodPost, "/classroom/" + classroom + "/teachers", `{"teacher_id":"` + other + `","role":"homeroom"}`},
+		{"Test change a teacher's role", http.MethodPatch, "/classroom/" + classroom + "/teachers/" + other, `{"role":"homeroom"}`},
+		{"Test remove a teacher", http.MethodDelete, "/classroom/" + classroom + "/teachers/" + other, ""},
+		{"Test delegate in another's name", http.MethodPost, "/classroom/" + classroom + "/delegations", `{"delegator_id":"` + other + `","delegate_id":"` + other + `","starts_on":"2026-10-19","ends_on":"2099-12-31"}`},
+		{"Test revoke a delegation", http.MethodDelete, "/classroom/" + classroom + "/delegations/" + other, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusNotFound {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+			}
+		})
+	}
+}
@@ -2,15 +2,15 @@ package classroom
 
 import (
 	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
 	"github.com/easy-attend-serviceV3/app/utils/base"
-	"github.com/easy-attend-serviceV3/config/i18n"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 type UpdateControllerRequest struct {
 	Name     string `json:"name" binding:"required"`
-	SchoolID string `json:"school_id"`
+	SchoolID string `json:"school_id" binding:"required,uuid"`
 }
 
 func (c *Controller) UpdateController(ctx *gin.Context) {
@@ -21,26 +21,31 @@ func (c *Controller) UpdateController(ctx *gin.Context) {
 	idParam := ctx.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.InvalidField(ctx, "id", "uuid")
+		return
+	}
+
+	ov, err := auth.NewOwnershipVerifier(ctx, c.access)
+	if err == nil {
+		err = ov.VerifyClassroomAccess(ctx.Request.Context(), id)
+	}
+	if err != nil {
+		base.HandleCustomError(ctx, err)
 		return
 	}
 
 	var request UpdateControllerRequest
 	if err := ctx.ShouldBindJSON(&request); err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.ValidationFailed(ctx, err)
 		return
 	}
 	span.AddEvent(`school.update.ctl.request`)
 
-	// Validate required fields
-	if request.Name == "" {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
-		return
-	}
+	schoolID := uuid.MustParse(request.SchoolID)
 
-	schoolID, err := uuid.Parse(request.SchoolID)
+	version, err := base.IfMatch(ctx)
 	if err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.HandleCustomError(ctx, err)
 		return
 	}
 
@@ -48,8 +53,9 @@ func (c *Controller) UpdateController(ctx *gin.Context) {
 		ID:       id,
 		Name:     request.Name,
 		SchoolID: schoolID,
+		Version:  version,
 	}); err != nil {
-		base.HandleError(ctx, err)
+		base.HandleCustomError(ctx, err)
 		return
 	}
 
@@ -3,22 +3,24 @@ package classroom
 import (
 	"context"
 	"log/slog"
+	"time"
 
 	"github.com/easy-attend-serviceV3/app/utils"
 	"github.com/google/uuid"
 )
 
 type UpdateServiceRequest struct {
-	ID       uuid.UUID `json:"id"`
-	SchoolID uuid.UUID `json:"school_id"`
-	Name     string    `json:"name"`
+	ID       uuid.UUID  `json:"id"`
+	SchoolID uuid.UUID  `json:"school_id"`
+	Name     string     `json:"name"`
+	Version  *time.Time `json:"-"` // from If-Match; nil writes unconditionally
 }
 
 func (s *Service) UpdateService(ctx context.Context, req *UpdateServiceRequest) error {
 	span, log := utils.LogSpanFromContext(ctx)
 	span.AddEvent(`school.svc.update.start`)
 
-	_, err := s.db.UpdateClassroom(ctx, req.ID, req.SchoolID, req.Name)
+	_, err := s.db.UpdateClassroom(ctx, req.ID, req.SchoolID, req.Name, req.Version)
 	if err != nil {
 		log.With(slog.Any(`body`, req)).Error(err)
 		return err
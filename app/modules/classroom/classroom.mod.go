@@ -13,44 +13,62 @@ type Module struct {
 }
 type (
 	Service struct {
-		tracer trace.Tracer
-		db     entitiesinf.ClassroomEntity
+		tracer             trace.Tracer
+		db                 entitiesinf.ClassroomEntity
+		dbClassroomTeacher entitiesinf.ClassroomTeacherEntity
+		dbTeacher          entitiesinf.TeacherEntity
+		dbDelegation       entitiesinf.ClassroomDelegationEntity
+		dbMember           entitiesinf.ClassroomMemberEntity
 	}
 	Controller struct {
 		tracer trace.Tracer
 		svc    *Service
+		access entitiesinf.AccessEntity
 	}
 )
 
 type Options struct {
 	// *configDTO.Config[Config]
-	tracer trace.Tracer
-	db     entitiesinf.ClassroomEntity
+	tracer             trace.Tracer
+	db                 entitiesinf.ClassroomEntity
+	dbClassroomTeacher entitiesinf.ClassroomTeacherEntity
+	dbTeacher          entitiesinf.TeacherEntity
+	dbDelegation       entitiesinf.ClassroomDelegationEntity
+	dbMember           entitiesinf.ClassroomMemberEntity
 }
 
-func New(db entitiesinf.ClassroomEntity) *Module {
+func New(db entitiesinf.ClassroomEntity, dbClassroomTeacher entitiesinf.ClassroomTeacherEntity, dbTeacher entitiesinf.TeacherEntity, dbDelegation entitiesinf.ClassroomDelegationEntity, dbMember entitiesinf.ClassroomMemberEntity, dbAccess entitiesinf.AccessEntity) *Module {
 	tracer := otel.Tracer("easy-attend-serviceV3.modules.classroom")
 	svc := newService(&Options{
 		// Config: conf,
-		tracer: tracer,
-		db:     db,
+		tracer:             tracer,
+		db:                 db,
+		dbClassroomTeacher: dbClassroomTeacher,
+		dbTeacher:          dbTeacher,
+		dbDelegation:       dbDelegation,
+		dbMember:           dbMember,
 	})
 	return &Module{
 		Svc: svc,
-		Ctl: newController(tracer, svc),
+		Ctl: newController(tracer, svc, dbAccess),
 	}
 }
 
 func newService(opt *Options) *Service {
 	return &Service{
-		tracer: opt.tracer,
-		db:     opt.db,
+		tracer:             opt.tracer,
+		db:                 opt.db,
+		dbClassroomTeacher: opt.dbClassroomTeacher,
+		dbTeacher:          opt.dbTeacher,
+		dbDelegation:       opt.dbDelegation,
+		dbMember:           opt.dbMember,
 	}
 }
 
-func newController(trace trace.Tracer, svc *Service) *Controller {
+func newController(trace trace.Tracer, svc *Service, access entitiesinf.AccessEntity) *Controller {
 	return &Controller{
 		tracer: trace,
 		svc:    svc,
+		access: access,
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/base"
 	"github.com/gin-gonic/gin"
 )
 
@@ -14,22 +15,14 @@ func (c *Controller) CreateController(ctx *gin.Context) {
 	var req CreateServiceRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		log.Error(err)
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"code":    "400",
-			"message": "Invalid request body",
-			"data":    nil,
-		})
+		base.ValidationFailed(ctx, err)
 		return
 	}
 
-	result, err := c.svc.CreateService(ctx, &req)
+	result, err := c.svc.CreateService(ctx.Request.Context(), &req)
 	if err != nil {
 		log.Error(err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"code":    "500",
-			"message": err.Error(),
-			"data":    nil,
-		})
+		base.HandleCustomError(ctx, err)
 		return
 	}
 
@@ -9,16 +9,20 @@ import (
 )
 
 type CreateServiceRequest struct {
-	ClassroomID uuid.UUID `json:"classroom_id" binding:"required,uuid"`
-	TeacherID   uuid.UUID `json:"teacher_id" binding:"required,uuid"`
-	StudentID   uuid.UUID `json:"student_id" binding:"required,uuid"`
+	ClassroomID   uuid.UUID `json:"classroom_id" binding:"required,uuid"`
+	TeacherID     uuid.UUID `json:"teacher_id" binding:"required,uuid"`
+	StudentID     uuid.UUID `json:"student_id" binding:"required,uuid"`
+	SeatNo        *int      `json:"seat_no" binding:"omitempty,min=1"`                      // เลขที่นั่ง/เลขที่ในห้อง
+	EffectiveFrom string    `json:"effective_from" binding:"omitempty,datetime=2006-01-02"` // วันแรกในห้อง ค่าเริ่มต้นวันนี้
 }
 
 type CreateServiceResponse struct {
-	ID          uuid.UUID `json:"id"`
-	ClassroomID uuid.UUID `json:"classroom_id"`
-	TeacherID   uuid.UUID `json:"teacher_id"`
-	StudentID   uuid.UUID `json:"student_id"`
+	ID            uuid.UUID `json:"id"`
+	ClassroomID   uuid.UUID `json:"classroom_id"`
+	TeacherID     uuid.UUID `json:"teacher_id"`
+	StudentID     uuid.UUID `json:"student_id"`
+	SeatNo        *int      `json:"seat_no,omitempty"`
+	EffectiveFrom string    `json:"effective_from"`
 }
 
 func (s *Service) CreateService(ctx context.Context, req *CreateServiceRequest) (*CreateServiceResponse, error) {
@@ -26,9 +30,11 @@ func (s *Service) CreateService(ctx context.Context, req *CreateServiceRequest)
 	span.AddEvent(`classroom_member.svc.create.start`)
 
 	member, err := s.db.CreateClassroomMember(ctx, &entitiesdto.ClassroomMemberCreateRequest{
-		ClassroomID: req.ClassroomID,
-		TeacherID:   req.TeacherID,
-		StudentID:   req.StudentID,
+		ClassroomID:   req.ClassroomID,
+		TeacherID:     req.TeacherID,
+		StudentID:     req.StudentID,
+		SeatNo:        req.SeatNo,
+		EffectiveFrom: req.EffectiveFrom,
 	})
 	if err != nil {
 		log.Error(err)
@@ -36,10 +42,12 @@ func (s *Service) CreateService(ctx context.Context, req *CreateServiceRequest)
 	}
 
 	response := &CreateServiceResponse{
-		ID:          member.ID,
-		ClassroomID: member.ClassroomID,
-		TeacherID:   member.TeacherID,
-		StudentID:   member.StudentID,
+		ID:            member.ID,
+		ClassroomID:   member.ClassroomID,
+		TeacherID:     member.TeacherID,
+		StudentID:     member.StudentID,
+		SeatNo:        member.SeatNo,
+		EffectiveFrom: member.EffectiveFrom,
 	}
 
 	span.AddEvent(`classroom_member.svc.create.end`)
@@ -2,6 +2,7 @@ package classroommember
 
 import (
 	"context"
+	"time"
 
 	"github.com/easy-attend-serviceV3/app/utils"
 	"github.com/google/uuid"
@@ -19,6 +20,10 @@ type InfoServiceResponse struct {
 	TeacherID     uuid.UUID   `json:"teacher_id"`
 	TeacherName   string      `json:"teacher_name"`
 	Student       StudentInfo `json:"student_info"`
+	SeatNo        *int        `json:"seat_no,omitempty"`
+	EffectiveFrom string      `json:"effective_from"`
+	EffectiveTo   *string     `json:"effective_to,omitempty"`
+	UpdatedAt     time.Time   `json:"updated_at"`
 }
 
 type SchoolInfo struct {
@@ -89,6 +94,10 @@ func (s *Service) InfoService(ctx context.Context, req *InfoServiceRequest) (*In
 			StudentCode: student.StudentCode,
 			Phone:       student.Phone,
 		},
+		SeatNo:        member.SeatNo,
+		EffectiveFrom: member.EffectiveFrom,
+		EffectiveTo:   member.EffectiveTo,
+		UpdatedAt:     member.UpdatedAt,
 	}
 
 	span.AddEvent(`classroom_member.svc.info.end`)
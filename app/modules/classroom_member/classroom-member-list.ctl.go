@@ -2,9 +2,12 @@ package classroommember
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/easy-attend-serviceV3/app/utils"
 	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/config/i18n"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -20,11 +23,7 @@ func (c *Controller) ListController(ctx *gin.Context) {
 		classroomID, err := uuid.Parse(classroomIDStr)
 		if err != nil {
 			log.Error(err)
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"code":    "400",
-				"message": "Invalid classroom_id format",
-				"data":    nil,
-			})
+			base.InvalidField(ctx, "classroom_id", "uuid")
 			return
 		}
 		req.ClassroomID = &classroomID
@@ -34,37 +33,47 @@ func (c *Controller) ListController(ctx *gin.Context) {
 		studentID, err := uuid.Parse(studentIDStr)
 		if err != nil {
 			log.Error(err)
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"code":    "400",
-				"message": "Invalid student_id format",
-				"data":    nil,
-			})
+			base.InvalidField(ctx, "student_id", "uuid")
 			return
 		}
 		req.StudentID = &studentID
 	}
 
-	// Get user ID from token context
-	userID, err := auth.GetUserID(ctx)
+	if asOf := ctx.Query("as_of"); asOf != "" {
+		if _, err := time.Parse("2006-01-02", asOf); err != nil {
+			base.InvalidField(ctx, "as_of", "datetime", "2006-01-02")
+			return
+		}
+		req.AsOf = asOf
+	}
+
+	// The caller sees the members of a classroom they can reach, or the
+	// memberships of a student they can reach; only those who reach every
+	// row may list without either
+	ov, err := auth.NewOwnershipVerifier(ctx, c.access)
 	if err != nil {
 		log.Error(err)
-		ctx.JSON(http.StatusUnauthorized, gin.H{
-			"code":    "401",
-			"message": "User not authenticated",
-			"data":    nil,
-		})
+		base.Unauthorized(ctx, i18n.Unauthorized, nil)
+		return
+	}
+	switch {
+	case req.ClassroomID != nil:
+		err = ov.VerifyClassroomAccess(ctx.Request.Context(), *req.ClassroomID)
+	case req.StudentID != nil:
+		err = ov.VerifyStudentAccess(ctx.Request.Context(), *req.StudentID)
+	case !ov.ReachesAll():
+		base.InvalidField(ctx, "classroom_id", "required_without", "student_id")
+		return
+	}
+	if err != nil {
+		base.HandleCustomError(ctx, err)
 		return
 	}
-	req.UserID = userID
 
-	result, err := c.svc.ListService(ctx, &req)
+	result, err := c.svc.ListService(ctx.Request.Context(), &req)
 	if err != nil {
 		log.Error(err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"code":    "500",
-			"message": err.Error(),
-			"data":    nil,
-		})
+		base.HandleCustomError(ctx, err)
 		return
 	}
 
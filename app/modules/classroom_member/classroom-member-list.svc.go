@@ -10,14 +10,17 @@ import (
 type ListServiceRequest struct {
 	ClassroomID *uuid.UUID `json:"classroom_id,omitempty"`
 	StudentID   *uuid.UUID `json:"student_id,omitempty"`
-	UserID      uuid.UUID  `json:"-"` // Teacher ID from token context
+	AsOf        string     `json:"as_of,omitempty"` // members of the classroom on this date, today when empty
 }
 
 type ListServiceResponse struct {
-	ID          uuid.UUID `json:"id"`
-	ClassroomID uuid.UUID `json:"classroom_id"`
-	TeacherID   uuid.UUID `json:"teacher_id"`
-	StudentID   uuid.UUID `json:"student_id"`
+	ID            uuid.UUID `json:"id"`
+	ClassroomID   uuid.UUID `json:"classroom_id"`
+	TeacherID     uuid.UUID `json:"teacher_id"`
+	StudentID     uuid.UUID `json:"student_id"`
+	SeatNo        *int      `json:"seat_no,omitempty"`
+	EffectiveFrom string    `json:"effective_from"`
+	EffectiveTo   *string   `json:"effective_to,omitempty"` // วันสุดท้ายในห้อง ค่าว่างคือยังอยู่
 }
 
 func (s *Service) ListService(ctx context.Context, req *ListServiceRequest) ([]*ListServiceResponse, error) {
@@ -26,7 +29,8 @@ func (s *Service) ListService(ctx context.Context, req *ListServiceRequest) ([]*
 
 	var members []*ListServiceResponse
 
-	// If classroom ID is provided, get members by classroom (filtered by teacher)
+	// If classroom ID is provided, get members by classroom; the controller
+	// has checked that the caller can reach it
 	if req.ClassroomID != nil {
 		// First verify if classroom exists
 		_, err := s.classroomDB.GetByIDClassroom(ctx, *req.ClassroomID)
@@ -35,28 +39,7 @@ func (s *Service) ListService(ctx context.Context, req *ListServiceRequest) ([]*
 			return nil, err
 		}
 
-		// Check if teacher is associated with this classroom via classroom_members
-		teacherMembers, err := s.db.GetClassroomMembersByTeacherID(ctx, req.UserID)
-		if err != nil {
-			log.Errf("Failed to check teacher access: %s", err)
-			return nil, err
-		}
-
-		// Verify teacher has access to this classroom
-		hasAccess := false
-		for _, member := range teacherMembers {
-			if member.ClassroomID == *req.ClassroomID {
-				hasAccess = true
-				break
-			}
-		}
-
-		if !hasAccess {
-			log.Infof("Teacher %s does not have access to classroom %s", req.UserID, *req.ClassroomID)
-			return []*ListServiceResponse{}, nil // Return empty list
-		}
-
-		dbMembers, dbErr := s.db.GetListClassroomMember(ctx, *req.ClassroomID)
+		dbMembers, dbErr := s.db.GetListClassroomMember(ctx, *req.ClassroomID, req.AsOf)
 		if dbErr != nil {
 			log.Error(dbErr)
 			return nil, dbErr
@@ -64,10 +47,13 @@ func (s *Service) ListService(ctx context.Context, req *ListServiceRequest) ([]*
 
 		for _, member := range dbMembers {
 			members = append(members, &ListServiceResponse{
-				ID:          member.ID,
-				ClassroomID: member.ClassroomID,
-				TeacherID:   member.TeacherID,
-				StudentID:   member.StudentID,
+				ID:            member.ID,
+				ClassroomID:   member.ClassroomID,
+				TeacherID:     member.TeacherID,
+				StudentID:     member.StudentID,
+				SeatNo:        member.SeatNo,
+				EffectiveFrom: member.EffectiveFrom,
+				EffectiveTo:   member.EffectiveTo,
 			})
 		}
 	} else if req.StudentID != nil {
@@ -80,10 +66,13 @@ func (s *Service) ListService(ctx context.Context, req *ListServiceRequest) ([]*
 
 		for _, member := range dbMembers {
 			members = append(members, &ListServiceResponse{
-				ID:          member.ID,
-				ClassroomID: member.ClassroomID,
-				TeacherID:   member.TeacherID,
-				StudentID:   member.StudentID,
+				ID:            member.ID,
+				ClassroomID:   member.ClassroomID,
+				TeacherID:     member.TeacherID,
+				StudentID:     member.StudentID,
+				SeatNo:        member.SeatNo,
+				EffectiveFrom: member.EffectiveFrom,
+				EffectiveTo:   member.EffectiveTo,
 			})
 		}
 	} else {
@@ -97,10 +86,13 @@ func (s *Service) ListService(ctx context.Context, req *ListServiceRequest) ([]*
 
 		for _, member := range dbMembers {
 			members = append(members, &ListServiceResponse{
-				ID:          member.ID,
-				ClassroomID: member.ClassroomID,
-				TeacherID:   member.TeacherID,
-				StudentID:   member.StudentID,
+				ID:            member.ID,
+				ClassroomID:   member.ClassroomID,
+				TeacherID:     member.TeacherID,
+				StudentID:     member.StudentID,
+				SeatNo:        member.SeatNo,
+				EffectiveFrom: member.EffectiveFrom,
+				EffectiveTo:   member.EffectiveTo,
 			})
 		}
 	}
@@ -0,0 +1,59 @@
+package classroommember
+
+import (
+	"net/http"
+
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (c *Controller) TransferController(ctx *gin.Context) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`classroom_member.ctl.transfer.start`)
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		log.Error(err)
+		base.InvalidField(ctx, "id", "uuid")
+		return
+	}
+
+	var req TransferServiceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error(err)
+		base.ValidationFailed(ctx, err)
+		return
+	}
+
+	// The caller needs the membership and the classroom it moves to
+	ov, err := auth.NewOwnershipVerifier(ctx, c.access)
+	if err == nil {
+		err = ov.VerifyClassroomMemberAccess(ctx.Request.Context(), id)
+	}
+	if err == nil {
+		err = ov.VerifyClassroomAccess(ctx.Request.Context(), req.ClassroomID)
+	}
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+	req.ID = id
+
+	result, err := c.svc.TransferService(ctx.Request.Context(), &req)
+	if err != nil {
+		log.Error(err)
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"code":    "201",
+		"message": "Student transferred successfully",
+		"data":    result,
+	})
+
+	span.AddEvent(`classroom_member.ctl.transfer.end`)
+}
@@ -0,0 +1,57 @@
+package classroommember
+
+import (
+	"context"
+
+	entitiesdto "github.com/easy-attend-serviceV3/app/modules/entities/dto"
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/google/uuid"
+)
+
+type TransferServiceRequest struct {
+	ID          uuid.UUID `json:"-"`
+	ClassroomID uuid.UUID `json:"classroom_id" binding:"required,uuid"`        // ห้องเรียนใหม่
+	TeacherID   uuid.UUID `json:"teacher_id" binding:"required,uuid"`          // ครูที่ย้ายนักเรียน
+	Date        string    `json:"date" binding:"required,datetime=2006-01-02"` // วันแรกในห้องใหม่
+	SeatNo      *int      `json:"seat_no" binding:"omitempty,min=1"`           // เลขที่ในห้องใหม่
+}
+
+type TransferServiceResponse struct {
+	ID            uuid.UUID `json:"id"`
+	ClassroomID   uuid.UUID `json:"classroom_id"`
+	TeacherID     uuid.UUID `json:"teacher_id"`
+	StudentID     uuid.UUID `json:"student_id"`
+	SeatNo        *int      `json:"seat_no,omitempty"`
+	EffectiveFrom string    `json:"effective_from"`
+	PreviousID    uuid.UUID `json:"previous_id"` // สมาชิกภาพเดิมที่สิ้นสุดก่อนวันที่ย้าย
+}
+
+// TransferService moves the student of membership req.ID to req.ClassroomID
+// from req.Date on, closing the old membership the day before.
+func (s *Service) TransferService(ctx context.Context, req *TransferServiceRequest) (*TransferServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`classroom_member.svc.transfer.start`)
+
+	member, err := s.db.TransferClassroomMember(ctx, req.ID, &entitiesdto.ClassroomMemberTransferRequest{
+		ClassroomID: req.ClassroomID,
+		TeacherID:   req.TeacherID,
+		Date:        req.Date,
+		SeatNo:      req.SeatNo,
+	})
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	log.Infof("student %s moved to classroom %s from %s", member.StudentID, member.ClassroomID, member.EffectiveFrom)
+
+	span.AddEvent(`classroom_member.svc.transfer.end`)
+	return &TransferServiceResponse{
+		ID:            member.ID,
+		ClassroomID:   member.ClassroomID,
+		TeacherID:     member.TeacherID,
+		StudentID:     member.StudentID,
+		SeatNo:        member.SeatNo,
+		EffectiveFrom: member.EffectiveFrom,
+		PreviousID:    req.ID,
+	}, nil
+}
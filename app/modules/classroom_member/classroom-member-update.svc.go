@@ -2,6 +2,7 @@ package classroommember
 
 import (
 	"context"
+	"time"
 
 	entitiesdto "github.com/easy-attend-serviceV3/app/modules/entities/dto"
 	"github.com/easy-attend-serviceV3/app/utils"
@@ -9,17 +10,24 @@ import (
 )
 
 type UpdateServiceRequest struct {
-	ID          uuid.UUID `json:"id"`
-	ClassroomID uuid.UUID `json:"classroom_id" binding:"required,uuid"`
-	TeacherID   uuid.UUID `json:"teacher_id" binding:"required,uuid"`
-	StudentID   uuid.UUID `json:"student_id" binding:"required,uuid"`
+	ID            uuid.UUID  `json:"id"`
+	ClassroomID   uuid.UUID  `json:"classroom_id" binding:"required,uuid"`
+	TeacherID     uuid.UUID  `json:"teacher_id" binding:"required,uuid"`
+	StudentID     uuid.UUID  `json:"student_id" binding:"required,uuid"`
+	SeatNo        *int       `json:"seat_no" binding:"omitempty,min=1"`                      // เลขที่นั่ง/เลขที่ในห้อง
+	EffectiveFrom string     `json:"effective_from" binding:"omitempty,datetime=2006-01-02"` // ค่าว่างคือไม่เปลี่ยน
+	EffectiveTo   *string    `json:"effective_to" binding:"omitempty,datetime=2006-01-02"`   // ค่าว่างคือยังอยู่ในห้อง
+	Version       *time.Time `json:"-"`                                                      // from If-Match; nil writes unconditionally
 }
 
 type UpdateServiceResponse struct {
-	ID          uuid.UUID `json:"id"`
-	ClassroomID uuid.UUID `json:"classroom_id"`
-	TeacherID   uuid.UUID `json:"teacher_id"`
-	StudentID   uuid.UUID `json:"student_id"`
+	ID            uuid.UUID `json:"id"`
+	ClassroomID   uuid.UUID `json:"classroom_id"`
+	TeacherID     uuid.UUID `json:"teacher_id"`
+	StudentID     uuid.UUID `json:"student_id"`
+	SeatNo        *int      `json:"seat_no,omitempty"`
+	EffectiveFrom string    `json:"effective_from"`
+	EffectiveTo   *string   `json:"effective_to,omitempty"` // วันสุดท้ายในห้อง ค่าว่างคือยังอยู่
 }
 
 func (s *Service) UpdateService(ctx context.Context, req *UpdateServiceRequest) (*UpdateServiceResponse, error) {
@@ -27,21 +35,27 @@ func (s *Service) UpdateService(ctx context.Context, req *UpdateServiceRequest)
 	span.AddEvent(`classroom_member.svc.update.start`)
 
 	member, err := s.db.UpdateClassroomMember(ctx, req.ID, &entitiesdto.ClassroomMemberUpdateRequest{
-		ID:          req.ID,
-		ClassroomID: req.ClassroomID,
-		TeacherID:   req.TeacherID,
-		StudentID:   req.StudentID,
-	})
+		ID:            req.ID,
+		ClassroomID:   req.ClassroomID,
+		TeacherID:     req.TeacherID,
+		StudentID:     req.StudentID,
+		SeatNo:        req.SeatNo,
+		EffectiveFrom: req.EffectiveFrom,
+		EffectiveTo:   req.EffectiveTo,
+	}, req.Version)
 	if err != nil {
 		log.Error(err)
 		return nil, err
 	}
 
 	response := &UpdateServiceResponse{
-		ID:          member.ID,
-		ClassroomID: member.ClassroomID,
-		TeacherID:   member.TeacherID,
-		StudentID:   member.StudentID,
+		ID:            member.ID,
+		ClassroomID:   member.ClassroomID,
+		TeacherID:     member.TeacherID,
+		StudentID:     member.StudentID,
+		SeatNo:        member.SeatNo,
+		EffectiveFrom: member.EffectiveFrom,
+		EffectiveTo:   member.EffectiveTo,
 	}
 
 	span.AddEvent(`classroom_member.svc.update.end`)
@@ -23,6 +23,7 @@ type (
 	Controller struct {
 		tracer trace.Tracer
 		svc    *Service
+		access entitiesinf.AccessEntity
 	}
 )
 
@@ -36,7 +37,7 @@ type Options struct {
 	studentDB   entitiesinf.StudentEntity
 }
 
-func New(db entitiesinf.ClassroomMemberEntity, classroomDB entitiesinf.ClassroomEntity, schoolDB entitiesinf.SchoolEntity, teacherDB entitiesinf.TeacherEntity, studentDB entitiesinf.StudentEntity) *Module {
+func New(db entitiesinf.ClassroomMemberEntity, classroomDB entitiesinf.ClassroomEntity, schoolDB entitiesinf.SchoolEntity, teacherDB entitiesinf.TeacherEntity, studentDB entitiesinf.StudentEntity, dbAccess entitiesinf.AccessEntity) *Module {
 	tracer := otel.Tracer("easy-attend-serviceV3.modules.classroom_member")
 	svc := newService(&Options{
 		// Config: conf,
@@ -49,7 +50,7 @@ func New(db entitiesinf.ClassroomMemberEntity, classroomDB entitiesinf.Classroom
 	})
 	return &Module{
 		Svc: svc,
-		Ctl: newController(tracer, svc),
+		Ctl: newController(tracer, svc, dbAccess),
 	}
 }
 
@@ -64,9 +65,10 @@ func newService(opt *Options) *Service {
 	}
 }
 
-func newController(trace trace.Tracer, svc *Service) *Controller {
+func newController(trace trace.Tracer, svc *Service, access entitiesinf.AccessEntity) *Controller {
 	return &Controller{
 		tracer: trace,
 		svc:    svc,
+		access: access,
 	}
 }
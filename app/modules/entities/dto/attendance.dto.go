@@ -7,22 +7,28 @@ import (
 )
 
 type AttendanceCreateRequest struct {
-	ClassroomID uuid.UUID `json:"classroom_id" binding:"required,uuid"`
-	TeacherID   uuid.UUID `json:"teacher_id" binding:"required,uuid"`
-	StudentID   uuid.UUID `json:"student_id" binding:"required,uuid"`
-	Date        string    `json:"date" binding:"required"`   // YYYY-MM-DD format
-	Time        string    `json:"time" binding:"required"`   // HH:MM:SS format
-	Status      string    `json:"status" binding:"required"` // present, absent, late, excused
+	ClassroomID uuid.UUID  `json:"classroom_id" binding:"required,uuid"`
+	TeacherID   uuid.UUID  `json:"teacher_id" binding:"required,uuid"`
+	StudentID   uuid.UUID  `json:"student_id" binding:"required,uuid"`
+	Date        string     `json:"date" binding:"required"`            // YYYY-MM-DD format
+	Time        string     `json:"time" binding:"required"`            // HH:MM:SS format
+	Status      string     `json:"status" binding:"required"`          // present, absent, late, excused
+	Session     string     `json:"session" binding:"omitempty,max=20"` // คาบ/รอบ ค่าว่างคือทั้งวัน
+	MarkedBy    *uuid.UUID `json:"-"`
+	OnBehalfOf  *uuid.UUID `json:"-"`
 }
 
 type AttendanceUpdateRequest struct {
-	ID          uuid.UUID `json:"id"`
-	ClassroomID uuid.UUID `json:"classroom_id" binding:"required,uuid"`
-	TeacherID   uuid.UUID `json:"teacher_id" binding:"required,uuid"`
-	StudentID   uuid.UUID `json:"student_id" binding:"required,uuid"`
-	Date        string    `json:"date" binding:"required"`
-	Time        string    `json:"time" binding:"required"`
-	Status      string    `json:"status" binding:"required"`
+	ID          uuid.UUID  `json:"id"`
+	ClassroomID uuid.UUID  `json:"classroom_id" binding:"required,uuid"`
+	TeacherID   uuid.UUID  `json:"teacher_id" binding:"required,uuid"`
+	StudentID   uuid.UUID  `json:"student_id" binding:"required,uuid"`
+	Date        string     `json:"date" binding:"required"`
+	Time        string     `json:"time" binding:"required"`
+	Status      string     `json:"status" binding:"required"`
+	Session     string     `json:"session" binding:"omitempty,max=20"`
+	MarkedBy    *uuid.UUID `json:"-"`
+	OnBehalfOf  *uuid.UUID `json:"-"`
 }
 
 type AttendanceListRequest struct {
@@ -33,13 +39,16 @@ type AttendanceListRequest struct {
 }
 
 type AttendanceResponse struct {
-	ID          uuid.UUID `json:"id"`
-	ClassroomID uuid.UUID `json:"classroom_id"`
-	TeacherID   uuid.UUID `json:"teacher_id"`
-	StudentID   uuid.UUID `json:"student_id"`
-	Date        string    `json:"date"`
-	Time        string    `json:"time"`
-	Status      string    `json:"status"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uuid.UUID  `json:"id"`
+	ClassroomID uuid.UUID  `json:"classroom_id"`
+	TeacherID   uuid.UUID  `json:"teacher_id"`
+	StudentID   uuid.UUID  `json:"student_id"`
+	Date        string     `json:"date"`
+	Time        string     `json:"time"`
+	Status      string     `json:"status"`
+	Session     string     `json:"session,omitempty"`
+	MarkedBy    *uuid.UUID `json:"marked_by,omitempty"`
+	OnBehalfOf  *uuid.UUID `json:"on_behalf_of,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 }
@@ -3,21 +3,97 @@ package entitiesdto
 import "github.com/google/uuid"
 
 type ClassroomMemberCreateRequest struct {
-	ClassroomID uuid.UUID `json:"classroom_id" binding:"required,uuid"`
-	TeacherID   uuid.UUID `json:"teacher_id" binding:"required,uuid"`
-	StudentID   uuid.UUID `json:"student_id" binding:"required,uuid"`
+	ClassroomID   uuid.UUID `json:"classroom_id" binding:"required,uuid"`
+	TeacherID     uuid.UUID `json:"teacher_id" binding:"required,uuid"`
+	StudentID     uuid.UUID `json:"student_id" binding:"required,uuid"`
+	SeatNo        *int      `json:"seat_no" binding:"omitempty,min=1"`                      // เลขที่นั่ง/เลขที่ในห้อง
+	EffectiveFrom string    `json:"effective_from" binding:"omitempty,datetime=2006-01-02"` // ค่าเริ่มต้นวันนี้
 }
 
 type ClassroomMemberUpdateRequest struct {
-	ID          uuid.UUID `json:"id"`
-	ClassroomID uuid.UUID `json:"classroom_id" binding:"required,uuid"`
-	TeacherID   uuid.UUID `json:"teacher_id" binding:"required,uuid"`
-	StudentID   uuid.UUID `json:"student_id" binding:"required,uuid"`
+	ID            uuid.UUID `json:"id"`
+	ClassroomID   uuid.UUID `json:"classroom_id" binding:"required,uuid"`
+	TeacherID     uuid.UUID `json:"teacher_id" binding:"required,uuid"`
+	StudentID     uuid.UUID `json:"student_id" binding:"required,uuid"`
+	SeatNo        *int      `json:"seat_no" binding:"omitempty,min=1"`                      // เลขที่นั่ง/เลขที่ในห้อง
+	EffectiveFrom string    `json:"effective_from" binding:"omitempty,datetime=2006-01-02"` // ค่าว่างคือไม่เปลี่ยน
+	EffectiveTo   *string   `json:"effective_to" binding:"omitempty,datetime=2006-01-02"`   // ค่าว่างคือยังอยู่ในห้อง
 }
 
-type ClassroomMemberResponse struct {
-	ID          uuid.UUID `json:"id"`
+// ClassroomMemberTransferRequest moves a student, by their membership, to
+// another classroom from Date on.
+type ClassroomMemberTransferRequest struct {
 	ClassroomID uuid.UUID `json:"classroom_id"`
 	TeacherID   uuid.UUID `json:"teacher_id"`
-	StudentID   uuid.UUID `json:"student_id"`
+	Date        string    `json:"date"`
+	SeatNo      *int      `json:"seat_no"`
+}
+
+type ClassroomMemberResponse struct {
+	ID            uuid.UUID `json:"id"`
+	ClassroomID   uuid.UUID `json:"classroom_id"`
+	TeacherID     uuid.UUID `json:"teacher_id"`
+	StudentID     uuid.UUID `json:"student_id"`
+	SeatNo        *int      `json:"seat_no,omitempty"`
+	EffectiveFrom string    `json:"effective_from"`
+	EffectiveTo   *string   `json:"effective_to,omitempty"`
+}
+
+// Orders of a classroom roster
+const (
+	RosterOrderSeat        = "seat" // by seat number, students without one last by student code
+	RosterOrderStudentCode = "student_code"
+)
+
+// ClassroomRosterRow is a member of a classroom with the student's details and
+// their attendance mark for one date and session, if any.
+type ClassroomRosterRow struct {
+	MemberID     uuid.UUID  `bun:"member_id"`
+	SeatNo       *int       `bun:"seat_no"`
+	StudentID    uuid.UUID  `bun:"student_id"`
+	StudentCode  string     `bun:"student_code"`
+	Prefix       string     `bun:"prefix"`
+	FirstName    string     `bun:"first_name"`
+	LastName     string     `bun:"last_name"`
+	Gender       string     `bun:"gender"`
+	PhotoURL     string     `bun:"photo_url"`
+	AttendanceID *uuid.UUID `bun:"attendance_id"`
+	Status       string     `bun:"status"`
+	Time         string     `bun:"time"`
+}
+
+// Statuses of the items of a bulk membership operation. The last three are
+// invalid items; any of them stops the whole operation.
+const (
+	BulkAdded     = "added"
+	BulkRemoved   = "removed"
+	BulkSkipped   = "skipped" // already a member when adding, not one when removing
+	BulkNotFound  = "not_found"
+	BulkDuplicate = "duplicate"
+	BulkAmbiguous = "ambiguous" // several students of the school have the code
+)
+
+// ClassroomMemberBulkRequest adds students to, or removes them from, a
+// classroom from Date on, today when empty. Students are named by ID or by
+// student code within the classroom's school.
+type ClassroomMemberBulkRequest struct {
+	ClassroomID  uuid.UUID
+	TeacherID    uuid.UUID // who adds them; uuid.Nil for API keys
+	Date         string
+	StudentIDs   []uuid.UUID
+	StudentCodes []string
+}
+
+// ClassroomMemberBulkResult is the outcome for one student of a bulk
+// operation, in the order of the request: IDs first, then codes.
+type ClassroomMemberBulkResult struct {
+	StudentID   *uuid.UUID
+	StudentCode string
+	Status      string
+	MemberID    *uuid.UUID
+}
+
+// Valid reports whether the result lets the operation go ahead.
+func (r *ClassroomMemberBulkResult) Valid() bool {
+	return r.Status != BulkNotFound && r.Status != BulkDuplicate && r.Status != BulkAmbiguous
 }
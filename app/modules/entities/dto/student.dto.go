@@ -12,6 +12,7 @@ type StudentListResponse struct {
 	FirstName   string    `json:"first_name"`
 	LastName    string    `json:"last_name"`
 	Phone       string    `json:"phone"`
+	PhotoURL    string    `json:"photo_url,omitempty"`
 }
 
 type StudentInfoResponse struct {
@@ -24,6 +25,7 @@ type StudentInfoResponse struct {
 	FirstName   string            `json:"first_name"`
 	LastName    string            `json:"last_name"`
 	Phone       string            `json:"phone"`
+	PhotoURL    string            `json:"photo_url,omitempty"`
 }
 
 type StudentCreateRequest struct {
@@ -35,6 +37,8 @@ type StudentCreateRequest struct {
 	FirstName   string    `json:"first_name" validate:"required"`
 	LastName    string    `json:"last_name" validate:"required"`
 	Phone       string    `json:"phone"`
+	PhotoURL    string    `json:"photo_url"`
+	TeacherID   uuid.UUID `json:"-"` // who places the student in Classroom; uuid.Nil for API keys
 }
 
 type StudentUpdateRequest struct {
@@ -46,4 +50,17 @@ type StudentUpdateRequest struct {
 	FirstName   string    `json:"first_name" validate:"required"`
 	LastName    string    `json:"last_name" validate:"required"`
 	Phone       string    `json:"phone"`
+	PhotoURL    string    `json:"photo_url"`
+	TeacherID   uuid.UUID `json:"-"` // who places the student in Classroom; uuid.Nil for API keys
+}
+
+// StudentClassroomMismatch is a student whose classroom_id differs from the
+// classroom of their membership in effect today.
+type StudentClassroomMismatch struct {
+	StudentID         uuid.UUID  `bun:"student_id"`
+	SchoolID          uuid.UUID  `bun:"school_id"`
+	StudentCode       string     `bun:"student_code"`
+	ClassroomID       *uuid.UUID `bun:"classroom_id"`        // students.classroom_id
+	MemberClassroomID *uuid.UUID `bun:"member_classroom_id"` // from classroom_members
+	HasMembership     bool       `bun:"has_membership"`      // in any classroom, at any time
 }
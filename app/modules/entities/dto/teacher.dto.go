@@ -16,6 +16,11 @@ type TeacherCreateRequest struct {
 	Email       string     `json:"email"`
 	Password    string     `json:"password"`
 	Phone       string     `json:"phone"`
+	Role        string     `json:"role"` // granted with the account when set
+
+	// InvitationID is the invitation the teacher registers with. It is
+	// accepted along with the account, which starts with a verified email.
+	InvitationID *uuid.UUID `json:"invitation_id,omitempty"`
 }
 
 type TeacherUpdateRequest struct {
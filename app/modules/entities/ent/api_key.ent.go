@@ -0,0 +1,29 @@
+package ent
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// APIKeyEntity lets an integration, such as a school's SIS or a gate scanner,
+// call the API for one school without a teacher login. Only the hash of the
+// key is stored; Prefix is its first characters, kept so admins can tell keys
+// apart. Scopes are the permissions the key carries.
+type APIKeyEntity struct {
+	bun.BaseModel `bun:"table:api_keys"`
+
+	ID         uuid.UUID  `bun:"type:uuid,default:gen_random_uuid(),pk"`
+	SchoolID   uuid.UUID  `bun:"type:uuid,notnull"`
+	Name       string     `bun:"type:varchar(100),notnull"`
+	Prefix     string     `bun:"type:varchar(16),notnull"`
+	KeyHash    string     `bun:"type:char(64),notnull,unique"`
+	Scopes     []string   `bun:"type:text[],array,notnull"`
+	CreatedBy  *uuid.UUID `bun:"type:uuid"`
+	LastUsedAt *time.Time `bun:"type:timestamptz"`
+	ExpiresAt  *time.Time `bun:"type:timestamptz"`
+	RevokedAt  *time.Time `bun:"type:timestamptz"`
+	CreatedAt  time.Time  `bun:"type:timestamptz,default:current_timestamp,notnull"`
+	UpdatedAt  time.Time  `bun:"type:timestamptz,default:current_timestamp,notnull"`
+}
@@ -10,13 +10,16 @@ import (
 type AttendanceEntity struct {
 	bun.BaseModel `bun:"table:attendances"`
 
-	ID          uuid.UUID `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
-	ClassroomID uuid.UUID `bun:"classroom_id,type:uuid,notnull"`
-	TeacherID   uuid.UUID `bun:"teacher_id,type:uuid,notnull"`
-	StudentID   uuid.UUID `bun:"student_id,type:uuid,notnull"`
-	Date        string    `bun:"date,type:date,notnull"`
-	Time        string    `bun:"time,type:time,notnull"`
-	Status        string    `bun:"status,type:varchar(50),notnull"`
-	CreatedAt     time.Time `bun:"created_at,notnull,default:current_timestamp"`
-	UpdatedAt     time.Time `bun:"updated_at,notnull,default:current_timestamp"`
+	ID          uuid.UUID  `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	ClassroomID uuid.UUID  `bun:"classroom_id,type:uuid,notnull"`
+	TeacherID   uuid.UUID  `bun:"teacher_id,type:uuid,notnull"`
+	StudentID   uuid.UUID  `bun:"student_id,type:uuid,notnull"`
+	Date        string     `bun:"date,type:date,notnull"`
+	Time        string     `bun:"time,type:time,notnull"`
+	Status      string     `bun:"status,type:varchar(50),notnull"`
+	Session     string     `bun:"session,type:varchar(20),notnull,default:''"` // the period of the day; empty for the whole day
+	MarkedBy    *uuid.UUID `bun:"marked_by,type:uuid"`                         // the teacher who recorded it
+	OnBehalfOf  *uuid.UUID `bun:"on_behalf_of,type:uuid"`                      // the teacher MarkedBy stood in for, through a delegation
+	CreatedAt   time.Time  `bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt   time.Time  `bun:"updated_at,notnull,default:current_timestamp"`
 }
@@ -0,0 +1,31 @@
+package ent
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// What a delegation lets the delegate do in the classroom.
+const (
+	DelegationScopeFull       = "full"       // everything the delegator can
+	DelegationScopeAttendance = "attendance" // mark attendance only
+)
+
+// ClassroomDelegationEntity lets DelegateID stand in for DelegatorID in a
+// classroom from StartsOn to EndsOn, both included. It lapses by itself once
+// EndsOn has passed.
+type ClassroomDelegationEntity struct {
+	bun.BaseModel `bun:"table:classroom_delegations"`
+
+	ID          uuid.UUID  `bun:"type:uuid,default:gen_random_uuid(),pk"`
+	ClassroomID uuid.UUID  `bun:"type:uuid,notnull"`
+	DelegatorID uuid.UUID  `bun:"type:uuid,notnull"`
+	DelegateID  uuid.UUID  `bun:"type:uuid,notnull"`
+	Scope       string     `bun:"type:varchar(20),notnull"`
+	StartsOn    string     `bun:"type:date,notnull"`
+	EndsOn      string     `bun:"type:date,notnull"`
+	CreatedBy   *uuid.UUID `bun:"type:uuid"`
+	CreatedAt   time.Time  `bun:"type:timestamptz,default:current_timestamp,notnull"`
+}
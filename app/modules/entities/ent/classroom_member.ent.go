@@ -7,13 +7,22 @@ import (
 	"github.com/uptrace/bun"
 )
 
+// ClassroomMemberEntity places a student in a classroom from EffectiveFrom to
+// EffectiveTo, both inclusive; an open EffectiveTo means they are still
+// there. A student who changes classrooms keeps the old membership, closed,
+// so past rosters stay as they were. TeacherID records who added them, when
+// known; which teachers may access the classroom is up to classroom_teachers
+// (see ClassroomTeacherEntity).
 type ClassroomMemberEntity struct {
 	bun.BaseModel `bun:"table:classroom_members"`
 
-	ID          uuid.UUID `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
-	ClassroomID uuid.UUID `bun:"classroom_id,type:uuid,notnull"`
-	StudentID   uuid.UUID `bun:"student_id,type:uuid,notnull"`
-	TeacherID   uuid.UUID `bun:"teacher_id,type:uuid,notnull"`
-	CreatedAt   time.Time `bun:"created_at,notnull,default:current_timestamp"`
-	UpdatedAt   time.Time `bun:"updated_at,notnull,default:current_timestamp"`
+	ID            uuid.UUID `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	ClassroomID   uuid.UUID `bun:"classroom_id,type:uuid,notnull"`
+	StudentID     uuid.UUID `bun:"student_id,type:uuid,notnull"`
+	TeacherID     uuid.UUID `bun:"teacher_id,type:uuid,nullzero"`
+	SeatNo        *int      `bun:"seat_no"` // the student's number in the classroom, for the roster
+	EffectiveFrom string    `bun:"effective_from,type:date,nullzero,notnull,default:current_date"`
+	EffectiveTo   *string   `bun:"effective_to,type:date"`
+	CreatedAt     time.Time `bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt     time.Time `bun:"updated_at,notnull,default:current_timestamp"`
 }
@@ -0,0 +1,29 @@
+package ent
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Roles a teacher holds in a classroom.
+const (
+	ClassroomRoleHomeroom  = "homeroom"
+	ClassroomRoleSubject   = "subject"
+	ClassroomRoleAssistant = "assistant"
+)
+
+// ClassroomTeacherEntity assigns a teacher to a classroom, which gives them
+// access to it and its students. A classroom can have several teachers but
+// each one at most once.
+type ClassroomTeacherEntity struct {
+	bun.BaseModel `bun:"table:classroom_teachers"`
+
+	ID          uuid.UUID `bun:"type:uuid,default:gen_random_uuid(),pk"`
+	ClassroomID uuid.UUID `bun:"type:uuid,notnull"`
+	TeacherID   uuid.UUID `bun:"type:uuid,notnull"`
+	Role        string    `bun:"type:varchar(20),notnull"`
+	CreatedAt   time.Time `bun:"type:timestamptz,default:current_timestamp,notnull"`
+	UpdatedAt   time.Time `bun:"type:timestamptz,default:current_timestamp,notnull"`
+}
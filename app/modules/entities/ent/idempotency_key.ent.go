@@ -0,0 +1,22 @@
+package ent
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// IdempotencyKeyEntity remembers the response to a POST sent with an
+// Idempotency-Key so a retry can be answered without running it again.
+// Status is zero while the first request is still in flight.
+type IdempotencyKeyEntity struct {
+	bun.BaseModel `bun:"table:idempotency_keys"`
+
+	Key         string    `bun:"type:varchar(320),pk"`
+	Fingerprint string    `bun:"type:char(64),notnull"`
+	Status      int       `bun:"type:smallint,notnull,default:0"`
+	ContentType string    `bun:"type:varchar(255)"`
+	Body        []byte    `bun:"type:bytea"`
+	CreatedAt   time.Time `bun:"type:timestamptz,notnull,default:current_timestamp"`
+	ExpiresAt   time.Time `bun:"type:timestamptz,notnull"`
+}
@@ -0,0 +1,39 @@
+package ent
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// TeacherInvitationEntity invites Email to register as a teacher of a school
+// with Role. Only the hash of the emailed token is stored; registering with it
+// sets AcceptedAt and AcceptedBy, so an invitation works once.
+type TeacherInvitationEntity struct {
+	bun.BaseModel `bun:"table:teacher_invitations"`
+
+	ID         uuid.UUID  `bun:"type:uuid,default:gen_random_uuid(),pk"`
+	SchoolID   uuid.UUID  `bun:"type:uuid,notnull"`
+	Email      string     `bun:"type:varchar(100),notnull"`
+	Role       string     `bun:"type:varchar(50),notnull"`
+	TokenHash  string     `bun:"type:char(64),notnull,unique"`
+	InvitedBy  *uuid.UUID `bun:"type:uuid"`
+	ExpiresAt  time.Time  `bun:"type:timestamptz,notnull"`
+	AcceptedAt *time.Time `bun:"type:timestamptz"`
+	AcceptedBy *uuid.UUID `bun:"type:uuid"`
+	CreatedAt  time.Time  `bun:"type:timestamptz,default:current_timestamp,notnull"`
+}
+
+// SchoolJoinCodeEntity is the code a school hands out for teachers to
+// register with themselves. A school has at most one; rotating it replaces
+// the old one.
+type SchoolJoinCodeEntity struct {
+	bun.BaseModel `bun:"table:school_join_codes"`
+
+	SchoolID  uuid.UUID  `bun:"type:uuid,pk"`
+	Code      string     `bun:"type:varchar(16),notnull,unique"`
+	ExpiresAt time.Time  `bun:"type:timestamptz,notnull"`
+	CreatedBy *uuid.UUID `bun:"type:uuid"`
+	CreatedAt time.Time  `bun:"type:timestamptz,default:current_timestamp,notnull"`
+}
@@ -0,0 +1,37 @@
+package ent
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// OIDCStateEntity is a sign-in started at an OpenID provider, stored by the
+// hash of the state sent there. It holds the nonce and PKCE verifier that
+// finish the sign-in and is removed when the provider sends the teacher back.
+type OIDCStateEntity struct {
+	bun.BaseModel `bun:"table:oidc_states"`
+
+	StateHash    string    `bun:"type:char(64),pk"`
+	Provider     string    `bun:"type:varchar(32),notnull"`
+	Nonce        string    `bun:"type:varchar(64),notnull"`
+	CodeVerifier string    `bun:"type:varchar(128),notnull"`
+	ExpiresAt    time.Time `bun:"type:timestamptz,notnull"`
+	CreatedAt    time.Time `bun:"type:timestamptz,default:current_timestamp,notnull"`
+}
+
+// TeacherIdentityEntity links a teacher to an account at an OpenID provider.
+// Subject is the provider's id of the account, which unlike the email never
+// changes.
+type TeacherIdentityEntity struct {
+	bun.BaseModel `bun:"table:teacher_identities"`
+
+	ID          uuid.UUID  `bun:"type:uuid,default:gen_random_uuid(),pk"`
+	TeacherID   uuid.UUID  `bun:"type:uuid,notnull"`
+	Provider    string     `bun:"type:varchar(32),notnull"`
+	Subject     string     `bun:"type:varchar(255),notnull"`
+	Email       string     `bun:"type:varchar(255),notnull"`
+	LastLoginAt *time.Time `bun:"type:timestamptz"`
+	CreatedAt   time.Time  `bun:"type:timestamptz,default:current_timestamp,notnull"`
+}
@@ -0,0 +1,25 @@
+package ent
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// RefreshTokenEntity is one issued refresh token, stored by hash. Every token
+// rotated from the same login shares a FamilyID; presenting a token that was
+// already used revokes the whole family.
+type RefreshTokenEntity struct {
+	bun.BaseModel `bun:"table:refresh_tokens"`
+
+	ID        uuid.UUID  `bun:"type:uuid,default:gen_random_uuid(),pk"`
+	FamilyID  uuid.UUID  `bun:"type:uuid,notnull"`
+	ParentID  *uuid.UUID `bun:"type:uuid"`
+	TeacherID uuid.UUID  `bun:"type:uuid,notnull"`
+	TokenHash string     `bun:"type:char(64),notnull,unique"`
+	ExpiresAt time.Time  `bun:"type:timestamptz,notnull"`
+	UsedAt    *time.Time `bun:"type:timestamptz"`
+	RevokedAt *time.Time `bun:"type:timestamptz"`
+	CreatedAt time.Time  `bun:"type:timestamptz,default:current_timestamp,notnull"`
+}
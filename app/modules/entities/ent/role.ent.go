@@ -0,0 +1,34 @@
+package ent
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// RoleEntity is a named set of permissions, such as "school:write", that can
+// be granted to teachers. Teachers holding a role with MFARequired must log
+// in with a TOTP code.
+type RoleEntity struct {
+	bun.BaseModel `bun:"table:roles"`
+
+	ID          uuid.UUID `bun:"type:uuid,default:gen_random_uuid(),pk"`
+	Name        string    `bun:"type:varchar(50),notnull,unique"`
+	Description string    `bun:"type:varchar(255)"`
+	Permissions []string  `bun:"type:text[],array,notnull"`
+	MFARequired bool      `bun:"mfa_required,notnull"`
+	CreatedAt   time.Time `bun:"type:timestamptz,default:current_timestamp,notnull"`
+	UpdatedAt   time.Time `bun:"type:timestamptz,default:current_timestamp,notnull"`
+}
+
+// TeacherRoleEntity grants a role to a teacher. GrantedBy is the teacher who
+// assigned it, nil for roles given on registration or by the migrations.
+type TeacherRoleEntity struct {
+	bun.BaseModel `bun:"table:teacher_roles"`
+
+	TeacherID uuid.UUID  `bun:"type:uuid,pk"`
+	RoleID    uuid.UUID  `bun:"type:uuid,pk"`
+	GrantedBy *uuid.UUID `bun:"type:uuid"`
+	CreatedAt time.Time  `bun:"type:timestamptz,default:current_timestamp,notnull"`
+}
@@ -0,0 +1,25 @@
+package ent
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// SchoolDomainEntity lets teachers sign in with the accounts an OpenID
+// provider manages for one of the school's email domains. TenantID is the
+// Microsoft tenant that owns the domain; Google accounts are matched by their
+// Workspace domain. With AutoProvision, accounts without a teacher get one.
+type SchoolDomainEntity struct {
+	bun.BaseModel `bun:"table:school_domains"`
+
+	ID            uuid.UUID  `bun:"type:uuid,default:gen_random_uuid(),pk"`
+	SchoolID      uuid.UUID  `bun:"type:uuid,notnull"`
+	Provider      string     `bun:"type:varchar(32),notnull"`
+	Domain        string     `bun:"type:varchar(255),notnull"`
+	TenantID      string     `bun:"type:varchar(64),nullzero"`
+	AutoProvision bool       `bun:"type:boolean,notnull"`
+	CreatedBy     *uuid.UUID `bun:"type:uuid"`
+	CreatedAt     time.Time  `bun:"type:timestamptz,default:current_timestamp,notnull"`
+}
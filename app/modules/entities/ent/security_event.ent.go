@@ -0,0 +1,30 @@
+package ent
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Security event kinds.
+const (
+	SecurityEventLockout = "lockout"
+	SecurityEventUnlock  = "unlock"
+)
+
+// SecurityEventEntity is an append-only record of something an administrator
+// may want to review, such as an account locked after repeated failed logins.
+// Subject names what the event is about, e.g. "email:a@b.c" or "ip:10.0.0.1";
+// ActorID is the teacher who caused it, when there is one.
+type SecurityEventEntity struct {
+	bun.BaseModel `bun:"table:security_events"`
+
+	ID        uuid.UUID      `bun:"type:uuid,default:gen_random_uuid(),pk"`
+	Event     string         `bun:"type:varchar(32),notnull"`
+	Subject   string         `bun:"type:varchar(320),notnull"`
+	ActorID   *uuid.UUID     `bun:"type:uuid"`
+	IP        string         `bun:"type:varchar(45)"`
+	Detail    map[string]any `bun:"type:jsonb"`
+	CreatedAt time.Time      `bun:"type:timestamptz,default:current_timestamp,notnull"`
+}
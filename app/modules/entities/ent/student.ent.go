@@ -12,13 +12,14 @@ type StudentEntity struct {
 
 	ID          uuid.UUID `bun:"type:uuid,default:gen_random_uuid(),pk"`
 	SchoolID    uuid.UUID `bun:"type:uuid,notnull"`
-	ClassroomID uuid.UUID `bun:"type:uuid"`
+	ClassroomID uuid.UUID `bun:"type:uuid,nullzero"` // the classroom of the student's current membership, kept in step with classroom_members
 	PrefixID    uuid.UUID `bun:"type:uuid,notnull"`
 	GenderID    uuid.UUID `bun:"type:uuid,notnull"`
 	StudentCode string    `bun:"type:varchar(20),notnull"`
 	FirstName   string    `bun:"type:varchar(100),notnull"`
 	LastName    string    `bun:"type:varchar(100),notnull"`
 	Phone       string    `bun:"type:varchar(15)"`
+	PhotoURL    string    `bun:"type:varchar(500),nullzero"`
 	CreatedAt   time.Time `bun:"type:timestamptz,default:current_timestamp,notnull"`
 	UpdatedAt   time.Time `bun:"type:timestamptz,default:current_timestamp,notnull"`
 }
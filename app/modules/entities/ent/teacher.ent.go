@@ -20,6 +20,8 @@ type TeacherEntity struct {
 	Email       string     `bun:"type:varchar(100),notnull,unique"`
 	Password    string     `bun:"type:varchar(255),notnull"`
 	Phone       string     `bun:"type:varchar(15)"`
-	CreatedAt   time.Time  `bun:"type:timestamptz,default:current_timestamp,notnull"`
-	UpdatedAt   time.Time  `bun:"type:timestamptz,default:current_timestamp,notnull"`
+	// EmailVerifiedAt is set once the teacher opens the verification link.
+	EmailVerifiedAt *time.Time `bun:"type:timestamptz"`
+	CreatedAt       time.Time  `bun:"type:timestamptz,default:current_timestamp,notnull"`
+	UpdatedAt       time.Time  `bun:"type:timestamptz,default:current_timestamp,notnull"`
 }
@@ -0,0 +1,34 @@
+package ent
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// TeacherMFAEntity is the TOTP secret of a teacher. It only guards logins
+// once confirmed; LastStep is the time step of the last code accepted, so no
+// code works twice.
+type TeacherMFAEntity struct {
+	bun.BaseModel `bun:"table:teacher_mfa"`
+
+	TeacherID   uuid.UUID  `bun:"type:uuid,pk"`
+	Secret      string     `bun:"type:varchar(64),notnull"`
+	ConfirmedAt *time.Time `bun:"type:timestamptz"`
+	LastStep    int64      `bun:"type:bigint,notnull"`
+	CreatedAt   time.Time  `bun:"type:timestamptz,default:current_timestamp,notnull"`
+	UpdatedAt   time.Time  `bun:"type:timestamptz,default:current_timestamp,notnull"`
+}
+
+// TeacherRecoveryCodeEntity is a one-time code, stored by hash, that stands
+// in for a TOTP code when the teacher has lost their authenticator.
+type TeacherRecoveryCodeEntity struct {
+	bun.BaseModel `bun:"table:teacher_recovery_codes"`
+
+	ID        uuid.UUID  `bun:"type:uuid,default:gen_random_uuid(),pk"`
+	TeacherID uuid.UUID  `bun:"type:uuid,notnull"`
+	CodeHash  string     `bun:"type:char(64),notnull"`
+	UsedAt    *time.Time `bun:"type:timestamptz"`
+	CreatedAt time.Time  `bun:"type:timestamptz,default:current_timestamp,notnull"`
+}
@@ -0,0 +1,25 @@
+package ent
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// TeacherSessionEntity is one login of a teacher. Its ID is the family of the
+// refresh tokens rotated from that login and the sid claim of their access
+// tokens, so revoking it ends both.
+type TeacherSessionEntity struct {
+	bun.BaseModel `bun:"table:teacher_sessions"`
+
+	ID         uuid.UUID  `bun:"type:uuid,default:gen_random_uuid(),pk"`
+	TeacherID  uuid.UUID  `bun:"type:uuid,notnull"`
+	Device     string     `bun:"type:varchar(100)"`
+	IP         string     `bun:"type:varchar(45)"`
+	UserAgent  string     `bun:"type:varchar(512)"`
+	CreatedAt  time.Time  `bun:"type:timestamptz,default:current_timestamp,notnull"`
+	LastSeenAt time.Time  `bun:"type:timestamptz,default:current_timestamp,notnull"`
+	ExpiresAt  time.Time  `bun:"type:timestamptz,notnull"`
+	RevokedAt  *time.Time `bun:"type:timestamptz"`
+}
@@ -0,0 +1,30 @@
+package ent
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Purposes of a TeacherTokenEntity.
+const (
+	TeacherTokenPasswordReset = "password_reset"
+	TeacherTokenEmailVerify   = "email_verify"
+	TeacherTokenMFAChallenge  = "mfa_challenge"
+)
+
+// TeacherTokenEntity is a single-use token mailed to a teacher, or handed out
+// by a login waiting for its TOTP code, stored by hash. A new token of the
+// same purpose replaces any unused one.
+type TeacherTokenEntity struct {
+	bun.BaseModel `bun:"table:teacher_tokens"`
+
+	ID        uuid.UUID  `bun:"type:uuid,default:gen_random_uuid(),pk"`
+	TeacherID uuid.UUID  `bun:"type:uuid,notnull"`
+	Purpose   string     `bun:"type:varchar(32),notnull"`
+	TokenHash string     `bun:"type:char(64),notnull,unique"`
+	ExpiresAt time.Time  `bun:"type:timestamptz,notnull"`
+	UsedAt    *time.Time `bun:"type:timestamptz"`
+	CreatedAt time.Time  `bun:"type:timestamptz,default:current_timestamp,notnull"`
+}
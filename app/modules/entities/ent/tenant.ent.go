@@ -0,0 +1,391 @@
+package ent
+
+import (
+	"context"
+
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/app/utils/tenant"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// The tables below belong to one school each. Their model hooks add the
+// school of the query's context (see package tenant) to every select, update
+// and delete bun builds for them, and refuse inserts and updates that would
+// place a row in another school. bun runs no model hooks for Count, Exists or
+// raw queries, so those apply ScopeSchool themselves.
+
+var (
+	_ bun.BeforeSelectHook = (*SchoolEntity)(nil)
+	_ bun.BeforeSelectHook = (*TeacherEntity)(nil)
+	_ bun.BeforeSelectHook = (*ClassroomEntity)(nil)
+	_ bun.BeforeSelectHook = (*StudentEntity)(nil)
+	_ bun.BeforeSelectHook = (*ClassroomMemberEntity)(nil)
+	_ bun.BeforeSelectHook = (*ClassroomTeacherEntity)(nil)
+	_ bun.BeforeSelectHook = (*ClassroomDelegationEntity)(nil)
+	_ bun.BeforeSelectHook = (*AttendanceEntity)(nil)
+	_ bun.BeforeSelectHook = (*APIKeyEntity)(nil)
+	_ bun.BeforeSelectHook = (*SchoolDomainEntity)(nil)
+	_ bun.BeforeSelectHook = (*TeacherInvitationEntity)(nil)
+	_ bun.BeforeSelectHook = (*SchoolJoinCodeEntity)(nil)
+)
+
+type tenantModel interface {
+	schoolScope(schoolID uuid.UUID) func(bun.QueryBuilder) bun.QueryBuilder
+}
+
+// ScopeSchool limits a query on model to the school in ctx. It leaves the
+// query alone for models outside the tenant tables and for unscoped contexts.
+func ScopeSchool(ctx context.Context, model any) func(bun.QueryBuilder) bun.QueryBuilder {
+	schoolID, scoped := tenant.School(ctx)
+	m, ok := model.(tenantModel)
+	if !scoped || !ok {
+		return func(q bun.QueryBuilder) bun.QueryBuilder { return q }
+	}
+	return m.schoolScope(schoolID)
+}
+
+func bySchool(column string, schoolID uuid.UUID) func(bun.QueryBuilder) bun.QueryBuilder {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		return q.Where("?TableAlias.? = ?", bun.Ident(column), schoolID)
+	}
+}
+
+func byClassroomSchool(schoolID uuid.UUID) func(bun.QueryBuilder) bun.QueryBuilder {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		return q.Where("?TableAlias.classroom_id IN (SELECT id FROM classrooms WHERE school_id = ?)", schoolID)
+	}
+}
+
+func (*SchoolEntity) schoolScope(id uuid.UUID) func(bun.QueryBuilder) bun.QueryBuilder {
+	return bySchool("id", id)
+}
+
+func (*TeacherEntity) schoolScope(id uuid.UUID) func(bun.QueryBuilder) bun.QueryBuilder {
+	return bySchool("school_id", id)
+}
+
+func (*ClassroomEntity) schoolScope(id uuid.UUID) func(bun.QueryBuilder) bun.QueryBuilder {
+	return bySchool("school_id", id)
+}
+
+func (*StudentEntity) schoolScope(id uuid.UUID) func(bun.QueryBuilder) bun.QueryBuilder {
+	return bySchool("school_id", id)
+}
+
+func (*APIKeyEntity) schoolScope(id uuid.UUID) func(bun.QueryBuilder) bun.QueryBuilder {
+	return bySchool("school_id", id)
+}
+
+func (*SchoolDomainEntity) schoolScope(id uuid.UUID) func(bun.QueryBuilder) bun.QueryBuilder {
+	return bySchool("school_id", id)
+}
+
+func (*TeacherInvitationEntity) schoolScope(id uuid.UUID) func(bun.QueryBuilder) bun.QueryBuilder {
+	return bySchool("school_id", id)
+}
+
+func (*SchoolJoinCodeEntity) schoolScope(id uuid.UUID) func(bun.QueryBuilder) bun.QueryBuilder {
+	return bySchool("school_id", id)
+}
+
+func (*ClassroomMemberEntity) schoolScope(id uuid.UUID) func(bun.QueryBuilder) bun.QueryBuilder {
+	return byClassroomSchool(id)
+}
+
+func (*ClassroomTeacherEntity) schoolScope(id uuid.UUID) func(bun.QueryBuilder) bun.QueryBuilder {
+	return byClassroomSchool(id)
+}
+
+func (*ClassroomDelegationEntity) schoolScope(id uuid.UUID) func(bun.QueryBuilder) bun.QueryBuilder {
+	return byClassroomSchool(id)
+}
+
+func (*AttendanceEntity) schoolScope(id uuid.UUID) func(bun.QueryBuilder) bun.QueryBuilder {
+	return byClassroomSchool(id)
+}
+
+// rows returns the models a query writes, whether it was given one or a slice.
+func rows[T any](model bun.Query) []*T {
+	switch v := model.GetModel().Value().(type) {
+	case *T:
+		if v == nil {
+			return nil
+		}
+		return []*T{v}
+	case *[]*T:
+		return *v
+	case *[]T:
+		out := make([]*T, len(*v))
+		for i := range *v {
+			out[i] = &(*v)[i]
+		}
+		return out
+	}
+	return nil
+}
+
+// checkSchool refuses writes of rows into a school other than the one in
+// ctx. A zero school is left to the database (an update through Set).
+func checkSchool(ctx context.Context, resource string, schoolIDs ...uuid.UUID) error {
+	schoolID, scoped := tenant.School(ctx)
+	if !scoped {
+		return nil
+	}
+	for _, id := range schoolIDs {
+		if id != uuid.Nil && id != schoolID {
+			return base.ReferenceError{Resource: resource, Reference: "school"}
+		}
+	}
+	return nil
+}
+
+// checkClassrooms refuses writes of rows into classrooms outside the school
+// in ctx, which is how members and attendances belong to a school.
+func checkClassrooms(ctx context.Context, db *bun.DB, resource string, classroomIDs ...uuid.UUID) error {
+	if _, scoped := tenant.School(ctx); !scoped {
+		return nil
+	}
+	ids := make(map[uuid.UUID]struct{}, len(classroomIDs))
+	for _, id := range classroomIDs {
+		if id != uuid.Nil {
+			ids[id] = struct{}{}
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	list := make([]uuid.UUID, 0, len(ids))
+	for id := range ids {
+		list = append(list, id)
+	}
+	n, err := db.NewSelect().
+		Model((*ClassroomEntity)(nil)).
+		Where("?TableAlias.id IN (?)", bun.In(list)).
+		ApplyQueryBuilder(ScopeSchool(ctx, (*ClassroomEntity)(nil))).
+		Count(ctx)
+	if err != nil {
+		return err
+	}
+	if n != len(list) {
+		return base.ReferenceError{Resource: resource, Reference: "classroom"}
+	}
+	return nil
+}
+
+func idsOf[T any](model bun.Query, id func(*T) uuid.UUID) []uuid.UUID {
+	var ids []uuid.UUID
+	for _, row := range rows[T](model) {
+		ids = append(ids, id(row))
+	}
+	return ids
+}
+
+func (m *SchoolEntity) BeforeSelect(ctx context.Context, q *bun.SelectQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
+
+func (m *SchoolEntity) BeforeUpdate(ctx context.Context, q *bun.UpdateQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
+
+func (m *SchoolEntity) BeforeDelete(ctx context.Context, q *bun.DeleteQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
+
+func (m *TeacherEntity) BeforeSelect(ctx context.Context, q *bun.SelectQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
+
+func (m *TeacherEntity) BeforeInsert(ctx context.Context, q *bun.InsertQuery) error {
+	return checkSchool(ctx, "teacher", idsOf(q, func(t *TeacherEntity) uuid.UUID { return t.SchoolID })...)
+}
+
+func (m *TeacherEntity) BeforeUpdate(ctx context.Context, q *bun.UpdateQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return checkSchool(ctx, "teacher", idsOf(q, func(t *TeacherEntity) uuid.UUID { return t.SchoolID })...)
+}
+
+func (m *TeacherEntity) BeforeDelete(ctx context.Context, q *bun.DeleteQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
+
+func (m *ClassroomEntity) BeforeSelect(ctx context.Context, q *bun.SelectQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
+
+func (m *ClassroomEntity) BeforeInsert(ctx context.Context, q *bun.InsertQuery) error {
+	return checkSchool(ctx, "classroom", idsOf(q, func(c *ClassroomEntity) uuid.UUID { return c.SchoolID })...)
+}
+
+func (m *ClassroomEntity) BeforeUpdate(ctx context.Context, q *bun.UpdateQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return checkSchool(ctx, "classroom", idsOf(q, func(c *ClassroomEntity) uuid.UUID { return c.SchoolID })...)
+}
+
+func (m *ClassroomEntity) BeforeDelete(ctx context.Context, q *bun.DeleteQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
+
+func (m *StudentEntity) BeforeSelect(ctx context.Context, q *bun.SelectQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
+
+func (m *StudentEntity) BeforeInsert(ctx context.Context, q *bun.InsertQuery) error {
+	return checkSchool(ctx, "student", idsOf(q, func(s *StudentEntity) uuid.UUID { return s.SchoolID })...)
+}
+
+func (m *StudentEntity) BeforeUpdate(ctx context.Context, q *bun.UpdateQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return checkSchool(ctx, "student", idsOf(q, func(s *StudentEntity) uuid.UUID { return s.SchoolID })...)
+}
+
+func (m *StudentEntity) BeforeDelete(ctx context.Context, q *bun.DeleteQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
+
+func (m *ClassroomMemberEntity) BeforeSelect(ctx context.Context, q *bun.SelectQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
+
+func (m *ClassroomMemberEntity) BeforeInsert(ctx context.Context, q *bun.InsertQuery) error {
+	return checkClassrooms(ctx, q.DB(), "classroom_member", idsOf(q, func(c *ClassroomMemberEntity) uuid.UUID { return c.ClassroomID })...)
+}
+
+func (m *ClassroomMemberEntity) BeforeUpdate(ctx context.Context, q *bun.UpdateQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return checkClassrooms(ctx, q.DB(), "classroom_member", idsOf(q, func(c *ClassroomMemberEntity) uuid.UUID { return c.ClassroomID })...)
+}
+
+func (m *ClassroomMemberEntity) BeforeDelete(ctx context.Context, q *bun.DeleteQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
+
+func (m *ClassroomTeacherEntity) BeforeSelect(ctx context.Context, q *bun.SelectQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
+
+func (m *ClassroomTeacherEntity) BeforeInsert(ctx context.Context, q *bun.InsertQuery) error {
+	return checkClassrooms(ctx, q.DB(), "classroom_teacher", idsOf(q, func(c *ClassroomTeacherEntity) uuid.UUID { return c.ClassroomID })...)
+}
+
+func (m *ClassroomTeacherEntity) BeforeUpdate(ctx context.Context, q *bun.UpdateQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return checkClassrooms(ctx, q.DB(), "classroom_teacher", idsOf(q, func(c *ClassroomTeacherEntity) uuid.UUID { return c.ClassroomID })...)
+}
+
+func (m *ClassroomTeacherEntity) BeforeDelete(ctx context.Context, q *bun.DeleteQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
+
+func (m *ClassroomDelegationEntity) BeforeSelect(ctx context.Context, q *bun.SelectQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
+
+func (m *ClassroomDelegationEntity) BeforeInsert(ctx context.Context, q *bun.InsertQuery) error {
+	return checkClassrooms(ctx, q.DB(), "classroom_delegation", idsOf(q, func(d *ClassroomDelegationEntity) uuid.UUID { return d.ClassroomID })...)
+}
+
+func (m *ClassroomDelegationEntity) BeforeDelete(ctx context.Context, q *bun.DeleteQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
+
+func (m *AttendanceEntity) BeforeSelect(ctx context.Context, q *bun.SelectQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
+
+func (m *AttendanceEntity) BeforeInsert(ctx context.Context, q *bun.InsertQuery) error {
+	return checkClassrooms(ctx, q.DB(), "attendance", idsOf(q, func(a *AttendanceEntity) uuid.UUID { return a.ClassroomID })...)
+}
+
+func (m *AttendanceEntity) BeforeUpdate(ctx context.Context, q *bun.UpdateQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return checkClassrooms(ctx, q.DB(), "attendance", idsOf(q, func(a *AttendanceEntity) uuid.UUID { return a.ClassroomID })...)
+}
+
+func (m *AttendanceEntity) BeforeDelete(ctx context.Context, q *bun.DeleteQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
+
+func (m *APIKeyEntity) BeforeSelect(ctx context.Context, q *bun.SelectQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
+
+func (m *APIKeyEntity) BeforeInsert(ctx context.Context, q *bun.InsertQuery) error {
+	return checkSchool(ctx, "api_key", idsOf(q, func(k *APIKeyEntity) uuid.UUID { return k.SchoolID })...)
+}
+
+func (m *APIKeyEntity) BeforeUpdate(ctx context.Context, q *bun.UpdateQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return checkSchool(ctx, "api_key", idsOf(q, func(k *APIKeyEntity) uuid.UUID { return k.SchoolID })...)
+}
+
+func (m *APIKeyEntity) BeforeDelete(ctx context.Context, q *bun.DeleteQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
+
+func (m *SchoolDomainEntity) BeforeSelect(ctx context.Context, q *bun.SelectQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
+
+func (m *SchoolDomainEntity) BeforeInsert(ctx context.Context, q *bun.InsertQuery) error {
+	return checkSchool(ctx, "school_domain", idsOf(q, func(d *SchoolDomainEntity) uuid.UUID { return d.SchoolID })...)
+}
+
+func (m *SchoolDomainEntity) BeforeDelete(ctx context.Context, q *bun.DeleteQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
+
+func (m *TeacherInvitationEntity) BeforeSelect(ctx context.Context, q *bun.SelectQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
+
+func (m *TeacherInvitationEntity) BeforeInsert(ctx context.Context, q *bun.InsertQuery) error {
+	return checkSchool(ctx, "teacher_invitation", idsOf(q, func(i *TeacherInvitationEntity) uuid.UUID { return i.SchoolID })...)
+}
+
+func (m *TeacherInvitationEntity) BeforeUpdate(ctx context.Context, q *bun.UpdateQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
+
+func (m *TeacherInvitationEntity) BeforeDelete(ctx context.Context, q *bun.DeleteQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
+
+func (m *SchoolJoinCodeEntity) BeforeSelect(ctx context.Context, q *bun.SelectQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
+
+func (m *SchoolJoinCodeEntity) BeforeInsert(ctx context.Context, q *bun.InsertQuery) error {
+	return checkSchool(ctx, "school_join_code", idsOf(q, func(c *SchoolJoinCodeEntity) uuid.UUID { return c.SchoolID })...)
+}
+
+func (m *SchoolJoinCodeEntity) BeforeDelete(ctx context.Context, q *bun.DeleteQuery) error {
+	q.ApplyQueryBuilder(ScopeSchool(ctx, m))
+	return nil
+}
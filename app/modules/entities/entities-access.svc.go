@@ -0,0 +1,153 @@
+package entities
+
+import (
+	"context"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+	"github.com/google/uuid"
+)
+
+var _ entitiesinf.AccessEntity = (*Service)(nil)
+
+// A teacher who administers their school, holding a role that grants
+// school:write, reaches every row of it. Every other teacher reaches, through
+// classroom_teachers, the classrooms they are assigned to and the students
+// who are members of those classrooms today; being in the same school gives
+// nothing by itself. A full delegation in classroom_delegations reaches the
+// same as an assignment for its dates; one limited to attendance reaches the
+// classroom's attendance only. Delegations and memberships are compared with
+// CURRENT_DATE, so they lapse without anything removing them; a delegation
+// must also cover the date of the attendance it reaches or records. Who teaches in
+// a classroom and who stands in there is managed only by the school's admins
+// and the classroom's homeroom teacher; access through a delegation, or as
+// another teacher of the classroom, does not extend to it.
+
+// schoolAdmin is the condition that teacher t administers their school.
+const schoolAdmin = `EXISTS (
+	SELECT 1 FROM teacher_roles tr JOIN roles r ON r.id = tr.role_id
+	WHERE tr.teacher_id = t.id AND r.permissions && '{*,school:*,school:write}'::text[]
+)`
+
+// delegated is the condition that teacher t stands in for someone in the
+// classroom today, with the given scope or any when it is empty. For
+// attendance, day is the date of the record, which the delegation must cover
+// too; it is empty for everything else.
+func delegated(classroom, scope, day string) string {
+	cond := "d.classroom_id = " + classroom + " AND d.delegate_id = t.id AND CURRENT_DATE BETWEEN d.starts_on AND d.ends_on"
+	if day != "" {
+		cond += " AND " + day + " BETWEEN d.starts_on AND d.ends_on"
+	}
+	if scope != "" {
+		cond += " AND d.scope = '" + scope + "'"
+	}
+	return "EXISTS (SELECT 1 FROM classroom_delegations d WHERE " + cond + ")"
+}
+
+// assigned is the condition that teacher t teaches in the classroom: they
+// are assigned to it, or stand in there today, and on day, with the given
+// scope.
+func assigned(classroom, scope, day string) string {
+	return "(EXISTS (SELECT 1 FROM classroom_teachers ct WHERE ct.classroom_id = " + classroom + " AND ct.teacher_id = t.id) OR " + delegated(classroom, scope, day) + ")"
+}
+
+// reaches is the condition that teacher t reaches the classroom, of the
+// given school: they administer the school or teach in the classroom. It is
+// the one access rule, shared with the classroom list.
+func reaches(classroom, school, scope, day string) string {
+	return "((" + school + " = t.school_id AND " + schoolAdmin + ") OR " + assigned(classroom, scope, day) + ")"
+}
+
+// markingDay is the day attendance is marked for, given as YYYY-MM-DD or
+// empty for today.
+const markingDay = "COALESCE(NULLIF(?, '')::date, CURRENT_DATE)"
+
+func (s *Service) exists(ctx context.Context, query string, args ...any) (bool, error) {
+	var ok bool
+	err := s.db.NewRaw("SELECT EXISTS ("+query+")", args...).Scan(ctx, &ok)
+	return ok, err
+}
+
+func (s *Service) TeacherCanAccessSchool(ctx context.Context, teacherID, schoolID uuid.UUID) (bool, error) {
+	return s.exists(ctx, `
+		SELECT 1 FROM teachers t
+		WHERE t.id = ? AND t.school_id = ?`,
+		teacherID, schoolID)
+}
+
+func (s *Service) TeacherCanAccessTeacher(ctx context.Context, teacherID, otherID uuid.UUID) (bool, error) {
+	return s.exists(ctx, `
+		SELECT 1 FROM teachers t
+		JOIN teachers o ON o.school_id = t.school_id
+		WHERE t.id = ? AND o.id = ?`,
+		teacherID, otherID)
+}
+
+func (s *Service) TeacherCanAccessClassroom(ctx context.Context, teacherID, classroomID uuid.UUID) (bool, error) {
+	return s.exists(ctx, `
+		SELECT 1 FROM classrooms c
+		JOIN teachers t ON t.id = ?
+		WHERE c.id = ? AND `+reaches("c.id", "c.school_id", ent.DelegationScopeFull, ""),
+		teacherID, classroomID)
+}
+
+// TeacherCanManageClassroom reports whether the teacher may assign teachers
+// to the classroom and delegate it: they administer its school or are its
+// homeroom teacher.
+func (s *Service) TeacherCanManageClassroom(ctx context.Context, teacherID, classroomID uuid.UUID) (bool, error) {
+	return s.exists(ctx, `
+		SELECT 1 FROM classrooms c
+		JOIN teachers t ON t.id = ?
+		WHERE c.id = ? AND (
+			(c.school_id = t.school_id AND `+schoolAdmin+`)
+			OR EXISTS (SELECT 1 FROM classroom_teachers ct WHERE ct.classroom_id = c.id AND ct.teacher_id = t.id AND ct.role = ?)
+		)`,
+		teacherID, classroomID, ent.ClassroomRoleHomeroom)
+}
+
+func (s *Service) TeacherCanAccessClassroomMember(ctx context.Context, teacherID, memberID uuid.UUID) (bool, error) {
+	return s.exists(ctx, `
+		SELECT 1 FROM classroom_members cm
+		JOIN classrooms c ON c.id = cm.classroom_id
+		JOIN teachers t ON t.id = ?
+		WHERE cm.id = ? AND `+reaches("c.id", "c.school_id", ent.DelegationScopeFull, ""),
+		teacherID, memberID)
+}
+
+func (s *Service) TeacherCanAccessStudent(ctx context.Context, teacherID, studentID uuid.UUID) (bool, error) {
+	return s.exists(ctx, `
+		SELECT 1 FROM students st
+		JOIN teachers t ON t.id = ?
+		WHERE st.id = ? AND (
+			(st.school_id = t.school_id AND `+schoolAdmin+`)
+			OR EXISTS (
+				SELECT 1 FROM classroom_members cm
+				WHERE cm.student_id = st.id AND `+assigned("cm.classroom_id", ent.DelegationScopeFull, "")+`
+					AND CURRENT_DATE BETWEEN cm.effective_from AND COALESCE(cm.effective_to, 'infinity')
+			)
+		)`,
+		teacherID, studentID)
+}
+
+// TeacherCanAccessAttendance reports whether the teacher reaches the record:
+// they can access its classroom, or stand in there with any scope on the
+// record's date.
+func (s *Service) TeacherCanAccessAttendance(ctx context.Context, teacherID, attendanceID uuid.UUID) (bool, error) {
+	return s.exists(ctx, `
+		SELECT 1 FROM attendances a
+		JOIN classrooms c ON c.id = a.classroom_id
+		JOIN teachers t ON t.id = ?
+		WHERE a.id = ? AND `+reaches("c.id", "c.school_id", "", "a.date"),
+		teacherID, attendanceID)
+}
+
+// TeacherCanMarkAttendance reports whether the teacher may record attendance
+// in the classroom for date, today when it is empty: they can access it, or
+// stand in there with any scope on that date.
+func (s *Service) TeacherCanMarkAttendance(ctx context.Context, teacherID, classroomID uuid.UUID, date string) (bool, error) {
+	return s.exists(ctx, `
+		SELECT 1 FROM classrooms c
+		JOIN teachers t ON t.id = ?
+		WHERE c.id = ? AND `+reaches("c.id", "c.school_id", "", markingDay),
+		teacherID, classroomID, date)
+}
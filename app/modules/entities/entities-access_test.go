@@ -0,0 +1,147 @@
+package entities
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/google/uuid"
+)
+
+// Test_AccessRules follows each access check to the SQL it sends, since the
+// rule lives there: a teacher's school reaches nothing unless they administer
+// it, a delegation counts only between its dates and, for attendance, on the
+// record's date, only a full one reaches more than the classroom's
+// attendance, and none lets the delegate manage who teaches in the classroom.
+func Test_AccessRules(t *testing.T) {
+	teacher, id := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name      string
+		check     func(context.Context, *Service) (bool, error)
+		fullScope bool // an attendance delegation is refused
+		manage    bool   // only the school's admins and the homeroom teacher pass
+		day       string // the attendance date delegations must cover besides today
+	}{
+		{
+			name: "Test classroom refuses attendance delegate",
+			check: func(ctx context.Context, s *Service) (bool, error) {
+				return s.TeacherCanAccessClassroom(ctx, teacher, id)
+			},
+			fullScope: true,
+		},
+		{
+			name: "Test managing classroom refuses delegate and other teachers",
+			check: func(ctx context.Context, s *Service) (bool, error) {
+				return s.TeacherCanManageClassroom(ctx, teacher, id)
+			},
+			manage: true,
+		},
+		{
+			name: "Test classroom member refuses attendance delegate",
+			check: func(ctx context.Context, s *Service) (bool, error) {
+				return s.TeacherCanAccessClassroomMember(ctx, teacher, id)
+			},
+			fullScope: true,
+		},
+		{
+			name: "Test student refuses attendance delegate",
+			check: func(ctx context.Context, s *Service) (bool, error) {
+				return s.TeacherCanAccessStudent(ctx, teacher, id)
+			},
+			fullScope: true,
+		},
+		{
+			name: "Test attendance admits attendance delegate",
+			check: func(ctx context.Context, s *Service) (bool, error) {
+				return s.TeacherCanAccessAttendance(ctx, teacher, id)
+			},
+			day: "a.date",
+		},
+		{
+			name: "Test marking admits attendance delegate",
+			check: func(ctx context.Context, s *Service) (bool, error) {
+				return s.TeacherCanMarkAttendance(ctx, teacher, id, "2026-11-01")
+			},
+			day: "COALESCE(NULLIF('2026-11-01', '')::date, CURRENT_DATE)",
+		},
+		{
+			name: "Test marking today",
+			check: func(ctx context.Context, s *Service) (bool, error) {
+				return s.TeacherCanMarkAttendance(ctx, teacher, id, "")
+			},
+			day: "COALESCE(NULLIF('', '')::date, CURRENT_DATE)",
+		},
+		{
+			name: "Test classroom list",
+			check: func(ctx context.Context, s *Service) (bool, error) {
+				_, err := s.GetClassroomsByTeacherID(ctx, teacher)
+				return true, err
+			},
+			fullScope: true,
+		},
+		{
+			name: "Test attendance list",
+			check: func(ctx context.Context, s *Service) (bool, error) {
+				_, err := s.GetAttendanceByTeacherID(ctx, teacher)
+				return true, err
+			},
+			day: `"attendance_entity".date`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, rec := newRecorderService(t)
+			if _, err := tt.check(context.Background(), s); err != nil {
+				t.Fatal(err)
+			}
+			queries := rec.take()
+			if len(queries) != 1 {
+				t.Fatalf("got %d queries, want 1", len(queries))
+			}
+			query := queries[0]
+
+			if n := strings.Count(query, "school_id = t.school_id"); n != strings.Count(query, "school_id = t.school_id AND "+schoolAdmin) {
+				t.Errorf("the teacher's school reaches without them administering it:\n%s", query)
+			}
+			if !strings.Contains(query, "FROM classroom_teachers ct") {
+				t.Errorf("classroom_teachers is not consulted:\n%s", query)
+			}
+			if tt.manage {
+				if strings.Contains(query, "classroom_delegations") {
+					t.Errorf("a delegate manages the classroom:\n%s", query)
+				}
+				if !strings.Contains(query, "ct.role = '"+ent.ClassroomRoleHomeroom+"'") {
+					t.Errorf("any teacher of the classroom manages it:\n%s", query)
+				}
+				return
+			}
+
+			// An expired delegation is refused by every check
+			delegations := strings.Count(query, "FROM classroom_delegations d")
+			if delegations == 0 {
+				t.Fatalf("delegations are not consulted:\n%s", query)
+			}
+			if n := strings.Count(query, "CURRENT_DATE BETWEEN d.starts_on AND d.ends_on"); n != delegations {
+				t.Errorf("%d of %d delegations are not limited to their dates:\n%s", delegations-n, delegations, query)
+			}
+			// Attendance is reached only on the days the delegation covers
+			days := strings.Count(query, "BETWEEN d.starts_on AND d.ends_on") - delegations
+			if tt.day == "" && days != 0 {
+				t.Errorf("delegations are limited by another day:\n%s", query)
+			}
+			if n := strings.Count(query, tt.day+" BETWEEN d.starts_on AND d.ends_on"); tt.day != "" && (n != delegations || days != delegations) {
+				t.Errorf("%d of %d delegations are not limited to %s:\n%s", delegations-n, delegations, tt.day, query)
+			}
+
+			full := strings.Count(query, "d.scope = 'full'")
+			if tt.fullScope && full != delegations {
+				t.Errorf("%d of %d delegations admit any scope:\n%s", delegations-full, delegations, query)
+			}
+			if !tt.fullScope && (full != 0 || strings.Contains(query, "d.scope =")) {
+				t.Errorf("delegations are limited by scope:\n%s", query)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package entities
+
+import (
+	"context"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/google/uuid"
+)
+
+var _ entitiesinf.APIKeyEntity = (*Service)(nil)
+
+// apiKeyTouchInterval is how stale last_used_at may get before a request
+// writes it again, so a busy scanner does not update its row every call.
+const apiKeyTouchInterval = time.Minute
+
+func (s *Service) CreateAPIKey(ctx context.Context, key *ent.APIKeyEntity) error {
+	key.ID = uuid.New()
+	key.CreatedAt = time.Now()
+	key.UpdatedAt = key.CreatedAt
+	if _, err := s.db.NewInsert().Model(key).Exec(ctx); err != nil {
+		return translateError(err, "api_key", nil)
+	}
+	return nil
+}
+
+// GetListAPIKey lists the keys of the caller's school, newest first,
+// including revoked ones.
+func (s *Service) GetListAPIKey(ctx context.Context) ([]*ent.APIKeyEntity, error) {
+	var keys []*ent.APIKeyEntity
+	err := s.db.NewSelect().Model(&keys).Order("created_at DESC").Scan(ctx)
+	if err != nil {
+		return nil, translateError(err, "api_key", nil)
+	}
+	return keys, nil
+}
+
+func (s *Service) GetAPIKeyByID(ctx context.Context, id uuid.UUID) (*ent.APIKeyEntity, error) {
+	var key ent.APIKeyEntity
+	err := s.db.NewSelect().Model(&key).Where("id = ?", id).Scan(ctx)
+	if err != nil {
+		return nil, translateError(err, "api_key", id)
+	}
+	return &key, nil
+}
+
+// GetActiveAPIKeyByHash returns the unrevoked, unexpired key with hash. Any
+// other key is reported as not found.
+func (s *Service) GetActiveAPIKeyByHash(ctx context.Context, hash string) (*ent.APIKeyEntity, error) {
+	var key ent.APIKeyEntity
+	err := s.db.NewSelect().
+		Model(&key).
+		Where("key_hash = ?", hash).
+		Where("revoked_at IS NULL").
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Scan(ctx)
+	if err != nil {
+		return nil, translateError(err, "api_key", nil)
+	}
+	return &key, nil
+}
+
+// UpdateAPIKey saves the name, scopes and expiry of an unrevoked key.
+func (s *Service) UpdateAPIKey(ctx context.Context, key *ent.APIKeyEntity) error {
+	key.UpdatedAt = time.Now()
+	res, err := s.db.NewUpdate().
+		Model(key).
+		Column("name", "scopes", "expires_at", "updated_at").
+		Where("id = ?", key.ID).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return translateError(err, "api_key", key.ID)
+	}
+	return s.checkWritten(ctx, res, (*ent.APIKeyEntity)(nil), "api_key", key.ID, nil)
+}
+
+func (s *Service) RevokeAPIKey(ctx context.Context, id uuid.UUID) error {
+	now := time.Now()
+	res, err := s.db.NewUpdate().
+		Model((*ent.APIKeyEntity)(nil)).
+		Set("revoked_at = ?", now).
+		Set("updated_at = ?", now).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return translateError(err, "api_key", id)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		if err == nil {
+			err = base.NotFoundError{Resource: "api_key", ID: id.String()}
+		}
+		return err
+	}
+	return nil
+}
+
+// TouchAPIKey records that the key was just used.
+func (s *Service) TouchAPIKey(ctx context.Context, id uuid.UUID) error {
+	now := time.Now()
+	_, err := s.db.NewUpdate().
+		Model((*ent.APIKeyEntity)(nil)).
+		Set("last_used_at = ?", now).
+		Where("id = ?", id).
+		Where("last_used_at IS NULL OR last_used_at < ?", now.Add(-apiKeyTouchInterval)).
+		Exec(ctx)
+	return err
+}
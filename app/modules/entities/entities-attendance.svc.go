@@ -7,6 +7,7 @@ import (
 	entitiesdto "github.com/easy-attend-serviceV3/app/modules/entities/dto"
 	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
 	"github.com/google/uuid"
+	"github.com/uptrace/bun"
 )
 
 // CreateAttendance creates a new attendance record
@@ -22,13 +23,16 @@ func (s *Service) CreateAttendance(ctx context.Context, req *entitiesdto.Attenda
 		Date:        req.Date,
 		Time:        req.Time,
 		Status:      req.Status,
+		Session:     req.Session,
+		MarkedBy:    req.MarkedBy,
+		OnBehalfOf:  req.OnBehalfOf,
 	}
 	attendance.CreatedAt = time.Now()
 	attendance.UpdatedAt = time.Now()
 
 	_, err := s.db.NewInsert().Model(attendance).Exec(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "attendance", nil)
 	}
 	return attendance, nil
 }
@@ -44,7 +48,7 @@ func (s *Service) GetListAttendance(ctx context.Context, classroomID uuid.UUID,
 
 	err := query.Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "attendance", nil)
 	}
 	return attendances, nil
 }
@@ -57,20 +61,26 @@ func (s *Service) GetAllAttendance(ctx context.Context, limit int) ([]*ent.Atten
 		Limit(limit).
 		Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "attendance", nil)
 	}
 	return attendances, nil
 }
 
-// GetAttendanceByTeacherID retrieves all attendance records for a specific teacher
+// GetAttendanceByTeacherID retrieves all attendance records for a specific
+// teacher, including those a substitute recorded on their behalf, in the
+// classrooms the teacher still reaches, on the days a delegation covers when
+// it is what reaches them
 func (s *Service) GetAttendanceByTeacherID(ctx context.Context, teacherID uuid.UUID) ([]*ent.AttendanceEntity, error) {
 	var attendances []*ent.AttendanceEntity
 	err := s.db.NewSelect().
 		Model(&attendances).
-		Where("teacher_id = ?", teacherID).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("teacher_id = ?", teacherID).WhereOr("on_behalf_of = ?", teacherID)
+		}).
+		Where("EXISTS (SELECT 1 FROM classrooms c JOIN teachers t ON t.id = ? WHERE c.id = ?TableAlias.classroom_id AND "+reaches("c.id", "c.school_id", "", "?TableAlias.date")+")", teacherID).
 		Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "attendance", nil)
 	}
 	return attendances, nil
 }
@@ -85,14 +95,14 @@ func (s *Service) GetAttendanceByID(ctx context.Context, id uuid.UUID) (*ent.Att
 		Scan(ctx)
 
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "attendance", id)
 	}
 
 	return &attendance, nil
 }
 
 // UpdateAttendance updates an attendance record
-func (s *Service) UpdateAttendance(ctx context.Context, id uuid.UUID, req *entitiesdto.AttendanceUpdateRequest) (*ent.AttendanceEntity, error) {
+func (s *Service) UpdateAttendance(ctx context.Context, id uuid.UUID, req *entitiesdto.AttendanceUpdateRequest, version *time.Time) (*ent.AttendanceEntity, error) {
 	attendance := &ent.AttendanceEntity{
 		ID:          id,
 		ClassroomID: req.ClassroomID,
@@ -101,16 +111,22 @@ func (s *Service) UpdateAttendance(ctx context.Context, id uuid.UUID, req *entit
 		Date:        req.Date,
 		Time:        req.Time,
 		Status:      req.Status,
+		Session:     req.Session,
+		MarkedBy:    req.MarkedBy,
+		OnBehalfOf:  req.OnBehalfOf,
 	}
 	attendance.UpdatedAt = time.Now()
 
-	_, err := s.db.NewUpdate().
+	res, err := s.db.NewUpdate().
 		Model(attendance).
-		Column("classroom_id", "teacher_id", "student_id", "date", "time", "status", "updated_at").
+		Column("classroom_id", "teacher_id", "student_id", "date", "time", "status", "session", "marked_by", "on_behalf_of", "updated_at").
 		Where("id = ?", id).
+		ApplyQueryBuilder(atVersion(version)).
 		Exec(ctx)
-
 	if err != nil {
+		return nil, translateError(err, "attendance", id)
+	}
+	if err := s.checkWritten(ctx, res, (*ent.AttendanceEntity)(nil), "attendance", id, version); err != nil {
 		return nil, err
 	}
 
@@ -118,13 +134,17 @@ func (s *Service) UpdateAttendance(ctx context.Context, id uuid.UUID, req *entit
 }
 
 // DeleteAttendance deletes an attendance record
-func (s *Service) DeleteAttendance(ctx context.Context, id uuid.UUID) error {
-	_, err := s.db.NewDelete().
+func (s *Service) DeleteAttendance(ctx context.Context, id uuid.UUID, version *time.Time) error {
+	res, err := s.db.NewDelete().
 		Model((*ent.AttendanceEntity)(nil)).
 		Where("id = ?", id).
+		ApplyQueryBuilder(atVersion(version)).
 		Exec(ctx)
+	if err != nil {
+		return translateDeleteError(err, "attendance", id)
+	}
 
-	return err
+	return s.checkWritten(ctx, res, (*ent.AttendanceEntity)(nil), "attendance", id, version)
 }
 
 // CheckExistAttendance checks if an attendance record exists
@@ -132,10 +152,11 @@ func (s *Service) CheckExistAttendance(ctx context.Context, id uuid.UUID) (bool,
 	count, err := s.db.NewSelect().
 		Model((*ent.AttendanceEntity)(nil)).
 		Where("id = ?", id).
+		ApplyQueryBuilder(ent.ScopeSchool(ctx, (*ent.AttendanceEntity)(nil))).
 		Count(ctx)
 
 	if err != nil {
-		return false, err
+		return false, translateError(err, "attendance", id)
 	}
 	return count > 0, nil
 }
@@ -151,7 +172,7 @@ func (s *Service) GetAttendanceByStudentID(ctx context.Context, studentID uuid.U
 
 	err := query.Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "attendance", nil)
 	}
 
 	return &attendance, nil
@@ -165,7 +186,7 @@ func (s *Service) GetAttendanceByClassroomAndDate(ctx context.Context, classroom
 		Where("classroom_id = ? AND date = ?", classroomID, date).
 		Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "attendance", nil)
 	}
 	return attendances, nil
 }
@@ -0,0 +1,75 @@
+package entities
+
+import (
+	"context"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/google/uuid"
+)
+
+var _ entitiesinf.ClassroomDelegationEntity = (*Service)(nil)
+
+// GetListClassroomDelegation returns the classroom's delegations, lapsed ones
+// included, the latest first.
+func (s *Service) GetListClassroomDelegation(ctx context.Context, classroomID uuid.UUID) ([]*ent.ClassroomDelegationEntity, error) {
+	var delegations []*ent.ClassroomDelegationEntity
+	err := s.db.NewSelect().
+		Model(&delegations).
+		Where("classroom_id = ?", classroomID).
+		OrderExpr("starts_on DESC, created_at DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, translateError(err, "classroom_delegation", nil)
+	}
+	return delegations, nil
+}
+
+// GetActiveClassroomDelegation returns the delegation that lets delegateID
+// stand in for a teacher of the classroom today and on date.
+func (s *Service) GetActiveClassroomDelegation(ctx context.Context, classroomID, delegateID uuid.UUID, date string) (*ent.ClassroomDelegationEntity, error) {
+	var d ent.ClassroomDelegationEntity
+	err := s.db.NewSelect().
+		Model(&d).
+		Where("classroom_id = ?", classroomID).
+		Where("delegate_id = ?", delegateID).
+		Where("CURRENT_DATE BETWEEN starts_on AND ends_on").
+		Where("?::date BETWEEN starts_on AND ends_on", date).
+		OrderExpr("created_at DESC").
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		return nil, translateError(err, "classroom_delegation", nil)
+	}
+	return &d, nil
+}
+
+func (s *Service) CreateClassroomDelegation(ctx context.Context, d *ent.ClassroomDelegationEntity) error {
+	d.ID = uuid.New()
+	d.CreatedAt = time.Now()
+	if _, err := s.db.NewInsert().Model(d).Exec(ctx); err != nil {
+		return translateError(err, "classroom_delegation", nil)
+	}
+	return nil
+}
+
+// DeleteClassroomDelegation revokes a delegation before it lapses.
+func (s *Service) DeleteClassroomDelegation(ctx context.Context, classroomID, id uuid.UUID) error {
+	res, err := s.db.NewDelete().
+		Model((*ent.ClassroomDelegationEntity)(nil)).
+		Where("id = ?", id).
+		Where("classroom_id = ?", classroomID).
+		Exec(ctx)
+	if err != nil {
+		return translateError(err, "classroom_delegation", id)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		if err == nil {
+			err = base.NotFoundError{Resource: "classroom_delegation", ID: id.String()}
+		}
+		return err
+	}
+	return nil
+}
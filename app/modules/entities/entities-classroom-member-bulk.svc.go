@@ -0,0 +1,278 @@
+package entities
+
+import (
+	"context"
+	"time"
+
+	entitiesdto "github.com/easy-attend-serviceV3/app/modules/entities/dto"
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// The bulk operations below each run in one transaction. They first resolve
+// every student they are given; if any cannot be, they write nothing and
+// return the results with the invalid items marked, so the caller can tell
+// which to correct. A student already in the state asked for is skipped.
+
+// bulkStudent is a student named by a bulk operation.
+type bulkStudent struct {
+	ID          uuid.UUID `bun:"id"`
+	StudentCode string    `bun:"student_code"`
+	SeatNo      *int      `bun:"seat_no"`
+}
+
+// resolveBulkStudents finds the students req names among those of the
+// classroom's school and returns a result for each, in the order of the
+// request, and whether every one of them is valid.
+func resolveBulkStudents(ctx context.Context, tx bun.Tx, req *entitiesdto.ClassroomMemberBulkRequest) ([]*entitiesdto.ClassroomMemberBulkResult, bool, error) {
+	var found []bulkStudent
+	if len(req.StudentIDs) > 0 || len(req.StudentCodes) > 0 {
+		err := tx.NewSelect().
+			Model((*ent.StudentEntity)(nil)).
+			Column("id", "student_code").
+			Where("?TableAlias.school_id = (SELECT school_id FROM classrooms WHERE id = ?)", req.ClassroomID).
+			WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+				if len(req.StudentIDs) > 0 {
+					q = q.WhereOr("?TableAlias.id IN (?)", bun.In(req.StudentIDs))
+				}
+				if len(req.StudentCodes) > 0 {
+					q = q.WhereOr("?TableAlias.student_code IN (?)", bun.In(req.StudentCodes))
+				}
+				return q
+			}).
+			Scan(ctx, &found)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	byID := make(map[uuid.UUID]bulkStudent, len(found))
+	byCode := make(map[string][]bulkStudent, len(found))
+	for _, st := range found {
+		byID[st.ID] = st
+		byCode[st.StudentCode] = append(byCode[st.StudentCode], st)
+	}
+
+	results := make([]*entitiesdto.ClassroomMemberBulkResult, 0, len(req.StudentIDs)+len(req.StudentCodes))
+	seen := make(map[uuid.UUID]bool, len(found))
+	name := func(r *entitiesdto.ClassroomMemberBulkResult, st bulkStudent) {
+		r.StudentID, r.StudentCode = &st.ID, st.StudentCode
+		if seen[st.ID] {
+			r.Status = entitiesdto.BulkDuplicate
+		}
+		seen[st.ID] = true
+	}
+	for _, id := range req.StudentIDs {
+		r := &entitiesdto.ClassroomMemberBulkResult{StudentID: &id}
+		if st, ok := byID[id]; ok {
+			name(r, st)
+		} else {
+			r.Status = entitiesdto.BulkNotFound
+		}
+		results = append(results, r)
+	}
+	for _, code := range req.StudentCodes {
+		r := &entitiesdto.ClassroomMemberBulkResult{StudentCode: code}
+		switch sts := byCode[code]; len(sts) {
+		case 0:
+			r.Status = entitiesdto.BulkNotFound
+		case 1:
+			name(r, sts[0])
+		default:
+			r.Status = entitiesdto.BulkAmbiguous
+		}
+		results = append(results, r)
+	}
+
+	valid := true
+	for _, r := range results {
+		valid = valid && r.Valid()
+	}
+	return results, valid, nil
+}
+
+// bulkStudentIDs returns the students of results.
+func bulkStudentIDs(results []*entitiesdto.ClassroomMemberBulkResult) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(results))
+	for _, r := range results {
+		ids = append(ids, *r.StudentID)
+	}
+	return ids
+}
+
+// currentMembers returns the memberships in classroomID in effect on date of
+// the students, by student.
+func currentMembers(ctx context.Context, tx bun.Tx, classroomID uuid.UUID, date string, studentIDs []uuid.UUID) (map[uuid.UUID]*ent.ClassroomMemberEntity, error) {
+	var members []*ent.ClassroomMemberEntity
+	err := tx.NewSelect().
+		Model(&members).
+		Where("classroom_id = ?", classroomID).
+		Where("student_id IN (?)", bun.In(studentIDs)).
+		ApplyQueryBuilder(memberOn(date)).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byStudent := make(map[uuid.UUID]*ent.ClassroomMemberEntity, len(members))
+	for _, m := range members {
+		byStudent[m.StudentID] = m
+	}
+	return byStudent, nil
+}
+
+// addMembers makes the students of results members of req.ClassroomID from
+// req.Date, with the seat in seats if any, and skips those who already are.
+func addMembers(ctx context.Context, tx bun.Tx, req *entitiesdto.ClassroomMemberBulkRequest, results []*entitiesdto.ClassroomMemberBulkResult, seats map[uuid.UUID]*int) error {
+	if len(results) == 0 {
+		return nil
+	}
+	current, err := currentMembers(ctx, tx, req.ClassroomID, req.Date, bulkStudentIDs(results))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var added []*ent.ClassroomMemberEntity
+	var students []uuid.UUID
+	for _, r := range results {
+		if m, ok := current[*r.StudentID]; ok {
+			r.Status, r.MemberID = entitiesdto.BulkSkipped, &m.ID
+			continue
+		}
+		m := &ent.ClassroomMemberEntity{
+			ID:            uuid.New(),
+			ClassroomID:   req.ClassroomID,
+			StudentID:     *r.StudentID,
+			TeacherID:     req.TeacherID,
+			SeatNo:        seats[*r.StudentID],
+			EffectiveFrom: req.Date,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		r.Status, r.MemberID = entitiesdto.BulkAdded, &m.ID
+		added = append(added, m)
+		students = append(students, m.StudentID)
+	}
+	if len(added) == 0 {
+		return nil
+	}
+	if _, err := tx.NewInsert().Model(&added).Exec(ctx); err != nil {
+		return err
+	}
+	_, err = syncStudentClassrooms(ctx, tx, students...)
+	return err
+}
+
+// BulkAddClassroomMembers makes the students req names members of
+// req.ClassroomID from req.Date on.
+func (s *Service) BulkAddClassroomMembers(ctx context.Context, req *entitiesdto.ClassroomMemberBulkRequest) ([]*entitiesdto.ClassroomMemberBulkResult, error) {
+	var results []*entitiesdto.ClassroomMemberBulkResult
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var valid bool
+		var err error
+		results, valid, err = resolveBulkStudents(ctx, tx, req)
+		if err != nil || !valid {
+			return err
+		}
+		return addMembers(ctx, tx, req, results, nil)
+	})
+	if err != nil {
+		return nil, translateError(err, "classroom_member", nil)
+	}
+	return results, nil
+}
+
+// BulkRemoveClassroomMembers takes the students req names out of
+// req.ClassroomID from req.Date on: their membership ends the day before, or
+// goes if it only starts on req.Date. Students who are not members on
+// req.Date are skipped.
+func (s *Service) BulkRemoveClassroomMembers(ctx context.Context, req *entitiesdto.ClassroomMemberBulkRequest) ([]*entitiesdto.ClassroomMemberBulkResult, error) {
+	var results []*entitiesdto.ClassroomMemberBulkResult
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var valid bool
+		var err error
+		results, valid, err = resolveBulkStudents(ctx, tx, req)
+		if err != nil || !valid || len(results) == 0 {
+			return err
+		}
+		current, err := currentMembers(ctx, tx, req.ClassroomID, req.Date, bulkStudentIDs(results))
+		if err != nil {
+			return err
+		}
+
+		var removed, students []uuid.UUID
+		for _, r := range results {
+			m, ok := current[*r.StudentID]
+			if !ok {
+				r.Status = entitiesdto.BulkSkipped
+				continue
+			}
+			r.Status, r.MemberID = entitiesdto.BulkRemoved, &m.ID
+			removed = append(removed, m.ID)
+			students = append(students, m.StudentID)
+		}
+		if len(removed) == 0 {
+			return nil
+		}
+
+		_, err = tx.NewDelete().
+			Model((*ent.ClassroomMemberEntity)(nil)).
+			Where("id IN (?)", bun.In(removed)).
+			Where("effective_from = "+dateOrToday, req.Date).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = tx.NewUpdate().
+			Model((*ent.ClassroomMemberEntity)(nil)).
+			Set("effective_to = "+dateOrToday+" - 1", req.Date).
+			Set("updated_at = CURRENT_TIMESTAMP").
+			Where("id IN (?)", bun.In(removed)).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = syncStudentClassrooms(ctx, tx, students...)
+		return err
+	})
+	if err != nil {
+		return nil, translateError(err, "classroom_member", nil)
+	}
+	return results, nil
+}
+
+// CopyClassroomMembers makes the members of fromClassroomID on fromDate
+// (today when empty) members of req.ClassroomID from req.Date on, in the same
+// seats, as when a classroom moves up a year for a new term. req names no
+// students; the results are in seat order.
+func (s *Service) CopyClassroomMembers(ctx context.Context, fromClassroomID uuid.UUID, fromDate string, req *entitiesdto.ClassroomMemberBulkRequest) ([]*entitiesdto.ClassroomMemberBulkResult, error) {
+	var results []*entitiesdto.ClassroomMemberBulkResult
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var source []bulkStudent
+		err := tx.NewSelect().
+			Model((*ent.ClassroomMemberEntity)(nil)).
+			ColumnExpr("st.id, st.student_code, ?TableAlias.seat_no").
+			Join("JOIN students AS st ON st.id = ?TableAlias.student_id").
+			Where("?TableAlias.classroom_id = ?", fromClassroomID).
+			ApplyQueryBuilder(memberOn(fromDate)).
+			OrderExpr("?TableAlias.seat_no NULLS LAST, st.student_code").
+			Scan(ctx, &source)
+		if err != nil {
+			return err
+		}
+
+		seats := make(map[uuid.UUID]*int, len(source))
+		for _, st := range source {
+			if _, ok := seats[st.ID]; ok {
+				continue
+			}
+			seats[st.ID] = st.SeatNo
+			results = append(results, &entitiesdto.ClassroomMemberBulkResult{StudentID: &st.ID, StudentCode: st.StudentCode})
+		}
+		return addMembers(ctx, tx, req, results, seats)
+	})
+	if err != nil {
+		return nil, translateError(err, "classroom_member", nil)
+	}
+	return results, nil
+}
@@ -0,0 +1,186 @@
+package entities
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+
+	entitiesdto "github.com/easy-attend-serviceV3/app/modules/entities/dto"
+	"github.com/easy-attend-serviceV3/app/utils/tenant"
+	"github.com/google/uuid"
+)
+
+func Test_BulkClassroomMembers(t *testing.T) {
+	const date = "2026-11-01"
+	classroom, teacher, unknown := uuid.New(), uuid.New(), uuid.New()
+
+	// Students a and b have codes of their own; c1 and c2 share one
+	type student struct {
+		name string
+		id   uuid.UUID
+		code string
+	}
+	students := []student{{"a", uuid.New(), "001"}, {"b", uuid.New(), "002"}, {"c1", uuid.New(), "003"}, {"c2", uuid.New(), "003"}}
+	a, b := students[0].id, students[1].id
+	memberships := map[uuid.UUID]uuid.UUID{}
+	for _, st := range students {
+		memberships[st.id] = uuid.New()
+	}
+
+	// who names the students query is about, by their id or membership
+	who := func(query string) string {
+		var names []string
+		for _, st := range students {
+			if strings.Contains(query, st.id.String()) || strings.Contains(query, memberships[st.id].String()) {
+				names = append(names, st.name)
+			}
+		}
+		return strings.Join(names, ",")
+	}
+	onDate := "COALESCE(NULLIF('" + date + "', '')::date, CURRENT_DATE)"
+	step := func(query string) string {
+		switch {
+		case strings.HasPrefix(query, "SELECT"):
+			return ""
+		case strings.HasPrefix(query, `INSERT INTO "classroom_members"`) && strings.Contains(query, "'"+teacher.String()+"'"):
+			return "add " + who(query)
+		case strings.HasPrefix(query, `DELETE FROM "classroom_members"`) && strings.Contains(query, "effective_from = "+onDate):
+			return "delete " + who(query) + " starting on the date"
+		case strings.HasPrefix(query, `UPDATE "classroom_members"`) && strings.Contains(query, "effective_to = "+onDate+" - 1"):
+			return "close " + who(query) + " the day before"
+		case strings.HasPrefix(query, `UPDATE "students"`):
+			return "sync " + who(query)
+		}
+		return query
+	}
+
+	tests := []struct {
+		name       string
+		remove     bool
+		ids        []uuid.UUID
+		codes      []string
+		members    []uuid.UUID // students who are members on the date
+		want       []string
+		wantWrites []string
+	}{
+		{
+			name:       "Test add by id and code",
+			ids:        []uuid.UUID{a},
+			codes:      []string{"002"},
+			want:       []string{entitiesdto.BulkAdded, entitiesdto.BulkAdded},
+			wantWrites: []string{"add a,b", "sync a,b"},
+		},
+		{
+			name:       "Test add skips members",
+			ids:        []uuid.UUID{a, b},
+			members:    []uuid.UUID{a},
+			want:       []string{entitiesdto.BulkSkipped, entitiesdto.BulkAdded},
+			wantWrites: []string{"add b", "sync b"},
+		},
+		{
+			name:    "Test add only members",
+			ids:     []uuid.UUID{a},
+			members: []uuid.UUID{a},
+			want:    []string{entitiesdto.BulkSkipped},
+		},
+		{
+			name: "Test add unknown id writes nothing",
+			ids:  []uuid.UUID{a, unknown},
+			want: []string{"", entitiesdto.BulkNotFound},
+		},
+		{
+			name:  "Test add unknown code writes nothing",
+			ids:   []uuid.UUID{a},
+			codes: []string{"404"},
+			want:  []string{"", entitiesdto.BulkNotFound},
+		},
+		{
+			name:  "Test add duplicate writes nothing",
+			ids:   []uuid.UUID{a},
+			codes: []string{"001"},
+			want:  []string{"", entitiesdto.BulkDuplicate},
+		},
+		{
+			name:  "Test add ambiguous code writes nothing",
+			ids:   []uuid.UUID{b},
+			codes: []string{"003"},
+			want:  []string{"", entitiesdto.BulkAmbiguous},
+		},
+		// Of the memberships removed, those starting on the date are deleted
+		// and the rest closed, as the database sees their dates
+		{
+			name:       "Test remove deletes or closes",
+			remove:     true,
+			ids:        []uuid.UUID{a, b},
+			members:    []uuid.UUID{a, b},
+			want:       []string{entitiesdto.BulkRemoved, entitiesdto.BulkRemoved},
+			wantWrites: []string{"delete a,b starting on the date", "close a,b the day before", "sync a,b"},
+		},
+		{
+			name:       "Test remove skips non-members",
+			remove:     true,
+			ids:        []uuid.UUID{a, b},
+			members:    []uuid.UUID{a},
+			want:       []string{entitiesdto.BulkRemoved, entitiesdto.BulkSkipped},
+			wantWrites: []string{"delete a starting on the date", "close a the day before", "sync a"},
+		},
+		{
+			name:   "Test remove unknown code writes nothing",
+			remove: true,
+			ids:    []uuid.UUID{a},
+			codes:  []string{"404"},
+			want:   []string{"", entitiesdto.BulkNotFound},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, rec := newRecorderService(t)
+			rec.answer = func(query string) ([]string, [][]driver.Value) {
+				switch {
+				case strings.HasPrefix(query, `SELECT "student_entity"."id"`):
+					var rows [][]driver.Value
+					for _, st := range students {
+						rows = append(rows, []driver.Value{st.id.String(), st.code})
+					}
+					return []string{"id", "student_code"}, rows
+				case strings.HasPrefix(query, `SELECT "classroom_member_entity"."id"`):
+					var rows [][]driver.Value
+					for _, id := range tt.members {
+						rows = append(rows, []driver.Value{memberships[id].String(), classroom.String(), id.String()})
+					}
+					return []string{"id", "classroom_id", "student_id"}, rows
+				}
+				return nil, nil
+			}
+
+			req := &entitiesdto.ClassroomMemberBulkRequest{ClassroomID: classroom, TeacherID: teacher, Date: date, StudentIDs: tt.ids, StudentCodes: tt.codes}
+			bulk := s.BulkAddClassroomMembers
+			if tt.remove {
+				bulk = s.BulkRemoveClassroomMembers
+			}
+			results, err := bulk(tenant.Bypass(context.Background()), req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, r := range results {
+				got = append(got, r.Status)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("statuses = %q, want %q", got, tt.want)
+			}
+			var writes []string
+			for _, query := range rec.take() {
+				if step := step(query); step != "" {
+					writes = append(writes, step)
+				}
+			}
+			if !reflect.DeepEqual(writes, tt.wantWrites) {
+				t.Errorf("got writes\n%s\nwant\n%s", strings.Join(writes, "\n"), strings.Join(tt.wantWrites, "\n"))
+			}
+		})
+	}
+}
@@ -2,11 +2,15 @@ package entities
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"time"
 
 	entitiesdto "github.com/easy-attend-serviceV3/app/modules/entities/dto"
 	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/easy-attend-serviceV3/app/utils/base"
 	"github.com/google/uuid"
+	"github.com/uptrace/bun"
 )
 
 // CreateClassroomMember creates a new classroom member
@@ -15,30 +19,67 @@ func (s *Service) CreateClassroomMember(ctx context.Context, req *entitiesdto.Cl
 	memberID := uuid.New()
 
 	member := &ent.ClassroomMemberEntity{
-		ID:          memberID,
-		ClassroomID: req.ClassroomID,
-		StudentID:   req.StudentID,
-		TeacherID:   req.TeacherID,
+		ID:            memberID,
+		ClassroomID:   req.ClassroomID,
+		StudentID:     req.StudentID,
+		SeatNo:        req.SeatNo,
+		EffectiveFrom: req.EffectiveFrom,
+		TeacherID:     req.TeacherID,
 	}
 	member.CreatedAt = time.Now()
 	member.UpdatedAt = time.Now()
 
-	_, err := s.db.NewInsert().Model(member).Exec(ctx)
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(member).Exec(ctx); err != nil {
+			return err
+		}
+		_, err := syncStudentClassrooms(ctx, tx, member.StudentID)
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "classroom_member", nil)
 	}
 	return member, nil
 }
 
-// GetListClassroomMember retrieves all members of a specific classroom
-func (s *Service) GetListClassroomMember(ctx context.Context, classroomID uuid.UUID) ([]*ent.ClassroomMemberEntity, error) {
+// memberStudents returns the student of membership id, if it exists, so
+// their classroom can be synced once the membership changes.
+func memberStudents(ctx context.Context, tx bun.Tx, id uuid.UUID) ([]uuid.UUID, error) {
+	var students []uuid.UUID
+	err := tx.NewSelect().
+		Model((*ent.ClassroomMemberEntity)(nil)).
+		Column("student_id").
+		Where("id = ?", id).
+		Scan(ctx, &students)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+	}
+	return students, err
+}
+
+// memberOn limits a query on classroom_members to the memberships in effect
+// on date, today when it is empty.
+func memberOn(date string) func(bun.QueryBuilder) bun.QueryBuilder {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		return q.Where(dateOrToday+" BETWEEN ?TableAlias.effective_from AND COALESCE(?TableAlias.effective_to, 'infinity')", date)
+	}
+}
+
+// dateOrToday is the date of its argument, a YYYY-MM-DD string, or today
+// when it is empty.
+const dateOrToday = "COALESCE(NULLIF(?, '')::date, CURRENT_DATE)"
+
+// GetListClassroomMember retrieves the members of a classroom on asOf, today
+// when it is empty.
+func (s *Service) GetListClassroomMember(ctx context.Context, classroomID uuid.UUID, asOf string) ([]*ent.ClassroomMemberEntity, error) {
 	var members []*ent.ClassroomMemberEntity
 	err := s.db.NewSelect().
 		Model(&members).
 		Where("classroom_id = ?", classroomID).
+		ApplyQueryBuilder(memberOn(asOf)).
 		Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "classroom_member", nil)
 	}
 	return members, nil
 }
@@ -51,7 +92,7 @@ func (s *Service) GetClassroomMembersByTeacherID(ctx context.Context, teacherID
 		Where("teacher_id = ?", teacherID).
 		Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "classroom_member", nil)
 	}
 	return members, nil
 }
@@ -66,43 +107,78 @@ func (s *Service) GetClassroomMemberByID(ctx context.Context, id uuid.UUID) (*en
 		Scan(ctx)
 
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "classroom_member", id)
 	}
 
 	return &member, nil
 }
 
 // UpdateClassroomMember updates a classroom member
-func (s *Service) UpdateClassroomMember(ctx context.Context, id uuid.UUID, req *entitiesdto.ClassroomMemberUpdateRequest) (*ent.ClassroomMemberEntity, error) {
+func (s *Service) UpdateClassroomMember(ctx context.Context, id uuid.UUID, req *entitiesdto.ClassroomMemberUpdateRequest, version *time.Time) (*ent.ClassroomMemberEntity, error) {
 	member := &ent.ClassroomMemberEntity{
-		ID:          id,
-		ClassroomID: req.ClassroomID,
-		StudentID:   req.StudentID,
-		TeacherID:   req.TeacherID,
+		ID:            id,
+		ClassroomID:   req.ClassroomID,
+		StudentID:     req.StudentID,
+		SeatNo:        req.SeatNo,
+		EffectiveFrom: req.EffectiveFrom,
+		EffectiveTo:   req.EffectiveTo,
+		TeacherID:     req.TeacherID,
 	}
 	member.UpdatedAt = time.Now()
 
-	_, err := s.db.NewUpdate().
-		Model(member).
-		Column("classroom_id", "student_id", "teacher_id", "updated_at").
-		Where("id = ?", id).
-		Exec(ctx)
-
+	columns := []string{"classroom_id", "student_id", "teacher_id", "seat_no", "effective_to", "updated_at"}
+	if req.EffectiveFrom != "" {
+		columns = append(columns, "effective_from")
+	}
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		students, err := memberStudents(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		res, err := tx.NewUpdate().
+			Model(member).
+			Column(columns...).
+			Where("id = ?", id).
+			ApplyQueryBuilder(atVersion(version)).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if err := s.checkWritten(ctx, res, (*ent.ClassroomMemberEntity)(nil), "classroom_member", id, version); err != nil {
+			return err
+		}
+		_, err = syncStudentClassrooms(ctx, tx, append(students, member.StudentID)...)
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "classroom_member", id)
 	}
 
 	return member, nil
 }
 
 // DeleteClassroomMember deletes a classroom member
-func (s *Service) DeleteClassroomMember(ctx context.Context, id uuid.UUID) error {
-	_, err := s.db.NewDelete().
-		Model((*ent.ClassroomMemberEntity)(nil)).
-		Where("id = ?", id).
-		Exec(ctx)
-
-	return err
+func (s *Service) DeleteClassroomMember(ctx context.Context, id uuid.UUID, version *time.Time) error {
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		students, err := memberStudents(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		res, err := tx.NewDelete().
+			Model((*ent.ClassroomMemberEntity)(nil)).
+			Where("id = ?", id).
+			ApplyQueryBuilder(atVersion(version)).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if err := s.checkWritten(ctx, res, (*ent.ClassroomMemberEntity)(nil), "classroom_member", id, version); err != nil {
+			return err
+		}
+		_, err = syncStudentClassrooms(ctx, tx, students...)
+		return err
+	})
+	return translateDeleteError(err, "classroom_member", id)
 }
 
 // CheckExistClassroomMember checks if a classroom member exists
@@ -110,10 +186,11 @@ func (s *Service) CheckExistClassroomMember(ctx context.Context, id uuid.UUID) (
 	count, err := s.db.NewSelect().
 		Model((*ent.ClassroomMemberEntity)(nil)).
 		Where("id = ?", id).
+		ApplyQueryBuilder(ent.ScopeSchool(ctx, (*ent.ClassroomMemberEntity)(nil))).
 		Count(ctx)
 
 	if err != nil {
-		return false, err
+		return false, translateError(err, "classroom_member", id)
 	}
 	return count > 0, nil
 }
@@ -126,11 +203,141 @@ func (s *Service) GetClassroomMembersByStudentID(ctx context.Context, studentID
 		Where("student_id = ?", studentID).
 		Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "classroom_member", nil)
 	}
 	return members, nil
 }
 
+// GetClassroomRoster returns the members of a classroom on date (today when
+// empty) with their prefix, gender and photo, and the latest attendance mark
+// each has for that date and session, in one query.
+func (s *Service) GetClassroomRoster(ctx context.Context, classroomID uuid.UUID, date, session, order string) ([]*entitiesdto.ClassroomRosterRow, error) {
+	var rows []*entitiesdto.ClassroomRosterRow
+	q := s.db.NewSelect().
+		Model((*ent.ClassroomMemberEntity)(nil)).
+		ColumnExpr("?TableAlias.id AS member_id, ?TableAlias.seat_no").
+		ColumnExpr("st.id AS student_id, st.student_code, st.first_name, st.last_name, st.photo_url").
+		ColumnExpr("p.name AS prefix, g.name AS gender").
+		ColumnExpr("a.id AS attendance_id, a.status, a.time").
+		Join("JOIN students AS st ON st.id = ?TableAlias.student_id").
+		Join("LEFT JOIN prefixes AS p ON p.id = st.prefix_id").
+		Join("LEFT JOIN genders AS g ON g.id = st.gender_id").
+		Join(`LEFT JOIN LATERAL (
+			SELECT id, status, time FROM attendances
+			WHERE classroom_id = ?TableAlias.classroom_id AND student_id = st.id
+				AND date = `+dateOrToday+` AND session = ?
+			ORDER BY updated_at DESC
+			LIMIT 1
+		) AS a ON TRUE`, date, session).
+		Where("?TableAlias.classroom_id = ?", classroomID).
+		ApplyQueryBuilder(memberOn(date))
+	if order == entitiesdto.RosterOrderStudentCode {
+		q = q.OrderExpr("st.student_code")
+	} else {
+		q = q.OrderExpr("?TableAlias.seat_no NULLS LAST, st.student_code")
+	}
+	if err := q.Scan(ctx, &rows); err != nil {
+		return nil, translateError(err, "classroom_member", nil)
+	}
+	return rows, nil
+}
+
+// TransferClassroomMember moves the student of membership id to another
+// classroom: the membership ends the day before req.Date and a new one starts
+// on it, together or not at all. The student's classroom_id follows at once
+// when req.Date is today or earlier, and with SyncStudentClassrooms once a
+// later date has come. It refuses a membership not in effect on req.Date, or one
+// that starts on it, and a ConflictError if the student already has a
+// membership of the new classroom on or after req.Date.
+func (s *Service) TransferClassroomMember(ctx context.Context, id uuid.UUID, req *entitiesdto.ClassroomMemberTransferRequest) (*ent.ClassroomMemberEntity, error) {
+	now := time.Now()
+	next := &ent.ClassroomMemberEntity{
+		ID:            uuid.New(),
+		ClassroomID:   req.ClassroomID,
+		TeacherID:     req.TeacherID,
+		SeatNo:        req.SeatNo,
+		EffectiveFrom: req.Date,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var prev ent.ClassroomMemberEntity
+		err := tx.NewUpdate().
+			Model(&prev).
+			Set("effective_to = ?::date - 1", req.Date).
+			Set("updated_at = ?", now).
+			Where("id = ?", id).
+			ApplyQueryBuilder(memberOn(req.Date)).
+			Returning("*").
+			Scan(ctx)
+		if errors.Is(err, sql.ErrNoRows) {
+			return s.transferRefused(ctx, tx, id, req.Date)
+		}
+		if err != nil {
+			return err
+		}
+		if prev.ClassroomID == req.ClassroomID {
+			return base.ValidationError{Field: "classroom_id", Rule: "invalid"}
+		}
+
+		next.StudentID = prev.StudentID
+		if err := checkNoOverlap(ctx, tx, next); err != nil {
+			return err
+		}
+		if _, err := tx.NewInsert().Model(next).Exec(ctx); err != nil {
+			return err
+		}
+		_, err = syncStudentClassrooms(ctx, tx, prev.StudentID)
+		return err
+	})
+	if err != nil {
+		return nil, translateError(err, "classroom_member", id)
+	}
+	return next, nil
+}
+
+// checkNoOverlap returns a ConflictError naming the membership of m's student
+// in m's classroom whose dates overlap m's, if there is one. The
+// classroom_members_period_excl constraint refuses such a row anyway; checking
+// first names the membership in the way.
+func checkNoOverlap(ctx context.Context, tx bun.Tx, m *ent.ClassroomMemberEntity) error {
+	var other ent.ClassroomMemberEntity
+	err := tx.NewSelect().
+		Model(&other).
+		Column("id").
+		Where("student_id = ? AND classroom_id = ?", m.StudentID, m.ClassroomID).
+		Where("daterange(effective_from, effective_to, '[]') && daterange(?::date, ?::date, '[]')", m.EffectiveFrom, m.EffectiveTo).
+		Limit(1).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return base.ConflictError{Resource: "classroom_member", Value: other.ID.String()}
+}
+
+// transferRefused tells why membership id could not be transferred on date.
+func (s *Service) transferRefused(ctx context.Context, tx bun.Tx, id uuid.UUID, date string) error {
+	var member ent.ClassroomMemberEntity
+	err := tx.NewSelect().
+		Model(&member).
+		Where("id = ?", id).
+		Where("effective_from >= ?::date", date).
+		Where("effective_to IS NULL OR effective_to >= ?::date", date).
+		Scan(ctx)
+	if err == nil {
+		// Closing it the day before would leave it ending before it starts
+		return base.ValidationError{Field: "date", Rule: "gt", Param: member.EffectiveFrom}
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return base.NotFoundError{Resource: "classroom_member", ID: id.String()}
+	}
+	return err
+}
+
 // GetAllClassroomMembers retrieves all classroom members with limit
 func (s *Service) GetAllClassroomMembers(ctx context.Context, limit int) ([]*ent.ClassroomMemberEntity, error) {
 	var members []*ent.ClassroomMemberEntity
@@ -139,7 +346,7 @@ func (s *Service) GetAllClassroomMembers(ctx context.Context, limit int) ([]*ent
 		Limit(limit).
 		Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "classroom_member", nil)
 	}
 	return members, nil
 }
@@ -0,0 +1,80 @@
+package entities
+
+import (
+	"context"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/google/uuid"
+)
+
+var _ entitiesinf.ClassroomTeacherEntity = (*Service)(nil)
+
+func (s *Service) GetListClassroomTeacher(ctx context.Context, classroomID uuid.UUID) ([]*ent.ClassroomTeacherEntity, error) {
+	var teachers []*ent.ClassroomTeacherEntity
+	err := s.db.NewSelect().
+		Model(&teachers).
+		Where("classroom_id = ?", classroomID).
+		Order("created_at").
+		Scan(ctx)
+	if err != nil {
+		return nil, translateError(err, "classroom_teacher", nil)
+	}
+	return teachers, nil
+}
+
+// CreateClassroomTeacher assigns a teacher to a classroom. A teacher is
+// assigned to a classroom once; assigning them again gets a ConflictError.
+func (s *Service) CreateClassroomTeacher(ctx context.Context, ct *ent.ClassroomTeacherEntity) error {
+	ct.ID = uuid.New()
+	ct.CreatedAt = time.Now()
+	ct.UpdatedAt = ct.CreatedAt
+	if _, err := s.db.NewInsert().Model(ct).Exec(ctx); err != nil {
+		return translateError(err, "classroom_teacher", ct.TeacherID)
+	}
+	return nil
+}
+
+// UpdateClassroomTeacherRole changes the role of a teacher in a classroom and
+// returns the updated assignment.
+func (s *Service) UpdateClassroomTeacherRole(ctx context.Context, classroomID, teacherID uuid.UUID, role string) (*ent.ClassroomTeacherEntity, error) {
+	var ct ent.ClassroomTeacherEntity
+	res, err := s.db.NewUpdate().
+		Model(&ct).
+		Set("role = ?", role).
+		Set("updated_at = ?", time.Now()).
+		Where("classroom_id = ?", classroomID).
+		Where("teacher_id = ?", teacherID).
+		Returning("*").
+		Exec(ctx)
+	if err != nil {
+		return nil, translateError(err, "classroom_teacher", teacherID)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		if err == nil {
+			err = base.NotFoundError{Resource: "classroom_teacher", ID: teacherID.String()}
+		}
+		return nil, err
+	}
+	return &ct, nil
+}
+
+func (s *Service) DeleteClassroomTeacher(ctx context.Context, classroomID, teacherID uuid.UUID) error {
+	res, err := s.db.NewDelete().
+		Model((*ent.ClassroomTeacherEntity)(nil)).
+		Where("classroom_id = ?", classroomID).
+		Where("teacher_id = ?", teacherID).
+		Exec(ctx)
+	if err != nil {
+		return translateError(err, "classroom_teacher", teacherID)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		if err == nil {
+			err = base.NotFoundError{Resource: "classroom_teacher", ID: teacherID.String()}
+		}
+		return err
+	}
+	return nil
+}
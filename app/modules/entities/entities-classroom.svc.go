@@ -2,12 +2,13 @@ package entities
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
 	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+	"github.com/easy-attend-serviceV3/app/utils/base"
 	"github.com/google/uuid"
+	"github.com/uptrace/bun"
 )
 
 var _ entitiesinf.ClassroomEntity = (*Service)(nil)
@@ -16,20 +17,23 @@ func (s *Service) GetListClassroom(ctx context.Context) ([]*ent.ClassroomEntity,
 	var classrooms []*ent.ClassroomEntity
 	err := s.db.NewSelect().Model(&classrooms).Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "classroom", nil)
 	}
 	return classrooms, nil
 }
 
+// GetClassroomsByTeacherID returns the classrooms the teacher reaches: every
+// classroom of their school if they administer it, otherwise those they are
+// assigned to, whatever their role there, and those they stand in for today
+// with a full delegation.
 func (s *Service) GetClassroomsByTeacherID(ctx context.Context, teacherID uuid.UUID) ([]*ent.ClassroomEntity, error) {
 	var classrooms []*ent.ClassroomEntity
 	err := s.db.NewSelect().
 		Model(&classrooms).
-		Join("JOIN classroom_members cm ON classrooms.id = cm.classroom_id").
-		Where("cm.teacher_id = ?", teacherID).
+		Where("EXISTS (SELECT 1 FROM teachers t WHERE t.id = ? AND "+reaches("?TableAlias.id", "?TableAlias.school_id", ent.DelegationScopeFull, "")+")", teacherID).
 		Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "classroom", nil)
 	}
 	return classrooms, nil
 }
@@ -38,12 +42,15 @@ func (s *Service) GetByIDClassroom(ctx context.Context, id uuid.UUID) (*ent.Clas
 	var classroom ent.ClassroomEntity
 	err := s.db.NewSelect().Model(&classroom).Where("id = ?", id).Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "classroom", id)
 	}
 	return &classroom, nil
 }
 
-func (s *Service) CreateClassroom(ctx context.Context, schoolID uuid.UUID, name string) (*ent.ClassroomEntity, error) {
+// CreateClassroom creates a classroom and, unless teacherID is uuid.Nil as
+// for an API key, assigns its creator as homeroom teacher in the same
+// transaction, so they can reach what they have just created.
+func (s *Service) CreateClassroom(ctx context.Context, schoolID uuid.UUID, name string, teacherID uuid.UUID) (*ent.ClassroomEntity, error) {
 	classroom := &ent.ClassroomEntity{
 		ID:       uuid.New(),
 		SchoolID: schoolID,
@@ -51,14 +58,30 @@ func (s *Service) CreateClassroom(ctx context.Context, schoolID uuid.UUID, name
 	}
 	classroom.CreatedAt = time.Now()
 	classroom.UpdatedAt = time.Now()
-	_, err := s.db.NewInsert().Model(classroom).Exec(ctx)
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(classroom).Exec(ctx); err != nil {
+			return err
+		}
+		if teacherID == uuid.Nil {
+			return nil
+		}
+		_, err := tx.NewInsert().Model(&ent.ClassroomTeacherEntity{
+			ID:          uuid.New(),
+			ClassroomID: classroom.ID,
+			TeacherID:   teacherID,
+			Role:        ent.ClassroomRoleHomeroom,
+			CreatedAt:   classroom.CreatedAt,
+			UpdatedAt:   classroom.UpdatedAt,
+		}).Exec(ctx)
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "classroom", name)
 	}
 	return classroom, nil
 }
 
-func (s *Service) UpdateClassroom(ctx context.Context, id uuid.UUID, schoolID uuid.UUID, name string) (*ent.ClassroomEntity, error) {
+func (s *Service) UpdateClassroom(ctx context.Context, id uuid.UUID, schoolID uuid.UUID, name string, version *time.Time) (*ent.ClassroomEntity, error) {
 	classroom, err := s.GetByIDClassroom(ctx, id)
 	if err != nil {
 		return nil, err
@@ -66,25 +89,31 @@ func (s *Service) UpdateClassroom(ctx context.Context, id uuid.UUID, schoolID uu
 	classroom.SchoolID = schoolID
 	classroom.Name = name
 	classroom.UpdatedAt = time.Now()
-	_, err = s.db.NewUpdate().Model(classroom).Where("id = ?", id).Exec(ctx)
+	res, err := s.db.NewUpdate().Model(classroom).Where("id = ?", id).ApplyQueryBuilder(atVersion(version)).Exec(ctx)
 	if err != nil {
+		return nil, translateError(err, "classroom", id)
+	}
+	if err := s.checkWritten(ctx, res, (*ent.ClassroomEntity)(nil), "classroom", id, version); err != nil {
 		return nil, err
 	}
 	return classroom, nil
 }
 
-func (s *Service) DeleteClassroom(ctx context.Context, id uuid.UUID) error {
-	_, err := s.db.NewDelete().Model(&ent.ClassroomEntity{}).Where("id = ?", id).Exec(ctx)
-	return err
+func (s *Service) DeleteClassroom(ctx context.Context, id uuid.UUID, version *time.Time) error {
+	res, err := s.db.NewDelete().Model(&ent.ClassroomEntity{}).Where("id = ?", id).ApplyQueryBuilder(atVersion(version)).Exec(ctx)
+	if err != nil {
+		return translateDeleteError(err, "classroom", id)
+	}
+	return s.checkWritten(ctx, res, (*ent.ClassroomEntity)(nil), "classroom", id, version)
 }
 
 func (s *Service) CheckExistClassroom(ctx context.Context, id uuid.UUID) (bool, error) {
-	count, err := s.db.NewSelect().Model(&ent.ClassroomEntity{}).Where("id = ?", id).Count(ctx)
+	count, err := s.db.NewSelect().Model((*ent.ClassroomEntity)(nil)).Where("id = ?", id).ApplyQueryBuilder(ent.ScopeSchool(ctx, (*ent.ClassroomEntity)(nil))).Count(ctx)
 	if err != nil {
-		return false, err
+		return false, translateError(err, "classroom", id)
 	}
 	if count == 0 {
-		return false, fmt.Errorf("classroom with id %s does not exist", id)
+		return false, base.NotFoundError{Resource: "classroom", ID: id.String()}
 	}
 	return true, nil
 }
@@ -0,0 +1,49 @@
+package entities
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/easy-attend-serviceV3/app/utils/tenant"
+	"github.com/google/uuid"
+)
+
+func Test_CreateClassroom(t *testing.T) {
+	tests := []struct {
+		name       string
+		teacherID  uuid.UUID
+		wantAssign bool
+	}{
+		{"Test teacher is assigned as homeroom", uuid.New(), true},
+		{"Test API key assigns no one", uuid.Nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, rec := newRecorderService(t)
+			classroom, err := s.CreateClassroom(tenant.Bypass(context.Background()), uuid.New(), "1/1", tt.teacherID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			created, assigned := false, false
+			for _, query := range rec.take() {
+				switch {
+				case strings.HasPrefix(query, `INSERT INTO "classrooms"`):
+					created = true
+				case strings.HasPrefix(query, `INSERT INTO "classroom_teachers"`):
+					assigned = strings.Contains(query, "'"+classroom.ID.String()+"', '"+tt.teacherID.String()+"', 'homeroom'")
+					if !created {
+						t.Error("the creator is assigned before the classroom exists")
+					}
+				}
+			}
+			if !created {
+				t.Fatal("the classroom is not inserted")
+			}
+			if assigned != tt.wantAssign {
+				t.Errorf("creator assigned = %v, want %v", assigned, tt.wantAssign)
+			}
+		})
+	}
+}
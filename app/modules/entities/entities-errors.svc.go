@@ -0,0 +1,87 @@
+package entities
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgreSQL error codes translated by translateError.
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgExclusionViolation  = "23P01"
+)
+
+// translateError maps driver errors onto the typed errors in base so the
+// controllers can answer 404/409/422 instead of a generic 500. Anything it
+// does not recognise is returned unchanged.
+func translateError(err error, resource string, id any) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return base.NotFoundError{Resource: resource, ID: idString(id)}
+	}
+
+	code, constraint, detail := pgErrorFields(err)
+	switch code {
+	case pgUniqueViolation, pgExclusionViolation:
+		return base.ConflictError{Resource: resource, Value: conflictField(constraint, detail)}
+	case pgForeignKeyViolation:
+		return base.ReferenceError{Resource: resource, Reference: referencedName(constraint)}
+	}
+	return err
+}
+
+// translateDeleteError is translateError for deletes, where a foreign-key
+// violation means other rows still point at the one being removed.
+func translateDeleteError(err error, resource string, id any) error {
+	if code, constraint, _ := pgErrorFields(err); code == pgForeignKeyViolation {
+		return base.ConflictError{Resource: resource, Value: constraint}
+	}
+	return translateError(err, resource, id)
+}
+
+func pgErrorFields(err error) (code, constraint, detail string) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code, pgErr.ConstraintName, pgErr.Detail
+	}
+	return "", "", ""
+}
+
+// conflictField names the columns of a violated key from Postgres' detail,
+// "email" from "Key (email)=(x@other-school) already exists.", so the
+// conflicting values, which may belong to another school, are not answered.
+// Keys over expressions fall back to the constraint name.
+func conflictField(constraint, detail string) string {
+	rest, keyed := strings.CutPrefix(detail, "Key (")
+	columns, _, ok := strings.Cut(rest, ")=(")
+	if !keyed || !ok || strings.Contains(columns, "(") {
+		return constraint
+	}
+	return columns
+}
+
+// referencedName extracts "classroom" from Postgres' default constraint
+// name "students_classroom_id_fkey".
+func referencedName(constraint string) string {
+	name := strings.TrimSuffix(constraint, "_fkey")
+	if !strings.HasSuffix(name, "_id") {
+		return constraint
+	}
+	name = strings.TrimSuffix(name, "_id")
+	return name[strings.LastIndex(name, "_")+1:]
+}
+
+func idString(id any) string {
+	if id == nil {
+		return ""
+	}
+	return fmt.Sprint(id)
+}
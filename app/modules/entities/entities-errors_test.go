@@ -0,0 +1,60 @@
+package entities
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func Test_TranslateError(t *testing.T) {
+	other := errors.New("connection reset")
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"Test no rows", sql.ErrNoRows, base.NotFoundError{Resource: "classroom_member", ID: "42"}},
+		{
+			"Test unique violation",
+			&pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "students_student_code_key", Detail: "Key (student_code)=(001) already exists."},
+			base.ConflictError{Resource: "classroom_member", Value: "student_code"},
+		},
+		{
+			"Test unique violation of another school",
+			&pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "teachers_email_key", Detail: "Key (email)=(x@other-school.ac.th) already exists."},
+			base.ConflictError{Resource: "classroom_member", Value: "email"},
+		},
+		{
+			"Test unique violation of several columns",
+			&pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "students_school_id_student_code_key", Detail: "Key (school_id, student_code)=(6ba7b810-9dad-11d1-80b4-00c04fd430c8, 001) already exists."},
+			base.ConflictError{Resource: "classroom_member", Value: "school_id, student_code"},
+		},
+		{
+			"Test unique violation without detail",
+			&pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "students_student_code_key"},
+			base.ConflictError{Resource: "classroom_member", Value: "students_student_code_key"},
+		},
+		{
+			"Test overlapping membership",
+			&pgconn.PgError{Code: pgExclusionViolation, ConstraintName: "classroom_members_period_excl", Detail: "Key (student_id, classroom_id, daterange(effective_from, effective_to, '[]'::text))=(6ba7b810-9dad-11d1-80b4-00c04fd430c8, 6ba7b811-9dad-11d1-80b4-00c04fd430c8, [2026-09-01,2026-10-01)) conflicts with existing key."},
+			base.ConflictError{Resource: "classroom_member", Value: "classroom_members_period_excl"},
+		},
+		{
+			"Test foreign key violation",
+			&pgconn.PgError{Code: pgForeignKeyViolation, ConstraintName: "classroom_members_classroom_id_fkey"},
+			base.ReferenceError{Resource: "classroom_member", Reference: "classroom"},
+		},
+		{"Test other error", other, other},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := translateError(tt.err, "classroom_member", 42); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("translateError() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
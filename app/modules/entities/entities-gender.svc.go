@@ -14,7 +14,7 @@ func (s *Service) GetListGender(ctx context.Context) ([]*ent.GenderEntity, error
 	var genders []*ent.GenderEntity
 	err := s.db.NewSelect().Model(&genders).Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "gender", nil)
 	}
 	return genders, nil
 }
@@ -23,7 +23,7 @@ func (s *Service) GetByIDGender(ctx context.Context, id uuid.UUID) (*ent.GenderE
 	var gender ent.GenderEntity
 	err := s.db.NewSelect().Model(&gender).Where("id = ?", id).Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "gender", id)
 	}
 	return &gender, nil
 }
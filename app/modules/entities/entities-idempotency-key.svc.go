@@ -0,0 +1,57 @@
+package entities
+
+import (
+	"context"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+)
+
+var _ entitiesinf.IdempotencyKeyEntity = (*Service)(nil)
+
+// ReserveIdempotencyKey claims key.Key for a new request. When another request
+// already holds it, that record is returned instead and nothing is written.
+func (s *Service) ReserveIdempotencyKey(ctx context.Context, key *ent.IdempotencyKeyEntity) (*ent.IdempotencyKeyEntity, error) {
+	// Expired keys are free to be claimed again.
+	if _, err := s.db.NewDelete().
+		Model((*ent.IdempotencyKeyEntity)(nil)).
+		Where("expires_at <= ?", time.Now()).
+		Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	key.CreatedAt = time.Now()
+	res, err := s.db.NewInsert().Model(key).On("CONFLICT (key) DO NOTHING").Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 1 {
+		return nil, err
+	}
+
+	var existing ent.IdempotencyKeyEntity
+	if err := s.db.NewSelect().Model(&existing).Where("key = ?", key.Key).Scan(ctx); err != nil {
+		return nil, translateError(err, "idempotency_key", key.Key)
+	}
+	return &existing, nil
+}
+
+// CompleteIdempotencyKey stores the response a reserved key replays.
+func (s *Service) CompleteIdempotencyKey(ctx context.Context, key *ent.IdempotencyKeyEntity) error {
+	_, err := s.db.NewUpdate().
+		Model(key).
+		Column("status", "content_type", "body").
+		Where("key = ?", key.Key).
+		Exec(ctx)
+	return err
+}
+
+// DeleteIdempotencyKey releases a key so the request can be retried.
+func (s *Service) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	_, err := s.db.NewDelete().
+		Model((*ent.IdempotencyKeyEntity)(nil)).
+		Where("key = ?", key).
+		Exec(ctx)
+	return err
+}
@@ -0,0 +1,91 @@
+package entities
+
+import (
+	"context"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/google/uuid"
+)
+
+var _ entitiesinf.OIDCEntity = (*Service)(nil)
+
+// CreateOIDCState stores a sign-in started at a provider, clearing the ones
+// that were never finished.
+func (s *Service) CreateOIDCState(ctx context.Context, state *ent.OIDCStateEntity) error {
+	state.CreatedAt = time.Now()
+	if _, err := s.db.NewDelete().Model((*ent.OIDCStateEntity)(nil)).Where("expires_at < ?", state.CreatedAt).Exec(ctx); err != nil {
+		return translateError(err, "oidc_state", nil)
+	}
+	if _, err := s.db.NewInsert().Model(state).Exec(ctx); err != nil {
+		return translateError(err, "oidc_state", nil)
+	}
+	return nil
+}
+
+// ConsumeOIDCState removes and returns the unexpired sign-in of provider with
+// state hash, so a state works once.
+func (s *Service) ConsumeOIDCState(ctx context.Context, provider, hash string) (*ent.OIDCStateEntity, error) {
+	var state ent.OIDCStateEntity
+	res, err := s.db.NewDelete().
+		Model(&state).
+		Where("state_hash = ?", hash).
+		Where("provider = ?", provider).
+		Where("expires_at > ?", time.Now()).
+		Returning("*").
+		Exec(ctx)
+	if err != nil {
+		return nil, translateError(err, "oidc_state", nil)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		if err == nil {
+			err = base.NotFoundError{Resource: "oidc_state"}
+		}
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (s *Service) GetTeacherIdentity(ctx context.Context, provider, subject string) (*ent.TeacherIdentityEntity, error) {
+	var identity ent.TeacherIdentityEntity
+	err := s.db.NewSelect().
+		Model(&identity).
+		Where("provider = ?", provider).
+		Where("subject = ?", subject).
+		Scan(ctx)
+	if err != nil {
+		return nil, translateError(err, "teacher_identity", subject)
+	}
+	return &identity, nil
+}
+
+// LinkTeacherIdentity records a sign-in with identity, linking it to its
+// teacher the first time. An identity linked to another teacher gets a
+// ConflictError.
+func (s *Service) LinkTeacherIdentity(ctx context.Context, identity *ent.TeacherIdentityEntity) error {
+	now := time.Now()
+	identity.ID = uuid.New()
+	identity.LastLoginAt = &now
+	identity.CreatedAt = now
+
+	res, err := s.db.NewInsert().
+		Model(identity).
+		On("CONFLICT (provider, subject) DO UPDATE").
+		Set("email = EXCLUDED.email").
+		Set("last_login_at = EXCLUDED.last_login_at").
+		Where("teacher_identity_entity.teacher_id = EXCLUDED.teacher_id").
+		Returning("NULL").
+		Exec(ctx)
+	if err != nil {
+		return translateError(err, "teacher_identity", identity.Subject)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		if err == nil {
+			err = base.ConflictError{Resource: "teacher_identity", Value: identity.Subject}
+		}
+		return err
+	}
+	return nil
+}
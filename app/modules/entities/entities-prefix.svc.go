@@ -14,7 +14,7 @@ func (s *Service) GetListPrefix(ctx context.Context) ([]*ent.PrefixEntity, error
 	var prefixes []*ent.PrefixEntity
 	err := s.db.NewSelect().Model(&prefixes).Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "prefix", nil)
 	}
 	return prefixes, nil
 }
@@ -23,7 +23,7 @@ func (s *Service) GetByIDPrefix(ctx context.Context, id uuid.UUID) (*ent.PrefixE
 	var prefix ent.PrefixEntity
 	err := s.db.NewSelect().Model(&prefix).Where("id = ?", id).Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "prefix", id)
 	}
 	return &prefix, nil
 }
@@ -0,0 +1,74 @@
+package entities
+
+import (
+	"context"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+var _ entitiesinf.RefreshTokenEntity = (*Service)(nil)
+
+func (s *Service) GetRefreshTokenByHash(ctx context.Context, hash string) (*ent.RefreshTokenEntity, error) {
+	var token ent.RefreshTokenEntity
+	if err := s.db.NewSelect().Model(&token).Where("token_hash = ?", hash).Scan(ctx); err != nil {
+		return nil, translateError(err, "refresh_token", nil)
+	}
+	return &token, nil
+}
+
+// RotateRefreshToken marks current as used and stores next in its family,
+// extending the session to next's expiry. It reports false, writing nothing,
+// when current was used or revoked in the meantime.
+func (s *Service) RotateRefreshToken(ctx context.Context, current, next *ent.RefreshTokenEntity) (bool, error) {
+	rotated := false
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		now := time.Now()
+		res, err := tx.NewUpdate().
+			Model((*ent.RefreshTokenEntity)(nil)).
+			Set("used_at = ?", now).
+			Where("id = ?", current.ID).
+			Where("used_at IS NULL").
+			Where("revoked_at IS NULL").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return err
+		}
+
+		next.ID = uuid.New()
+		next.FamilyID = current.FamilyID
+		next.ParentID = &current.ID
+		next.TeacherID = current.TeacherID
+		next.CreatedAt = now
+		if _, err := tx.NewInsert().Model(next).Exec(ctx); err != nil {
+			return translateError(err, "refresh_token", nil)
+		}
+
+		if _, err := tx.NewUpdate().
+			Model((*ent.TeacherSessionEntity)(nil)).
+			Set("last_seen_at = ?", now).
+			Set("expires_at = ?", next.ExpiresAt).
+			Where("id = ?", current.FamilyID).
+			Exec(ctx); err != nil {
+			return err
+		}
+		rotated = true
+		return nil
+	})
+	return rotated, err
+}
+
+// RevokeRefreshTokenFamily revokes every token rotated from the same login,
+// ending its session.
+func (s *Service) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error {
+	_, err := s.revokeSessions(ctx, func(q *bun.UpdateQuery) *bun.UpdateQuery {
+		return q.Where("id = ?", familyID)
+	})
+	return err
+}
@@ -0,0 +1,95 @@
+package entities
+
+import (
+	"context"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/google/uuid"
+)
+
+var _ entitiesinf.RoleEntity = (*Service)(nil)
+
+func (s *Service) GetListRole(ctx context.Context) ([]*ent.RoleEntity, error) {
+	var roles []*ent.RoleEntity
+	if err := s.db.NewSelect().Model(&roles).Order("name").Scan(ctx); err != nil {
+		return nil, translateError(err, "role", nil)
+	}
+	return roles, nil
+}
+
+func (s *Service) GetRoleByName(ctx context.Context, name string) (*ent.RoleEntity, error) {
+	var role ent.RoleEntity
+	if err := s.db.NewSelect().Model(&role).Where("name = ?", name).Scan(ctx); err != nil {
+		return nil, translateError(err, "role", name)
+	}
+	return &role, nil
+}
+
+// GetTeacherRoles returns the roles granted to a teacher.
+func (s *Service) GetTeacherRoles(ctx context.Context, teacherID uuid.UUID) ([]*ent.RoleEntity, error) {
+	var roles []*ent.RoleEntity
+	err := s.db.NewSelect().
+		Model(&roles).
+		Join("JOIN teacher_roles AS tr ON tr.role_id = role_entity.id").
+		Where("tr.teacher_id = ?", teacherID).
+		Order("role_entity.name").
+		Scan(ctx)
+	if err != nil {
+		return nil, translateError(err, "role", nil)
+	}
+	return roles, nil
+}
+
+// AssignTeacherRole grants a role. Granting one the teacher already has is
+// not an error.
+func (s *Service) AssignTeacherRole(ctx context.Context, grant *ent.TeacherRoleEntity) error {
+	grant.CreatedAt = time.Now()
+	_, err := s.db.NewInsert().Model(grant).On("CONFLICT (teacher_id, role_id) DO NOTHING").Exec(ctx)
+	return translateError(err, "teacher_role", nil)
+}
+
+// RevokeTeacherRole takes a role away, or returns NotFoundError when the
+// teacher did not have it.
+func (s *Service) RevokeTeacherRole(ctx context.Context, teacherID, roleID uuid.UUID) error {
+	res, err := s.db.NewDelete().
+		Model((*ent.TeacherRoleEntity)(nil)).
+		Where("teacher_id = ? AND role_id = ?", teacherID, roleID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return base.NotFoundError{Resource: "teacher_role", ID: roleID.String()}
+	}
+	return nil
+}
+
+// SetRoleMFARequired sets whether teachers holding the role named name must
+// log in with a TOTP code, and returns the updated role.
+func (s *Service) SetRoleMFARequired(ctx context.Context, name string, required bool) (*ent.RoleEntity, error) {
+	var role ent.RoleEntity
+	res, err := s.db.NewUpdate().
+		Model(&role).
+		Set("mfa_required = ?", required).
+		Set("updated_at = ?", time.Now()).
+		Where("name = ?", name).
+		Returning("*").
+		Exec(ctx)
+	if err != nil {
+		return nil, translateError(err, "role", name)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		if err == nil {
+			err = base.NotFoundError{Resource: "role", ID: name}
+		}
+		return nil, err
+	}
+	return &role, nil
+}
@@ -0,0 +1,69 @@
+package entities
+
+import (
+	"context"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/google/uuid"
+)
+
+var _ entitiesinf.SchoolDomainEntity = (*Service)(nil)
+
+// GetSchoolDomain returns the school domain registered for provider.
+func (s *Service) GetSchoolDomain(ctx context.Context, provider, domain string) (*ent.SchoolDomainEntity, error) {
+	var d ent.SchoolDomainEntity
+	err := s.db.NewSelect().
+		Model(&d).
+		Where("provider = ?", provider).
+		Where("domain = ?", domain).
+		Scan(ctx)
+	if err != nil {
+		return nil, translateError(err, "school_domain", domain)
+	}
+	return &d, nil
+}
+
+func (s *Service) GetListSchoolDomain(ctx context.Context, schoolID uuid.UUID) ([]*ent.SchoolDomainEntity, error) {
+	var domains []*ent.SchoolDomainEntity
+	err := s.db.NewSelect().
+		Model(&domains).
+		Where("school_id = ?", schoolID).
+		Order("provider", "domain").
+		Scan(ctx)
+	if err != nil {
+		return nil, translateError(err, "school_domain", nil)
+	}
+	return domains, nil
+}
+
+// CreateSchoolDomain registers a domain. A domain belongs to one school per
+// provider; registering it again gets a ConflictError.
+func (s *Service) CreateSchoolDomain(ctx context.Context, domain *ent.SchoolDomainEntity) error {
+	domain.ID = uuid.New()
+	domain.CreatedAt = time.Now()
+	if _, err := s.db.NewInsert().Model(domain).Exec(ctx); err != nil {
+		return translateError(err, "school_domain", domain.Domain)
+	}
+	return nil
+}
+
+func (s *Service) DeleteSchoolDomain(ctx context.Context, schoolID, id uuid.UUID) error {
+	res, err := s.db.NewDelete().
+		Model((*ent.SchoolDomainEntity)(nil)).
+		Where("id = ?", id).
+		Where("school_id = ?", schoolID).
+		Exec(ctx)
+	if err != nil {
+		return translateError(err, "school_domain", id)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		if err == nil {
+			err = base.NotFoundError{Resource: "school_domain", ID: id.String()}
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package entities
+
+import (
+	"context"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/google/uuid"
+)
+
+var _ entitiesinf.SchoolJoinCodeEntity = (*Service)(nil)
+
+func (s *Service) GetSchoolJoinCode(ctx context.Context, schoolID uuid.UUID) (*ent.SchoolJoinCodeEntity, error) {
+	var code ent.SchoolJoinCodeEntity
+	err := s.db.NewSelect().Model(&code).Where("school_id = ?", schoolID).Scan(ctx)
+	if err != nil {
+		return nil, translateError(err, "school_join_code", schoolID)
+	}
+	return &code, nil
+}
+
+// GetSchoolJoinCodeByCode returns the unexpired join code code.
+func (s *Service) GetSchoolJoinCodeByCode(ctx context.Context, code string) (*ent.SchoolJoinCodeEntity, error) {
+	var joinCode ent.SchoolJoinCodeEntity
+	err := s.db.NewSelect().
+		Model(&joinCode).
+		Where("code = ?", code).
+		Where("expires_at > ?", time.Now()).
+		Scan(ctx)
+	if err != nil {
+		return nil, translateError(err, "school_join_code", nil)
+	}
+	return &joinCode, nil
+}
+
+// RotateSchoolJoinCode gives a school code as its join code, replacing the
+// one it had.
+func (s *Service) RotateSchoolJoinCode(ctx context.Context, code *ent.SchoolJoinCodeEntity) error {
+	code.CreatedAt = time.Now()
+	_, err := s.db.NewInsert().
+		Model(code).
+		On("CONFLICT (school_id) DO UPDATE").
+		Set("code = EXCLUDED.code").
+		Set("expires_at = EXCLUDED.expires_at").
+		Set("created_by = EXCLUDED.created_by").
+		Set("created_at = EXCLUDED.created_at").
+		Returning("NULL").
+		Exec(ctx)
+	if err != nil {
+		return translateError(err, "school_join_code", code.SchoolID)
+	}
+	return nil
+}
+
+func (s *Service) DeleteSchoolJoinCode(ctx context.Context, schoolID uuid.UUID) error {
+	res, err := s.db.NewDelete().
+		Model((*ent.SchoolJoinCodeEntity)(nil)).
+		Where("school_id = ?", schoolID).
+		Exec(ctx)
+	if err != nil {
+		return translateError(err, "school_join_code", schoolID)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		if err == nil {
+			err = base.NotFoundError{Resource: "school_join_code", ID: schoolID.String()}
+		}
+		return err
+	}
+	return nil
+}
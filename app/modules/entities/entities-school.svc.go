@@ -2,12 +2,13 @@ package entities
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
 	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+	"github.com/easy-attend-serviceV3/app/utils/base"
 	"github.com/google/uuid"
+	"github.com/uptrace/bun"
 )
 
 var _ entitiesinf.SchoolEntity = (*Service)(nil)
@@ -16,7 +17,7 @@ func (s *Service) GetListSchool(ctx context.Context) ([]*ent.SchoolEntity, error
 	var schools []*ent.SchoolEntity
 	err := s.db.NewSelect().Model(&schools).Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "school", nil)
 	}
 	return schools, nil
 }
@@ -29,7 +30,7 @@ func (s *Service) GetSchoolsByTeacherID(ctx context.Context, teacherID uuid.UUID
 		Where("t.id = ?", teacherID).
 		Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "school", nil)
 	}
 	return schools, nil
 }
@@ -38,7 +39,7 @@ func (s *Service) GetByIDSchool(ctx context.Context, id uuid.UUID) (*ent.SchoolE
 	var school ent.SchoolEntity
 	err := s.db.NewSelect().Model(&school).Where("id = ?", id).Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "school", id)
 	}
 	return &school, nil
 }
@@ -47,29 +48,11 @@ func (s *Service) GetSchoolByName(ctx context.Context, name string) (*ent.School
 	var school ent.SchoolEntity
 	err := s.db.NewSelect().Model(&school).Where("name = ?", name).Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "school", name)
 	}
 	return &school, nil
 }
 
-func (s *Service) FindOrCreateSchoolByName(ctx context.Context, name string) (*ent.SchoolEntity, error) {
-	// ลองหาโรงเรียนที่มีชื่อนี้อยู่แล้ว
-	school, err := s.GetSchoolByName(ctx, name)
-	if err == nil {
-		// พบโรงเรียนแล้ว ส่งกลับ
-		return school, nil
-	}
-
-	// ไม่พบโรงเรียน สร้างใหม่
-	// ใช้ข้อมูลเริ่มต้นสำหรับ address และ phone
-	newSchool, createErr := s.CreateSchool(ctx, name, "", "")
-	if createErr != nil {
-		return nil, fmt.Errorf("failed to create school: %w", createErr)
-	}
-
-	return newSchool, nil
-}
-
 func (s *Service) CreateSchool(ctx context.Context, name, address, phone string) (*ent.SchoolEntity, error) {
 	school := &ent.SchoolEntity{
 		ID:      uuid.New(),
@@ -82,12 +65,12 @@ func (s *Service) CreateSchool(ctx context.Context, name, address, phone string)
 	school.UpdatedAt = time.Now()
 	_, err := s.db.NewInsert().Model(school).Exec(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "school", nil)
 	}
 	return school, nil
 }
 
-func (s *Service) UpdateSchool(ctx context.Context, id uuid.UUID, name, address, phone string) (*ent.SchoolEntity, error) {
+func (s *Service) UpdateSchool(ctx context.Context, id uuid.UUID, name, address, phone string, version *time.Time) (*ent.SchoolEntity, error) {
 	school, err := s.GetByIDSchool(ctx, id)
 	if err != nil {
 		return nil, err
@@ -95,25 +78,98 @@ func (s *Service) UpdateSchool(ctx context.Context, id uuid.UUID, name, address,
 	school.Name = name
 	school.Address = address
 	school.Phone = phone
-	_, err = s.db.NewUpdate().Model(school).Where("id = ?", id).Exec(ctx)
+	school.UpdatedAt = time.Now()
+	res, err := s.db.NewUpdate().Model(school).Where("id = ?", id).ApplyQueryBuilder(atVersion(version)).Exec(ctx)
 	if err != nil {
+		return nil, translateError(err, "school", id)
+	}
+	if err := s.checkWritten(ctx, res, (*ent.SchoolEntity)(nil), "school", id, version); err != nil {
 		return nil, err
 	}
 	return school, nil
 }
 
-func (s *Service) DeleteSchool(ctx context.Context, id uuid.UUID) error {
-	_, err := s.db.NewDelete().Model(&ent.SchoolEntity{}).Where("id = ?", id).Exec(ctx)
-	return err
+func (s *Service) DeleteSchool(ctx context.Context, id uuid.UUID, version *time.Time) error {
+	res, err := s.db.NewDelete().Model(&ent.SchoolEntity{}).Where("id = ?", id).ApplyQueryBuilder(atVersion(version)).Exec(ctx)
+	if err != nil {
+		return translateDeleteError(err, "school", id)
+	}
+	return s.checkWritten(ctx, res, (*ent.SchoolEntity)(nil), "school", id, version)
 }
 
 func (s *Service) CheckExistSchool(ctx context.Context, id uuid.UUID) (bool, error) {
-	count, err := s.db.NewSelect().Model(&ent.SchoolEntity{}).Where("id = ?", id).Count(ctx)
+	count, err := s.db.NewSelect().Model((*ent.SchoolEntity)(nil)).Where("id = ?", id).ApplyQueryBuilder(ent.ScopeSchool(ctx, (*ent.SchoolEntity)(nil))).Count(ctx)
 	if err != nil {
-		return false, err
+		return false, translateError(err, "school", id)
 	}
 	if count == 0 {
-		return false, fmt.Errorf("school with id %s does not exist", id)
+		return false, base.NotFoundError{Resource: "school", ID: id.String()}
 	}
 	return true, nil
 }
+
+// schoolTables are the tables whose rows belong to a school through their
+// school_id, and follow it when schools are merged.
+var schoolTables = []string{"teachers", "classrooms", "students", "api_keys", "school_domains", "teacher_invitations"}
+
+// MergeSchools moves everything of the schools from into the school into and
+// deletes them, all or nothing. Their join codes are dropped rather than
+// moved, since a school has one. The sessions of the teachers moved are
+// revoked, since their tokens still name the deleted school.
+func (s *Service) MergeSchools(ctx context.Context, into uuid.UUID, from []uuid.UUID) error {
+	if len(from) == 0 {
+		return nil
+	}
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		exists, err := tx.NewSelect().Model((*ent.SchoolEntity)(nil)).Where("id = ?", into).Exists(ctx)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return base.NotFoundError{Resource: "school", ID: into.String()}
+		}
+
+		// Before the teachers move, while they can still be told apart
+		_, err = revokeSessionsIn(ctx, tx, func(q *bun.UpdateQuery) *bun.UpdateQuery {
+			return q.Where("teacher_id IN (SELECT id FROM teachers WHERE school_id IN (?))", bun.In(from))
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, table := range schoolTables {
+			if _, err := tx.NewRaw(
+				"UPDATE ? SET school_id = ? WHERE school_id IN (?)",
+				bun.Ident(table), into, bun.In(from),
+			).Exec(ctx); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.NewDelete().
+			Model((*ent.SchoolJoinCodeEntity)(nil)).
+			Where("school_id IN (?)", bun.In(from)).
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		res, err := tx.NewDelete().
+			Model((*ent.SchoolEntity)(nil)).
+			Where("id IN (?)", bun.In(from)).
+			Where("id <> ?", into).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || int(n) != len(from) {
+			if err == nil {
+				err = base.NotFoundError{Resource: "school"}
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return translateError(err, "school", into)
+	}
+	return nil
+}
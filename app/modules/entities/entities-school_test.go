@@ -0,0 +1,34 @@
+package entities
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func Test_MergeSchools(t *testing.T) {
+	s, rec := newRecorderService(t)
+	into, from := uuid.New(), uuid.New()
+
+	// The recorder deletes nothing, so the merge fails at the end; what
+	// matters is what it sent before
+	_ = s.MergeSchools(context.Background(), into, []uuid.UUID{from})
+
+	revoked, moved := -1, -1
+	for i, query := range rec.take() {
+		switch {
+		case strings.HasPrefix(query, `UPDATE "teacher_sessions"`) && strings.Contains(query, "SELECT id FROM teachers WHERE school_id IN ('"+from.String()+"')"):
+			revoked = i
+		case strings.HasPrefix(query, `UPDATE "teachers"`):
+			moved = i
+		}
+	}
+	if revoked < 0 {
+		t.Fatal("sessions of the teachers moved are not revoked")
+	}
+	if moved < revoked {
+		t.Errorf("teachers moved at query %d, before their sessions were revoked at %d", moved, revoked)
+	}
+}
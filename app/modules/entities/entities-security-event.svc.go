@@ -0,0 +1,18 @@
+package entities
+
+import (
+	"context"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+)
+
+var _ entitiesinf.SecurityEventEntity = (*Service)(nil)
+
+// CreateSecurityEvent appends event to the security log.
+func (s *Service) CreateSecurityEvent(ctx context.Context, event *ent.SecurityEventEntity) error {
+	event.CreatedAt = time.Now()
+	_, err := s.db.NewInsert().Model(event).Exec(ctx)
+	return err
+}
@@ -0,0 +1,170 @@
+package entities
+
+import (
+	"context"
+
+	entitiesdto "github.com/easy-attend-serviceV3/app/modules/entities/dto"
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+var _ entitiesinf.StudentClassroomEntity = (*Service)(nil)
+
+// A student's classroom is kept twice: in classroom_members, with the dates
+// of each placement, and in students.classroom_id. The memberships are the
+// source of truth; students.classroom_id is a copy of the classroom of the
+// membership in effect today, the latest if several are, and NULL if none
+// is. Every write through either table refreshes the copy in its own
+// transaction. A membership dated ahead starts or ends with no write at all,
+// so SyncStudentClassrooms refreshes the copy as the days pass, and the
+// student-classroom command finds and fixes the rows that drifted before.
+
+// currentClassroom is the classroom the student ?TableAlias is a member of
+// today.
+const currentClassroom = `(
+	SELECT cm.classroom_id FROM classroom_members cm
+	WHERE cm.student_id = ?TableAlias.id
+		AND CURRENT_DATE BETWEEN cm.effective_from AND COALESCE(cm.effective_to, 'infinity')
+	ORDER BY cm.effective_from DESC, cm.created_at DESC
+	LIMIT 1
+)`
+
+// syncStudentClassrooms copies the current classroom of the students into
+// students.classroom_id.
+func syncStudentClassrooms(ctx context.Context, db bun.IDB, studentIDs ...uuid.UUID) (int64, error) {
+	if len(studentIDs) == 0 {
+		return 0, nil
+	}
+	res, err := db.NewUpdate().
+		Model((*ent.StudentEntity)(nil)).
+		Set("classroom_id = "+currentClassroom).
+		Where("?TableAlias.id IN (?)", bun.In(studentIDs)).
+		Where("?TableAlias.classroom_id IS DISTINCT FROM " + currentClassroom).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// placeStudent moves a student written through the student API from the
+// classroom from to the classroom to, either of which may be uuid.Nil: the
+// membership in from ends yesterday, or goes if it only started today, and
+// one in to starts today unless the student already is a member there.
+func placeStudent(ctx context.Context, tx bun.Tx, studentID, from, to, teacherID uuid.UUID) error {
+	if from == to {
+		return nil
+	}
+	if from != uuid.Nil {
+		_, err := tx.NewDelete().
+			Model((*ent.ClassroomMemberEntity)(nil)).
+			Where("student_id = ? AND classroom_id = ?", studentID, from).
+			Where("effective_from = CURRENT_DATE").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = tx.NewUpdate().
+			Model((*ent.ClassroomMemberEntity)(nil)).
+			Set("effective_to = CURRENT_DATE - 1").
+			Set("updated_at = CURRENT_TIMESTAMP").
+			Where("student_id = ? AND classroom_id = ?", studentID, from).
+			ApplyQueryBuilder(memberOn("")).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	if to != uuid.Nil {
+		member, err := tx.NewSelect().
+			Model((*ent.ClassroomMemberEntity)(nil)).
+			Where("student_id = ? AND classroom_id = ?", studentID, to).
+			ApplyQueryBuilder(memberOn("")).
+			Exists(ctx)
+		if err != nil {
+			return err
+		}
+		if !member {
+			_, err := tx.NewInsert().Model(&ent.ClassroomMemberEntity{
+				ID:          uuid.New(),
+				ClassroomID: to,
+				StudentID:   studentID,
+				TeacherID:   teacherID,
+			}).Exec(ctx)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	_, err := syncStudentClassrooms(ctx, tx, studentID)
+	return err
+}
+
+// SyncStudentClassrooms copies the current classroom into
+// students.classroom_id for every student with a membership whose copy is
+// out of date, as when a transfer dated ahead comes into effect, and returns
+// how many it changed. Students with no membership at all are left to
+// FixStudentClassrooms, which keeps their classroom.
+func (s *Service) SyncStudentClassrooms(ctx context.Context) (int64, error) {
+	res, err := s.db.NewUpdate().
+		Model((*ent.StudentEntity)(nil)).
+		Set("classroom_id = " + currentClassroom).
+		Where("?TableAlias.classroom_id IS DISTINCT FROM " + currentClassroom).
+		Where("EXISTS (SELECT 1 FROM classroom_members cm WHERE cm.student_id = ?TableAlias.id)").
+		Exec(ctx)
+	if err != nil {
+		return 0, translateError(err, "student", nil)
+	}
+	return res.RowsAffected()
+}
+
+// GetStudentClassroomMismatches lists the students whose classroom_id is not
+// the classroom of their membership in effect today.
+func (s *Service) GetStudentClassroomMismatches(ctx context.Context) ([]*entitiesdto.StudentClassroomMismatch, error) {
+	var rows []*entitiesdto.StudentClassroomMismatch
+	err := s.db.NewSelect().
+		Model((*ent.StudentEntity)(nil)).
+		ColumnExpr("?TableAlias.id AS student_id, ?TableAlias.school_id, ?TableAlias.student_code, ?TableAlias.classroom_id").
+		ColumnExpr(currentClassroom+" AS member_classroom_id").
+		ColumnExpr("EXISTS (SELECT 1 FROM classroom_members cm WHERE cm.student_id = ?TableAlias.id) AS has_membership").
+		Where("?TableAlias.classroom_id IS DISTINCT FROM "+currentClassroom).
+		OrderExpr("?TableAlias.school_id, ?TableAlias.student_code").
+		Scan(ctx, &rows)
+	if err != nil {
+		return nil, translateError(err, "student", nil)
+	}
+	return rows, nil
+}
+
+// FixStudentClassrooms resolves the mismatches, all or nothing. A student
+// with a classroom_id and no membership at all predates the memberships
+// being kept: they become a member of that classroom from the day they were
+// created. Every other student gets the classroom of their memberships.
+func (s *Service) FixStudentClassrooms(ctx context.Context, mismatches []*entitiesdto.StudentClassroomMismatch) error {
+	var synced []uuid.UUID
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		for _, m := range mismatches {
+			if m.ClassroomID != nil && !m.HasMembership {
+				_, err := tx.NewRaw(`
+					INSERT INTO classroom_members (id, classroom_id, student_id, effective_from, created_at, updated_at)
+					SELECT gen_random_uuid(), st.classroom_id, st.id, COALESCE(st.created_at::date, CURRENT_DATE), CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+					FROM students st
+					WHERE st.id = ? AND st.classroom_id = ?
+						AND NOT EXISTS (SELECT 1 FROM classroom_members cm WHERE cm.student_id = st.id)`,
+					m.StudentID, *m.ClassroomID).Exec(ctx)
+				if err != nil {
+					return err
+				}
+			}
+			synced = append(synced, m.StudentID)
+		}
+		_, err := syncStudentClassrooms(ctx, tx, synced...)
+		return err
+	})
+	if err != nil {
+		return translateError(err, "student", nil)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package entities
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	entitiesdto "github.com/easy-attend-serviceV3/app/modules/entities/dto"
+	"github.com/easy-attend-serviceV3/app/utils/tenant"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Test_StudentClassroomSync follows the writes that keep a student's
+// memberships and students.classroom_id in step. UpdateStudent is not
+// followed itself, as the recorder finds no student to update; it hands the
+// move to placeStudent, which is.
+func Test_StudentClassroomSync(t *testing.T) {
+	student, from, to := uuid.New(), uuid.New(), uuid.New()
+	place := func(from, to uuid.UUID) func(context.Context, *Service) error {
+		return func(ctx context.Context, s *Service) error {
+			return s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+				return placeStudent(ctx, tx, student, from, to, uuid.Nil)
+			})
+		}
+	}
+
+	// step names what query does to the student
+	step := func(query string) string {
+		has := func(parts ...string) bool {
+			for _, part := range parts {
+				if !strings.Contains(query, part) {
+					return false
+				}
+			}
+			return true
+		}
+		in := func(classroom uuid.UUID) string { return "classroom_id = '" + classroom.String() + "'" }
+		switch {
+		case strings.HasPrefix(query, `INSERT INTO "students"`):
+			return "insert student"
+		case strings.HasPrefix(query, `DELETE FROM "classroom_members"`) && has(in(from), "effective_from = CURRENT_DATE"):
+			return "delete from"
+		case strings.HasPrefix(query, `UPDATE "classroom_members"`) && has(in(from), "effective_to = CURRENT_DATE - 1"):
+			return "close from"
+		case strings.HasPrefix(query, "SELECT EXISTS") && has(in(to)):
+			return "check to"
+		case strings.HasPrefix(query, `INSERT INTO "classroom_members"`) && has("'"+to.String()+"'"):
+			return "add to"
+		case strings.Contains(query, "INSERT INTO classroom_members") && has("st.classroom_id = '"+from.String()+"'"):
+			return "keep classroom"
+		case strings.HasPrefix(query, `UPDATE "students"`) && has("SET classroom_id = ("):
+			if strings.Contains(query, ".id IN (") {
+				return "sync"
+			}
+			if has("EXISTS (SELECT 1 FROM classroom_members cm WHERE cm.student_id = \"student_entity\".id)") {
+				return "sync all"
+			}
+		}
+		return query
+	}
+
+	tests := []struct {
+		name    string
+		missing bool // the student is not a member of to yet
+		write   func(context.Context, *Service) error
+		want    []string
+	}{
+		{
+			name:    "Test create in classroom",
+			missing: true,
+			write: func(ctx context.Context, s *Service) error {
+				_, err := s.CreateStudent(ctx, &entitiesdto.StudentCreateRequest{School: uuid.New(), Classroom: to})
+				return err
+			},
+			want: []string{"insert student", "check to", "add to", "sync"},
+		},
+		{
+			name: "Test create without classroom",
+			write: func(ctx context.Context, s *Service) error {
+				_, err := s.CreateStudent(ctx, &entitiesdto.StudentCreateRequest{School: uuid.New()})
+				return err
+			},
+			want: []string{"insert student"},
+		},
+		{
+			name:    "Test update moves to classroom",
+			missing: true,
+			write:   place(from, to),
+			want:    []string{"delete from", "close from", "check to", "add to", "sync"},
+		},
+		{
+			name:  "Test update moves to classroom already a member of",
+			write: place(from, to),
+			want:  []string{"delete from", "close from", "check to", "sync"},
+		},
+		{
+			name:  "Test update leaves classroom",
+			write: place(from, uuid.Nil),
+			want:  []string{"delete from", "close from", "sync"},
+		},
+		{
+			name:  "Test update keeps classroom",
+			write: place(from, from),
+			want:  nil,
+		},
+		{
+			name: "Test fix student without membership",
+			write: func(ctx context.Context, s *Service) error {
+				return s.FixStudentClassrooms(ctx, []*entitiesdto.StudentClassroomMismatch{{StudentID: student, ClassroomID: &from}})
+			},
+			want: []string{"keep classroom", "sync"},
+		},
+		{
+			name: "Test fix drifted student",
+			write: func(ctx context.Context, s *Service) error {
+				return s.FixStudentClassrooms(ctx, []*entitiesdto.StudentClassroomMismatch{{StudentID: student, ClassroomID: &from, MemberClassroomID: &to, HasMembership: true}})
+			},
+			want: []string{"sync"},
+		},
+		{
+			name: "Test scheduled sync",
+			write: func(ctx context.Context, s *Service) error {
+				_, err := s.SyncStudentClassrooms(ctx)
+				return err
+			},
+			want: []string{"sync all"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, rec := newRecorderService(t)
+			rec.missing = tt.missing
+			if err := tt.write(tenant.Bypass(context.Background()), s); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, query := range rec.take() {
+				got = append(got, step(query))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got steps\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
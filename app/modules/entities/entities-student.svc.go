@@ -8,6 +8,7 @@ import (
 	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
 	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
 	"github.com/google/uuid"
+	"github.com/uptrace/bun"
 )
 
 var _ entitiesinf.StudentEntity = (*Service)(nil)
@@ -23,22 +24,29 @@ func (s *Service) CreateStudent(ctx context.Context, req *entitiesdto.StudentCre
 		FirstName:   req.FirstName,
 		LastName:    req.LastName,
 		Phone:       req.Phone,
+		PhotoURL:    req.PhotoURL,
 	}
 	student.CreatedAt = time.Now()
 	student.UpdatedAt = time.Now()
-	_, err := s.db.NewInsert().Model(student).Exec(ctx)
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(student).Exec(ctx); err != nil {
+			return err
+		}
+		return placeStudent(ctx, tx, student.ID, uuid.Nil, student.ClassroomID, req.TeacherID)
+	})
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "student", nil)
 	}
 	return student, nil
 }
 
-func (s *Service) UpdateStudent(ctx context.Context, id uuid.UUID, req *entitiesdto.StudentUpdateRequest) (*ent.StudentEntity, error) {
+func (s *Service) UpdateStudent(ctx context.Context, id uuid.UUID, req *entitiesdto.StudentUpdateRequest, version *time.Time) (*ent.StudentEntity, error) {
 	student := &ent.StudentEntity{}
 	err := s.db.NewSelect().Model(student).Where("id = ?", id).Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "student", id)
 	}
+	from := student.ClassroomID
 	student.SchoolID = req.School
 	student.ClassroomID = req.Classroom
 	student.PrefixID = req.Prefix
@@ -47,10 +55,20 @@ func (s *Service) UpdateStudent(ctx context.Context, id uuid.UUID, req *entities
 	student.FirstName = req.FirstName
 	student.LastName = req.LastName
 	student.Phone = req.Phone
+	student.PhotoURL = req.PhotoURL
 	student.UpdatedAt = time.Now()
-	_, err = s.db.NewUpdate().Model(student).Where("id = ?", id).Exec(ctx)
+	err = s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		res, err := tx.NewUpdate().Model(student).Where("id = ?", id).ApplyQueryBuilder(atVersion(version)).Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if err := s.checkWritten(ctx, res, (*ent.StudentEntity)(nil), "student", id, version); err != nil {
+			return err
+		}
+		return placeStudent(ctx, tx, id, from, student.ClassroomID, req.TeacherID)
+	})
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "student", id)
 	}
 	return student, nil
 }
@@ -59,7 +77,7 @@ func (s *Service) GetListStudent(ctx context.Context, resp *entitiesdto.StudentL
 	var students []*ent.StudentEntity
 	err := s.db.NewSelect().Model(&students).Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "student", nil)
 	}
 	return students, nil
 }
@@ -68,16 +86,16 @@ func (s *Service) GetStudentByID(ctx context.Context, id uuid.UUID, resp *entiti
 	student := &ent.StudentEntity{}
 	err := s.db.NewSelect().Model(student).Where("id = ?", id).Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "student", id)
 	}
 	return student, nil
 }
 
-func (s *Service) DeleteStudent(ctx context.Context, id uuid.UUID) error {
+func (s *Service) DeleteStudent(ctx context.Context, id uuid.UUID, version *time.Time) error {
 	student := &ent.StudentEntity{}
-	_, err := s.db.NewDelete().Model(student).Where("id = ?", id).Exec(ctx)
+	res, err := s.db.NewDelete().Model(student).Where("id = ?", id).ApplyQueryBuilder(atVersion(version)).Exec(ctx)
 	if err != nil {
-		return err
+		return translateDeleteError(err, "student", id)
 	}
-	return nil
+	return s.checkWritten(ctx, res, (*ent.StudentEntity)(nil), "student", id, version)
 }
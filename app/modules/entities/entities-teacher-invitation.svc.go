@@ -0,0 +1,96 @@
+package entities
+
+import (
+	"context"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+var _ entitiesinf.TeacherInvitationEntity = (*Service)(nil)
+
+func (s *Service) CreateTeacherInvitation(ctx context.Context, invitation *ent.TeacherInvitationEntity) error {
+	invitation.ID = uuid.New()
+	invitation.CreatedAt = time.Now()
+	if _, err := s.db.NewInsert().Model(invitation).Exec(ctx); err != nil {
+		return translateError(err, "teacher_invitation", nil)
+	}
+	return nil
+}
+
+// GetListTeacherInvitation lists the invitations of a school, newest first.
+func (s *Service) GetListTeacherInvitation(ctx context.Context, schoolID uuid.UUID) ([]*ent.TeacherInvitationEntity, error) {
+	var invitations []*ent.TeacherInvitationEntity
+	err := s.db.NewSelect().
+		Model(&invitations).
+		Where("school_id = ?", schoolID).
+		Order("created_at DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, translateError(err, "teacher_invitation", nil)
+	}
+	return invitations, nil
+}
+
+// GetPendingTeacherInvitation returns the unexpired, unaccepted invitation
+// with token hash.
+func (s *Service) GetPendingTeacherInvitation(ctx context.Context, hash string) (*ent.TeacherInvitationEntity, error) {
+	var invitation ent.TeacherInvitationEntity
+	err := s.db.NewSelect().
+		Model(&invitation).
+		Where("token_hash = ?", hash).
+		Where("accepted_at IS NULL").
+		Where("expires_at > ?", time.Now()).
+		Scan(ctx)
+	if err != nil {
+		return nil, translateError(err, "teacher_invitation", nil)
+	}
+	return &invitation, nil
+}
+
+func (s *Service) DeleteTeacherInvitation(ctx context.Context, schoolID, id uuid.UUID) error {
+	res, err := s.db.NewDelete().
+		Model((*ent.TeacherInvitationEntity)(nil)).
+		Where("id = ?", id).
+		Where("school_id = ?", schoolID).
+		Exec(ctx)
+	if err != nil {
+		return translateError(err, "teacher_invitation", id)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		if err == nil {
+			err = base.NotFoundError{Resource: "teacher_invitation", ID: id.String()}
+		}
+		return err
+	}
+	return nil
+}
+
+// acceptTeacherInvitation marks invitation id accepted by teacherID within
+// tx. An invitation accepted or expired meanwhile is a NotFoundError, so two
+// registrations cannot share one.
+func acceptTeacherInvitation(ctx context.Context, tx bun.Tx, id, teacherID uuid.UUID) error {
+	now := time.Now()
+	res, err := tx.NewUpdate().
+		Model((*ent.TeacherInvitationEntity)(nil)).
+		Set("accepted_at = ?", now).
+		Set("accepted_by = ?", teacherID).
+		Where("id = ?", id).
+		Where("accepted_at IS NULL").
+		Where("expires_at > ?", now).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		if err == nil {
+			err = base.NotFoundError{Resource: "teacher_invitation", ID: id.String()}
+		}
+		return err
+	}
+	return nil
+}
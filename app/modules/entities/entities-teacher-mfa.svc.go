@@ -0,0 +1,190 @@
+package entities
+
+import (
+	"context"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+var _ entitiesinf.TeacherMFAEntity = (*Service)(nil)
+
+func (s *Service) GetTeacherMFA(ctx context.Context, teacherID uuid.UUID) (*ent.TeacherMFAEntity, error) {
+	var mfa ent.TeacherMFAEntity
+	if err := s.db.NewSelect().Model(&mfa).Where("teacher_id = ?", teacherID).Scan(ctx); err != nil {
+		return nil, translateError(err, "teacher_mfa", teacherID)
+	}
+	return &mfa, nil
+}
+
+// EnrollTeacherMFA stores a new, unconfirmed secret, replacing one that was
+// never confirmed. A teacher whose secret is confirmed gets a ConflictError.
+func (s *Service) EnrollTeacherMFA(ctx context.Context, mfa *ent.TeacherMFAEntity) error {
+	now := time.Now()
+	mfa.ConfirmedAt = nil
+	mfa.LastStep = 0
+	mfa.CreatedAt = now
+	mfa.UpdatedAt = now
+
+	res, err := s.db.NewInsert().
+		Model(mfa).
+		On("CONFLICT (teacher_id) DO UPDATE").
+		Set("secret = EXCLUDED.secret").
+		Set("last_step = 0").
+		Set("created_at = EXCLUDED.created_at").
+		Set("updated_at = EXCLUDED.updated_at").
+		Where("teacher_mfa_entity.confirmed_at IS NULL").
+		Returning("NULL").
+		Exec(ctx)
+	if err != nil {
+		return translateError(err, "teacher_mfa", nil)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		if err == nil {
+			err = base.ConflictError{Resource: "teacher_mfa"}
+		}
+		return err
+	}
+	return nil
+}
+
+// ConfirmTeacherMFA turns the teacher's pending secret on, recording step as
+// used, and gives them a fresh set of recovery codes.
+func (s *Service) ConfirmTeacherMFA(ctx context.Context, teacherID uuid.UUID, step int64, codes []*ent.TeacherRecoveryCodeEntity) error {
+	now := time.Now()
+	return s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		res, err := tx.NewUpdate().
+			Model((*ent.TeacherMFAEntity)(nil)).
+			Set("confirmed_at = ?", now).
+			Set("last_step = ?", step).
+			Set("updated_at = ?", now).
+			Where("teacher_id = ?", teacherID).
+			Where("confirmed_at IS NULL").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			if err == nil {
+				err = base.NotFoundError{Resource: "teacher_mfa", ID: teacherID.String()}
+			}
+			return err
+		}
+		return replaceRecoveryCodes(ctx, tx, teacherID, codes)
+	})
+}
+
+// UseTeacherMFAStep records step as the last one used, or returns
+// NotFoundError when it is not later than the last one, i.e. a replay.
+func (s *Service) UseTeacherMFAStep(ctx context.Context, teacherID uuid.UUID, step int64) error {
+	res, err := s.db.NewUpdate().
+		Model((*ent.TeacherMFAEntity)(nil)).
+		Set("last_step = ?", step).
+		Set("updated_at = ?", time.Now()).
+		Where("teacher_id = ?", teacherID).
+		Where("confirmed_at IS NOT NULL").
+		Where("last_step < ?", step).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		if err == nil {
+			err = base.NotFoundError{Resource: "teacher_mfa", ID: teacherID.String()}
+		}
+		return err
+	}
+	return nil
+}
+
+// DeleteTeacherMFA turns MFA off, dropping the secret and recovery codes.
+func (s *Service) DeleteTeacherMFA(ctx context.Context, teacherID uuid.UUID) error {
+	return s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewDelete().
+			Model((*ent.TeacherRecoveryCodeEntity)(nil)).
+			Where("teacher_id = ?", teacherID).
+			Exec(ctx); err != nil {
+			return err
+		}
+		res, err := tx.NewDelete().
+			Model((*ent.TeacherMFAEntity)(nil)).
+			Where("teacher_id = ?", teacherID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			if err == nil {
+				err = base.NotFoundError{Resource: "teacher_mfa", ID: teacherID.String()}
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// ReplaceRecoveryCodes discards the teacher's recovery codes, used or not,
+// for codes.
+func (s *Service) ReplaceRecoveryCodes(ctx context.Context, teacherID uuid.UUID, codes []*ent.TeacherRecoveryCodeEntity) error {
+	return s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return replaceRecoveryCodes(ctx, tx, teacherID, codes)
+	})
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx bun.Tx, teacherID uuid.UUID, codes []*ent.TeacherRecoveryCodeEntity) error {
+	if _, err := tx.NewDelete().
+		Model((*ent.TeacherRecoveryCodeEntity)(nil)).
+		Where("teacher_id = ?", teacherID).
+		Exec(ctx); err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, code := range codes {
+		code.ID = uuid.New()
+		code.TeacherID = teacherID
+		code.CreatedAt = now
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	if _, err := tx.NewInsert().Model(&codes).Exec(ctx); err != nil {
+		return translateError(err, "teacher_recovery_code", nil)
+	}
+	return nil
+}
+
+// ConsumeRecoveryCode marks the teacher's unused code with hash as used, or
+// returns NotFoundError.
+func (s *Service) ConsumeRecoveryCode(ctx context.Context, teacherID uuid.UUID, hash string) error {
+	res, err := s.db.NewUpdate().
+		Model((*ent.TeacherRecoveryCodeEntity)(nil)).
+		Set("used_at = ?", time.Now()).
+		Where("teacher_id = ?", teacherID).
+		Where("code_hash = ?", hash).
+		Where("used_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		if err == nil {
+			err = base.NotFoundError{Resource: "teacher_recovery_code"}
+		}
+		return err
+	}
+	return nil
+}
+
+// CountRecoveryCodes returns how many of the teacher's recovery codes are
+// still unused.
+func (s *Service) CountRecoveryCodes(ctx context.Context, teacherID uuid.UUID) (int, error) {
+	return s.db.NewSelect().
+		Model((*ent.TeacherRecoveryCodeEntity)(nil)).
+		Where("teacher_id = ?", teacherID).
+		Where("used_at IS NULL").
+		Count(ctx)
+}
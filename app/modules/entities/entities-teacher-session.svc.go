@@ -0,0 +1,150 @@
+package entities
+
+import (
+	"context"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+var _ entitiesinf.TeacherSessionEntity = (*Service)(nil)
+
+// CreateTeacherSession starts a session with its first refresh token.
+func (s *Service) CreateTeacherSession(ctx context.Context, session *ent.TeacherSessionEntity, token *ent.RefreshTokenEntity) error {
+	now := time.Now()
+	if session.ID == uuid.Nil {
+		session.ID = uuid.New()
+	}
+	session.CreatedAt = now
+	session.LastSeenAt = now
+	session.ExpiresAt = token.ExpiresAt
+
+	token.ID = uuid.New()
+	token.FamilyID = session.ID
+	token.TeacherID = session.TeacherID
+	token.CreatedAt = now
+
+	return s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		// Expired sessions of the same teacher are of no further use.
+		if _, err := tx.NewDelete().
+			Model((*ent.TeacherSessionEntity)(nil)).
+			Where("teacher_id = ?", session.TeacherID).
+			Where("expires_at <= ?", now).
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		if _, err := tx.NewInsert().Model(session).Exec(ctx); err != nil {
+			return translateError(err, "teacher_session", nil)
+		}
+		if _, err := tx.NewInsert().Model(token).Exec(ctx); err != nil {
+			return translateError(err, "refresh_token", nil)
+		}
+		return nil
+	})
+}
+
+// GetActiveTeacherSessions lists the sessions of a teacher that have not been
+// revoked or expired, most recently used first.
+func (s *Service) GetActiveTeacherSessions(ctx context.Context, teacherID uuid.UUID) ([]*ent.TeacherSessionEntity, error) {
+	var sessions []*ent.TeacherSessionEntity
+	err := s.db.NewSelect().
+		Model(&sessions).
+		Where("teacher_id = ?", teacherID).
+		Where("revoked_at IS NULL").
+		Where("expires_at > ?", time.Now()).
+		Order("last_seen_at DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// TouchTeacherSession records that the session was just used and reports
+// whether it is still active.
+func (s *Service) TouchTeacherSession(ctx context.Context, id uuid.UUID) (bool, error) {
+	now := time.Now()
+	res, err := s.db.NewUpdate().
+		Model((*ent.TeacherSessionEntity)(nil)).
+		Set("last_seen_at = ?", now).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Where("expires_at > ?", now).
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// RevokeTeacherSession ends one session of a teacher. A session of another
+// teacher is reported as not found.
+func (s *Service) RevokeTeacherSession(ctx context.Context, teacherID, id uuid.UUID) error {
+	revoked, err := s.revokeSessions(ctx, func(q *bun.UpdateQuery) *bun.UpdateQuery {
+		return q.Where("id = ?", id).Where("teacher_id = ?", teacherID)
+	})
+	if err != nil {
+		return err
+	}
+	if len(revoked) == 0 {
+		return base.NotFoundError{Resource: "teacher_session", ID: id.String()}
+	}
+	return nil
+}
+
+// RevokeTeacherSessions ends every session of a teacher and returns their IDs.
+func (s *Service) RevokeTeacherSessions(ctx context.Context, teacherID uuid.UUID) ([]uuid.UUID, error) {
+	return s.revokeSessions(ctx, func(q *bun.UpdateQuery) *bun.UpdateQuery {
+		return q.Where("teacher_id = ?", teacherID)
+	})
+}
+
+// RevokeOtherTeacherSessions ends every session of a teacher but keepID and
+// returns their IDs.
+func (s *Service) RevokeOtherTeacherSessions(ctx context.Context, teacherID, keepID uuid.UUID) ([]uuid.UUID, error) {
+	return s.revokeSessions(ctx, func(q *bun.UpdateQuery) *bun.UpdateQuery {
+		return q.Where("teacher_id = ?", teacherID).Where("id <> ?", keepID)
+	})
+}
+
+// revokeSessions revokes the active sessions matched by where together with
+// their refresh tokens.
+func (s *Service) revokeSessions(ctx context.Context, where func(*bun.UpdateQuery) *bun.UpdateQuery) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		ids, err = revokeSessionsIn(ctx, tx, where)
+		return err
+	})
+	return ids, err
+}
+
+// revokeSessionsIn is revokeSessions within the transaction tx.
+func revokeSessionsIn(ctx context.Context, tx bun.Tx, where func(*bun.UpdateQuery) *bun.UpdateQuery) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	now := time.Now()
+	q := tx.NewUpdate().
+		Model((*ent.TeacherSessionEntity)(nil)).
+		Set("revoked_at = ?", now).
+		Where("revoked_at IS NULL")
+	if err := where(q).Returning("id").Scan(ctx, &ids); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	_, err := tx.NewUpdate().
+		Model((*ent.RefreshTokenEntity)(nil)).
+		Set("revoked_at = ?", now).
+		Where("family_id IN (?)", bun.In(ids)).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	return ids, err
+}
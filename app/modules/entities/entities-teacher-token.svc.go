@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"context"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+var _ entitiesinf.TeacherTokenEntity = (*Service)(nil)
+
+// CreateTeacherToken stores token, discarding the teacher's unused tokens of
+// the same purpose so only the latest email works.
+func (s *Service) CreateTeacherToken(ctx context.Context, token *ent.TeacherTokenEntity) error {
+	token.ID = uuid.New()
+	token.CreatedAt = time.Now()
+
+	return s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewDelete().
+			Model((*ent.TeacherTokenEntity)(nil)).
+			Where("teacher_id = ?", token.TeacherID).
+			Where("purpose = ?", token.Purpose).
+			Where("used_at IS NULL").
+			Exec(ctx); err != nil {
+			return err
+		}
+		if _, err := tx.NewInsert().Model(token).Exec(ctx); err != nil {
+			return translateError(err, "teacher_token", nil)
+		}
+		return nil
+	})
+}
+
+// GetTeacherToken returns the unused, unexpired token with hash without using
+// it up. Any other token is reported as not found.
+func (s *Service) GetTeacherToken(ctx context.Context, purpose, hash string) (*ent.TeacherTokenEntity, error) {
+	var token ent.TeacherTokenEntity
+	err := s.db.NewSelect().
+		Model(&token).
+		Where("token_hash = ?", hash).
+		Where("purpose = ?", purpose).
+		Where("used_at IS NULL").
+		Where("expires_at > ?", time.Now()).
+		Scan(ctx)
+	if err != nil {
+		return nil, translateError(err, "teacher_token", nil)
+	}
+	return &token, nil
+}
+
+// ConsumeTeacherToken marks the unused, unexpired token with hash as used and
+// returns it. Any other token is reported as not found.
+func (s *Service) ConsumeTeacherToken(ctx context.Context, purpose, hash string) (*ent.TeacherTokenEntity, error) {
+	var token ent.TeacherTokenEntity
+	now := time.Now()
+	res, err := s.db.NewUpdate().
+		Model(&token).
+		Set("used_at = ?", now).
+		Where("token_hash = ?", hash).
+		Where("purpose = ?", purpose).
+		Where("used_at IS NULL").
+		Where("expires_at > ?", now).
+		Returning("*").
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		if err == nil {
+			err = base.NotFoundError{Resource: "teacher_token"}
+		}
+		return nil, err
+	}
+	return &token, nil
+}
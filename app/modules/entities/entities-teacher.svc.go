@@ -8,6 +8,7 @@ import (
 	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
 	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
 	"github.com/google/uuid"
+	"github.com/uptrace/bun"
 )
 
 var _ entitiesinf.TeacherEntity = (*Service)(nil)
@@ -30,10 +31,45 @@ func (s *Service) CreateTeacher(ctx context.Context, req *entitiesdto.TeacherCre
 	}
 	teacher.CreatedAt = time.Now()
 	teacher.UpdatedAt = time.Now()
+	if req.InvitationID != nil {
+		// The invitation reached the teacher at this email
+		teacher.EmailVerifiedAt = &teacher.CreatedAt
+	}
 
-	_, err := s.db.NewInsert().Model(teacher).Exec(ctx)
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(teacher).Exec(ctx); err != nil {
+			return err
+		}
+		if req.InvitationID != nil {
+			if err := acceptTeacherInvitation(ctx, tx, *req.InvitationID, teacher.ID); err != nil {
+				return err
+			}
+		}
+		if req.ClassroomID != nil {
+			// The teacher's own classroom is the one they are homeroom teacher of
+			ct := &ent.ClassroomTeacherEntity{
+				ID:          uuid.New(),
+				ClassroomID: *req.ClassroomID,
+				TeacherID:   teacher.ID,
+				Role:        ent.ClassroomRoleHomeroom,
+				CreatedAt:   teacher.CreatedAt,
+				UpdatedAt:   teacher.CreatedAt,
+			}
+			if _, err := tx.NewInsert().Model(ct).Exec(ctx); err != nil {
+				return err
+			}
+		}
+		if req.Role == "" {
+			return nil
+		}
+		_, err := tx.NewRaw(
+			"INSERT INTO teacher_roles (teacher_id, role_id) SELECT ?, id FROM roles WHERE name = ?",
+			teacher.ID, req.Role,
+		).Exec(ctx)
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "teacher", nil)
 	}
 	return teacher, nil
 }
@@ -42,7 +78,7 @@ func (s *Service) GetListTeacher(ctx context.Context) ([]*ent.TeacherEntity, err
 	var teachers []*ent.TeacherEntity
 	err := s.db.NewSelect().Model(&teachers).Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "teacher", nil)
 	}
 	return teachers, nil
 }
@@ -51,12 +87,12 @@ func (s *Service) GetByIDTeacher(ctx context.Context, id uuid.UUID) (*ent.Teache
 	var teacher ent.TeacherEntity
 	err := s.db.NewSelect().Model(&teacher).Where("id = ?", id).Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "teacher", id)
 	}
 	return &teacher, nil
 }
 
-func (s *Service) UpdateTeacher(ctx context.Context, id uuid.UUID, req *entitiesdto.TeacherUpdateRequest) (*ent.TeacherEntity, error) {
+func (s *Service) UpdateTeacher(ctx context.Context, id uuid.UUID, req *entitiesdto.TeacherUpdateRequest, version *time.Time) (*ent.TeacherEntity, error) {
 	teacher := &ent.TeacherEntity{
 		ID:          id,
 		SchoolID:    req.SchoolID,
@@ -70,22 +106,34 @@ func (s *Service) UpdateTeacher(ctx context.Context, id uuid.UUID, req *entities
 	}
 	teacher.UpdatedAt = time.Now()
 
-	_, err := s.db.NewUpdate().Model(teacher).Where("id = ?", id).Exec(ctx)
+	// Leave password and created_at alone; the request does not carry them.
+	res, err := s.db.NewUpdate().
+		Model(teacher).
+		Column("school_id", "classroom_id", "prefix_id", "gender_id", "first_name", "last_name", "email", "phone", "updated_at").
+		Where("id = ?", id).
+		ApplyQueryBuilder(atVersion(version)).
+		Exec(ctx)
 	if err != nil {
+		return nil, translateError(err, "teacher", id)
+	}
+	if err := s.checkWritten(ctx, res, (*ent.TeacherEntity)(nil), "teacher", id, version); err != nil {
 		return nil, err
 	}
 	return teacher, nil
 }
 
-func (s *Service) DeleteTeacher(ctx context.Context, id uuid.UUID) error {
-	_, err := s.db.NewDelete().Model(&ent.TeacherEntity{}).Where("id = ?", id).Exec(ctx)
-	return err
+func (s *Service) DeleteTeacher(ctx context.Context, id uuid.UUID, version *time.Time) error {
+	res, err := s.db.NewDelete().Model(&ent.TeacherEntity{}).Where("id = ?", id).ApplyQueryBuilder(atVersion(version)).Exec(ctx)
+	if err != nil {
+		return translateDeleteError(err, "teacher", id)
+	}
+	return s.checkWritten(ctx, res, (*ent.TeacherEntity)(nil), "teacher", id, version)
 }
 
 func (s *Service) CheckExistTeacher(ctx context.Context, id uuid.UUID) (bool, error) {
-	count, err := s.db.NewSelect().Model(&ent.TeacherEntity{}).Where("id = ?", id).Count(ctx)
+	count, err := s.db.NewSelect().Model((*ent.TeacherEntity)(nil)).Where("id = ?", id).ApplyQueryBuilder(ent.ScopeSchool(ctx, (*ent.TeacherEntity)(nil))).Count(ctx)
 	if err != nil {
-		return false, err
+		return false, translateError(err, "teacher", id)
 	}
 	return count > 0, nil
 }
@@ -100,8 +148,35 @@ func (s *Service) GetTeacherByEmail(ctx context.Context, email string) (*ent.Tea
 		Scan(ctx)
 
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "teacher", email)
 	}
 
 	return &teacher, nil
 }
+
+// UpdateTeacherPassword replaces the stored password hash.
+func (s *Service) UpdateTeacherPassword(ctx context.Context, id uuid.UUID, password string) error {
+	res, err := s.db.NewUpdate().
+		Model((*ent.TeacherEntity)(nil)).
+		Set("password = ?", password).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return translateError(err, "teacher", id)
+	}
+	return s.checkWritten(ctx, res, (*ent.TeacherEntity)(nil), "teacher", id, nil)
+}
+
+// VerifyTeacherEmail records that the teacher proved they own their email.
+func (s *Service) VerifyTeacherEmail(ctx context.Context, id uuid.UUID) error {
+	res, err := s.db.NewUpdate().
+		Model((*ent.TeacherEntity)(nil)).
+		Set("email_verified_at = COALESCE(email_verified_at, ?)", time.Now()).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return translateError(err, "teacher", id)
+	}
+	return s.checkWritten(ctx, res, (*ent.TeacherEntity)(nil), "teacher", id, nil)
+}
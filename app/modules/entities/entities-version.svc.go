@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// atVersion limits a write to the row version (its updated_at) the client
+// last read, so a concurrent edit makes it match nothing. A nil version leaves
+// the write unconditional.
+func atVersion(version *time.Time) func(bun.QueryBuilder) bun.QueryBuilder {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		if version == nil {
+			return q
+		}
+		return q.Where("updated_at = ?", *version)
+	}
+}
+
+// checkWritten explains a write that matched no row: the row is gone (404) or,
+// for a conditional write, it moved past version (412).
+func (s *Service) checkWritten(ctx context.Context, res sql.Result, model any, resource string, id uuid.UUID, version *time.Time) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+	if version != nil {
+		exists, err := s.db.NewSelect().Model(model).Where("id = ?", id).ApplyQueryBuilder(ent.ScopeSchool(ctx, model)).Exists(ctx)
+		if err != nil {
+			return translateError(err, resource, id)
+		}
+		if exists {
+			return base.PreconditionFailedError{Resource: resource, ID: id.String()}
+		}
+	}
+	return base.NotFoundError{Resource: resource, ID: id.String()}
+}
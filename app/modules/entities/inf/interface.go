@@ -2,6 +2,7 @@ package entitiesinf
 
 import (
 	"context"
+	"time"
 
 	entitiesdto "github.com/easy-attend-serviceV3/app/modules/entities/dto"
 	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
@@ -26,8 +27,15 @@ type StudentEntity interface {
 	CreateStudent(ctx context.Context, req *entitiesdto.StudentCreateRequest) (*ent.StudentEntity, error)
 	GetListStudent(ctx context.Context, resp *entitiesdto.StudentListResponse) ([]*ent.StudentEntity, error)
 	GetStudentByID(ctx context.Context, id uuid.UUID, resp *entitiesdto.StudentInfoResponse) (*ent.StudentEntity, error)
-	UpdateStudent(ctx context.Context, id uuid.UUID, req *entitiesdto.StudentUpdateRequest) (*ent.StudentEntity, error)
-	DeleteStudent(ctx context.Context, id uuid.UUID) error
+	UpdateStudent(ctx context.Context, id uuid.UUID, req *entitiesdto.StudentUpdateRequest, version *time.Time) (*ent.StudentEntity, error)
+	DeleteStudent(ctx context.Context, id uuid.UUID, version *time.Time) error
+}
+
+// StudentClassroomEntity checks students.classroom_id against classroom_members
+type StudentClassroomEntity interface {
+	GetStudentClassroomMismatches(ctx context.Context) ([]*entitiesdto.StudentClassroomMismatch, error)
+	FixStudentClassrooms(ctx context.Context, mismatches []*entitiesdto.StudentClassroomMismatch) error
+	SyncStudentClassrooms(ctx context.Context) (int64, error)
 }
 
 // teacher
@@ -36,9 +44,11 @@ type TeacherEntity interface {
 	GetListTeacher(ctx context.Context) ([]*ent.TeacherEntity, error)
 	GetByIDTeacher(ctx context.Context, id uuid.UUID) (*ent.TeacherEntity, error)
 	GetTeacherByEmail(ctx context.Context, email string) (*ent.TeacherEntity, error)
-	UpdateTeacher(ctx context.Context, id uuid.UUID, req *entitiesdto.TeacherUpdateRequest) (*ent.TeacherEntity, error)
-	DeleteTeacher(ctx context.Context, id uuid.UUID) error
+	UpdateTeacher(ctx context.Context, id uuid.UUID, req *entitiesdto.TeacherUpdateRequest, version *time.Time) (*ent.TeacherEntity, error)
+	DeleteTeacher(ctx context.Context, id uuid.UUID, version *time.Time) error
 	CheckExistTeacher(ctx context.Context, id uuid.UUID) (bool, error)
+	UpdateTeacherPassword(ctx context.Context, id uuid.UUID, password string) error
+	VerifyTeacherEmail(ctx context.Context, id uuid.UUID) error
 }
 
 // prefix
@@ -59,11 +69,11 @@ type SchoolEntity interface {
 	GetSchoolsByTeacherID(ctx context.Context, teacherID uuid.UUID) ([]*ent.SchoolEntity, error)
 	GetByIDSchool(ctx context.Context, id uuid.UUID) (*ent.SchoolEntity, error)
 	GetSchoolByName(ctx context.Context, name string) (*ent.SchoolEntity, error)
-	FindOrCreateSchoolByName(ctx context.Context, name string) (*ent.SchoolEntity, error)
 	CreateSchool(ctx context.Context, name, address, phone string) (*ent.SchoolEntity, error)
-	UpdateSchool(ctx context.Context, id uuid.UUID, name, address, phone string) (*ent.SchoolEntity, error)
-	DeleteSchool(ctx context.Context, id uuid.UUID) error
+	UpdateSchool(ctx context.Context, id uuid.UUID, name, address, phone string, version *time.Time) (*ent.SchoolEntity, error)
+	DeleteSchool(ctx context.Context, id uuid.UUID, version *time.Time) error
 	CheckExistSchool(ctx context.Context, id uuid.UUID) (bool, error)
+	MergeSchools(ctx context.Context, into uuid.UUID, from []uuid.UUID) error
 }
 
 // classroom
@@ -71,23 +81,44 @@ type ClassroomEntity interface {
 	GetListClassroom(ctx context.Context) ([]*ent.ClassroomEntity, error)
 	GetClassroomsByTeacherID(ctx context.Context, teacherID uuid.UUID) ([]*ent.ClassroomEntity, error)
 	GetByIDClassroom(ctx context.Context, id uuid.UUID) (*ent.ClassroomEntity, error)
-	CreateClassroom(ctx context.Context, schoolID uuid.UUID, name string) (*ent.ClassroomEntity, error)
-	UpdateClassroom(ctx context.Context, id uuid.UUID, schoolID uuid.UUID, name string) (*ent.ClassroomEntity, error)
-	DeleteClassroom(ctx context.Context, id uuid.UUID) error
+	CreateClassroom(ctx context.Context, schoolID uuid.UUID, name string, teacherID uuid.UUID) (*ent.ClassroomEntity, error)
+	UpdateClassroom(ctx context.Context, id uuid.UUID, schoolID uuid.UUID, name string, version *time.Time) (*ent.ClassroomEntity, error)
+	DeleteClassroom(ctx context.Context, id uuid.UUID, version *time.Time) error
 	CheckExistClassroom(ctx context.Context, id uuid.UUID) (bool, error)
 }
 
+// classroom teacher
+type ClassroomTeacherEntity interface {
+	GetListClassroomTeacher(ctx context.Context, classroomID uuid.UUID) ([]*ent.ClassroomTeacherEntity, error)
+	CreateClassroomTeacher(ctx context.Context, ct *ent.ClassroomTeacherEntity) error
+	UpdateClassroomTeacherRole(ctx context.Context, classroomID, teacherID uuid.UUID, role string) (*ent.ClassroomTeacherEntity, error)
+	DeleteClassroomTeacher(ctx context.Context, classroomID, teacherID uuid.UUID) error
+}
+
+// classroom delegation
+type ClassroomDelegationEntity interface {
+	GetListClassroomDelegation(ctx context.Context, classroomID uuid.UUID) ([]*ent.ClassroomDelegationEntity, error)
+	GetActiveClassroomDelegation(ctx context.Context, classroomID, delegateID uuid.UUID, date string) (*ent.ClassroomDelegationEntity, error)
+	CreateClassroomDelegation(ctx context.Context, d *ent.ClassroomDelegationEntity) error
+	DeleteClassroomDelegation(ctx context.Context, classroomID, id uuid.UUID) error
+}
+
 // classroom member
 type ClassroomMemberEntity interface {
 	CreateClassroomMember(ctx context.Context, req *entitiesdto.ClassroomMemberCreateRequest) (*ent.ClassroomMemberEntity, error)
-	GetListClassroomMember(ctx context.Context, classroomID uuid.UUID) ([]*ent.ClassroomMemberEntity, error)
+	GetListClassroomMember(ctx context.Context, classroomID uuid.UUID, asOf string) ([]*ent.ClassroomMemberEntity, error)
 	GetAllClassroomMembers(ctx context.Context, limit int) ([]*ent.ClassroomMemberEntity, error)
 	GetClassroomMembersByTeacherID(ctx context.Context, teacherID uuid.UUID) ([]*ent.ClassroomMemberEntity, error)
 	GetClassroomMemberByID(ctx context.Context, id uuid.UUID) (*ent.ClassroomMemberEntity, error)
-	UpdateClassroomMember(ctx context.Context, id uuid.UUID, req *entitiesdto.ClassroomMemberUpdateRequest) (*ent.ClassroomMemberEntity, error)
-	DeleteClassroomMember(ctx context.Context, id uuid.UUID) error
+	UpdateClassroomMember(ctx context.Context, id uuid.UUID, req *entitiesdto.ClassroomMemberUpdateRequest, version *time.Time) (*ent.ClassroomMemberEntity, error)
+	TransferClassroomMember(ctx context.Context, id uuid.UUID, req *entitiesdto.ClassroomMemberTransferRequest) (*ent.ClassroomMemberEntity, error)
+	BulkAddClassroomMembers(ctx context.Context, req *entitiesdto.ClassroomMemberBulkRequest) ([]*entitiesdto.ClassroomMemberBulkResult, error)
+	BulkRemoveClassroomMembers(ctx context.Context, req *entitiesdto.ClassroomMemberBulkRequest) ([]*entitiesdto.ClassroomMemberBulkResult, error)
+	CopyClassroomMembers(ctx context.Context, fromClassroomID uuid.UUID, fromDate string, req *entitiesdto.ClassroomMemberBulkRequest) ([]*entitiesdto.ClassroomMemberBulkResult, error)
+	DeleteClassroomMember(ctx context.Context, id uuid.UUID, version *time.Time) error
 	CheckExistClassroomMember(ctx context.Context, id uuid.UUID) (bool, error)
 	GetClassroomMembersByStudentID(ctx context.Context, studentID uuid.UUID) ([]*ent.ClassroomMemberEntity, error)
+	GetClassroomRoster(ctx context.Context, classroomID uuid.UUID, date, session, order string) ([]*entitiesdto.ClassroomRosterRow, error)
 }
 
 // Attendance
@@ -97,9 +128,122 @@ type AttendanceEntity interface {
 	GetAllAttendance(ctx context.Context, limit int) ([]*ent.AttendanceEntity, error)
 	GetAttendanceByTeacherID(ctx context.Context, teacherID uuid.UUID) ([]*ent.AttendanceEntity, error)
 	GetAttendanceByID(ctx context.Context, id uuid.UUID) (*ent.AttendanceEntity, error)
-	UpdateAttendance(ctx context.Context, id uuid.UUID, req *entitiesdto.AttendanceUpdateRequest) (*ent.AttendanceEntity, error)
-	DeleteAttendance(ctx context.Context, id uuid.UUID) error
+	UpdateAttendance(ctx context.Context, id uuid.UUID, req *entitiesdto.AttendanceUpdateRequest, version *time.Time) (*ent.AttendanceEntity, error)
+	DeleteAttendance(ctx context.Context, id uuid.UUID, version *time.Time) error
 	CheckExistAttendance(ctx context.Context, id uuid.UUID) (bool, error)
 	GetAttendanceByStudentID(ctx context.Context, studentID uuid.UUID, date string) (*ent.AttendanceEntity, error)
 	GetAttendanceByClassroomAndDate(ctx context.Context, classroomID uuid.UUID, date string) ([]*ent.AttendanceEntity, error)
 }
+
+// idempotency key
+type IdempotencyKeyEntity interface {
+	ReserveIdempotencyKey(ctx context.Context, key *ent.IdempotencyKeyEntity) (*ent.IdempotencyKeyEntity, error)
+	CompleteIdempotencyKey(ctx context.Context, key *ent.IdempotencyKeyEntity) error
+	DeleteIdempotencyKey(ctx context.Context, key string) error
+}
+
+// refresh token
+type RefreshTokenEntity interface {
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*ent.RefreshTokenEntity, error)
+	RotateRefreshToken(ctx context.Context, current, next *ent.RefreshTokenEntity) (bool, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error
+}
+
+// teacher session
+type TeacherSessionEntity interface {
+	CreateTeacherSession(ctx context.Context, session *ent.TeacherSessionEntity, token *ent.RefreshTokenEntity) error
+	GetActiveTeacherSessions(ctx context.Context, teacherID uuid.UUID) ([]*ent.TeacherSessionEntity, error)
+	TouchTeacherSession(ctx context.Context, id uuid.UUID) (bool, error)
+	RevokeTeacherSession(ctx context.Context, teacherID, id uuid.UUID) error
+	RevokeTeacherSessions(ctx context.Context, teacherID uuid.UUID) ([]uuid.UUID, error)
+	RevokeOtherTeacherSessions(ctx context.Context, teacherID, keepID uuid.UUID) ([]uuid.UUID, error)
+}
+
+// teacher token
+type TeacherTokenEntity interface {
+	CreateTeacherToken(ctx context.Context, token *ent.TeacherTokenEntity) error
+	GetTeacherToken(ctx context.Context, purpose, hash string) (*ent.TeacherTokenEntity, error)
+	ConsumeTeacherToken(ctx context.Context, purpose, hash string) (*ent.TeacherTokenEntity, error)
+}
+
+// teacher mfa
+type TeacherMFAEntity interface {
+	GetTeacherMFA(ctx context.Context, teacherID uuid.UUID) (*ent.TeacherMFAEntity, error)
+	EnrollTeacherMFA(ctx context.Context, mfa *ent.TeacherMFAEntity) error
+	ConfirmTeacherMFA(ctx context.Context, teacherID uuid.UUID, step int64, codes []*ent.TeacherRecoveryCodeEntity) error
+	UseTeacherMFAStep(ctx context.Context, teacherID uuid.UUID, step int64) error
+	DeleteTeacherMFA(ctx context.Context, teacherID uuid.UUID) error
+	ReplaceRecoveryCodes(ctx context.Context, teacherID uuid.UUID, codes []*ent.TeacherRecoveryCodeEntity) error
+	ConsumeRecoveryCode(ctx context.Context, teacherID uuid.UUID, hash string) error
+	CountRecoveryCodes(ctx context.Context, teacherID uuid.UUID) (int, error)
+}
+
+// security event
+type SecurityEventEntity interface {
+	CreateSecurityEvent(ctx context.Context, event *ent.SecurityEventEntity) error
+}
+
+// role
+type RoleEntity interface {
+	GetListRole(ctx context.Context) ([]*ent.RoleEntity, error)
+	GetRoleByName(ctx context.Context, name string) (*ent.RoleEntity, error)
+	GetTeacherRoles(ctx context.Context, teacherID uuid.UUID) ([]*ent.RoleEntity, error)
+	AssignTeacherRole(ctx context.Context, grant *ent.TeacherRoleEntity) error
+	RevokeTeacherRole(ctx context.Context, teacherID, roleID uuid.UUID) error
+	SetRoleMFARequired(ctx context.Context, name string, required bool) (*ent.RoleEntity, error)
+}
+
+// access
+type AccessEntity interface {
+	TeacherCanAccessSchool(ctx context.Context, teacherID, schoolID uuid.UUID) (bool, error)
+	TeacherCanAccessTeacher(ctx context.Context, teacherID, otherID uuid.UUID) (bool, error)
+	TeacherCanAccessClassroom(ctx context.Context, teacherID, classroomID uuid.UUID) (bool, error)
+	TeacherCanManageClassroom(ctx context.Context, teacherID, classroomID uuid.UUID) (bool, error)
+	TeacherCanAccessClassroomMember(ctx context.Context, teacherID, memberID uuid.UUID) (bool, error)
+	TeacherCanAccessStudent(ctx context.Context, teacherID, studentID uuid.UUID) (bool, error)
+	TeacherCanAccessAttendance(ctx context.Context, teacherID, attendanceID uuid.UUID) (bool, error)
+	TeacherCanMarkAttendance(ctx context.Context, teacherID, classroomID uuid.UUID, date string) (bool, error)
+}
+
+// api key
+type APIKeyEntity interface {
+	CreateAPIKey(ctx context.Context, key *ent.APIKeyEntity) error
+	GetListAPIKey(ctx context.Context) ([]*ent.APIKeyEntity, error)
+	GetAPIKeyByID(ctx context.Context, id uuid.UUID) (*ent.APIKeyEntity, error)
+	GetActiveAPIKeyByHash(ctx context.Context, hash string) (*ent.APIKeyEntity, error)
+	UpdateAPIKey(ctx context.Context, key *ent.APIKeyEntity) error
+	RevokeAPIKey(ctx context.Context, id uuid.UUID) error
+	TouchAPIKey(ctx context.Context, id uuid.UUID) error
+}
+
+// school domain
+type SchoolDomainEntity interface {
+	GetSchoolDomain(ctx context.Context, provider, domain string) (*ent.SchoolDomainEntity, error)
+	GetListSchoolDomain(ctx context.Context, schoolID uuid.UUID) ([]*ent.SchoolDomainEntity, error)
+	CreateSchoolDomain(ctx context.Context, domain *ent.SchoolDomainEntity) error
+	DeleteSchoolDomain(ctx context.Context, schoolID, id uuid.UUID) error
+}
+
+// oidc
+type OIDCEntity interface {
+	CreateOIDCState(ctx context.Context, state *ent.OIDCStateEntity) error
+	ConsumeOIDCState(ctx context.Context, provider, hash string) (*ent.OIDCStateEntity, error)
+	GetTeacherIdentity(ctx context.Context, provider, subject string) (*ent.TeacherIdentityEntity, error)
+	LinkTeacherIdentity(ctx context.Context, identity *ent.TeacherIdentityEntity) error
+}
+
+// teacher invitation
+type TeacherInvitationEntity interface {
+	CreateTeacherInvitation(ctx context.Context, invitation *ent.TeacherInvitationEntity) error
+	GetListTeacherInvitation(ctx context.Context, schoolID uuid.UUID) ([]*ent.TeacherInvitationEntity, error)
+	GetPendingTeacherInvitation(ctx context.Context, hash string) (*ent.TeacherInvitationEntity, error)
+	DeleteTeacherInvitation(ctx context.Context, schoolID, id uuid.UUID) error
+}
+
+// school join code
+type SchoolJoinCodeEntity interface {
+	GetSchoolJoinCode(ctx context.Context, schoolID uuid.UUID) (*ent.SchoolJoinCodeEntity, error)
+	GetSchoolJoinCodeByCode(ctx context.Context, code string) (*ent.SchoolJoinCodeEntity, error)
+	RotateSchoolJoinCode(ctx context.Context, code *ent.SchoolJoinCodeEntity) error
+	DeleteSchoolJoinCode(ctx context.Context, schoolID uuid.UUID) error
+}
@@ -0,0 +1,489 @@
+package entities
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	entitiesdto "github.com/easy-attend-serviceV3/app/modules/entities/dto"
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/app/utils/tenant"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+// recorder is a database/sql driver that remembers the SQL bun sends it. It
+// answers a query answer gives rows for with those, counts with count,
+// EXISTS with true unless missing is set and everything else with no rows,
+// which is enough to follow each entity method to its queries.
+type recorder struct {
+	mu      sync.Mutex
+	queries []string
+	count   int64
+	missing bool
+	answer  func(query string) (columns []string, rows [][]driver.Value)
+}
+
+func (r *recorder) Connect(context.Context) (driver.Conn, error) { return &recorderConn{r}, nil }
+func (r *recorder) Driver() driver.Driver                        { return nil }
+
+func (r *recorder) record(query string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries = append(r.queries, query)
+}
+
+func (r *recorder) take() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	queries := r.queries
+	r.queries = nil
+	return queries
+}
+
+type recorderConn struct{ r *recorder }
+
+func (c *recorderConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *recorderConn) Close() error                        { return nil }
+func (c *recorderConn) Begin() (driver.Tx, error)           { return c, nil }
+func (c *recorderConn) Commit() error                       { return nil }
+func (c *recorderConn) Rollback() error                     { return nil }
+
+func (c *recorderConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.r.record(query)
+	return driver.RowsAffected(0), nil
+}
+
+func (c *recorderConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.r.record(query)
+	if c.r.answer != nil {
+		if columns, rows := c.r.answer(query); columns != nil {
+			return &recorderRows{columns: columns, rows: rows}, nil
+		}
+	}
+	switch {
+	case strings.HasPrefix(query, "SELECT count(*)"):
+		return &recorderRows{columns: []string{""}, rows: [][]driver.Value{{c.r.count}}}, nil
+	case strings.HasPrefix(query, "SELECT EXISTS"):
+		return &recorderRows{columns: []string{""}, rows: [][]driver.Value{{!c.r.missing}}}, nil
+	}
+	return &recorderRows{}, nil
+}
+
+type recorderRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *recorderRows) Columns() []string {
+	return r.columns
+}
+
+func (r *recorderRows) Close() error { return nil }
+
+func (r *recorderRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func newRecorderService(t *testing.T) (*Service, *recorder) {
+	t.Helper()
+	rec := &recorder{count: 1}
+	db := bun.NewDB(sql.OpenDB(rec), pgdialect.New())
+	t.Cleanup(func() { db.Close() })
+	return newService(db), rec
+}
+
+func Test_TenantIsolation(t *testing.T) {
+	schoolA := uuid.MustParse("aaaaaaaa-0000-0000-0000-000000000000")
+	id := uuid.MustParse("11111111-0000-0000-0000-000000000000")
+	version := time.Now()
+
+	bySchool := func(alias, column string) string {
+		return `"` + alias + `"."` + column + `" = '` + schoolA.String() + `'`
+	}
+	byClassroom := func(alias string) string {
+		return `"` + alias + `".classroom_id IN (SELECT id FROM classrooms WHERE school_id = '` + schoolA.String() + `')`
+	}
+
+	type call func(ctx context.Context, s *Service) error
+	tests := []struct {
+		name  string
+		scope string
+		calls []call
+	}{
+		{
+			name:  "Test school",
+			scope: bySchool("school_entity", "id"),
+			calls: []call{
+				func(ctx context.Context, s *Service) error { _, err := s.GetListSchool(ctx); return err },
+				func(ctx context.Context, s *Service) error { _, err := s.GetByIDSchool(ctx, id); return err },
+				func(ctx context.Context, s *Service) error { _, err := s.GetSchoolByName(ctx, "a"); return err },
+				func(ctx context.Context, s *Service) error { _, err := s.CheckExistSchool(ctx, id); return err },
+				func(ctx context.Context, s *Service) error { return s.DeleteSchool(ctx, id, &version) },
+				func(ctx context.Context, s *Service) error {
+					_, err := s.db.NewUpdate().Model(&ent.SchoolEntity{ID: id}).WherePK().Exec(ctx)
+					return err
+				},
+			},
+		},
+		{
+			name:  "Test teacher",
+			scope: bySchool("teacher_entity", "school_id"),
+			calls: []call{
+				func(ctx context.Context, s *Service) error { _, err := s.GetListTeacher(ctx); return err },
+				func(ctx context.Context, s *Service) error { _, err := s.GetByIDTeacher(ctx, id); return err },
+				func(ctx context.Context, s *Service) error { _, err := s.GetTeacherByEmail(ctx, "a@b.c"); return err },
+				func(ctx context.Context, s *Service) error { _, err := s.CheckExistTeacher(ctx, id); return err },
+				func(ctx context.Context, s *Service) error { return s.DeleteTeacher(ctx, id, &version) },
+				func(ctx context.Context, s *Service) error { return s.UpdateTeacherPassword(ctx, id, "x") },
+			},
+		},
+		{
+			name:  "Test classroom",
+			scope: bySchool("classroom_entity", "school_id"),
+			calls: []call{
+				func(ctx context.Context, s *Service) error { _, err := s.GetListClassroom(ctx); return err },
+				func(ctx context.Context, s *Service) error { _, err := s.GetByIDClassroom(ctx, id); return err },
+				func(ctx context.Context, s *Service) error { _, err := s.CheckExistClassroom(ctx, id); return err },
+				func(ctx context.Context, s *Service) error { return s.DeleteClassroom(ctx, id, &version) },
+				func(ctx context.Context, s *Service) error {
+					_, err := s.db.NewUpdate().Model(&ent.ClassroomEntity{ID: id, SchoolID: schoolA}).WherePK().Exec(ctx)
+					return err
+				},
+			},
+		},
+		{
+			name:  "Test student",
+			scope: bySchool("student_entity", "school_id"),
+			calls: []call{
+				func(ctx context.Context, s *Service) error {
+					_, err := s.GetListStudent(ctx, &entitiesdto.StudentListResponse{})
+					return err
+				},
+				func(ctx context.Context, s *Service) error {
+					_, err := s.GetStudentByID(ctx, id, &entitiesdto.StudentInfoResponse{})
+					return err
+				},
+				func(ctx context.Context, s *Service) error { return s.DeleteStudent(ctx, id, &version) },
+				func(ctx context.Context, s *Service) error {
+					_, err := s.GetStudentClassroomMismatches(ctx)
+					return err
+				},
+				func(ctx context.Context, s *Service) error {
+					_, err := s.BulkAddClassroomMembers(ctx, &entitiesdto.ClassroomMemberBulkRequest{ClassroomID: id, StudentCodes: []string{"001"}})
+					return err
+				},
+				func(ctx context.Context, s *Service) error {
+					_, err := s.db.NewUpdate().Model(&ent.StudentEntity{ID: id, SchoolID: schoolA}).WherePK().Exec(ctx)
+					return err
+				},
+			},
+		},
+		{
+			name:  "Test classroom member",
+			scope: byClassroom("classroom_member_entity"),
+			calls: []call{
+				func(ctx context.Context, s *Service) error {
+					_, err := s.GetListClassroomMember(ctx, id, "")
+					return err
+				},
+				func(ctx context.Context, s *Service) error { _, err := s.GetAllClassroomMembers(ctx, 10); return err },
+				func(ctx context.Context, s *Service) error { _, err := s.GetClassroomMemberByID(ctx, id); return err },
+				func(ctx context.Context, s *Service) error {
+					_, err := s.CheckExistClassroomMember(ctx, id)
+					return err
+				},
+				func(ctx context.Context, s *Service) error { return s.DeleteClassroomMember(ctx, id, &version) },
+				func(ctx context.Context, s *Service) error {
+					_, err := s.GetClassroomRoster(ctx, id, "", "", entitiesdto.RosterOrderSeat)
+					return err
+				},
+				func(ctx context.Context, s *Service) error {
+					_, err := s.TransferClassroomMember(ctx, id, &entitiesdto.ClassroomMemberTransferRequest{Date: "2026-01-05"})
+					return err
+				},
+				func(ctx context.Context, s *Service) error {
+					_, err := s.CopyClassroomMembers(ctx, id, "", &entitiesdto.ClassroomMemberBulkRequest{ClassroomID: id})
+					return err
+				},
+			},
+		},
+		{
+			name:  "Test classroom teacher",
+			scope: byClassroom("classroom_teacher_entity"),
+			calls: []call{
+				func(ctx context.Context, s *Service) error {
+					_, err := s.GetListClassroomTeacher(ctx, id)
+					return err
+				},
+				func(ctx context.Context, s *Service) error {
+					_, err := s.UpdateClassroomTeacherRole(ctx, id, id, ent.ClassroomRoleSubject)
+					return err
+				},
+				func(ctx context.Context, s *Service) error { return s.DeleteClassroomTeacher(ctx, id, id) },
+			},
+		},
+		{
+			name:  "Test classroom delegation",
+			scope: byClassroom("classroom_delegation_entity"),
+			calls: []call{
+				func(ctx context.Context, s *Service) error {
+					_, err := s.GetListClassroomDelegation(ctx, id)
+					return err
+				},
+				func(ctx context.Context, s *Service) error {
+					_, err := s.GetActiveClassroomDelegation(ctx, id, id, "2025-01-01")
+					return err
+				},
+				func(ctx context.Context, s *Service) error { return s.DeleteClassroomDelegation(ctx, id, id) },
+			},
+		},
+		{
+			name:  "Test attendance",
+			scope: byClassroom("attendance_entity"),
+			calls: []call{
+				func(ctx context.Context, s *Service) error {
+					_, err := s.GetListAttendance(ctx, id, "2025-01-01")
+					return err
+				},
+				func(ctx context.Context, s *Service) error { _, err := s.GetAllAttendance(ctx, 10); return err },
+				func(ctx context.Context, s *Service) error { _, err := s.GetAttendanceByID(ctx, id); return err },
+				func(ctx context.Context, s *Service) error { _, err := s.CheckExistAttendance(ctx, id); return err },
+				func(ctx context.Context, s *Service) error { return s.DeleteAttendance(ctx, id, &version) },
+			},
+		},
+		{
+			name:  "Test school domain",
+			scope: bySchool("school_domain_entity", "school_id"),
+			calls: []call{
+				func(ctx context.Context, s *Service) error { _, err := s.GetListSchoolDomain(ctx, id); return err },
+				func(ctx context.Context, s *Service) error { return s.DeleteSchoolDomain(ctx, id, id) },
+			},
+		},
+		{
+			name:  "Test teacher invitation",
+			scope: bySchool("teacher_invitation_entity", "school_id"),
+			calls: []call{
+				func(ctx context.Context, s *Service) error {
+					_, err := s.GetListTeacherInvitation(ctx, id)
+					return err
+				},
+				func(ctx context.Context, s *Service) error { return s.DeleteTeacherInvitation(ctx, id, id) },
+			},
+		},
+		{
+			name:  "Test school join code",
+			scope: bySchool("school_join_code_entity", "school_id"),
+			calls: []call{
+				func(ctx context.Context, s *Service) error { _, err := s.GetSchoolJoinCode(ctx, id); return err },
+				func(ctx context.Context, s *Service) error { return s.DeleteSchoolJoinCode(ctx, id) },
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, rec := newRecorderService(t)
+			scoped := tenant.WithSchool(context.Background(), schoolA)
+			bypass := tenant.Bypass(context.Background())
+
+			for i, call := range tt.calls {
+				// The recorder has no rows, so reads miss and versioned
+				// deletes find a newer row.
+				var notFound base.NotFoundError
+				var modified base.PreconditionFailedError
+				if err := call(scoped, s); err != nil && !errors.As(err, &notFound) && !errors.As(err, &modified) {
+					t.Fatalf("call %d: %v", i, err)
+				}
+				queries := rec.take()
+				if len(queries) == 0 {
+					t.Fatalf("call %d sent no query", i)
+				}
+				for _, query := range queries {
+					if !strings.Contains(query, tt.scope) {
+						t.Errorf("call %d query is not scoped to the school:\n%s", i, query)
+					}
+				}
+
+				_ = call(bypass, s)
+				for _, query := range rec.take() {
+					if strings.Contains(query, tt.scope) {
+						t.Errorf("call %d bypass query is scoped:\n%s", i, query)
+					}
+				}
+			}
+		})
+	}
+}
+
+func Test_TenantWrites(t *testing.T) {
+	schoolA := uuid.New()
+	schoolB := uuid.New()
+	classroom := uuid.New()
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		count      int64
+		write      func(ctx context.Context, s *Service) error
+		wantErr    error
+		wantInsert bool
+	}{
+		{
+			name: "Test teacher own school",
+			ctx:  tenant.WithSchool(context.Background(), schoolA),
+			write: func(ctx context.Context, s *Service) error {
+				_, err := s.CreateTeacher(ctx, &entitiesdto.TeacherCreateRequest{SchoolID: schoolA})
+				return err
+			},
+			wantInsert: true,
+		},
+		{
+			name: "Test teacher other school",
+			ctx:  tenant.WithSchool(context.Background(), schoolA),
+			write: func(ctx context.Context, s *Service) error {
+				_, err := s.CreateTeacher(ctx, &entitiesdto.TeacherCreateRequest{SchoolID: schoolB})
+				return err
+			},
+			wantErr: base.ReferenceError{Resource: "teacher", Reference: "school"},
+		},
+		{
+			name: "Test classroom other school",
+			ctx:  tenant.WithSchool(context.Background(), schoolA),
+			write: func(ctx context.Context, s *Service) error {
+				_, err := s.CreateClassroom(ctx, schoolB, "1/1", uuid.Nil)
+				return err
+			},
+			wantErr: base.ReferenceError{Resource: "classroom", Reference: "school"},
+		},
+		{
+			name: "Test classroom bypass",
+			ctx:  tenant.Bypass(context.Background()),
+			write: func(ctx context.Context, s *Service) error {
+				_, err := s.CreateClassroom(ctx, schoolB, "1/1", uuid.Nil)
+				return err
+			},
+			wantInsert: true,
+		},
+		{
+			name: "Test student other school",
+			ctx:  tenant.WithSchool(context.Background(), schoolA),
+			write: func(ctx context.Context, s *Service) error {
+				_, err := s.CreateStudent(ctx, &entitiesdto.StudentCreateRequest{School: schoolB})
+				return err
+			},
+			wantErr: base.ReferenceError{Resource: "student", Reference: "school"},
+		},
+		{
+			name:  "Test classroom member own classroom",
+			ctx:   tenant.WithSchool(context.Background(), schoolA),
+			count: 1,
+			write: func(ctx context.Context, s *Service) error {
+				_, err := s.CreateClassroomMember(ctx, &entitiesdto.ClassroomMemberCreateRequest{ClassroomID: classroom})
+				return err
+			},
+			wantInsert: true,
+		},
+		{
+			name: "Test classroom member other classroom",
+			ctx:  tenant.WithSchool(context.Background(), schoolA),
+			write: func(ctx context.Context, s *Service) error {
+				_, err := s.CreateClassroomMember(ctx, &entitiesdto.ClassroomMemberCreateRequest{ClassroomID: classroom})
+				return err
+			},
+			wantErr: base.ReferenceError{Resource: "classroom_member", Reference: "classroom"},
+		},
+		{
+			name:  "Test classroom teacher own classroom",
+			ctx:   tenant.WithSchool(context.Background(), schoolA),
+			count: 1,
+			write: func(ctx context.Context, s *Service) error {
+				return s.CreateClassroomTeacher(ctx, &ent.ClassroomTeacherEntity{ClassroomID: classroom, Role: ent.ClassroomRoleSubject})
+			},
+			wantInsert: true,
+		},
+		{
+			name: "Test classroom teacher other classroom",
+			ctx:  tenant.WithSchool(context.Background(), schoolA),
+			write: func(ctx context.Context, s *Service) error {
+				return s.CreateClassroomTeacher(ctx, &ent.ClassroomTeacherEntity{ClassroomID: classroom, Role: ent.ClassroomRoleSubject})
+			},
+			wantErr: base.ReferenceError{Resource: "classroom_teacher", Reference: "classroom"},
+		},
+		{
+			name: "Test teacher homeroom of other classroom",
+			ctx:  tenant.WithSchool(context.Background(), schoolA),
+			write: func(ctx context.Context, s *Service) error {
+				_, err := s.CreateTeacher(ctx, &entitiesdto.TeacherCreateRequest{SchoolID: schoolA, ClassroomID: &classroom})
+				return err
+			},
+			wantErr: base.ReferenceError{Resource: "classroom_teacher", Reference: "classroom"},
+			// The teacher insert is rolled back with the transaction
+			wantInsert: true,
+		},
+		{
+			name: "Test classroom delegation other classroom",
+			ctx:  tenant.WithSchool(context.Background(), schoolA),
+			write: func(ctx context.Context, s *Service) error {
+				return s.CreateClassroomDelegation(ctx, &ent.ClassroomDelegationEntity{ClassroomID: classroom, Scope: ent.DelegationScopeFull})
+			},
+			wantErr: base.ReferenceError{Resource: "classroom_delegation", Reference: "classroom"},
+		},
+		{
+			name: "Test attendance other classroom",
+			ctx:  tenant.WithSchool(context.Background(), schoolA),
+			write: func(ctx context.Context, s *Service) error {
+				_, err := s.CreateAttendance(ctx, &entitiesdto.AttendanceCreateRequest{ClassroomID: classroom})
+				return err
+			},
+			wantErr: base.ReferenceError{Resource: "attendance", Reference: "classroom"},
+		},
+		{
+			name: "Test attendance moved to other classroom",
+			ctx:  tenant.WithSchool(context.Background(), schoolA),
+			write: func(ctx context.Context, s *Service) error {
+				_, err := s.db.NewUpdate().Model(&ent.AttendanceEntity{ID: uuid.New(), ClassroomID: classroom}).WherePK().Exec(ctx)
+				return err
+			},
+			wantErr: base.ReferenceError{Resource: "attendance", Reference: "classroom"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, rec := newRecorderService(t)
+			rec.count = tt.count
+
+			err := tt.write(tt.ctx, s)
+			if tt.wantErr != nil {
+				if !reflect.DeepEqual(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+
+			inserted := false
+			for _, query := range rec.take() {
+				inserted = inserted || strings.HasPrefix(query, "INSERT")
+			}
+			if inserted != tt.wantInsert {
+				t.Errorf("inserted = %v, want %v", inserted, tt.wantInsert)
+			}
+		})
+	}
+}
@@ -25,7 +25,7 @@ func (c *Controller) Create(ctx *gin.Context) {
 	var res CreateResponse
 	span, log := utils.LogSpanFromGin(ctx)
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(400, gin.H{"error": "Invalid request"})
+		base.ValidationFailed(ctx, err)
 		return
 	}
 	span.AddEvent("example.create.request", trace.WithAttributes(
@@ -3,7 +3,6 @@ package gender
 import (
 	"github.com/easy-attend-serviceV3/app/utils"
 	"github.com/easy-attend-serviceV3/app/utils/base"
-	"github.com/easy-attend-serviceV3/config/i18n"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -22,7 +21,7 @@ func (c *Controller) ListController(ctx *gin.Context) {
 
 	var req ListControllerRequest
 	if err := ctx.ShouldBind(&req); err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.ValidationFailed(ctx, err)
 		return
 	}
 	span.AddEvent(`gender.ctl.list.request`)
@@ -31,7 +30,7 @@ func (c *Controller) ListController(ctx *gin.Context) {
 		RequestPaginate: req.RequestPaginate,
 	})
 	if err != nil {
-		base.HandleError(ctx, err)
+		base.HandleCustomError(ctx, err)
 		return
 	}
 	span.AddEvent(`gender.ctl.list.callsvc`)
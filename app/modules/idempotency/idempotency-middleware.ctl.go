@@ -0,0 +1,141 @@
+package idempotency
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/config/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	HeaderKey      = "Idempotency-Key"
+	HeaderReplayed = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+
+	// sensitiveKey marks in the gin context a response that must not be kept.
+	sensitiveKey = "idempotency_sensitive"
+)
+
+// Sensitive marks the route it is added to as answering with a secret, such
+// as a key shown once. Middleware keeps only the status of its response: a
+// retry learns the request went through but gets no body, so the secret is
+// neither stored nor handed out twice.
+func Sensitive(ctx *gin.Context) {
+	ctx.Set(sensitiveKey, true)
+	ctx.Next()
+}
+
+// recorder keeps a copy of the response so it can be replayed.
+type recorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Middleware makes POST requests carrying an Idempotency-Key safe to retry.
+// The first request runs and its response is stored; a retry with the same
+// key and payload gets that response back, a retry while the first is still
+// running gets 409, and the same key with a different payload gets 422.
+// Keys are scoped to the authenticated teacher when there is one.
+func (c *Controller) Middleware(ctx *gin.Context) {
+	key := ctx.GetHeader(HeaderKey)
+	if ctx.Request.Method != http.MethodPost || key == "" {
+		ctx.Next()
+		return
+	}
+
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`idempotency.ctl.middleware.start`)
+
+	if len(key) > maxKeyLength {
+		base.InvalidField(ctx, HeaderKey, "max", "255")
+		ctx.Abort()
+		return
+	}
+
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		log.Error(err)
+		base.BadRequest(ctx, i18n.BadRequest, nil)
+		ctx.Abort()
+		return
+	}
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	if userID, err := auth.GetUserID(ctx); err == nil {
+		key = userID.String() + ":" + key
+	}
+	fingerprint := Fingerprint(ctx.Request.Method, ctx.FullPath(), body)
+
+	existing, err := c.svc.ReserveService(ctx, key, fingerprint)
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		ctx.Abort()
+		return
+	}
+	if existing != nil {
+		replay(ctx, existing, fingerprint)
+		ctx.Abort()
+		return
+	}
+
+	w := &recorder{ResponseWriter: ctx.Writer}
+	ctx.Writer = w
+	ctx.Next()
+
+	// Server errors and refusals are not the answer to the request, so the
+	// client may retry, once granted access for the latter. The permission
+	// and ownership checks run after this middleware, so a 403 reaches here.
+	if status := w.Status(); status >= http.StatusInternalServerError ||
+		status == http.StatusUnauthorized || status == http.StatusForbidden {
+		err = c.svc.ReleaseService(ctx, key)
+	} else if ctx.GetBool(sensitiveKey) {
+		err = c.svc.CompleteService(ctx, key, &Record{
+			Fingerprint: fingerprint,
+			Status:      status,
+		})
+	} else {
+		err = c.svc.CompleteService(ctx, key, &Record{
+			Fingerprint: fingerprint,
+			Status:      status,
+			ContentType: w.Header().Get("Content-Type"),
+			Body:        w.body.Bytes(),
+		})
+	}
+	if err != nil {
+		log.Error(err)
+	}
+
+	span.AddEvent(`idempotency.ctl.middleware.end`)
+}
+
+func replay(ctx *gin.Context, rec *Record, fingerprint string) {
+	switch {
+	case rec.Fingerprint != fingerprint:
+		base.UnprocessableEntity(ctx, i18n.IdempotencyKeyReused, nil)
+	case rec.Status == 0:
+		base.Conflict(ctx, i18n.IdempotencyKeyInFlight, nil)
+	case len(rec.Body) == 0:
+		// The response of a Sensitive route
+		ctx.Header(HeaderReplayed, "true")
+		ctx.Status(rec.Status)
+	default:
+		ctx.Header(HeaderReplayed, "true")
+		ctx.Data(rec.Status, rec.ContentType, rec.Body)
+	}
+}
@@ -0,0 +1,85 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+	"github.com/easy-attend-serviceV3/internal/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+type postgresStore struct {
+	db entitiesinf.IdempotencyKeyEntity
+}
+
+func (s *postgresStore) Reserve(ctx context.Context, key string, rec *Record, ttl time.Duration) (*Record, error) {
+	existing, err := s.db.ReserveIdempotencyKey(ctx, &ent.IdempotencyKeyEntity{
+		Key:         key,
+		Fingerprint: rec.Fingerprint,
+		ExpiresAt:   time.Now().Add(ttl),
+	})
+	if err != nil || existing == nil {
+		return nil, err
+	}
+	return &Record{
+		Fingerprint: existing.Fingerprint,
+		Status:      existing.Status,
+		ContentType: existing.ContentType,
+		Body:        existing.Body,
+	}, nil
+}
+
+func (s *postgresStore) Complete(ctx context.Context, key string, rec *Record) error {
+	return s.db.CompleteIdempotencyKey(ctx, &ent.IdempotencyKeyEntity{
+		Key:         key,
+		Status:      rec.Status,
+		ContentType: rec.ContentType,
+		Body:        rec.Body,
+	})
+}
+
+func (s *postgresStore) Release(ctx context.Context, key string) error {
+	return s.db.DeleteIdempotencyKey(ctx, key)
+}
+
+type redisStore struct {
+	rd *redis.JSONClient
+}
+
+func redisKey(key string) string {
+	return "idempotency:" + key
+}
+
+func (s *redisStore) Reserve(ctx context.Context, key string, rec *Record, ttl time.Duration) (*Record, error) {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.rd.SetNX(ctx, redisKey(key), b, ttl).Result()
+	if err != nil || ok {
+		return nil, err
+	}
+
+	var existing Record
+	if err := s.rd.GetJSON(ctx, redisKey(key), &existing); err != nil {
+		if errors.Is(err, redis.Nil) {
+			// Expired between the two calls; claim it again.
+			return s.Reserve(ctx, key, rec, ttl)
+		}
+		return nil, err
+	}
+	return &existing, nil
+}
+
+func (s *redisStore) Complete(ctx context.Context, key string, rec *Record) error {
+	return s.rd.SetJSON(ctx, redisKey(key), rec, goredis.KeepTTL)
+}
+
+func (s *redisStore) Release(ctx context.Context, key string) error {
+	return s.rd.Del(ctx, redisKey(key)).Err()
+}
@@ -0,0 +1,72 @@
+package idempotency
+
+import (
+	"time"
+
+	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+	configDTO "github.com/easy-attend-serviceV3/internal/config/dto"
+	"github.com/easy-attend-serviceV3/internal/redis"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Module struct {
+	Svc *Service
+	Ctl *Controller
+}
+
+type (
+	Service struct {
+		tracer trace.Tracer
+		ttl    time.Duration
+		store  Store
+	}
+	Controller struct {
+		tracer trace.Tracer
+		svc    *Service
+	}
+
+	Config struct {
+		TTL int // in hours
+	}
+)
+
+type Options struct {
+	*configDTO.Config[Config]
+	tracer trace.Tracer
+	store  Store
+}
+
+// New keeps keys in Redis when rd is configured and in Postgres otherwise.
+func New(conf *configDTO.Config[Config], db entitiesinf.IdempotencyKeyEntity, rd *redis.JSONClient) *Module {
+	tracer := otel.Tracer("easy-attend-serviceV3.modules.idempotency")
+	var store Store = &postgresStore{db: db}
+	if rd != nil {
+		store = &redisStore{rd: rd}
+	}
+	svc := newService(&Options{
+		Config: conf,
+		tracer: tracer,
+		store:  store,
+	})
+	return &Module{
+		Svc: svc,
+		Ctl: newController(tracer, svc),
+	}
+}
+
+func newService(opt *Options) *Service {
+	return &Service{
+		tracer: opt.tracer,
+		ttl:    time.Duration(opt.Val.TTL) * time.Hour,
+		store:  opt.store,
+	}
+}
+
+func newController(trace trace.Tracer, svc *Service) *Controller {
+	return &Controller{
+		tracer: trace,
+		svc:    svc,
+	}
+}
@@ -0,0 +1,83 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/utils"
+)
+
+// Record is what is kept under an idempotency key. Status stays zero until
+// the first request finishes.
+type Record struct {
+	Fingerprint string `json:"fingerprint"`
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// Store keeps records for the configured TTL.
+type Store interface {
+	// Reserve claims key for rec. If the key is already held it returns the
+	// holder's record and leaves it untouched.
+	Reserve(ctx context.Context, key string, rec *Record, ttl time.Duration) (*Record, error)
+	// Complete saves the response of the request holding key.
+	Complete(ctx context.Context, key string, rec *Record) error
+	// Release frees key so the request can be retried.
+	Release(ctx context.Context, key string) error
+}
+
+// Fingerprint identifies a request by method, path and body, so a key reused
+// for anything else can be told apart from a retry.
+func Fingerprint(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ReserveService claims key for a request with fingerprint. A nil record means
+// the caller holds the key and must Complete or Release it.
+func (s *Service) ReserveService(ctx context.Context, key, fingerprint string) (*Record, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`idempotency.svc.reserve.start`)
+
+	existing, err := s.store.Reserve(ctx, key, &Record{Fingerprint: fingerprint}, s.ttl)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	span.AddEvent(`idempotency.svc.reserve.end`)
+	return existing, nil
+}
+
+// CompleteService stores the response for key.
+func (s *Service) CompleteService(ctx context.Context, key string, rec *Record) error {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`idempotency.svc.complete.start`)
+
+	if err := s.store.Complete(ctx, key, rec); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	span.AddEvent(`idempotency.svc.complete.end`)
+	return nil
+}
+
+// ReleaseService frees key after a request that should not be replayed.
+func (s *Service) ReleaseService(ctx context.Context, key string) error {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`idempotency.svc.release.start`)
+
+	if err := s.store.Release(ctx, key); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	span.AddEvent(`idempotency.svc.release.end`)
+	return nil
+}
@@ -0,0 +1,123 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+)
+
+type memStore map[string]*Record
+
+func (m memStore) Reserve(_ context.Context, key string, rec *Record, _ time.Duration) (*Record, error) {
+	if existing, ok := m[key]; ok {
+		return existing, nil
+	}
+	m[key] = rec
+	return nil, nil
+}
+
+func (m memStore) Complete(_ context.Context, key string, rec *Record) error {
+	m[key] = rec
+	return nil
+}
+
+func (m memStore) Release(_ context.Context, key string) error {
+	delete(m, key)
+	return nil
+}
+
+func Test_Middleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := memStore{}
+	tracer := otel.Tracer("test")
+	ctl := newController(tracer, &Service{tracer: tracer, ttl: time.Hour, store: store})
+
+	calls := 0
+	r := gin.New()
+	r.POST("/school", ctl.Middleware, func(ctx *gin.Context) {
+		calls++
+		if status, err := strconv.Atoi(ctx.GetHeader("X-Fail")); err == nil {
+			ctx.JSON(status, gin.H{"calls": calls})
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"calls": calls})
+	})
+
+	tests := []struct {
+		name         string
+		key          string
+		body         string
+		fail         int // the status the handler fails with
+		wantStatus   int
+		wantBody     string
+		wantReplayed bool
+	}{
+		{"Test no key", "", `{"name":"a"}`, 0, http.StatusOK, `{"calls":1}`, false},
+		{"Test first", "k1", `{"name":"a"}`, 0, http.StatusOK, `{"calls":2}`, false},
+		{"Test retry", "k1", `{"name":"a"}`, 0, http.StatusOK, `{"calls":2}`, true},
+		{"Test other payload", "k1", `{"name":"b"}`, 0, http.StatusUnprocessableEntity, "", false},
+		{"Test server error", "k2", `{"name":"a"}`, http.StatusInternalServerError, http.StatusInternalServerError, `{"calls":3}`, false},
+		{"Test retry after server error", "k2", `{"name":"a"}`, 0, http.StatusOK, `{"calls":4}`, false},
+		{"Test forbidden", "k5", `{"name":"a"}`, http.StatusForbidden, http.StatusForbidden, `{"calls":5}`, false},
+		{"Test retry after forbidden", "k5", `{"name":"a"}`, 0, http.StatusOK, `{"calls":6}`, false},
+		{"Test unauthorized", "k6", `{"name":"a"}`, http.StatusUnauthorized, http.StatusUnauthorized, `{"calls":7}`, false},
+		{"Test retry after unauthorized", "k6", `{"name":"a"}`, 0, http.StatusOK, `{"calls":8}`, false},
+		{"Test key too long", strings.Repeat("k", 256), `{}`, 0, http.StatusBadRequest, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/school", strings.NewReader(tt.body))
+			req.Header.Set(HeaderKey, tt.key)
+			if tt.fail != 0 {
+				req.Header.Set("X-Fail", strconv.Itoa(tt.fail))
+			}
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+				t.Errorf("body = %s, want %s", w.Body.String(), tt.wantBody)
+			}
+			if replayed := w.Header().Get(HeaderReplayed) == "true"; replayed != tt.wantReplayed {
+				t.Errorf("replayed = %v, want %v", replayed, tt.wantReplayed)
+			}
+		})
+	}
+
+	// A secret in a response is neither stored nor replayed
+	r.POST("/api-key", ctl.Middleware, Sensitive, func(ctx *gin.Context) {
+		ctx.JSON(http.StatusCreated, gin.H{"key": "secret"})
+	})
+	for i, wantBody := range []string{`{"key":"secret"}`, ""} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api-key", strings.NewReader(`{}`))
+		req.Header.Set(HeaderKey, "k4")
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusCreated || w.Body.String() != wantBody {
+			t.Errorf("sensitive call %d = %d %s, want %d %s", i, w.Code, w.Body.String(), http.StatusCreated, wantBody)
+		}
+	}
+	if rec := store["k4"]; rec == nil || rec.Status != http.StatusCreated || len(rec.Body) != 0 {
+		t.Errorf("sensitive record = %+v, want the status only", rec)
+	}
+
+	store["k3"] = &Record{Fingerprint: Fingerprint(http.MethodPost, "/school", []byte(`{}`))}
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/school", strings.NewReader(`{}`))
+	req.Header.Set(HeaderKey, "k3")
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusConflict {
+		t.Errorf("in flight status = %d, want %d", w.Code, http.StatusConflict)
+	}
+}
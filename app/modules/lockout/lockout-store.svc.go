@@ -0,0 +1,126 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/easy-attend-serviceV3/internal/redis"
+)
+
+type memoryEntry struct {
+	count   int64
+	until   time.Time
+	expires time.Time
+}
+
+type memoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	swept   time.Time
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{entries: map[string]memoryEntry{}}
+}
+
+func (s *memoryStore) Incr(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+	e, ok := s.entries[key]
+	if !ok || !now.Before(e.expires) {
+		e = memoryEntry{expires: now.Add(ttl)}
+	}
+	e.count++
+	s.entries[key] = e
+	return e.count, nil
+}
+
+func (s *memoryStore) Lock(_ context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = memoryEntry{until: until, expires: until}
+	return nil
+}
+
+func (s *memoryStore) LockedUntil(_ context.Context, key string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[key]; ok && time.Now().Before(e.expires) {
+		return e.until, nil
+	}
+	return time.Time{}, nil
+}
+
+func (s *memoryStore) Reset(_ context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// sweep drops expired entries, at most once a minute.
+func (s *memoryStore) sweep(now time.Time) {
+	if now.Sub(s.swept) < time.Minute {
+		return
+	}
+	s.swept = now
+	for key, e := range s.entries {
+		if !now.Before(e.expires) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+type redisStore struct {
+	rd *redis.JSONClient
+}
+
+func redisKey(key string) string {
+	return "lockout:" + key
+}
+
+func (s *redisStore) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	n, err := s.rd.Incr(ctx, redisKey(key)).Result()
+	if err != nil {
+		return 0, err
+	}
+	if n == 1 {
+		if err := s.rd.Expire(ctx, redisKey(key), ttl).Err(); err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
+func (s *redisStore) Lock(ctx context.Context, key string, until time.Time) error {
+	return s.rd.Set(ctx, redisKey(key), until.UnixMilli(), time.Until(until)).Err()
+}
+
+func (s *redisStore) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	ms, err := s.rd.Get(ctx, redisKey(key)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(ms), nil
+}
+
+func (s *redisStore) Reset(ctx context.Context, keys ...string) error {
+	rkeys := make([]string, len(keys))
+	for i, key := range keys {
+		rkeys[i] = redisKey(key)
+	}
+	return s.rd.Del(ctx, rkeys...).Err()
+}
@@ -0,0 +1,76 @@
+package lockout
+
+import (
+	"time"
+
+	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+	configDTO "github.com/easy-attend-serviceV3/internal/config/dto"
+	"github.com/easy-attend-serviceV3/internal/redis"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Module struct {
+	Svc *Service
+}
+
+type (
+	Service struct {
+		tracer   trace.Tracer
+		conf     Config
+		store    Store
+		dbEvent  entitiesinf.SecurityEventEntity
+		window   time.Duration
+		decay    time.Duration
+		base     time.Duration
+		maxDelay time.Duration
+	}
+
+	Config struct {
+		MaxAttempts   int // failed logins per email before a lockout
+		IpMaxAttempts int // failed logins per IP before a lockout
+		Window        int // in minutes, how long failures are counted
+		BaseDelay     int // in seconds, the first lockout; each next one doubles
+		MaxDelay      int // in minutes, the longest lockout
+		Decay         int // in hours, how long a lockout still doubles the next one
+	}
+)
+
+type Options struct {
+	*configDTO.Config[Config]
+	tracer  trace.Tracer
+	store   Store
+	dbEvent entitiesinf.SecurityEventEntity
+}
+
+// New keeps counters in Redis when rd is configured and in memory otherwise,
+// which only holds while the service runs as a single instance.
+func New(conf *configDTO.Config[Config], dbEvent entitiesinf.SecurityEventEntity, rd *redis.JSONClient) *Module {
+	tracer := otel.Tracer("easy-attend-serviceV3.modules.lockout")
+	var store Store = newMemoryStore()
+	if rd != nil {
+		store = &redisStore{rd: rd}
+	}
+	return &Module{
+		Svc: newService(&Options{
+			Config:  conf,
+			tracer:  tracer,
+			store:   store,
+			dbEvent: dbEvent,
+		}),
+	}
+}
+
+func newService(opt *Options) *Service {
+	return &Service{
+		tracer:   opt.tracer,
+		conf:     *opt.Val,
+		store:    opt.store,
+		dbEvent:  opt.dbEvent,
+		window:   time.Duration(opt.Val.Window) * time.Minute,
+		decay:    time.Duration(opt.Val.Decay) * time.Hour,
+		base:     time.Duration(opt.Val.BaseDelay) * time.Second,
+		maxDelay: time.Duration(opt.Val.MaxDelay) * time.Minute,
+	}
+}
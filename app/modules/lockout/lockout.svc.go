@@ -0,0 +1,208 @@
+package lockout
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/google/uuid"
+)
+
+// Scope is what a failure counter is kept for.
+type Scope string
+
+const (
+	ScopeEmail Scope = "email"
+	ScopeIP    Scope = "ip"
+)
+
+// Key names one failure counter.
+type Key struct {
+	Scope Scope
+	Value string
+}
+
+func EmailKey(email string) Key {
+	return Key{Scope: ScopeEmail, Value: strings.ToLower(strings.TrimSpace(email))}
+}
+
+func IPKey(ip string) Key {
+	return Key{Scope: ScopeIP, Value: ip}
+}
+
+func (k Key) String() string {
+	return string(k.Scope) + ":" + k.Value
+}
+
+// LockedError is returned while a key is locked out.
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("too many failed attempts, retry after %s", e.RetryAfter.Round(time.Second))
+}
+
+// Store keeps the counters. Keys are opaque to it.
+type Store interface {
+	// Incr adds one to the counter at key and returns the new value. The first
+	// increment starts the counter's ttl; later ones do not extend it.
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	// Lock locks key until the given time.
+	Lock(ctx context.Context, key string, until time.Time) error
+	// LockedUntil returns when the lock at key ends, zero if there is none.
+	LockedUntil(ctx context.Context, key string) (time.Time, error)
+	// Reset removes counters and locks.
+	Reset(ctx context.Context, keys ...string) error
+}
+
+func failKey(k Key) string  { return "fail:" + k.String() }
+func levelKey(k Key) string { return "level:" + k.String() }
+func lockKey(k Key) string  { return "lock:" + k.String() }
+
+func (s *Service) limit(scope Scope) int64 {
+	if scope == ScopeIP {
+		return int64(s.conf.IpMaxAttempts)
+	}
+	return int64(s.conf.MaxAttempts)
+}
+
+// delay is how long the level-th lockout in a row lasts: BaseDelay doubled
+// for each earlier one, up to MaxDelay.
+func (s *Service) delay(level int64) time.Duration {
+	d := s.base
+	for i := int64(1); i < level && d < s.maxDelay; i++ {
+		d *= 2
+	}
+	return min(d, s.maxDelay)
+}
+
+// CheckService returns a LockedError when any of keys is locked out.
+func (s *Service) CheckService(ctx context.Context, keys ...Key) error {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`lockout.svc.check.start`)
+
+	var retryAfter time.Duration
+	for _, k := range keys {
+		until, err := s.store.LockedUntil(ctx, lockKey(k))
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		retryAfter = max(retryAfter, time.Until(until))
+	}
+	if retryAfter > 0 {
+		return &LockedError{RetryAfter: retryAfter}
+	}
+
+	span.AddEvent(`lockout.svc.check.end`)
+	return nil
+}
+
+// FailService counts a failed attempt from ip against each of keys. Keys that
+// reach their limit are locked out, the failure count starts over and a
+// lockout event is recorded; it then returns a LockedError.
+func (s *Service) FailService(ctx context.Context, ip string, keys ...Key) error {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`lockout.svc.fail.start`)
+
+	var retryAfter time.Duration
+	for _, k := range keys {
+		n, err := s.store.Incr(ctx, failKey(k), s.window)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		if n < s.limit(k.Scope) {
+			continue
+		}
+
+		level, err := s.store.Incr(ctx, levelKey(k), s.decay)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		delay := s.delay(level)
+		until := time.Now().Add(delay)
+		if err := s.store.Lock(ctx, lockKey(k), until); err != nil {
+			log.Error(err)
+			return err
+		}
+		if err := s.store.Reset(ctx, failKey(k)); err != nil {
+			log.Error(err)
+		}
+		retryAfter = max(retryAfter, delay)
+
+		log.Warnf("security: %s locked out for %s after %d failed logins", k, delay, n)
+		s.record(ctx, &ent.SecurityEventEntity{
+			Event:   ent.SecurityEventLockout,
+			Subject: k.String(),
+			IP:      ip,
+			Detail: map[string]any{
+				"failures":     n,
+				"level":        level,
+				"locked_until": until,
+			},
+		})
+	}
+	if retryAfter > 0 {
+		return &LockedError{RetryAfter: retryAfter}
+	}
+
+	span.AddEvent(`lockout.svc.fail.end`)
+	return nil
+}
+
+// SucceedService clears the failures counted against key. Lockout levels are
+// kept so that a lucky guess between lockouts does not reset the backoff.
+func (s *Service) SucceedService(ctx context.Context, key Key) error {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`lockout.svc.succeed.start`)
+
+	if err := s.store.Reset(ctx, failKey(key)); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	span.AddEvent(`lockout.svc.succeed.end`)
+	return nil
+}
+
+// UnlockService lifts the lock on keys and forgets their history. actorID is
+// the administrator doing so, recorded with an unlock event per key.
+func (s *Service) UnlockService(ctx context.Context, actorID uuid.UUID, ip string, keys ...Key) error {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`lockout.svc.unlock.start`)
+
+	for _, k := range keys {
+		if err := s.store.Reset(ctx, failKey(k), levelKey(k), lockKey(k)); err != nil {
+			log.Error(err)
+			return err
+		}
+		log.Warnf("security: %s unlocked by %s", k, actorID)
+		s.record(ctx, &ent.SecurityEventEntity{
+			Event:   ent.SecurityEventUnlock,
+			Subject: k.String(),
+			ActorID: &actorID,
+			IP:      ip,
+		})
+	}
+
+	span.AddEvent(`lockout.svc.unlock.end`)
+	return nil
+}
+
+// record saves event. A failure is only logged: losing the audit row must not
+// let the attempt it describes through.
+func (s *Service) record(ctx context.Context, event *ent.SecurityEventEntity) {
+	if s.dbEvent == nil {
+		return
+	}
+	if err := s.dbEvent.CreateSecurityEvent(ctx, event); err != nil {
+		_, log := utils.LogSpanFromContext(ctx)
+		log.Error(err)
+	}
+}
@@ -0,0 +1,73 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+func Test_Lockout(t *testing.T) {
+	ctx := context.Background()
+	svc := &Service{
+		tracer:   otel.Tracer("test"),
+		conf:     Config{MaxAttempts: 3, IpMaxAttempts: 4},
+		store:    newMemoryStore(),
+		window:   time.Minute,
+		decay:    time.Hour,
+		base:     30 * time.Second,
+		maxDelay: 90 * time.Second,
+	}
+	email, ip := EmailKey("Teacher@Example.com"), IPKey("10.0.0.1")
+
+	tests := []struct {
+		name      string
+		step      func() error
+		wantRetry time.Duration
+	}{
+		{"Test first failure", func() error { return svc.FailService(ctx, "", email, ip) }, 0},
+		{"Test second failure", func() error { return svc.FailService(ctx, "", email, ip) }, 0},
+		{"Test success clears email", func() error { return svc.SucceedService(ctx, EmailKey("teacher@example.com")) }, 0},
+		{"Test counting again", func() error { return svc.FailService(ctx, "", email, ip) }, 0},
+		{"Test ip locked", func() error { return svc.FailService(ctx, "", ip) }, 30 * time.Second},
+		{"Test check locked", func() error { return svc.CheckService(ctx, email, ip) }, 30 * time.Second},
+		{"Test unlock", func() error { return svc.UnlockService(ctx, uuid.New(), "", ip) }, 0},
+		{"Test check unlocked", func() error { return svc.CheckService(ctx, email, ip) }, 0},
+		{"Test email almost", func() error { return svc.FailService(ctx, "", email) }, 0},
+		{"Test email locked", func() error { return svc.FailService(ctx, "", email) }, 30 * time.Second},
+		{"Test relock doubles", func() error {
+			svc.store.Reset(ctx, lockKey(email))
+			svc.FailService(ctx, "", email)
+			svc.FailService(ctx, "", email)
+			return svc.FailService(ctx, "", email)
+		}, 60 * time.Second},
+		{"Test capped", func() error {
+			svc.store.Reset(ctx, lockKey(email))
+			svc.FailService(ctx, "", email)
+			svc.FailService(ctx, "", email)
+			return svc.FailService(ctx, "", email)
+		}, 90 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.step()
+			var locked *LockedError
+			if tt.wantRetry == 0 {
+				if err != nil {
+					t.Fatalf("error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.As(err, &locked) {
+				t.Fatalf("error = %v, want LockedError", err)
+			}
+			if locked.RetryAfter > tt.wantRetry || locked.RetryAfter < tt.wantRetry-time.Second {
+				t.Errorf("RetryAfter = %s, want %s", locked.RetryAfter, tt.wantRetry)
+			}
+		})
+	}
+}
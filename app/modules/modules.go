@@ -1,6 +1,7 @@
 package modules
 
 import (
+	"fmt"
 	"log/slog"
 	"sync"
 
@@ -8,8 +9,11 @@ import (
 	configDTO "github.com/easy-attend-serviceV3/internal/config/dto"
 	"github.com/easy-attend-serviceV3/internal/database"
 	"github.com/easy-attend-serviceV3/internal/log"
+	"github.com/easy-attend-serviceV3/internal/mailer"
 	"github.com/easy-attend-serviceV3/internal/otel/collector"
+	"github.com/easy-attend-serviceV3/internal/redis"
 
+	apikey "github.com/easy-attend-serviceV3/app/modules/api_key"
 	"github.com/easy-attend-serviceV3/app/modules/attendance"
 	"github.com/easy-attend-serviceV3/app/modules/classroom"
 	classroommember "github.com/easy-attend-serviceV3/app/modules/classroom_member"
@@ -17,20 +21,24 @@ import (
 	"github.com/easy-attend-serviceV3/app/modules/example"
 	exampletwo "github.com/easy-attend-serviceV3/app/modules/example-two"
 	"github.com/easy-attend-serviceV3/app/modules/gender"
+	"github.com/easy-attend-serviceV3/app/modules/idempotency"
+	"github.com/easy-attend-serviceV3/app/modules/lockout"
 	"github.com/easy-attend-serviceV3/app/modules/prefix"
 	"github.com/easy-attend-serviceV3/app/modules/school"
 	"github.com/easy-attend-serviceV3/app/modules/student"
 	"github.com/easy-attend-serviceV3/app/modules/teacher"
 	appConf "github.com/easy-attend-serviceV3/config"
+	"github.com/uptrace/bun"
 	// "mcop/app/modules/kafka"
 )
 
 type Modules struct {
-	Conf *config.Module[appConf.Config]
-	Log  *log.Module
-	OTEL *collector.Module
-	DB   *database.DatabaseModule
-	ENT  *entities.Module
+	Conf  *config.Module[appConf.Config]
+	Log   *log.Module
+	OTEL  *collector.Module
+	DB    *database.DatabaseModule
+	Redis *redis.RedisModule
+	ENT   *entities.Module
 	// Kafka *kafka.Module
 	Example  *example.Module
 	Example2 *exampletwo.Module
@@ -43,6 +51,9 @@ type Modules struct {
 	Student         *student.Module
 	Teacher         *teacher.Module
 	Attendance      *attendance.Module
+	Idempotency     *idempotency.Module
+	Lockout         *lockout.Module
+	APIKey          *apikey.Module
 }
 
 func modulesInit() {
@@ -57,7 +68,32 @@ func modulesInit() {
 	db := database.New(conf.Database.Sql)
 	log.Infof("database module initialized")
 
-	entitiesMod := entities.New(db.Svc.DB())
+	rd := redis.New(conf.AppName, conf.Database.Redis)
+	log.Infof("redis module initialized")
+
+	mod, modErr = wire(confMod, db.Svc.DB(), rd.Svc.DB())
+	if modErr != nil {
+		return
+	}
+	mod.Log = logMod
+	mod.OTEL = otel
+	mod.DB = db
+	mod.Redis = rd
+
+	log.Infof("all modules initialized")
+}
+
+// Offline wires the feature modules without opening any connection. The
+// handlers are real but have no database behind them, so the result is only
+// fit for tooling that inspects the route table, such as the OpenAPI export.
+func Offline() (*Modules, error) {
+	return wire(config.New(&appConf.App), nil, nil)
+}
+
+func wire(confMod *config.Module[appConf.Config], db *bun.DB, rd *redis.JSONClient) (*Modules, error) {
+	log := log.With(slog.String("module", "modules"))
+
+	entitiesMod := entities.New(db)
 	log.Infof("entities module initialized")
 
 	exampleMod := example.New(configDTO.Conf[example.Config](confMod.Svc), entitiesMod.Svc)
@@ -72,32 +108,43 @@ func modulesInit() {
 	prefixMod := prefix.New(entitiesMod.Svc)
 	log.Infof("prefix module initialized")
 
-	schoolMod := school.New(entitiesMod.Svc)
+	schoolMod := school.New(confMod.Svc.Config(), entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc)
 	log.Infof("school module initialized")
 
-	classroomMod := classroom.New(entitiesMod.Svc)
+	classroomMod := classroom.New(entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc)
 	log.Infof("classroom module initialized")
 
-	classroomMemberMod := classroommember.New(entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc)
+	classroomMemberMod := classroommember.New(entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc)
 	log.Infof("classroom member module initialized")
 
-	studentMod := student.New(entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc)
+	studentMod := student.New(entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc)
 	log.Infof("student module initialized")
 
-	teacherMod := teacher.New(confMod.Svc.Config(), entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc)
+	mail := mailer.New(configDTO.Conf[mailer.Config](confMod.Svc))
+
+	lockoutMod := lockout.New(configDTO.Conf[lockout.Config](confMod.Svc), entitiesMod.Svc, rd)
+	log.Infof("lockout module initialized")
+
+	teacherMod, err := teacher.New(confMod.Svc.Config(), entitiesMod.Svc, mail, lockoutMod.Svc)
+	if err != nil {
+		return nil, fmt.Errorf("teacher module: %w", err)
+	}
 	log.Infof("teacher module initialized")
 
-	attendanceMod := attendance.New(entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc)
+	attendanceMod := attendance.New(entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc, entitiesMod.Svc)
 	log.Infof("attendance module initialized")
 
+	idempotencyMod := idempotency.New(configDTO.Conf[idempotency.Config](confMod.Svc), entitiesMod.Svc, rd)
+	log.Infof("idempotency module initialized")
+
+	apiKeyMod := apikey.New(entitiesMod.Svc)
+	log.Infof("api key module initialized")
+
 	// kafka := kafka.New(&conf.Kafka)
 	// log.Infof("kafka module initialized")
 
-	mod = &Modules{
+	return &Modules{
 		Conf:            confMod,
-		Log:             logMod,
-		OTEL:            otel,
-		DB:              db,
 		ENT:             entitiesMod,
 		Example:         exampleMod,
 		Example2:        exampleMod2,
@@ -109,18 +156,22 @@ func modulesInit() {
 		Student:         studentMod,
 		Teacher:         teacherMod,
 		Attendance:      attendanceMod,
-	}
-
-	log.Infof("all modules initialized")
+		Idempotency:     idempotencyMod,
+		Lockout:         lockoutMod,
+		APIKey:          apiKeyMod,
+	}, nil
 }
 
 var (
-	once sync.Once
-	mod  *Modules
+	once   sync.Once
+	mod    *Modules
+	modErr error
 )
 
-func Get() *Modules {
+// Get initializes the modules on first use. A module whose configuration is
+// invalid fails it, and every later call returns the same error.
+func Get() (*Modules, error) {
 	once.Do(modulesInit)
 
-	return mod
+	return mod, modErr
 }
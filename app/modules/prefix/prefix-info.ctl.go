@@ -3,13 +3,12 @@ package prefix
 import (
 	"github.com/easy-attend-serviceV3/app/utils"
 	"github.com/easy-attend-serviceV3/app/utils/base"
-	"github.com/easy-attend-serviceV3/config/i18n"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 type InfoControllerRequest struct {
-	ID string `uri:"id"`
+	ID string `uri:"id" binding:"required,uuid"`
 }
 
 type InfoControllerResponse struct {
@@ -22,19 +21,15 @@ func (c *Controller) InfoController(ctx *gin.Context) {
 
 	var req InfoControllerRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.ValidationFailed(ctx, err)
 		return
 	}
 	span.AddEvent(`prefix.ctl.info.request`)
 
-	id, err := uuid.Parse(req.ID)
-	if err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
-		return
-	}
+	id := uuid.MustParse(req.ID)
 	data, err := c.svc.InfoService(ctx, id)
 	if err != nil {
-		base.HandleError(ctx, err)
+		base.HandleCustomError(ctx, err)
 		return
 	}
 	var resp InfoControllerResponse
@@ -3,7 +3,6 @@ package school
 import (
 	"github.com/easy-attend-serviceV3/app/utils"
 	"github.com/easy-attend-serviceV3/app/utils/base"
-	"github.com/easy-attend-serviceV3/config/i18n"
 	"github.com/gin-gonic/gin"
 )
 
@@ -19,23 +18,17 @@ func (c *Controller) CreateController(ctx *gin.Context) {
 
 	var request CreateControllerRequest
 	if err := ctx.ShouldBindJSON(&request); err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.ValidationFailed(ctx, err)
 		return
 	}
 	span.AddEvent(`school.create.ctl.request`)
 
-	// Validate required fields
-	if request.Name == "" {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
-		return
-	}
-
 	if err := c.svc.CreateService(ctx.Request.Context(), &CreateServiceRequest{
 		Name:    request.Name,
 		Address: request.Address,
 		Phone:   request.Phone,
 	}); err != nil {
-		base.HandleError(ctx, err)
+		base.HandleCustomError(ctx, err)
 		return
 	}
 	span.AddEvent(`school.create.ctl.end`)
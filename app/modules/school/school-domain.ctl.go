@@ -0,0 +1,104 @@
+package school
+
+import (
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/config/i18n"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (c *Controller) DomainListController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`school.domain.list.ctl.start`)
+
+	schoolID, ok := c.pathSchool(ctx)
+	if !ok {
+		return
+	}
+
+	data, err := c.svc.DomainListService(ctx.Request.Context(), schoolID)
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`school.domain.list.ctl.end`)
+	base.Success(ctx, data)
+}
+
+func (c *Controller) DomainCreateController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`school.domain.create.ctl.start`)
+
+	schoolID, ok := c.pathSchool(ctx)
+	if !ok {
+		return
+	}
+
+	var req DomainCreateServiceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		base.ValidationFailed(ctx, err)
+		return
+	}
+	actorID, err := auth.GetUserID(ctx)
+	if err != nil {
+		base.Unauthorized(ctx, i18n.Unauthorized, nil)
+		return
+	}
+	req.SchoolID = schoolID
+	req.ActorID = actorID
+
+	data, err := c.svc.DomainCreateService(ctx.Request.Context(), &req)
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`school.domain.create.ctl.end`)
+	base.Success(ctx, data)
+}
+
+func (c *Controller) DomainDeleteController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`school.domain.delete.ctl.start`)
+
+	schoolID, ok := c.pathSchool(ctx)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(ctx.Param("domain_id"))
+	if err != nil {
+		base.InvalidField(ctx, "domain_id", "uuid")
+		return
+	}
+
+	if err := c.svc.DomainDeleteService(ctx.Request.Context(), schoolID, id); err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`school.domain.delete.ctl.end`)
+	base.Success(ctx, nil)
+}
+
+// pathSchool returns the school of the URL once the caller may access it,
+// answering the request otherwise.
+func (c *Controller) pathSchool(ctx *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		base.InvalidField(ctx, "id", "uuid")
+		return uuid.Nil, false
+	}
+
+	ov, err := auth.NewOwnershipVerifier(ctx, c.access)
+	if err == nil {
+		err = ov.VerifySchoolAccess(ctx.Request.Context(), id)
+	}
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,99 @@
+package school
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/google/uuid"
+)
+
+type DomainServiceResponse struct {
+	ID            uuid.UUID `json:"id"`
+	SchoolID      uuid.UUID `json:"school_id"`
+	Provider      string    `json:"provider"`
+	Domain        string    `json:"domain"`
+	TenantID      string    `json:"tenant_id,omitempty"`
+	AutoProvision bool      `json:"auto_provision"` // สร้างบัญชีครูเมื่อเข้าสู่ระบบครั้งแรก
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type DomainCreateServiceRequest struct {
+	Provider      string `json:"provider" binding:"required,oneof=google microsoft"`
+	Domain        string `json:"domain" binding:"required,fqdn,max=255"`
+	TenantID      string `json:"tenant_id" binding:"required_if=Provider microsoft,max=64"` // Microsoft tenant ที่เป็นเจ้าของโดเมน
+	AutoProvision bool   `json:"auto_provision"`
+
+	SchoolID uuid.UUID `json:"-"`
+	ActorID  uuid.UUID `json:"-"`
+}
+
+func toDomainResponse(d *ent.SchoolDomainEntity) *DomainServiceResponse {
+	return &DomainServiceResponse{
+		ID:            d.ID,
+		SchoolID:      d.SchoolID,
+		Provider:      d.Provider,
+		Domain:        d.Domain,
+		TenantID:      d.TenantID,
+		AutoProvision: d.AutoProvision,
+		CreatedAt:     d.CreatedAt,
+	}
+}
+
+func (s *Service) DomainListService(ctx context.Context, schoolID uuid.UUID) ([]*DomainServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`school.svc.domain_list.start`)
+
+	domains, err := s.dbDomain.GetListSchoolDomain(ctx, schoolID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	resp := make([]*DomainServiceResponse, 0, len(domains))
+	for _, d := range domains {
+		resp = append(resp, toDomainResponse(d))
+	}
+
+	span.AddEvent(`school.svc.domain_list.end`)
+	return resp, nil
+}
+
+// DomainCreateService lets the school's teachers sign in with the accounts
+// req.Provider manages for req.Domain.
+func (s *Service) DomainCreateService(ctx context.Context, req *DomainCreateServiceRequest) (*DomainServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`school.svc.domain_create.start`)
+
+	domain := &ent.SchoolDomainEntity{
+		SchoolID:      req.SchoolID,
+		Provider:      req.Provider,
+		Domain:        strings.ToLower(strings.TrimSuffix(req.Domain, ".")),
+		TenantID:      req.TenantID,
+		AutoProvision: req.AutoProvision,
+		CreatedBy:     &req.ActorID,
+	}
+	if err := s.dbDomain.CreateSchoolDomain(ctx, domain); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	log.Infof("security: %s domain %s added to school %s by %s", domain.Provider, domain.Domain, domain.SchoolID, req.ActorID)
+
+	span.AddEvent(`school.svc.domain_create.end`)
+	return toDomainResponse(domain), nil
+}
+
+func (s *Service) DomainDeleteService(ctx context.Context, schoolID, id uuid.UUID) error {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`school.svc.domain_delete.start`)
+
+	if err := s.dbDomain.DeleteSchoolDomain(ctx, schoolID, id); err != nil {
+		log.Error(err)
+		return err
+	}
+	log.Infof("security: domain %s removed from school %s", id, schoolID)
+
+	span.AddEvent(`school.svc.domain_delete.end`)
+	return nil
+}
@@ -2,14 +2,14 @@ package school
 
 import (
 	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
 	"github.com/easy-attend-serviceV3/app/utils/base"
-	"github.com/easy-attend-serviceV3/config/i18n"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 type InfoControllerRequest struct {
-	ID string `uri:"id"`
+	ID string `uri:"id" binding:"required,uuid"`
 }
 
 type InfoControllerResponse struct {
@@ -26,19 +26,28 @@ func (c *Controller) InfoController(ctx *gin.Context) {
 
 	var req InfoControllerRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.ValidationFailed(ctx, err)
 		return
 	}
 	span.AddEvent(`prefix.ctl.info.request`)
 
-	id, err := uuid.Parse(req.ID)
+	id := uuid.MustParse(req.ID)
+
+	ov, err := auth.NewOwnershipVerifier(ctx, c.access)
+	if err == nil {
+		err = ov.VerifySchoolAccess(ctx.Request.Context(), id)
+	}
 	if err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.HandleCustomError(ctx, err)
 		return
 	}
-	data, err := c.svc.InfoService(ctx, id)
+
+	data, err := c.svc.InfoService(ctx.Request.Context(), id)
 	if err != nil {
-		base.HandleError(ctx, err)
+		base.HandleCustomError(ctx, err)
+		return
+	}
+	if base.NotModified(ctx, data.UpdatedAt) {
 		return
 	}
 	var resp InfoControllerResponse
@@ -0,0 +1,75 @@
+package school
+
+import (
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/config/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+func (c *Controller) JoinCodeInfoController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`school.join_code.info.ctl.start`)
+
+	schoolID, ok := c.pathSchool(ctx)
+	if !ok {
+		return
+	}
+
+	data, err := c.svc.JoinCodeInfoService(ctx.Request.Context(), schoolID)
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`school.join_code.info.ctl.end`)
+	base.Success(ctx, data)
+}
+
+func (c *Controller) JoinCodeRotateController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`school.join_code.rotate.ctl.start`)
+
+	schoolID, ok := c.pathSchool(ctx)
+	if !ok {
+		return
+	}
+	actorID, err := auth.GetUserID(ctx)
+	if err != nil {
+		base.Unauthorized(ctx, i18n.Unauthorized, nil)
+		return
+	}
+
+	data, err := c.svc.JoinCodeRotateService(ctx.Request.Context(), schoolID, actorID)
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`school.join_code.rotate.ctl.end`)
+	base.Success(ctx, data)
+}
+
+func (c *Controller) JoinCodeDeleteController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`school.join_code.delete.ctl.start`)
+
+	schoolID, ok := c.pathSchool(ctx)
+	if !ok {
+		return
+	}
+	actorID, err := auth.GetUserID(ctx)
+	if err != nil {
+		base.Unauthorized(ctx, i18n.Unauthorized, nil)
+		return
+	}
+
+	if err := c.svc.JoinCodeDeleteService(ctx.Request.Context(), schoolID, actorID); err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`school.join_code.delete.ctl.end`)
+	base.Success(ctx, nil)
+}
@@ -0,0 +1,88 @@
+package school
+
+import (
+	"context"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/google/uuid"
+)
+
+type JoinCodeServiceResponse struct {
+	SchoolID  uuid.UUID `json:"school_id"`
+	Code      string    `json:"code"` // รหัสที่ครูใช้สมัครเข้าโรงเรียน
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func toJoinCodeResponse(c *ent.SchoolJoinCodeEntity) *JoinCodeServiceResponse {
+	return &JoinCodeServiceResponse{
+		SchoolID:  c.SchoolID,
+		Code:      c.Code,
+		ExpiresAt: c.ExpiresAt,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+func (s *Service) JoinCodeInfoService(ctx context.Context, schoolID uuid.UUID) (*JoinCodeServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`school.svc.join_code_info.start`)
+
+	code, err := s.dbJoinCode.GetSchoolJoinCode(ctx, schoolID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	span.AddEvent(`school.svc.join_code_info.end`)
+	return toJoinCodeResponse(code), nil
+}
+
+// JoinCodeRotateService gives the school a new join code. The old one stops
+// working, so a code that was passed around too widely can be taken back.
+func (s *Service) JoinCodeRotateService(ctx context.Context, schoolID, actorID uuid.UUID) (*JoinCodeServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`school.svc.join_code_rotate.start`)
+
+	if _, err := s.db.GetByIDSchool(ctx, schoolID); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	value, err := auth.NewJoinCode()
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	code := &ent.SchoolJoinCodeEntity{
+		SchoolID:  schoolID,
+		Code:      value,
+		ExpiresAt: time.Now().AddDate(0, 0, s.config.JoinCodeTTL),
+		CreatedBy: &actorID,
+	}
+	if err := s.dbJoinCode.RotateSchoolJoinCode(ctx, code); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	log.Infof("security: join code of school %s rotated by %s", schoolID, actorID)
+
+	span.AddEvent(`school.svc.join_code_rotate.end`)
+	return toJoinCodeResponse(code), nil
+}
+
+// JoinCodeDeleteService turns off registration with a join code; teachers
+// then join the school by invitation only.
+func (s *Service) JoinCodeDeleteService(ctx context.Context, schoolID, actorID uuid.UUID) error {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`school.svc.join_code_delete.start`)
+
+	if err := s.dbJoinCode.DeleteSchoolJoinCode(ctx, schoolID); err != nil {
+		log.Error(err)
+		return err
+	}
+	log.Infof("security: join code of school %s removed by %s", schoolID, actorID)
+
+	span.AddEvent(`school.svc.join_code_delete.end`)
+	return nil
+}
@@ -2,8 +2,8 @@ package school
 
 import (
 	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
 	"github.com/easy-attend-serviceV3/app/utils/base"
-	"github.com/easy-attend-serviceV3/config/i18n"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -22,20 +22,29 @@ func (c *Controller) UpdateController(ctx *gin.Context) {
 	idParam := ctx.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.InvalidField(ctx, "id", "uuid")
+		return
+	}
+
+	ov, err := auth.NewOwnershipVerifier(ctx, c.access)
+	if err == nil {
+		err = ov.VerifySchoolAccess(ctx.Request.Context(), id)
+	}
+	if err != nil {
+		base.HandleCustomError(ctx, err)
 		return
 	}
 
 	var request UpdateControllerRequest
 	if err := ctx.ShouldBindJSON(&request); err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.ValidationFailed(ctx, err)
 		return
 	}
 	span.AddEvent(`school.update.ctl.request`)
 
-	// Validate required fields
-	if request.Name == "" {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+	version, err := base.IfMatch(ctx)
+	if err != nil {
+		base.HandleCustomError(ctx, err)
 		return
 	}
 
@@ -44,8 +53,9 @@ func (c *Controller) UpdateController(ctx *gin.Context) {
 		Name:    request.Name,
 		Address: request.Address,
 		Phone:   request.Phone,
+		Version: version,
 	}); err != nil {
-		base.HandleError(ctx, err)
+		base.HandleCustomError(ctx, err)
 		return
 	}
 
@@ -3,23 +3,25 @@ package school
 import (
 	"context"
 	"log/slog"
+	"time"
 
 	"github.com/easy-attend-serviceV3/app/utils"
 	"github.com/google/uuid"
 )
 
 type UpdateServiceRequest struct {
-	ID      uuid.UUID `json:"id"`
-	Name    string    `json:"name"`
-	Address string    `json:"address"`
-	Phone   string    `json:"phone"`
+	ID      uuid.UUID  `json:"id"`
+	Name    string     `json:"name"`
+	Address string     `json:"address"`
+	Phone   string     `json:"phone"`
+	Version *time.Time `json:"-"` // from If-Match; nil writes unconditionally
 }
 
 func (s *Service) UpdateService(ctx context.Context, req *UpdateServiceRequest) error {
 	span, log := utils.LogSpanFromContext(ctx)
 	span.AddEvent(`school.svc.update.start`)
 
-	_, err := s.db.UpdateSchool(ctx, req.ID, req.Name, req.Address, req.Phone)
+	_, err := s.db.UpdateSchool(ctx, req.ID, req.Name, req.Address, req.Phone, req.Version)
 	if err != nil {
 		log.With(slog.Any(`body`, req)).Error(err)
 		return err
@@ -2,6 +2,7 @@ package school
 
 import (
 	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+	"github.com/easy-attend-serviceV3/config"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
@@ -13,44 +14,57 @@ type Module struct {
 }
 type (
 	Service struct {
-		tracer trace.Tracer
-		db     entitiesinf.SchoolEntity
+		tracer     trace.Tracer
+		config     *config.Config
+		db         entitiesinf.SchoolEntity
+		dbDomain   entitiesinf.SchoolDomainEntity
+		dbJoinCode entitiesinf.SchoolJoinCodeEntity
 	}
 	Controller struct {
 		tracer trace.Tracer
 		svc    *Service
+		access entitiesinf.AccessEntity
 	}
 )
 
 type Options struct {
 	// *configDTO.Config[Config]
-	tracer trace.Tracer
-	db     entitiesinf.SchoolEntity
+	tracer     trace.Tracer
+	config     *config.Config
+	db         entitiesinf.SchoolEntity
+	dbDomain   entitiesinf.SchoolDomainEntity
+	dbJoinCode entitiesinf.SchoolJoinCodeEntity
 }
 
-func New(db entitiesinf.SchoolEntity) *Module {
+func New(conf *config.Config, db entitiesinf.SchoolEntity, dbDomain entitiesinf.SchoolDomainEntity, dbJoinCode entitiesinf.SchoolJoinCodeEntity, dbAccess entitiesinf.AccessEntity) *Module {
 	tracer := otel.Tracer("easy-attend-serviceV3.modules.school")
 	svc := newService(&Options{
-		// Config: conf,
-		tracer: tracer,
-		db:     db,
+		tracer:     tracer,
+		config:     conf,
+		db:         db,
+		dbDomain:   dbDomain,
+		dbJoinCode: dbJoinCode,
 	})
 	return &Module{
 		Svc: svc,
-		Ctl: newController(tracer, svc),
+		Ctl: newController(tracer, svc, dbAccess),
 	}
 }
 
 func newService(opt *Options) *Service {
 	return &Service{
-		tracer: opt.tracer,
-		db:     opt.db,
+		tracer:     opt.tracer,
+		config:     opt.config,
+		db:         opt.db,
+		dbDomain:   opt.dbDomain,
+		dbJoinCode: opt.dbJoinCode,
 	}
 }
 
-func newController(trace trace.Tracer, svc *Service) *Controller {
+func newController(trace trace.Tracer, svc *Service, access entitiesinf.AccessEntity) *Controller {
 	return &Controller{
 		tracer: trace,
 		svc:    svc,
+		access: access,
 	}
 }
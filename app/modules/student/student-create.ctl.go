@@ -2,6 +2,7 @@ package student
 
 import (
 	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
 	"github.com/easy-attend-serviceV3/app/utils/base"
 	"github.com/easy-attend-serviceV3/config/i18n"
 	"github.com/gin-gonic/gin"
@@ -9,14 +10,15 @@ import (
 )
 
 type CreateControllerRequest struct {
-	SchoolID    string `json:"school_id" binding:"required"`
-	ClassroomID string `json:"classroom_id"` // ไม่บังคับกรอก
-	PrefixID    string `json:"prefix_id" binding:"required"`
-	GenderID    string `json:"gender_id" binding:"required"`
+	SchoolID    string `json:"school_id" binding:"required,uuid"`
+	ClassroomID string `json:"classroom_id" binding:"omitempty,uuid"` // ไม่บังคับกรอก
+	PrefixID    string `json:"prefix_id" binding:"required,uuid"`
+	GenderID    string `json:"gender_id" binding:"required,uuid"`
 	StudentCode string `json:"student_code" binding:"required"`
 	FirstName   string `json:"first_name" binding:"required"`
 	LastName    string `json:"last_name" binding:"required"`
 	Phone       string `json:"phone"`
+	PhotoURL    string `json:"photo_url" binding:"omitempty,url,max=500"`
 }
 
 type CreateControllerResponse struct {
@@ -29,6 +31,7 @@ type CreateControllerResponse struct {
 	FirstName   string     `json:"first_name"`
 	LastName    string     `json:"last_name"`
 	Phone       string     `json:"phone"`
+	PhotoURL    string     `json:"photo_url,omitempty"`
 }
 
 func (c *Controller) CreateController(ctx *gin.Context) {
@@ -37,41 +40,25 @@ func (c *Controller) CreateController(ctx *gin.Context) {
 
 	var request CreateControllerRequest
 	if err := ctx.ShouldBindJSON(&request); err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.ValidationFailed(ctx, err)
 		return
 	}
 	span.AddEvent(`student.create.ctl.request`)
 
-	// Validate and parse UUIDs
-	schoolID, err := uuid.Parse(request.SchoolID)
-	if err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
-		return
-	}
+	// The binding has checked the UUIDs
+	schoolID := uuid.MustParse(request.SchoolID)
 
 	var classroomID uuid.UUID
 	if request.ClassroomID != "" {
-		classroomID, err = uuid.Parse(request.ClassroomID)
-		if err != nil {
-			base.BadRequest(ctx, i18n.BadRequest, nil)
-			return
-		}
+		classroomID = uuid.MustParse(request.ClassroomID)
 	}
 
-	prefixID, err := uuid.Parse(request.PrefixID)
-	if err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
-		return
-	}
-	genderID, err := uuid.Parse(request.GenderID)
-	if err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
-		return
-	}
+	prefixID := uuid.MustParse(request.PrefixID)
+	genderID := uuid.MustParse(request.GenderID)
 
-	// Validate required fields
-	if request.FirstName == "" || request.LastName == "" || request.StudentCode == "" {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+	teacherID, err := actorTeacher(ctx)
+	if err != nil {
+		base.Unauthorized(ctx, i18n.Unauthorized, nil)
 		return
 	}
 
@@ -84,9 +71,11 @@ func (c *Controller) CreateController(ctx *gin.Context) {
 		FirstName:   request.FirstName,
 		LastName:    request.LastName,
 		Phone:       request.Phone,
+		PhotoURL:    request.PhotoURL,
+		TeacherID:   teacherID,
 	})
 	if err != nil {
-		base.HandleError(ctx, err)
+		base.HandleCustomError(ctx, err)
 		return
 	}
 	span.AddEvent(`student.create.ctl.end`)
@@ -106,6 +95,16 @@ func (c *Controller) CreateController(ctx *gin.Context) {
 		FirstName:   student.FirstName,
 		LastName:    student.LastName,
 		Phone:       student.Phone,
+		PhotoURL:    student.PhotoURL,
 	}
 	base.Success(ctx, response)
 }
+
+// actorTeacher returns the teacher of the token, who places the student in
+// their classroom, or uuid.Nil for API keys.
+func actorTeacher(ctx *gin.Context) (uuid.UUID, error) {
+	if ctx.GetString("user_type") == auth.UserTypeAPIKey {
+		return uuid.Nil, nil
+	}
+	return auth.GetUserID(ctx)
+}
@@ -17,6 +17,8 @@ type CreateServiceRequest struct {
 	FirstName   string
 	LastName    string
 	Phone       string
+	PhotoURL    string
+	TeacherID   uuid.UUID // the teacher of the token; uuid.Nil for API keys
 }
 
 type CreateServiceResponse struct {
@@ -29,6 +31,7 @@ type CreateServiceResponse struct {
 	FirstName   string    `json:"first_name"`
 	LastName    string    `json:"last_name"`
 	Phone       string    `json:"phone"`
+	PhotoURL    string    `json:"photo_url,omitempty"`
 }
 
 func (s *Service) CreateService(ctx context.Context, req *CreateServiceRequest) (*CreateServiceResponse, error) {
@@ -44,6 +47,8 @@ func (s *Service) CreateService(ctx context.Context, req *CreateServiceRequest)
 		FirstName:   req.FirstName,
 		LastName:    req.LastName,
 		Phone:       req.Phone,
+		PhotoURL:    req.PhotoURL,
+		TeacherID:   req.TeacherID,
 	})
 	if err != nil {
 		log.Error(err)
@@ -60,6 +65,7 @@ func (s *Service) CreateService(ctx context.Context, req *CreateServiceRequest)
 		FirstName:   student.FirstName,
 		LastName:    student.LastName,
 		Phone:       student.Phone,
+		PhotoURL:    student.PhotoURL,
 	}
 
 	span.AddEvent(`student.svc.create.end`)
@@ -3,20 +3,22 @@ package student
 import (
 	"context"
 	"log/slog"
+	"time"
 
 	"github.com/easy-attend-serviceV3/app/utils"
 	"github.com/google/uuid"
 )
 
 type DeleteServiceRequest struct {
-	ID uuid.UUID `json:"id"`
+	ID      uuid.UUID  `json:"id"`
+	Version *time.Time `json:"-"` // from If-Match; nil writes unconditionally
 }
 
 func (s *Service) DeleteService(ctx context.Context, req *DeleteServiceRequest) error {
 	span, log := utils.LogSpanFromContext(ctx)
 	span.AddEvent(`student.svc.delete.start`)
 
-	err := s.db.DeleteStudent(ctx, req.ID)
+	err := s.db.DeleteStudent(ctx, req.ID, req.Version)
 	if err != nil {
 		log.With(slog.Any(`body`, req)).Error(err)
 		return err
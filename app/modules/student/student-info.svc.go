@@ -28,6 +28,7 @@ type InfoServiceResponse struct {
 	FirstName     string     `json:"first_name"`
 	LastName      string     `json:"last_name"`
 	Phone         string     `json:"phone"`
+	PhotoURL      string     `json:"photo_url,omitempty"`
 	CreatedAt     time.Time  `json:"created_at"`
 	UpdatedAt     time.Time  `json:"updated_at"`
 }
@@ -87,6 +88,7 @@ func (s *Service) InfoService(ctx context.Context, req *InfoServiceRequest) (*In
 		FirstName:     data.FirstName,
 		LastName:      data.LastName,
 		Phone:         data.Phone,
+		PhotoURL:      data.PhotoURL,
 		CreatedAt:     data.CreatedAt,
 		UpdatedAt:     data.UpdatedAt,
 	}
@@ -3,7 +3,6 @@ package student
 import (
 	"github.com/easy-attend-serviceV3/app/utils"
 	"github.com/easy-attend-serviceV3/app/utils/base"
-	"github.com/easy-attend-serviceV3/config/i18n"
 	"github.com/gin-gonic/gin"
 )
 
@@ -21,6 +20,7 @@ type ListControllerResponse struct {
 	FirstName   string `json:"first_name"`
 	LastName    string `json:"last_name"`
 	Phone       string `json:"phone"`
+	PhotoURL    string `json:"photo_url,omitempty"`
 	CreatedAt   int64  `json:"created_at"`
 	UpdatedAt   int64  `json:"updated_at"`
 }
@@ -31,7 +31,7 @@ func (c *Controller) ListController(ctx *gin.Context) {
 
 	var request ListControllerRequest
 	if err := ctx.ShouldBind(&request); err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.ValidationFailed(ctx, err)
 		return
 	}
 	span.AddEvent(`student.list.ctl.request`)
@@ -40,7 +40,7 @@ func (c *Controller) ListController(ctx *gin.Context) {
 		RequestPaginate: request.RequestPaginate,
 	})
 	if err != nil {
-		base.HandleError(ctx, err)
+		base.HandleCustomError(ctx, err)
 		return
 	}
 	span.AddEvent(`student.list.ctl.callsvc`)
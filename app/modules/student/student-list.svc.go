@@ -25,6 +25,7 @@ type ListServiceResponse struct {
 	FirstName   string     `json:"first_name"`
 	LastName    string     `json:"last_name"`
 	Phone       string     `json:"phone"`
+	PhotoURL    string     `json:"photo_url,omitempty"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
 }
@@ -56,6 +57,7 @@ func (s *Service) ListService(ctx context.Context, request *ListServiceRequest)
 			FirstName:   v.FirstName,
 			LastName:    v.LastName,
 			Phone:       v.Phone,
+			PhotoURL:    v.PhotoURL,
 			CreatedAt:   v.CreatedAt,
 			UpdatedAt:   v.UpdatedAt,
 		})
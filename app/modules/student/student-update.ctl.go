@@ -2,6 +2,7 @@ package student
 
 import (
 	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
 	"github.com/easy-attend-serviceV3/app/utils/base"
 	"github.com/easy-attend-serviceV3/config/i18n"
 	"github.com/gin-gonic/gin"
@@ -9,14 +10,15 @@ import (
 )
 
 type UpdateControllerRequest struct {
-	SchoolID    string `json:"school_id" binding:"required"`
-	ClassroomID string `json:"classroom_id"` // ไม่บังคับกรอก
-	PrefixID    string `json:"prefix_id" binding:"required"`
-	GenderID    string `json:"gender_id" binding:"required"`
+	SchoolID    string `json:"school_id" binding:"required,uuid"`
+	ClassroomID string `json:"classroom_id" binding:"omitempty,uuid"` // ไม่บังคับกรอก
+	PrefixID    string `json:"prefix_id" binding:"required,uuid"`
+	GenderID    string `json:"gender_id" binding:"required,uuid"`
 	StudentCode string `json:"student_code" binding:"required"`
 	FirstName   string `json:"first_name" binding:"required"`
 	LastName    string `json:"last_name" binding:"required"`
 	Phone       string `json:"phone"`
+	PhotoURL    string `json:"photo_url" binding:"omitempty,url,max=500"`
 }
 
 func (c *Controller) UpdateController(ctx *gin.Context) {
@@ -27,41 +29,46 @@ func (c *Controller) UpdateController(ctx *gin.Context) {
 	idParam := ctx.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.InvalidField(ctx, "id", "uuid")
+		return
+	}
+
+	ov, err := auth.NewOwnershipVerifier(ctx, c.access)
+	if err == nil {
+		err = ov.VerifyStudentAccess(ctx.Request.Context(), id)
+	}
+	if err != nil {
+		base.HandleCustomError(ctx, err)
 		return
 	}
 
 	var request UpdateControllerRequest
 	if err := ctx.ShouldBindJSON(&request); err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.ValidationFailed(ctx, err)
 		return
 	}
 	span.AddEvent(`student.update.ctl.request`)
 
-	// Parse UUIDs
-	schoolID, err := uuid.Parse(request.SchoolID)
-	if err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
-		return
-	}
+	// The binding has checked the UUIDs
+	schoolID := uuid.MustParse(request.SchoolID)
 
 	var classroomID uuid.UUID
 	if request.ClassroomID != "" {
-		classroomID, err = uuid.Parse(request.ClassroomID)
-		if err != nil {
-			base.BadRequest(ctx, i18n.BadRequest, nil)
-			return
-		}
+		classroomID = uuid.MustParse(request.ClassroomID)
 	}
 
-	prefixID, err := uuid.Parse(request.PrefixID)
+	prefixID := uuid.MustParse(request.PrefixID)
+	genderID := uuid.MustParse(request.GenderID)
+
+	version, err := base.IfMatch(ctx)
 	if err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.HandleCustomError(ctx, err)
 		return
 	}
-	genderID, err := uuid.Parse(request.GenderID)
+
+	teacherID, err := actorTeacher(ctx)
 	if err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.Unauthorized(ctx, i18n.Unauthorized, nil)
 		return
 	}
 
@@ -75,8 +82,11 @@ func (c *Controller) UpdateController(ctx *gin.Context) {
 		FirstName:   request.FirstName,
 		LastName:    request.LastName,
 		Phone:       request.Phone,
+		PhotoURL:    request.PhotoURL,
+		Version:     version,
+		TeacherID:   teacherID,
 	}); err != nil {
-		base.HandleError(ctx, err)
+		base.HandleCustomError(ctx, err)
 		return
 	}
 
@@ -3,6 +3,7 @@ package student
 import (
 	"context"
 	"log/slog"
+	"time"
 
 	entitiesdto "github.com/easy-attend-serviceV3/app/modules/entities/dto"
 	"github.com/easy-attend-serviceV3/app/utils"
@@ -10,15 +11,18 @@ import (
 )
 
 type UpdateServiceRequest struct {
-	ID          uuid.UUID `json:"id"`
-	SchoolID    uuid.UUID `json:"school_id"`
-	ClassroomID uuid.UUID `json:"classroom_id"`
-	PrefixID    uuid.UUID `json:"prefix_id"`
-	GenderID    uuid.UUID `json:"gender_id"`
-	StudentCode string    `json:"student_code"`
-	FirstName   string    `json:"first_name"`
-	LastName    string    `json:"last_name"`
-	Phone       string    `json:"phone"`
+	ID          uuid.UUID  `json:"id"`
+	SchoolID    uuid.UUID  `json:"school_id"`
+	ClassroomID uuid.UUID  `json:"classroom_id"`
+	PrefixID    uuid.UUID  `json:"prefix_id"`
+	GenderID    uuid.UUID  `json:"gender_id"`
+	StudentCode string     `json:"student_code"`
+	FirstName   string     `json:"first_name"`
+	LastName    string     `json:"last_name"`
+	Phone       string     `json:"phone"`
+	PhotoURL    string     `json:"photo_url,omitempty"`
+	Version     *time.Time `json:"-"` // from If-Match; nil writes unconditionally
+	TeacherID   uuid.UUID  `json:"-"` // the teacher of the token; uuid.Nil for API keys
 }
 
 func (s *Service) UpdateService(ctx context.Context, req *UpdateServiceRequest) error {
@@ -34,7 +38,9 @@ func (s *Service) UpdateService(ctx context.Context, req *UpdateServiceRequest)
 		FirstName:   req.FirstName,
 		LastName:    req.LastName,
 		Phone:       req.Phone,
-	})
+		PhotoURL:    req.PhotoURL,
+		TeacherID:   req.TeacherID,
+	}, req.Version)
 	if err != nil {
 		log.With(slog.Any(`body`, req)).Error(err)
 		return err
@@ -22,6 +22,7 @@ type (
 	Controller struct {
 		tracer trace.Tracer
 		svc    *Service
+		access entitiesinf.AccessEntity
 	}
 )
 
@@ -35,7 +36,7 @@ type Options struct {
 	dbGender    entitiesinf.GenderEntity
 }
 
-func New(db entitiesinf.StudentEntity, dbSchool entitiesinf.SchoolEntity, dbClassroom entitiesinf.ClassroomEntity, dbPrefix entitiesinf.PrefixEntity, dbGender entitiesinf.GenderEntity) *Module {
+func New(db entitiesinf.StudentEntity, dbSchool entitiesinf.SchoolEntity, dbClassroom entitiesinf.ClassroomEntity, dbPrefix entitiesinf.PrefixEntity, dbGender entitiesinf.GenderEntity, dbAccess entitiesinf.AccessEntity) *Module {
 	tracer := otel.Tracer("easy-attend-serviceV3.modules.student")
 	svc := newService(&Options{
 		// Config: conf,
@@ -48,7 +49,7 @@ func New(db entitiesinf.StudentEntity, dbSchool entitiesinf.SchoolEntity, dbClas
 	})
 	return &Module{
 		Svc: svc,
-		Ctl: newController(tracer, svc),
+		Ctl: newController(tracer, svc, dbAccess),
 	}
 }
 
@@ -63,9 +64,10 @@ func newService(opt *Options) *Service {
 	}
 }
 
-func newController(trace trace.Tracer, svc *Service) *Controller {
+func newController(trace trace.Tracer, svc *Service, access entitiesinf.AccessEntity) *Controller {
 	return &Controller{
 		tracer: trace,
 		svc:    svc,
+		access: access,
 	}
 }
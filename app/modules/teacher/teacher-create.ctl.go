@@ -1,6 +1,8 @@
 package teacher
 
 import (
+	"errors"
+
 	"github.com/easy-attend-serviceV3/app/utils"
 	"github.com/easy-attend-serviceV3/app/utils/base"
 	"github.com/easy-attend-serviceV3/config/i18n"
@@ -10,14 +12,15 @@ import (
 )
 
 type CreateControllerRequest struct {
-	SchoolName  string `json:"school_name" binding:"required"` // เปลี่ยนจาก school_id เป็น school_name
-	ClassroomID string `json:"classroom_id"`                   // ไม่บังคับกรอก
-	PrefixID    string `json:"prefix_id" binding:"required"`
-	GenderID    string `json:"gender_id" binding:"required"`
+	InviteToken string `json:"invite_token" binding:"required_without=JoinCode,excluded_with=JoinCode"` // โทเค็นในลิงก์คำเชิญ
+	JoinCode    string `json:"join_code" binding:"required_without=InviteToken,max=16"`                 // รหัสเข้าร่วมโรงเรียน
+	ClassroomID string `json:"classroom_id" binding:"omitempty,uuid"`                                   // ไม่บังคับกรอก
+	PrefixID    string `json:"prefix_id" binding:"required,uuid"`
+	GenderID    string `json:"gender_id" binding:"required,uuid"`
 	FirstName   string `json:"first_name" binding:"required"`
 	LastName    string `json:"last_name" binding:"required"`
 	Email       string `json:"email" binding:"required,email"`
-	Password    string `json:"password" binding:"required,min=6"`
+	Password    string `json:"password" binding:"required"` // ตรวจตามนโยบายรหัสผ่าน
 	Phone       string `json:"phone"`
 }
 
@@ -50,7 +53,7 @@ func (c *Controller) CreateController(ctx *gin.Context) {
 	var request CreateControllerRequest
 	if err := ctx.ShouldBindJSON(&request); err != nil {
 		span.AddEvent("teacher.registration.bind.failed")
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.ValidationFailed(ctx, err)
 		return
 	}
 	span.AddEvent(`teacher.create.ctl.request`)
@@ -58,36 +61,25 @@ func (c *Controller) CreateController(ctx *gin.Context) {
 	// Debug: Log the parsed request (without password)
 	span.SetAttributes(
 		attribute.String("email", request.Email),
-		attribute.String("school_name", request.SchoolName),
+		attribute.Bool("invited", request.InviteToken != ""),
 		attribute.String("first_name", request.FirstName),
 	)
 
-	// Parse and validate UUIDs
+	// The binding has checked the UUIDs
 	var classroomID *uuid.UUID
 	if request.ClassroomID != "" {
-		parsed, err := uuid.Parse(request.ClassroomID)
-		if err != nil {
-			base.BadRequest(ctx, i18n.BadRequest, nil)
-			return
-		}
+		parsed := uuid.MustParse(request.ClassroomID)
 		classroomID = &parsed
 	}
 
-	prefixID, err := uuid.Parse(request.PrefixID)
-	if err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
-		return
-	}
+	prefixID := uuid.MustParse(request.PrefixID)
 
-	genderID, err := uuid.Parse(request.GenderID)
-	if err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
-		return
-	}
+	genderID := uuid.MustParse(request.GenderID)
 
 	teacher, err := c.svc.CreateService(ctx.Request.Context(), &CreateServiceRequest{
-		SchoolName:  request.SchoolName, // ส่งชื่อโรงเรียนแทน ID
-		ClassroomID: classroomID,        // ส่งเป็น pointer
+		InviteToken: request.InviteToken,
+		JoinCode:    request.JoinCode,
+		ClassroomID: classroomID, // ส่งเป็น pointer
 		PrefixID:    prefixID,
 		GenderID:    genderID,
 		FirstName:   request.FirstName,
@@ -95,9 +87,11 @@ func (c *Controller) CreateController(ctx *gin.Context) {
 		Email:       request.Email,
 		Password:    request.Password,
 		Phone:       request.Phone,
+		Client:      sessionClient(ctx, ""),
+		Lang:        ctx.GetHeader("Accept-Language"),
 	})
 	if err != nil {
-		base.HandleCustomError(ctx, err)
+		handleRegistrationError(ctx, err)
 		return
 	}
 	span.AddEvent(`teacher.create.ctl.callsvc`)
@@ -129,3 +123,18 @@ func (c *Controller) CreateController(ctx *gin.Context) {
 
 	base.Success(ctx, resp)
 }
+
+// handleRegistrationError answers refused invitations and join codes; the
+// lockout they count towards is answered as for logins.
+func handleRegistrationError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidInvitation):
+		base.BadRequest(ctx, i18n.InvitationInvalid, nil)
+	case errors.Is(err, ErrInvitationEmail):
+		base.BadRequest(ctx, i18n.InvitationEmail, nil)
+	case errors.Is(err, ErrInvalidJoinCode):
+		base.BadRequest(ctx, i18n.JoinCodeInvalid, nil)
+	default:
+		handleLoginError(ctx, err)
+	}
+}
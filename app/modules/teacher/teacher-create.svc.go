@@ -2,17 +2,22 @@ package teacher
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"strings"
 	"time"
 
 	entitiesdto "github.com/easy-attend-serviceV3/app/modules/entities/dto"
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/easy-attend-serviceV3/app/modules/lockout"
 	"github.com/easy-attend-serviceV3/app/utils"
 	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
 	"github.com/google/uuid"
 )
 
 type CreateServiceRequest struct {
-	SchoolName  string     `json:"school_name"`            // ชื่อโรงเรียน (ระบบจะหาหรือสร้างใหม่)
+	InviteToken string     `json:"invite_token"`           // โทเค็นในลิงก์คำเชิญ
+	JoinCode    string     `json:"join_code"`              // รหัสเข้าร่วมโรงเรียน ใช้เมื่อไม่มีคำเชิญ
 	ClassroomID *uuid.UUID `json:"classroom_id,omitempty"` // ไม่บังคับกรอก
 	PrefixID    uuid.UUID  `json:"prefix_id"`
 	GenderID    uuid.UUID  `json:"gender_id"`
@@ -21,6 +26,9 @@ type CreateServiceRequest struct {
 	Email       string     `json:"email"`
 	Password    string     `json:"password"`
 	Phone       string     `json:"phone"`
+
+	Client SessionClient `json:"-"`
+	Lang   string        `json:"-"`
 }
 
 type CreateServiceResponse struct {
@@ -45,15 +53,18 @@ func (s *Service) CreateService(ctx context.Context, req *CreateServiceRequest)
 	span.AddEvent(`teacher.svc.create.start`)
 
 	// Hash password
-	passwordHasher := auth.NewPasswordHasher()
-	hashedPassword, err := passwordHasher.HashPassword(req.Password)
+	hashedPassword, err := s.hashNewPassword("password", req.Password)
 	if err != nil {
 		log.Error(err)
 		return nil, err
 	}
 
-	// Find or create school by name
-	school, err := s.dbSchool.FindOrCreateSchoolByName(ctx, req.SchoolName)
+	// The invitation or join code decides the school
+	invitation, schoolID, err := s.registrationSchool(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	school, err := s.dbSchool.GetByIDSchool(ctx, schoolID)
 	if err != nil {
 		log.Error(err)
 		return nil, err
@@ -63,37 +74,40 @@ func (s *Service) CreateService(ctx context.Context, req *CreateServiceRequest)
 	_, err = s.dbPrefix.GetByIDPrefix(ctx, req.PrefixID)
 	if err != nil {
 		log.Error(err)
-		return nil, fmt.Errorf("prefix not found: %s", req.PrefixID.String())
+		return nil, referenceError(err, "prefix")
 	}
 
 	// Validate gender_id exists
 	_, err = s.dbGender.GetByIDGender(ctx, req.GenderID)
 	if err != nil {
 		log.Error(err)
-		return nil, fmt.Errorf("gender not found: %s", req.GenderID.String())
+		return nil, referenceError(err, "gender")
 	}
 
 	// Check if email already exists
 	existingTeacher, err := s.db.GetTeacherByEmail(ctx, req.Email)
 	if err == nil && existingTeacher != nil {
-		return nil, fmt.Errorf("email already exists: %s", req.Email)
+		return nil, base.ConflictError{Resource: "teacher", Value: req.Email}
 	}
 
 	// Handle optional classroom_id with validation
 	var classroomIDPtr *uuid.UUID
 	if req.ClassroomID != nil {
 		// Validate that classroom exists before using it
-		_, err := s.dbClassroom.GetByIDClassroom(ctx, *req.ClassroomID)
+		classroom, err := s.dbClassroom.GetByIDClassroom(ctx, *req.ClassroomID)
 		if err != nil {
 			log.Error(err)
-			return nil, fmt.Errorf("classroom not found: %s", req.ClassroomID.String())
+			return nil, referenceError(err, "classroom")
+		}
+		if classroom.SchoolID != school.ID {
+			return nil, base.ReferenceError{Resource: "teacher", Reference: "classroom"}
 		}
 		classroomIDPtr = req.ClassroomID // ส่ง pointer ตรงๆ
 	}
 	// ถ้าไม่มีค่า classroomIDPtr จะเป็น nil
 
-	teacher, err := s.db.CreateTeacher(ctx, &entitiesdto.TeacherCreateRequest{
-		SchoolID:    school.ID,      // Use found/created school ID
+	create := &entitiesdto.TeacherCreateRequest{
+		SchoolID:    school.ID,
 		ClassroomID: classroomIDPtr, // ส่ง pointer ที่อาจเป็น nil
 		PrefixID:    req.PrefixID,
 		GenderID:    req.GenderID,
@@ -102,31 +116,40 @@ func (s *Service) CreateService(ctx context.Context, req *CreateServiceRequest)
 		Email:       req.Email,
 		Password:    hashedPassword, // Use hashed password
 		Phone:       req.Phone,
-	})
+		Role:        auth.RoleTeacher,
+	}
+	if invitation != nil {
+		create.Role = invitation.Role
+		create.InvitationID = &invitation.ID
+	}
+	teacher, err := s.db.CreateTeacher(ctx, create)
 	if err != nil {
+		var notFound base.NotFoundError
+		if errors.As(err, &notFound) && notFound.Resource == "teacher_invitation" {
+			// Accepted by a concurrent registration
+			return nil, ErrInvalidInvitation
+		}
 		log.Error(err)
 		return nil, err
 	}
+	log.Infof("security: teacher %s registered in school %s as %s", teacher.ID, school.ID, create.Role)
 
 	// Generate tokens for the new teacher
-	tokenMgr := auth.NewTokenManagerWithConfig(
-		s.config.JWT.SecretKey,
-		time.Duration(s.config.JWT.AccessTokenExpiry)*time.Hour,
-		time.Duration(s.config.JWT.RefreshTokenExpiry)*time.Hour,
-	)
-	tokens, err := tokenMgr.GenerateTokenPair(
-		teacher.ID,
-		teacher.Email,
-		teacher.FirstName,
-		teacher.LastName,
-		"teacher",
-	)
+	tokens, err := s.issueTokens(ctx, teacher, req.Client)
 	if err != nil {
 		log.Error(err)
 		// Don't fail the registration if token generation fails
 		// Log the error and continue
 	}
 
+	// Ask the teacher to confirm the email; they can request another link.
+	// An invitation reached them at it already.
+	if invitation == nil {
+		if err := s.sendVerification(ctx, teacher, req.Lang); err != nil {
+			log.Error(err)
+		}
+	}
+
 	// Handle optional classroom_id for response
 	var responseClassroomID *uuid.UUID
 	if teacher.ClassroomID != nil {
@@ -136,7 +159,7 @@ func (s *Service) CreateService(ctx context.Context, req *CreateServiceRequest)
 	response := &CreateServiceResponse{
 		ID:          teacher.ID,
 		SchoolID:    teacher.SchoolID,
-		SchoolName:  school.Name,         // เพิ่มชื่อโรงเรียน
+		SchoolName:  school.Name,
 		ClassroomID: responseClassroomID, // Handle optional classroom ID
 		PrefixID:    teacher.PrefixID,
 		GenderID:    teacher.GenderID,
@@ -157,3 +180,53 @@ func (s *Service) CreateService(ctx context.Context, req *CreateServiceRequest)
 	span.AddEvent(`teacher.svc.create.end`)
 	return response, nil
 }
+
+// registrationSchool returns the school req registers in: the one of its
+// invitation, which is returned too, or of its join code. Wrong tokens and
+// codes count towards the lockout of the client's IP, so codes cannot be
+// guessed.
+func (s *Service) registrationSchool(ctx context.Context, req *CreateServiceRequest) (*ent.TeacherInvitationEntity, uuid.UUID, error) {
+	_, log := utils.LogSpanFromContext(ctx)
+
+	ipKey := lockout.IPKey(req.Client.IP)
+	if err := s.locks.CheckService(ctx, ipKey); err != nil {
+		return nil, uuid.Nil, err
+	}
+	refuse := func(refusal error) (*ent.TeacherInvitationEntity, uuid.UUID, error) {
+		log.Infof("security: registration from %s refused: %v", req.Client.IP, refusal)
+		if err := s.loginFailed(ctx, req.Client.IP, ipKey); !errors.Is(err, ErrInvalidCredentials) {
+			return nil, uuid.Nil, err
+		}
+		return nil, uuid.Nil, refusal
+	}
+	var notFound base.NotFoundError
+
+	if req.InviteToken != "" {
+		invitation, err := s.dbInvitation.GetPendingTeacherInvitation(ctx, auth.HashOpaqueToken(req.InviteToken))
+		if errors.As(err, &notFound) {
+			return refuse(ErrInvalidInvitation)
+		}
+		if err != nil {
+			log.Error(err)
+			return nil, uuid.Nil, err
+		}
+		if !strings.EqualFold(invitation.Email, req.Email) {
+			return nil, uuid.Nil, ErrInvitationEmail
+		}
+		return invitation, invitation.SchoolID, nil
+	}
+
+	code := auth.NormalizeJoinCode(req.JoinCode)
+	if code == "" {
+		return refuse(ErrInvalidJoinCode)
+	}
+	joinCode, err := s.dbJoinCode.GetSchoolJoinCodeByCode(ctx, code)
+	if errors.As(err, &notFound) {
+		return refuse(ErrInvalidJoinCode)
+	}
+	if err != nil {
+		log.Error(err)
+		return nil, uuid.Nil, err
+	}
+	return nil, joinCode.SchoolID, nil
+}
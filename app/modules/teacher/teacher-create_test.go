@@ -0,0 +1,102 @@
+package teacher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/google/uuid"
+)
+
+// Test_CreateServiceInvitation registers teachers with an invitation to a
+// school as admin. The invitation decides the school, and works once before
+// it expires, for the email it was sent to.
+func Test_CreateServiceInvitation(t *testing.T) {
+	const email = "invited@school.ac.th"
+	school, otherSchool := uuid.New(), uuid.New()
+	classroom, otherClassroom := uuid.New(), uuid.New()
+	accepted := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name        string
+		invitation  ent.TeacherInvitationEntity
+		email       string
+		classroom   *uuid.UUID
+		joinCode    string
+		wantErr     error
+		wantInvalid bool // the classroom is refused as a bad reference
+	}{
+		{name: "Test accept", email: email},
+		{name: "Test accept with email in another case", email: "Invited@School.ac.th"},
+		{name: "Test accept into classroom of school", email: email, classroom: &classroom},
+		{name: "Test accept ignores join code", email: email, joinCode: "OTHER-SCHOOL"},
+		{name: "Test expired", invitation: ent.TeacherInvitationEntity{ExpiresAt: time.Now().Add(-time.Minute)}, email: email, wantErr: ErrInvalidInvitation},
+		{name: "Test used", invitation: ent.TeacherInvitationEntity{AcceptedAt: &accepted}, email: email, wantErr: ErrInvalidInvitation},
+		{name: "Test another email", email: "uninvited@school.ac.th", wantErr: ErrInvitationEmail},
+		{name: "Test classroom of another school", email: email, classroom: &otherClassroom, wantInvalid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newMemoryEntities()
+			svc := newTestService(t, db)
+			db.classrooms[classroom] = &ent.ClassroomEntity{ID: classroom, SchoolID: school}
+			db.classrooms[otherClassroom] = &ent.ClassroomEntity{ID: otherClassroom, SchoolID: otherSchool}
+
+			token, hash, err := auth.NewOpaqueToken()
+			if err != nil {
+				t.Fatal(err)
+			}
+			invitation := tt.invitation
+			invitation.ID, invitation.SchoolID, invitation.Email, invitation.Role, invitation.TokenHash = uuid.New(), school, email, auth.RoleSchoolAdmin, hash
+			if invitation.ExpiresAt.IsZero() {
+				invitation.ExpiresAt = time.Now().Add(time.Hour)
+			}
+			db.invitations = append(db.invitations, &invitation)
+
+			resp, err := svc.CreateService(context.Background(), &CreateServiceRequest{
+				InviteToken: token,
+				JoinCode:    tt.joinCode,
+				ClassroomID: tt.classroom,
+				FirstName:   "Somsri",
+				LastName:    "Jaidee",
+				Email:       tt.email,
+				Password:    "correct horse battery",
+			})
+			var reference base.ReferenceError
+			switch {
+			case tt.wantInvalid:
+				if !errors.As(err, &reference) || reference.Reference != "classroom" {
+					t.Fatalf("CreateService() error = %v, want a bad classroom reference", err)
+				}
+			case !errors.Is(err, tt.wantErr):
+				t.Fatalf("CreateService() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				if len(db.teachers) != 0 {
+					t.Errorf("%d teachers registered, want none", len(db.teachers))
+				}
+				if tt.invitation.AcceptedAt == nil && invitation.AcceptedAt != nil {
+					t.Error("the refused registration accepted the invitation")
+				}
+				return
+			}
+			if resp.SchoolID != school {
+				t.Errorf("registered in school %s, want the invitation's %s", resp.SchoolID, school)
+			}
+			if role := db.registered[resp.ID]; role != auth.RoleSchoolAdmin {
+				t.Errorf("registered as %q, want %q", role, auth.RoleSchoolAdmin)
+			}
+			if invitation.AcceptedBy == nil || *invitation.AcceptedBy != resp.ID {
+				t.Errorf("invitation accepted by %v, want %s", invitation.AcceptedBy, resp.ID)
+			}
+			if resp.AccessToken == "" {
+				t.Error("registration answered without tokens")
+			}
+		})
+	}
+}
@@ -2,8 +2,8 @@ package teacher
 
 import (
 	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
 	"github.com/easy-attend-serviceV3/app/utils/base"
-	"github.com/easy-attend-serviceV3/config/i18n"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -16,15 +16,32 @@ func (c *Controller) DeleteController(ctx *gin.Context) {
 	idParam := ctx.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.InvalidField(ctx, "id", "uuid")
 		return
 	}
+
+	ov, err := auth.NewOwnershipVerifier(ctx, c.access)
+	if err == nil {
+		err = ov.VerifyTeacherAccess(ctx.Request.Context(), id)
+	}
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
 	span.AddEvent(`teacher.delete.ctl.request`)
 
+	version, err := base.IfMatch(ctx)
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
 	if err := c.svc.DeleteService(ctx.Request.Context(), &DeleteServiceRequest{
-		ID: id,
+		ID:      id,
+		Version: version,
 	}); err != nil {
-		base.HandleError(ctx, err)
+		base.HandleCustomError(ctx, err)
 		return
 	}
 
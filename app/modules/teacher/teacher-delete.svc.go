@@ -3,32 +3,22 @@ package teacher
 import (
 	"context"
 	"log/slog"
+	"time"
 
 	"github.com/easy-attend-serviceV3/app/utils"
 	"github.com/google/uuid"
 )
 
 type DeleteServiceRequest struct {
-	ID uuid.UUID `json:"id"`
+	ID      uuid.UUID  `json:"id"`
+	Version *time.Time `json:"-"` // from If-Match; nil writes unconditionally
 }
 
 func (s *Service) DeleteService(ctx context.Context, req *DeleteServiceRequest) error {
 	span, log := utils.LogSpanFromContext(ctx)
 	span.AddEvent(`teacher.svc.delete.start`)
 
-	// Check if teacher exists first
-	exists, err := s.db.CheckExistTeacher(ctx, req.ID)
-	if err != nil {
-		log.With(slog.Any(`body`, req)).Error(err)
-		return err
-	}
-
-	if !exists {
-		log.With(slog.Any(`body`, req)).Warnf("teacher not found")
-		return nil // Teacher already doesn't exist, consider it successful
-	}
-
-	err = s.db.DeleteTeacher(ctx, req.ID)
+	err := s.db.DeleteTeacher(ctx, req.ID, req.Version)
 	if err != nil {
 		log.With(slog.Any(`body`, req)).Error(err)
 		return err
@@ -0,0 +1,53 @@
+package teacher
+
+import (
+	"errors"
+
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/config/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+func (c *Controller) VerifyEmailController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.email.verify.ctl.start`)
+
+	var request VerifyEmailServiceRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		base.ValidationFailed(ctx, err)
+		return
+	}
+
+	if err := c.svc.VerifyEmailService(ctx.Request.Context(), &request); err != nil {
+		if errors.Is(err, ErrInvalidLinkToken) {
+			base.BadRequest(ctx, i18n.LinkTokenInvalid, nil)
+			return
+		}
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`teacher.email.verify.ctl.end`)
+	base.Success(ctx, nil)
+}
+
+func (c *Controller) ResendVerificationController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.email.resend.ctl.start`)
+
+	teacherID, err := auth.GetUserID(ctx)
+	if err != nil {
+		base.Unauthorized(ctx, i18n.Unauthorized, nil)
+		return
+	}
+
+	if err := c.svc.ResendVerificationService(ctx.Request.Context(), teacherID, ctx.GetHeader("Accept-Language")); err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`teacher.email.resend.ctl.end`)
+	base.Success(ctx, nil)
+}
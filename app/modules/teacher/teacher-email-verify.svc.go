@@ -0,0 +1,60 @@
+package teacher
+
+import (
+	"context"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/google/uuid"
+)
+
+type VerifyEmailServiceRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// VerifyEmailService marks the teacher's email as verified with the token
+// mailed on registration.
+func (s *Service) VerifyEmailService(ctx context.Context, req *VerifyEmailServiceRequest) error {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.email.verify.start`)
+
+	token, err := s.consumeLinkToken(ctx, ent.TeacherTokenEmailVerify, req.Token)
+	if err != nil {
+		return err
+	}
+	if err := s.db.VerifyTeacherEmail(ctx, token.TeacherID); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	span.AddEvent(`teacher.svc.email.verify.end`)
+	return nil
+}
+
+// ResendVerificationService mails a new verification link unless the email is
+// already verified.
+func (s *Service) ResendVerificationService(ctx context.Context, teacherID uuid.UUID, lang string) error {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.email.resend.start`)
+
+	teacher, err := s.db.GetByIDTeacher(ctx, teacherID)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	if teacher.EmailVerifiedAt != nil {
+		return nil
+	}
+	if err := s.sendVerification(ctx, teacher, lang); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	span.AddEvent(`teacher.svc.email.resend.end`)
+	return nil
+}
+
+func (s *Service) sendVerification(ctx context.Context, teacher *ent.TeacherEntity, lang string) error {
+	return s.sendLink(ctx, teacher, emailVerifyLink, time.Duration(s.config.EmailVerifyTTL)*time.Hour, lang)
+}
@@ -0,0 +1,36 @@
+package teacher
+
+import (
+	"errors"
+
+	"github.com/easy-attend-serviceV3/app/utils/base"
+)
+
+var (
+	ErrInvalidCredentials     = errors.New("invalid email or password")
+	ErrInvalidRefreshToken    = errors.New("invalid or expired refresh token")
+	ErrInvalidLinkToken       = errors.New("invalid, used or expired link token")
+	ErrRoleNotGrantable       = errors.New("role grants permissions the caller does not hold")
+	ErrInvalidCurrentPassword = errors.New("current password is wrong")
+	ErrInvalidMFAChallenge    = errors.New("invalid, used or expired mfa challenge")
+	ErrInvalidMFACode         = errors.New("invalid or already used authentication code")
+	ErrMFARequired            = errors.New("a role of the teacher requires mfa")
+	ErrInvalidOIDCState       = errors.New("invalid, used or expired oidc state")
+	ErrOIDCLoginFailed        = errors.New("oidc sign-in failed at the provider")
+	ErrOIDCDomainNotAllowed   = errors.New("no school trusts the account's domain with the provider")
+	ErrOIDCNoAccount          = errors.New("no teacher for the oidc account")
+	ErrInvalidInvitation      = errors.New("invalid, accepted or expired invitation")
+	ErrInvitationEmail        = errors.New("invitation was sent to another email")
+	ErrInvalidJoinCode        = errors.New("invalid or expired school join code")
+	ErrInsecureSecretKey      = errors.New("set JWT_SECRET_KEY to a secret of its own to sign tokens with HS256")
+)
+
+// referenceError reports a missing lookup row as a bad reference from the
+// teacher being written, leaving other failures untouched.
+func referenceError(err error, reference string) error {
+	var notFound base.NotFoundError
+	if errors.As(err, &notFound) {
+		return base.ReferenceError{Resource: "teacher", Reference: reference}
+	}
+	return err
+}
@@ -0,0 +1,106 @@
+package teacher
+
+import (
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/config/i18n"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (c *Controller) InvitationListController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.invitation.list.ctl.start`)
+
+	schoolID, ok := c.invitationSchool(ctx)
+	if !ok {
+		return
+	}
+
+	data, err := c.svc.InvitationListService(ctx.Request.Context(), schoolID)
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`teacher.invitation.list.ctl.end`)
+	base.Success(ctx, data)
+}
+
+func (c *Controller) InvitationCreateController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.invitation.create.ctl.start`)
+
+	schoolID, ok := c.invitationSchool(ctx)
+	if !ok {
+		return
+	}
+
+	var req InvitationCreateServiceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		base.ValidationFailed(ctx, err)
+		return
+	}
+	actorID, err := auth.GetUserID(ctx)
+	if err != nil {
+		base.Unauthorized(ctx, i18n.Unauthorized, nil)
+		return
+	}
+	req.SchoolID = schoolID
+	req.ActorID = actorID
+	req.ActorPermissions = auth.GetPermissions(ctx)
+	req.Lang = ctx.GetHeader("Accept-Language")
+
+	data, err := c.svc.InvitationCreateService(ctx.Request.Context(), &req)
+	if err != nil {
+		handleRoleError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`teacher.invitation.create.ctl.end`)
+	base.Success(ctx, data)
+}
+
+func (c *Controller) InvitationDeleteController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.invitation.delete.ctl.start`)
+
+	schoolID, ok := c.invitationSchool(ctx)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(ctx.Param("invitation_id"))
+	if err != nil {
+		base.InvalidField(ctx, "invitation_id", "uuid")
+		return
+	}
+
+	if err := c.svc.InvitationDeleteService(ctx.Request.Context(), schoolID, id); err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`teacher.invitation.delete.ctl.end`)
+	base.Success(ctx, nil)
+}
+
+// invitationSchool returns the school of the URL once the caller may access
+// it, answering the request otherwise.
+func (c *Controller) invitationSchool(ctx *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		base.InvalidField(ctx, "id", "uuid")
+		return uuid.Nil, false
+	}
+
+	ov, err := auth.NewOwnershipVerifier(ctx, c.access)
+	if err == nil {
+		err = ov.VerifySchoolAccess(ctx.Request.Context(), id)
+	}
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,153 @@
+package teacher
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/google/uuid"
+)
+
+// invitationPath is the web app page that opens the registration form for an
+// invitation.
+const invitationPath = "/invite"
+
+type InvitationServiceResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	SchoolID   uuid.UUID  `json:"school_id"`
+	Email      string     `json:"email"`
+	Role       string     `json:"role"`
+	InvitedBy  *uuid.UUID `json:"invited_by,omitempty"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"` // วันที่ครูสมัครด้วยคำเชิญ
+	AcceptedBy *uuid.UUID `json:"accepted_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type InvitationCreateServiceRequest struct {
+	Email string `json:"email" binding:"required,email,max=100"`
+	Role  string `json:"role" binding:"omitempty,max=50"` // บทบาทเมื่อสมัคร (ค่าเริ่มต้น teacher)
+
+	SchoolID         uuid.UUID `json:"-"`
+	ActorID          uuid.UUID `json:"-"`
+	ActorPermissions []string  `json:"-"`
+	Lang             string    `json:"-"`
+}
+
+func toInvitationResponse(i *ent.TeacherInvitationEntity) *InvitationServiceResponse {
+	return &InvitationServiceResponse{
+		ID:         i.ID,
+		SchoolID:   i.SchoolID,
+		Email:      i.Email,
+		Role:       i.Role,
+		InvitedBy:  i.InvitedBy,
+		ExpiresAt:  i.ExpiresAt,
+		AcceptedAt: i.AcceptedAt,
+		AcceptedBy: i.AcceptedBy,
+		CreatedAt:  i.CreatedAt,
+	}
+}
+
+func (s *Service) InvitationListService(ctx context.Context, schoolID uuid.UUID) ([]*InvitationServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.invitation.list.start`)
+
+	invitations, err := s.dbInvitation.GetListTeacherInvitation(ctx, schoolID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	resp := make([]*InvitationServiceResponse, 0, len(invitations))
+	for _, i := range invitations {
+		resp = append(resp, toInvitationResponse(i))
+	}
+
+	span.AddEvent(`teacher.svc.invitation.list.end`)
+	return resp, nil
+}
+
+// InvitationCreateService mails req.Email a link to register as a teacher of
+// req.SchoolID with req.Role. The actor must be able to grant the role, as
+// when assigning it.
+func (s *Service) InvitationCreateService(ctx context.Context, req *InvitationCreateServiceRequest) (*InvitationServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.invitation.create.start`)
+
+	if req.Role == "" {
+		req.Role = auth.RoleTeacher
+	}
+	role, err := s.dbRole.GetRoleByName(ctx, req.Role)
+	if err != nil {
+		log.With(slog.Any(`body`, req)).Error(err)
+		return nil, err
+	}
+	if !canGrant(req.ActorPermissions, role) {
+		return nil, ErrRoleNotGrantable
+	}
+
+	var notFound base.NotFoundError
+	if _, err := s.db.GetTeacherByEmail(ctx, req.Email); err == nil {
+		return nil, base.ConflictError{Resource: "teacher", Value: req.Email}
+	} else if !errors.As(err, &notFound) {
+		log.Error(err)
+		return nil, err
+	}
+	school, err := s.dbSchool.GetByIDSchool(ctx, req.SchoolID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	token, hash, err := auth.NewOpaqueToken()
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	ttl := time.Duration(s.config.InvitationTTL) * time.Hour
+	invitation := &ent.TeacherInvitationEntity{
+		SchoolID:  school.ID,
+		Email:     req.Email,
+		Role:      role.Name,
+		TokenHash: hash,
+		InvitedBy: &req.ActorID,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.dbInvitation.CreateTeacherInvitation(ctx, invitation); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	log.Infof("security: %s invited %s to school %s as %s", req.ActorID, invitation.Email, school.ID, role.Name)
+
+	if err := s.sendMail(ctx, invitation.Email, "invitation", map[string]any{
+		"School": school.Name,
+		"Role":   role.Name,
+		"Link":   s.appLink(invitationPath, token),
+		"Hours":  int(ttl.Hours()),
+	}, req.Lang); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	span.AddEvent(`teacher.svc.invitation.create.end`)
+	return toInvitationResponse(invitation), nil
+}
+
+// InvitationDeleteService withdraws an invitation; its link stops working.
+func (s *Service) InvitationDeleteService(ctx context.Context, schoolID, id uuid.UUID) error {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.invitation.delete.start`)
+
+	if err := s.dbInvitation.DeleteTeacherInvitation(ctx, schoolID, id); err != nil {
+		log.Error(err)
+		return err
+	}
+	log.Infof("security: invitation %s of school %s withdrawn", id, schoolID)
+
+	span.AddEvent(`teacher.svc.invitation.delete.end`)
+	return nil
+}
@@ -3,7 +3,6 @@ package teacher
 import (
 	"github.com/easy-attend-serviceV3/app/utils"
 	"github.com/easy-attend-serviceV3/app/utils/base"
-	"github.com/easy-attend-serviceV3/config/i18n"
 	"github.com/gin-gonic/gin"
 )
 
@@ -31,7 +30,7 @@ func (c *Controller) ListController(ctx *gin.Context) {
 
 	var request ListControllerRequest
 	if err := ctx.ShouldBind(&request); err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.ValidationFailed(ctx, err)
 		return
 	}
 	span.AddEvent(`teacher.list.ctl.request`)
@@ -40,7 +39,7 @@ func (c *Controller) ListController(ctx *gin.Context) {
 		RequestPaginate: request.RequestPaginate,
 	})
 	if err != nil {
-		base.HandleError(ctx, err)
+		base.HandleCustomError(ctx, err)
 		return
 	}
 	span.AddEvent(`teacher.list.ctl.callsvc`)
@@ -1,9 +1,15 @@
 package teacher
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
+	"github.com/easy-attend-serviceV3/app/modules/lockout"
 	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/config/i18n"
 	"github.com/gin-gonic/gin"
 	"go.opentelemetry.io/otel/attribute"
 )
@@ -19,10 +25,7 @@ func (c *Controller) Login(ctx *gin.Context) {
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		log.Error(err)
 		span.AddEvent("teacher.login.bind.failed")
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request body",
-			"message": err.Error(),
-		})
+		base.ValidationFailed(ctx, err)
 		return
 	}
 
@@ -30,17 +33,24 @@ func (c *Controller) Login(ctx *gin.Context) {
 	span.AddEvent("teacher.login.request.parsed")
 	span.SetAttributes(attribute.String("email", req.Email))
 
+	req.Client = sessionClient(ctx, req.Device)
 	result, err := c.svc.LoginService(ctx, &req)
 	if err != nil {
 		log.Error(err)
-		status := http.StatusInternalServerError
-		if err.Error() == "invalid email or password" {
-			status = http.StatusUnauthorized
+		if errors.Is(err, ErrInvalidCredentials) {
+			base.Unauthorized(ctx, i18n.Unauthorized, nil)
+			return
 		}
-		ctx.JSON(status, gin.H{
-			"error":   "Login failed",
-			"message": err.Error(),
+		handleLoginError(ctx, err)
+		return
+	}
+
+	if result.MFA != nil {
+		ctx.JSON(http.StatusOK, gin.H{
+			"message": "MFA code required",
+			"data":    result.MFA,
 		})
+		span.AddEvent(`teacher.ctl.login.mfa`)
 		return
 	}
 
@@ -51,3 +61,21 @@ func (c *Controller) Login(ctx *gin.Context) {
 
 	span.AddEvent(`teacher.ctl.login.end`)
 }
+
+// handleLoginError answers the errors shared by the steps of a login.
+func handleLoginError(ctx *gin.Context, err error) {
+	var locked *lockout.LockedError
+	switch {
+	case errors.As(err, &locked):
+		ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+		base.TooManyRequests(ctx, i18n.TooManyAttempts, nil)
+	case errors.Is(err, ErrInvalidMFACode):
+		base.Unauthorized(ctx, i18n.MFACodeInvalid, nil)
+	case errors.Is(err, ErrInvalidMFAChallenge):
+		base.Unauthorized(ctx, i18n.MFAChallengeInvalid, nil)
+	case errors.Is(err, ErrMFARequired):
+		base.Forbidden(ctx, i18n.MFARequired, nil)
+	default:
+		base.HandleCustomError(ctx, err)
+	}
+}
@@ -5,14 +5,18 @@ import (
 	"errors"
 	"time"
 
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/easy-attend-serviceV3/app/modules/lockout"
 	"github.com/easy-attend-serviceV3/app/utils"
-	"github.com/easy-attend-serviceV3/app/utils/auth"
 	"github.com/google/uuid"
 )
 
 type LoginServiceRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
+	Device   string `json:"device" binding:"omitempty,max=100"` // ชื่ออุปกรณ์ที่แสดงในรายการเซสชัน
+
+	Client SessionClient `json:"-"`
 }
 
 type LoginServiceResponse struct {
@@ -29,50 +33,96 @@ type LoginServiceResponse struct {
 	RefreshToken string     `json:"refresh_token"`
 	TokenType    string     `json:"token_type"`
 	ExpiresAt    time.Time  `json:"expires_at"`
+
+	RecoveryCodes []string `json:"recovery_codes,omitempty"` // แสดงครั้งเดียวเมื่อยืนยัน MFA ระหว่างเข้าสู่ระบบ
+
+	MFA *MFAChallengeResponse `json:"-"` // set instead of the tokens when a TOTP code is needed
 }
 
 func (s *Service) LoginService(ctx context.Context, req *LoginServiceRequest) (*LoginServiceResponse, error) {
 	span, log := utils.LogSpanFromContext(ctx)
 	span.AddEvent(`teacher.svc.login.start`)
 
+	// Refuse locked out emails and IPs before spending time on the hash
+	emailKey, ipKey := lockout.EmailKey(req.Email), lockout.IPKey(req.Client.IP)
+	if err := s.locks.CheckService(ctx, emailKey, ipKey); err != nil {
+		return nil, err
+	}
+
 	// Get teacher by email
 	teacher, err := s.db.GetTeacherByEmail(ctx, req.Email)
 	if err != nil {
 		log.Error(err)
-		return nil, errors.New("invalid email or password")
+		return nil, s.loginFailed(ctx, req.Client.IP, emailKey, ipKey)
 	}
 
 	// Verify password
-	passwordHasher := auth.NewPasswordHasher()
-	valid, err := passwordHasher.VerifyPassword(req.Password, teacher.Password)
+	valid, err := s.hasher.VerifyPassword(req.Password, teacher.Password)
 	if err != nil {
 		log.Error(err)
-		return nil, errors.New("invalid email or password")
+		return nil, s.loginFailed(ctx, req.Client.IP, emailKey, ipKey)
 	}
 
 	if !valid {
-		return nil, errors.New("invalid email or password")
+		return nil, s.loginFailed(ctx, req.Client.IP, emailKey, ipKey)
+	}
+	s.rehash(ctx, teacher.ID, req.Password, teacher.Password)
+
+	response, err := s.signIn(ctx, teacher, req.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	span.AddEvent(`teacher.svc.login.end`)
+	return response, nil
+}
+
+// signIn finishes the login of a teacher who proved who they are, with a
+// password or an OpenID provider. Teachers with MFA, or whose role requires
+// it, continue with a code; the lockout is only reset once that code is
+// right too.
+func (s *Service) signIn(ctx context.Context, teacher *ent.TeacherEntity, client SessionClient) (*LoginServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+
+	mfa, err := s.teacherMFA(ctx, teacher.ID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	required, err := s.mfaRequired(ctx, teacher.ID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	if mfaEnabled(mfa) || required {
+		challenge, err := s.mfaChallenge(ctx, teacher, !mfaEnabled(mfa))
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		span.AddEvent(`teacher.svc.login.mfa`)
+		return &LoginServiceResponse{MFA: challenge}, nil
+	}
+	return s.completeLogin(ctx, teacher, client)
+}
+
+// completeLogin resets the lockout of a teacher who proved who they are and
+// signs them in.
+func (s *Service) completeLogin(ctx context.Context, teacher *ent.TeacherEntity, client SessionClient) (*LoginServiceResponse, error) {
+	_, log := utils.LogSpanFromContext(ctx)
+
+	if err := s.locks.SucceedService(ctx, lockout.EmailKey(teacher.Email)); err != nil {
+		log.Error(err)
 	}
 
 	// Generate tokens
-	tokenMgr := auth.NewTokenManagerWithConfig(
-		s.config.JWT.SecretKey,
-		time.Duration(s.config.JWT.AccessTokenExpiry)*time.Hour,
-		time.Duration(s.config.JWT.RefreshTokenExpiry)*time.Hour,
-	)
-	tokens, err := tokenMgr.GenerateTokenPair(
-		teacher.ID,
-		teacher.Email,
-		teacher.FirstName,
-		teacher.LastName,
-		"teacher",
-	)
+	tokens, err := s.issueTokens(ctx, teacher, client)
 	if err != nil {
 		log.Error(err)
 		return nil, errors.New("failed to generate authentication tokens")
 	}
 
-	response := &LoginServiceResponse{
+	return &LoginServiceResponse{
 		ID:           teacher.ID,
 		SchoolID:     teacher.SchoolID,
 		ClassroomID:  teacher.ClassroomID, // teacher.ClassroomID เป็น pointer อยู่แล้ว
@@ -86,8 +136,15 @@ func (s *Service) LoginService(ctx context.Context, req *LoginServiceRequest) (*
 		RefreshToken: tokens.RefreshToken,
 		TokenType:    tokens.TokenType,
 		ExpiresAt:    tokens.ExpiresAt,
-	}
+	}, nil
+}
 
-	span.AddEvent(`teacher.svc.login.end`)
-	return response, nil
+// loginFailed counts a failed login against keys. It returns the LockedError
+// of the attempt that locks them out, and ErrInvalidCredentials otherwise.
+func (s *Service) loginFailed(ctx context.Context, ip string, keys ...lockout.Key) error {
+	var locked *lockout.LockedError
+	if err := s.locks.FailService(ctx, ip, keys...); errors.As(err, &locked) {
+		return err
+	}
+	return ErrInvalidCredentials
 }
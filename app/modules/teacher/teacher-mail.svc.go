@@ -0,0 +1,76 @@
+package teacher
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	msg "github.com/easy-attend-serviceV3/config/i18n"
+	"github.com/easy-attend-serviceV3/internal/mailer"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// mailLink is a single-use link emailed to a teacher. Template names the
+// i18n messages "mail-<template>-subject" and "mail-<template>-body".
+type mailLink struct {
+	Purpose  string
+	Path     string
+	Template string
+}
+
+var (
+	passwordResetLink = mailLink{Purpose: ent.TeacherTokenPasswordReset, Path: "/reset-password", Template: "password-reset"}
+	emailVerifyLink   = mailLink{Purpose: ent.TeacherTokenEmailVerify, Path: "/verify-email", Template: "email-verify"}
+)
+
+// sendLink issues a token for link and mails it to teacher in lang, the
+// request's Accept-Language.
+func (s *Service) sendLink(ctx context.Context, teacher *ent.TeacherEntity, link mailLink, ttl time.Duration, lang string) error {
+	token, hash, err := auth.NewOpaqueToken()
+	if err != nil {
+		return err
+	}
+	if err := s.dbMailToken.CreateTeacherToken(ctx, &ent.TeacherTokenEntity{
+		TeacherID: teacher.ID,
+		Purpose:   link.Purpose,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(ttl),
+	}); err != nil {
+		return err
+	}
+
+	return s.sendMail(ctx, teacher.Email, link.Template, map[string]any{
+		"Name":    teacher.FirstName,
+		"Link":    s.appLink(link.Path, token),
+		"Minutes": int(ttl.Minutes()),
+		"Hours":   int(ttl.Hours()),
+	}, lang)
+}
+
+// appLink is the web app page at path, handed token.
+func (s *Service) appLink(path, token string) string {
+	return strings.TrimRight(s.config.AppURL, "/") + path + "?token=" + url.QueryEscape(token)
+}
+
+// sendMail mails the i18n messages of template, filled with data, to the
+// address to in lang.
+func (s *Service) sendMail(ctx context.Context, to, template string, data map[string]any, lang string) error {
+	localizer := i18n.NewLocalizer(msg.Bundle, lang)
+	subject, err := localizer.Localize(&i18n.LocalizeConfig{MessageID: "mail-" + template + "-subject", TemplateData: data})
+	if err != nil {
+		return err
+	}
+	body, err := localizer.Localize(&i18n.LocalizeConfig{MessageID: "mail-" + template + "-body", TemplateData: data})
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, &mailer.Message{
+		To:      to,
+		Subject: subject,
+		Body:    body,
+	})
+}
@@ -0,0 +1,164 @@
+package teacher
+
+import (
+	"net/http"
+
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/gin-gonic/gin"
+)
+
+func (c *Controller) LoginMFAController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.login.mfa.ctl.start`)
+
+	var req LoginMFAServiceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		base.ValidationFailed(ctx, err)
+		return
+	}
+	req.Client = sessionClient(ctx, req.Device)
+
+	result, err := c.svc.LoginMFAService(ctx.Request.Context(), &req)
+	if err != nil {
+		handleLoginError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Login successful",
+		"data":    result,
+	})
+
+	span.AddEvent(`teacher.login.mfa.ctl.end`)
+}
+
+func (c *Controller) LoginMFAEnrollController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.login.mfa.enroll.ctl.start`)
+
+	var req LoginMFAEnrollServiceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		base.ValidationFailed(ctx, err)
+		return
+	}
+
+	data, err := c.svc.LoginMFAEnrollService(ctx.Request.Context(), &req)
+	if err != nil {
+		handleLoginError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`teacher.login.mfa.enroll.ctl.end`)
+	base.Success(ctx, data)
+}
+
+func (c *Controller) MFAStatusController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.mfa.status.ctl.start`)
+
+	teacherID, _, ok := currentSession(ctx)
+	if !ok {
+		return
+	}
+
+	data, err := c.svc.MFAStatusService(ctx.Request.Context(), teacherID)
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`teacher.mfa.status.ctl.end`)
+	base.Success(ctx, data)
+}
+
+func (c *Controller) MFAEnrollController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.mfa.enroll.ctl.start`)
+
+	teacherID, _, ok := currentSession(ctx)
+	if !ok {
+		return
+	}
+
+	data, err := c.svc.MFAEnrollService(ctx.Request.Context(), teacherID)
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`teacher.mfa.enroll.ctl.end`)
+	base.Success(ctx, data)
+}
+
+func (c *Controller) MFAConfirmController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.mfa.confirm.ctl.start`)
+
+	req, ok := bindMFACode(ctx)
+	if !ok {
+		return
+	}
+
+	data, err := c.svc.MFAConfirmService(ctx.Request.Context(), req)
+	if err != nil {
+		handleLoginError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`teacher.mfa.confirm.ctl.end`)
+	base.Success(ctx, data)
+}
+
+func (c *Controller) MFARecoveryCodesController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.mfa.recovery.ctl.start`)
+
+	req, ok := bindMFACode(ctx)
+	if !ok {
+		return
+	}
+
+	data, err := c.svc.MFARecoveryCodesService(ctx.Request.Context(), req)
+	if err != nil {
+		handleLoginError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`teacher.mfa.recovery.ctl.end`)
+	base.Success(ctx, data)
+}
+
+func (c *Controller) MFADisableController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.mfa.disable.ctl.start`)
+
+	req, ok := bindMFACode(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.svc.MFADisableService(ctx.Request.Context(), req); err != nil {
+		handleLoginError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`teacher.mfa.disable.ctl.end`)
+	base.Success(ctx, nil)
+}
+
+// bindMFACode reads the code of the body for the teacher of the token.
+func bindMFACode(ctx *gin.Context) (*MFACodeServiceRequest, bool) {
+	var req MFACodeServiceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		base.ValidationFailed(ctx, err)
+		return nil, false
+	}
+	teacherID, _, ok := currentSession(ctx)
+	if !ok {
+		return nil, false
+	}
+	req.TeacherID = teacherID
+	req.IP = ctx.ClientIP()
+	return &req, true
+}
@@ -0,0 +1,482 @@
+package teacher
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/easy-attend-serviceV3/app/modules/lockout"
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/google/uuid"
+)
+
+// MFAChallengeResponse is what a login answers, instead of tokens, when it
+// needs a TOTP code. With Enroll the teacher has no authenticator yet but a
+// role requires one: they enroll with the token first, then log in with
+// their first code.
+type MFAChallengeResponse struct {
+	MFAToken  string    `json:"mfa_token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Enroll    bool      `json:"enroll"`
+}
+
+type MFAStatusServiceResponse struct {
+	Enabled           bool `json:"enabled"`
+	Pending           bool `json:"pending"` // enrolled but not confirmed with a code yet
+	Required          bool `json:"required"`
+	RecoveryCodesLeft int  `json:"recovery_codes_left"`
+}
+
+type MFAEnrollServiceResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+	QRCode          []byte `json:"qr_code"` // PNG of provisioning_uri
+}
+
+type MFARecoveryCodesServiceResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type MFACodeServiceRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+
+	TeacherID uuid.UUID `json:"-"`
+	IP        string    `json:"-"`
+}
+
+type LoginMFAServiceRequest struct {
+	MFAToken     string `json:"mfa_token" binding:"required"`
+	Code         string `json:"code" binding:"required_without=RecoveryCode,omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recovery_code" binding:"omitempty,max=32"` // ใช้แทน code เมื่อไม่มีแอปยืนยันตัวตน
+	Device       string `json:"device" binding:"omitempty,max=100"`
+
+	Client SessionClient `json:"-"`
+}
+
+type LoginMFAEnrollServiceRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+}
+
+// MFAStatusService reports whether the teacher has MFA on and whether a role
+// requires it.
+func (s *Service) MFAStatusService(ctx context.Context, teacherID uuid.UUID) (*MFAStatusServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.mfa.status.start`)
+
+	mfa, err := s.teacherMFA(ctx, teacherID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	required, err := s.mfaRequired(ctx, teacherID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	resp := &MFAStatusServiceResponse{
+		Enabled:  mfaEnabled(mfa),
+		Pending:  mfa != nil && !mfaEnabled(mfa),
+		Required: required,
+	}
+	if resp.Enabled {
+		if resp.RecoveryCodesLeft, err = s.dbMFA.CountRecoveryCodes(ctx, teacherID); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+	}
+
+	span.AddEvent(`teacher.svc.mfa.status.end`)
+	return resp, nil
+}
+
+// MFAEnrollService starts enrolling an authenticator. Until confirmed with a
+// code the secret does not guard logins, and enrolling again replaces it.
+func (s *Service) MFAEnrollService(ctx context.Context, teacherID uuid.UUID) (*MFAEnrollServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.mfa.enroll.start`)
+
+	teacher, err := s.db.GetByIDTeacher(ctx, teacherID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	resp, err := s.enroll(ctx, teacher)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	span.AddEvent(`teacher.svc.mfa.enroll.end`)
+	return resp, nil
+}
+
+// MFAConfirmService turns MFA on with the first code of the enrolled
+// authenticator and returns the recovery codes, which are shown only once.
+func (s *Service) MFAConfirmService(ctx context.Context, req *MFACodeServiceRequest) (*MFARecoveryCodesServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.mfa.confirm.start`)
+
+	teacher, mfa, err := s.teacherWithMFA(ctx, req.TeacherID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	if mfaEnabled(mfa) {
+		return nil, base.ConflictError{Resource: "teacher_mfa"}
+	}
+	step, err := s.checkCode(ctx, teacher, mfa, req.Code, req.IP)
+	if err != nil {
+		return nil, err
+	}
+	codes, err := s.confirm(ctx, teacher, step)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	span.AddEvent(`teacher.svc.mfa.confirm.end`)
+	return &MFARecoveryCodesServiceResponse{RecoveryCodes: codes}, nil
+}
+
+// MFARecoveryCodesService replaces the teacher's recovery codes, used or not.
+func (s *Service) MFARecoveryCodesService(ctx context.Context, req *MFACodeServiceRequest) (*MFARecoveryCodesServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.mfa.recovery.start`)
+
+	teacher, mfa, err := s.teacherWithMFA(ctx, req.TeacherID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	if err := s.useCode(ctx, teacher, mfa, req.Code, req.IP); err != nil {
+		return nil, err
+	}
+	codes, hashes, err := auth.NewRecoveryCodes()
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	if err := s.dbMFA.ReplaceRecoveryCodes(ctx, teacher.ID, recoveryCodes(hashes)); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	log.Infof("security: recovery codes of %s replaced", teacher.ID)
+
+	span.AddEvent(`teacher.svc.mfa.recovery.end`)
+	return &MFARecoveryCodesServiceResponse{RecoveryCodes: codes}, nil
+}
+
+// MFADisableService turns MFA off, unless a role of the teacher requires it.
+func (s *Service) MFADisableService(ctx context.Context, req *MFACodeServiceRequest) error {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.mfa.disable.start`)
+
+	required, err := s.mfaRequired(ctx, req.TeacherID)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	if required {
+		return ErrMFARequired
+	}
+	teacher, mfa, err := s.teacherWithMFA(ctx, req.TeacherID)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	if mfaEnabled(mfa) {
+		if err := s.useCode(ctx, teacher, mfa, req.Code, req.IP); err != nil {
+			return err
+		}
+	}
+	if err := s.dbMFA.DeleteTeacherMFA(ctx, teacher.ID); err != nil {
+		log.Error(err)
+		return err
+	}
+	log.Infof("security: mfa of %s disabled", teacher.ID)
+
+	span.AddEvent(`teacher.svc.mfa.disable.end`)
+	return nil
+}
+
+// LoginMFAEnrollService enrolls an authenticator for a login whose
+// challenge asked for it.
+func (s *Service) LoginMFAEnrollService(ctx context.Context, req *LoginMFAEnrollServiceRequest) (*MFAEnrollServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.login.mfa.enroll.start`)
+
+	teacher, err := s.challengedTeacher(ctx, req.MFAToken)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.enroll(ctx, teacher)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	span.AddEvent(`teacher.svc.login.mfa.enroll.end`)
+	return resp, nil
+}
+
+// LoginMFAService finishes a login with a TOTP or recovery code. The first
+// code of a pending enrollment also confirms it, and the response then
+// carries the new recovery codes. Wrong codes count towards the lockout like
+// wrong passwords.
+func (s *Service) LoginMFAService(ctx context.Context, req *LoginMFAServiceRequest) (*LoginServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.login.mfa.start`)
+
+	teacher, err := s.challengedTeacher(ctx, req.MFAToken)
+	if err != nil {
+		return nil, err
+	}
+	mfa, err := s.teacherMFA(ctx, teacher.ID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	var recoveryCodes []string
+	switch {
+	case mfa == nil:
+		return nil, base.NotFoundError{Resource: "teacher_mfa", ID: teacher.ID.String()}
+	case !mfaEnabled(mfa):
+		step, err := s.checkCode(ctx, teacher, mfa, req.Code, req.Client.IP)
+		if err != nil {
+			return nil, err
+		}
+		if recoveryCodes, err = s.confirm(ctx, teacher, step); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+	case req.RecoveryCode != "":
+		if err := s.useRecoveryCode(ctx, teacher, req.RecoveryCode, req.Client.IP); err != nil {
+			return nil, err
+		}
+	default:
+		if err := s.useCode(ctx, teacher, mfa, req.Code, req.Client.IP); err != nil {
+			return nil, err
+		}
+	}
+
+	// The challenge works once; a second request racing this one fails here.
+	if _, err := s.dbMailToken.ConsumeTeacherToken(ctx, ent.TeacherTokenMFAChallenge, auth.HashOpaqueToken(req.MFAToken)); err != nil {
+		var notFound base.NotFoundError
+		if errors.As(err, &notFound) {
+			return nil, ErrInvalidMFAChallenge
+		}
+		log.Error(err)
+		return nil, err
+	}
+
+	resp, err := s.completeLogin(ctx, teacher, req.Client)
+	if err != nil {
+		return nil, err
+	}
+	resp.RecoveryCodes = recoveryCodes
+
+	span.AddEvent(`teacher.svc.login.mfa.end`)
+	return resp, nil
+}
+
+// mfaChallenge hands out the token a login continues with once the teacher
+// has given a TOTP code.
+func (s *Service) mfaChallenge(ctx context.Context, teacher *ent.TeacherEntity, enroll bool) (*MFAChallengeResponse, error) {
+	token, hash, err := auth.NewOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(time.Duration(s.config.MfaChallengeTTL) * time.Minute)
+	if err := s.dbMailToken.CreateTeacherToken(ctx, &ent.TeacherTokenEntity{
+		TeacherID: teacher.ID,
+		Purpose:   ent.TeacherTokenMFAChallenge,
+		TokenHash: hash,
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return nil, err
+	}
+	return &MFAChallengeResponse{MFAToken: token, ExpiresAt: expiresAt, Enroll: enroll}, nil
+}
+
+// challengedTeacher returns the teacher of an MFA challenge without using it
+// up, so a mistyped code can be retried.
+func (s *Service) challengedTeacher(ctx context.Context, token string) (*ent.TeacherEntity, error) {
+	_, log := utils.LogSpanFromContext(ctx)
+
+	challenge, err := s.dbMailToken.GetTeacherToken(ctx, ent.TeacherTokenMFAChallenge, auth.HashOpaqueToken(token))
+	if err != nil {
+		var notFound base.NotFoundError
+		if errors.As(err, &notFound) {
+			return nil, ErrInvalidMFAChallenge
+		}
+		log.Error(err)
+		return nil, err
+	}
+	teacher, err := s.db.GetByIDTeacher(ctx, challenge.TeacherID)
+	if err != nil {
+		log.Error(err)
+		return nil, ErrInvalidMFAChallenge
+	}
+	return teacher, nil
+}
+
+func (s *Service) enroll(ctx context.Context, teacher *ent.TeacherEntity) (*MFAEnrollServiceResponse, error) {
+	key, err := auth.NewTOTP(s.config.MfaIssuer, teacher.Email)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.dbMFA.EnrollTeacherMFA(ctx, &ent.TeacherMFAEntity{
+		TeacherID: teacher.ID,
+		Secret:    key.Secret,
+	}); err != nil {
+		return nil, err
+	}
+	return &MFAEnrollServiceResponse{
+		Secret:          key.Secret,
+		ProvisioningURI: key.ProvisioningURI,
+		QRCode:          key.QRCode,
+	}, nil
+}
+
+// confirm turns a pending enrollment on with the step of its first code and
+// returns new recovery codes.
+func (s *Service) confirm(ctx context.Context, teacher *ent.TeacherEntity, step int64) ([]string, error) {
+	_, log := utils.LogSpanFromContext(ctx)
+
+	codes, hashes, err := auth.NewRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.dbMFA.ConfirmTeacherMFA(ctx, teacher.ID, step, recoveryCodes(hashes)); err != nil {
+		return nil, err
+	}
+	log.Infof("security: mfa of %s enabled", teacher.ID)
+	return codes, nil
+}
+
+// checkCode verifies a TOTP code against mfa and returns its time step. A
+// wrong code counts against the lockout of the teacher's email and ip.
+func (s *Service) checkCode(ctx context.Context, teacher *ent.TeacherEntity, mfa *ent.TeacherMFAEntity, code, ip string) (int64, error) {
+	emailKey, ipKey := lockout.EmailKey(teacher.Email), lockout.IPKey(ip)
+	if err := s.locks.CheckService(ctx, emailKey, ipKey); err != nil {
+		return 0, err
+	}
+	step, ok := auth.VerifyTOTP(mfa.Secret, code, time.Now())
+	if !ok {
+		return 0, s.mfaFailed(ctx, ip, emailKey, ipKey)
+	}
+	return step, nil
+}
+
+// useCode checks a TOTP code of an enabled authenticator and uses up its time
+// step, so the same code is refused next time.
+func (s *Service) useCode(ctx context.Context, teacher *ent.TeacherEntity, mfa *ent.TeacherMFAEntity, code, ip string) error {
+	_, log := utils.LogSpanFromContext(ctx)
+
+	step, err := s.checkCode(ctx, teacher, mfa, code, ip)
+	if err != nil {
+		return err
+	}
+	if err := s.dbMFA.UseTeacherMFAStep(ctx, teacher.ID, step); err != nil {
+		var notFound base.NotFoundError
+		if errors.As(err, &notFound) {
+			return s.mfaFailed(ctx, ip, lockout.EmailKey(teacher.Email), lockout.IPKey(ip))
+		}
+		log.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (s *Service) useRecoveryCode(ctx context.Context, teacher *ent.TeacherEntity, code, ip string) error {
+	_, log := utils.LogSpanFromContext(ctx)
+
+	emailKey, ipKey := lockout.EmailKey(teacher.Email), lockout.IPKey(ip)
+	if err := s.locks.CheckService(ctx, emailKey, ipKey); err != nil {
+		return err
+	}
+	if err := s.dbMFA.ConsumeRecoveryCode(ctx, teacher.ID, auth.HashRecoveryCode(code)); err != nil {
+		var notFound base.NotFoundError
+		if errors.As(err, &notFound) {
+			return s.mfaFailed(ctx, ip, emailKey, ipKey)
+		}
+		log.Error(err)
+		return err
+	}
+	log.Infof("security: recovery code of %s used", teacher.ID)
+	return nil
+}
+
+// mfaFailed is loginFailed for a wrong code.
+func (s *Service) mfaFailed(ctx context.Context, ip string, keys ...lockout.Key) error {
+	if err := s.loginFailed(ctx, ip, keys...); !errors.Is(err, ErrInvalidCredentials) {
+		return err
+	}
+	return ErrInvalidMFACode
+}
+
+// teacherMFA returns the teacher's enrollment, or nil without one.
+func (s *Service) teacherMFA(ctx context.Context, teacherID uuid.UUID) (*ent.TeacherMFAEntity, error) {
+	mfa, err := s.dbMFA.GetTeacherMFA(ctx, teacherID)
+	if err != nil {
+		var notFound base.NotFoundError
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return mfa, nil
+}
+
+// teacherWithMFA returns a teacher and their enrollment, which must exist.
+func (s *Service) teacherWithMFA(ctx context.Context, teacherID uuid.UUID) (*ent.TeacherEntity, *ent.TeacherMFAEntity, error) {
+	teacher, err := s.db.GetByIDTeacher(ctx, teacherID)
+	if err != nil {
+		return nil, nil, err
+	}
+	mfa, err := s.dbMFA.GetTeacherMFA(ctx, teacherID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return teacher, mfa, nil
+}
+
+// mfaRequired reports whether a role of the teacher requires MFA.
+func (s *Service) mfaRequired(ctx context.Context, teacherID uuid.UUID) (bool, error) {
+	roles, err := s.dbRole.GetTeacherRoles(ctx, teacherID)
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(roles, func(role *ent.RoleEntity) bool { return role.MFARequired }), nil
+}
+
+// mfaMissing reports whether a role of the teacher requires MFA they have not
+// turned on.
+func (s *Service) mfaMissing(ctx context.Context, teacherID uuid.UUID) (bool, error) {
+	required, err := s.mfaRequired(ctx, teacherID)
+	if err != nil || !required {
+		return false, err
+	}
+	mfa, err := s.teacherMFA(ctx, teacherID)
+	if err != nil {
+		return false, err
+	}
+	return !mfaEnabled(mfa), nil
+}
+
+func mfaEnabled(mfa *ent.TeacherMFAEntity) bool {
+	return mfa != nil && mfa.ConfirmedAt != nil
+}
+
+func recoveryCodes(hashes []string) []*ent.TeacherRecoveryCodeEntity {
+	codes := make([]*ent.TeacherRecoveryCodeEntity, 0, len(hashes))
+	for _, hash := range hashes {
+		codes = append(codes, &ent.TeacherRecoveryCodeEntity{CodeHash: hash})
+	}
+	return codes
+}
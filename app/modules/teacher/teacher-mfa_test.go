@@ -0,0 +1,165 @@
+package teacher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/easy-attend-serviceV3/app/modules/lockout"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/pquerna/otp/totp"
+)
+
+// Test_LoginMFAService logs a teacher with MFA in with their password, then
+// finishes the login with the challenge handed out for it.
+func Test_LoginMFAService(t *testing.T) {
+	const password = "correct horse battery"
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		// run finishes the login of challenge and returns the error of the
+		// step under test
+		run          func(t *testing.T, svc *Service, db *memoryEntities, teacher *ent.TeacherEntity, challenge, code string) error
+		wantErr      error
+		wantSessions int
+	}{
+		{
+			name: "Test right code",
+			run: func(t *testing.T, svc *Service, db *memoryEntities, teacher *ent.TeacherEntity, challenge, code string) error {
+				resp, err := svc.LoginMFAService(ctx, &LoginMFAServiceRequest{MFAToken: challenge, Code: code})
+				if err == nil && resp.AccessToken == "" {
+					t.Error("login answered without tokens")
+				}
+				return err
+			},
+			wantSessions: 1,
+		},
+		{
+			name: "Test challenge replayed",
+			run: func(t *testing.T, svc *Service, db *memoryEntities, teacher *ent.TeacherEntity, challenge, code string) error {
+				if _, err := svc.LoginMFAService(ctx, &LoginMFAServiceRequest{MFAToken: challenge, Code: code}); err != nil {
+					t.Fatal(err)
+				}
+				_, err := svc.LoginMFAService(ctx, &LoginMFAServiceRequest{MFAToken: challenge, Code: code})
+				return err
+			},
+			wantErr:      ErrInvalidMFAChallenge,
+			wantSessions: 1,
+		},
+		{
+			name: "Test challenge expired",
+			run: func(t *testing.T, svc *Service, db *memoryEntities, teacher *ent.TeacherEntity, challenge, code string) error {
+				for _, token := range db.tokens {
+					token.ExpiresAt = time.Now().Add(-time.Second)
+				}
+				_, err := svc.LoginMFAService(ctx, &LoginMFAServiceRequest{MFAToken: challenge, Code: code})
+				return err
+			},
+			wantErr: ErrInvalidMFAChallenge,
+		},
+		{
+			name: "Test unknown challenge",
+			run: func(t *testing.T, svc *Service, db *memoryEntities, teacher *ent.TeacherEntity, challenge, code string) error {
+				_, err := svc.LoginMFAService(ctx, &LoginMFAServiceRequest{MFAToken: challenge + "x", Code: code})
+				return err
+			},
+			wantErr: ErrInvalidMFAChallenge,
+		},
+		{
+			name: "Test wrong code",
+			run: func(t *testing.T, svc *Service, db *memoryEntities, teacher *ent.TeacherEntity, challenge, code string) error {
+				_, err := svc.LoginMFAService(ctx, &LoginMFAServiceRequest{MFAToken: challenge, Code: wrongCode(code)})
+				return err
+			},
+			wantErr: ErrInvalidMFACode,
+		},
+		{
+			name: "Test right code after wrong one",
+			run: func(t *testing.T, svc *Service, db *memoryEntities, teacher *ent.TeacherEntity, challenge, code string) error {
+				if _, err := svc.LoginMFAService(ctx, &LoginMFAServiceRequest{MFAToken: challenge, Code: wrongCode(code)}); !errors.Is(err, ErrInvalidMFACode) {
+					t.Fatalf("wrong code error = %v, want %v", err, ErrInvalidMFACode)
+				}
+				_, err := svc.LoginMFAService(ctx, &LoginMFAServiceRequest{MFAToken: challenge, Code: code})
+				return err
+			},
+			wantSessions: 1,
+		},
+		{
+			name: "Test code replayed",
+			run: func(t *testing.T, svc *Service, db *memoryEntities, teacher *ent.TeacherEntity, challenge, code string) error {
+				if _, err := svc.LoginMFAService(ctx, &LoginMFAServiceRequest{MFAToken: challenge, Code: code}); err != nil {
+					t.Fatal(err)
+				}
+				again, err := svc.LoginService(ctx, &LoginServiceRequest{Email: teacher.Email, Password: password})
+				if err != nil {
+					t.Fatal(err)
+				}
+				_, err = svc.LoginMFAService(ctx, &LoginMFAServiceRequest{MFAToken: again.MFA.MFAToken, Code: code})
+				return err
+			},
+			wantErr:      ErrInvalidMFACode,
+			wantSessions: 1,
+		},
+		{
+			name: "Test wrong codes lock out",
+			run: func(t *testing.T, svc *Service, db *memoryEntities, teacher *ent.TeacherEntity, challenge, code string) error {
+				var err error
+				for range svc.config.Lockout.MaxAttempts {
+					_, err = svc.LoginMFAService(ctx, &LoginMFAServiceRequest{MFAToken: challenge, Code: wrongCode(code)})
+				}
+				var locked *lockout.LockedError
+				if !errors.As(err, &locked) {
+					t.Fatalf("last wrong code error = %v, want LockedError", err)
+				}
+				_, err = svc.LoginMFAService(ctx, &LoginMFAServiceRequest{MFAToken: challenge, Code: code})
+				if !errors.As(err, &locked) {
+					t.Fatalf("right code error = %v, want LockedError", err)
+				}
+				return nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newMemoryEntities()
+			svc := newTestService(t, db)
+			teacher := db.addTeacher(t, svc, password)
+			key, err := auth.NewTOTP("Easy Attend", teacher.Email)
+			if err != nil {
+				t.Fatal(err)
+			}
+			confirmed := time.Now()
+			db.mfa[teacher.ID] = &ent.TeacherMFAEntity{TeacherID: teacher.ID, Secret: key.Secret, ConfirmedAt: &confirmed}
+
+			resp, err := svc.LoginService(ctx, &LoginServiceRequest{Email: teacher.Email, Password: password})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.MFA == nil || resp.MFA.Enroll || resp.AccessToken != "" || resp.RefreshToken != "" {
+				t.Fatalf("login answered %+v, want an mfa challenge only", resp)
+			}
+			code, err := totp.GenerateCode(key.Secret, time.Now())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tt.run(t, svc, db, teacher, resp.MFA.MFAToken, code); !errors.Is(err, tt.wantErr) {
+				t.Errorf("LoginMFAService() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(db.sessions) != tt.wantSessions {
+				t.Errorf("%d sessions started, want %d", len(db.sessions), tt.wantSessions)
+			}
+		})
+	}
+}
+
+// wrongCode returns a six digit code other than code.
+func wrongCode(code string) string {
+	if code == "000000" {
+		return "111111"
+	}
+	return "000000"
+}
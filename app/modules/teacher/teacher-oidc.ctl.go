@@ -0,0 +1,71 @@
+package teacher
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/config/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+func (c *Controller) OIDCAuthorizeController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.oidc.authorize.ctl.start`)
+
+	data, err := c.svc.OIDCAuthorizeService(ctx.Request.Context(), ctx.Param("provider"))
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`teacher.oidc.authorize.ctl.end`)
+	base.Success(ctx, data)
+}
+
+func (c *Controller) OIDCCallbackController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.oidc.callback.ctl.start`)
+
+	var req OIDCCallbackServiceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		base.ValidationFailed(ctx, err)
+		return
+	}
+	req.Provider = ctx.Param("provider")
+	req.Client = sessionClient(ctx, req.Device)
+
+	result, err := c.svc.OIDCCallbackService(ctx.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidOIDCState):
+			base.Unauthorized(ctx, i18n.OIDCStateInvalid, nil)
+		case errors.Is(err, ErrOIDCLoginFailed):
+			base.Unauthorized(ctx, i18n.OIDCLoginFailed, nil)
+		case errors.Is(err, ErrOIDCDomainNotAllowed):
+			base.Forbidden(ctx, i18n.OIDCDomainDenied, nil)
+		case errors.Is(err, ErrOIDCNoAccount):
+			base.Forbidden(ctx, i18n.OIDCNoAccount, nil)
+		default:
+			handleLoginError(ctx, err)
+		}
+		return
+	}
+
+	if result.MFA != nil {
+		ctx.JSON(http.StatusOK, gin.H{
+			"message": "MFA code required",
+			"data":    result.MFA,
+		})
+		span.AddEvent(`teacher.oidc.callback.ctl.mfa`)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Login successful",
+		"data":    result,
+	})
+
+	span.AddEvent(`teacher.oidc.callback.ctl.end`)
+}
@@ -0,0 +1,284 @@
+package teacher
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	entitiesdto "github.com/easy-attend-serviceV3/app/modules/entities/dto"
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/easy-attend-serviceV3/app/modules/net/httpx"
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/app/utils/oidc"
+	"github.com/easy-attend-serviceV3/config"
+	"github.com/google/uuid"
+)
+
+// OpenID providers teachers can sign in with
+const (
+	ProviderGoogle    = "google"
+	ProviderMicrosoft = "microsoft"
+)
+
+// Teachers created at their first sign-in start with the seeded "ไม่ระบุ"
+// prefix and gender, and set their own afterwards.
+var (
+	unspecifiedPrefixID = uuid.MustParse("5c0f8d1e-7a2b-4e63-9d41-0b8e6f3a2c17")
+	unspecifiedGenderID = uuid.MustParse("dd738d83-6f7f-4977-92f7-943ab04858cb")
+)
+
+// nameMaxLength is the length of the teachers' name columns.
+const nameMaxLength = 100
+
+type OIDCAuthorizeServiceResponse struct {
+	AuthorizationURL string    `json:"authorization_url"` // หน้าเข้าสู่ระบบของผู้ให้บริการ
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+type OIDCCallbackServiceRequest struct {
+	Code   string `json:"code" binding:"required"`
+	State  string `json:"state" binding:"required"`
+	Device string `json:"device" binding:"omitempty,max=100"` // ชื่ออุปกรณ์ที่แสดงในรายการเซสชัน
+
+	Provider string        `json:"-"`
+	Client   SessionClient `json:"-"`
+}
+
+// newOIDCProviders returns the providers set up in conf by name. Without a
+// redirect URL of their own, providers send teachers back to the web app at
+// appURL.
+func newOIDCProviders(appURL string, conf config.OidcConfig) map[string]*oidc.Provider {
+	client := httpx.NewClient().Client
+	providers := make(map[string]*oidc.Provider)
+	for name, c := range map[string]oidc.Config{ProviderGoogle: conf.Google, ProviderMicrosoft: conf.Microsoft} {
+		if !c.Enabled() {
+			continue
+		}
+		if c.RedirectUrl == "" {
+			c.RedirectUrl = strings.TrimSuffix(appURL, "/") + "/oidc/" + name + "/callback"
+		}
+		providers[name] = oidc.NewProvider(c, client)
+	}
+	return providers
+}
+
+// OIDCAuthorizeService starts a sign-in at provider. The web app sends the
+// teacher to the returned URL; the provider sends them back with a code and
+// the state for OIDCCallbackService.
+func (s *Service) OIDCAuthorizeService(ctx context.Context, provider string) (*OIDCAuthorizeServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.oidc_authorize.start`)
+
+	p, err := s.oidcProvider(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	var secrets [3]string // state, nonce and PKCE verifier
+	for i := range secrets {
+		if secrets[i], err = oidc.NewVerifier(); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+	}
+	state, nonce, verifier := secrets[0], secrets[1], secrets[2]
+
+	authURL, err := p.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(time.Duration(s.config.Oidc.StateTTL) * time.Minute)
+	if err := s.dbOIDC.CreateOIDCState(ctx, &ent.OIDCStateEntity{
+		StateHash:    auth.HashOpaqueToken(state),
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    expiresAt,
+	}); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	span.AddEvent(`teacher.svc.oidc_authorize.end`)
+	return &OIDCAuthorizeServiceResponse{AuthorizationURL: authURL, ExpiresAt: expiresAt}, nil
+}
+
+// OIDCCallbackService finishes a sign-in at req.Provider. The account must
+// belong to an email domain a school trusts with that provider; it signs in
+// the teacher linked to it or with its email, or a new teacher when the
+// school provisions them.
+func (s *Service) OIDCCallbackService(ctx context.Context, req *OIDCCallbackServiceRequest) (*LoginServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.oidc_callback.start`)
+
+	p, err := s.oidcProvider(req.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := s.dbOIDC.ConsumeOIDCState(ctx, req.Provider, auth.HashOpaqueToken(req.State))
+	if err != nil {
+		var notFound base.NotFoundError
+		if errors.As(err, &notFound) {
+			return nil, ErrInvalidOIDCState
+		}
+		log.Error(err)
+		return nil, err
+	}
+
+	token, err := p.Exchange(ctx, req.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		log.Infof("security: %s sign-in from %s refused: %v", req.Provider, req.Client.IP, err)
+		return nil, ErrOIDCLoginFailed
+	}
+
+	domain, err := s.oidcDomain(ctx, req.Provider, token)
+	if err != nil {
+		log.Infof("security: %s account %s refused: %v", req.Provider, token.Subject, err)
+		return nil, err
+	}
+
+	teacher, err := s.oidcTeacher(ctx, req.Provider, token, domain)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	if err := s.dbOIDC.LinkTeacherIdentity(ctx, &ent.TeacherIdentityEntity{
+		TeacherID: teacher.ID,
+		Provider:  req.Provider,
+		Subject:   token.Subject,
+		Email:     token.Email,
+	}); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	log.Infof("security: %s signed in with %s account %s", teacher.ID, req.Provider, token.Subject)
+
+	response, err := s.signIn(ctx, teacher, req.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	span.AddEvent(`teacher.svc.oidc_callback.end`)
+	return response, nil
+}
+
+func (s *Service) oidcProvider(name string) (*oidc.Provider, error) {
+	p, ok := s.oidc[name]
+	if !ok {
+		return nil, base.NotFoundError{Resource: "oidc_provider", ID: name}
+	}
+	return p, nil
+}
+
+// oidcDomain returns the school domain of the account in token, once the
+// provider vouches that the account belongs to it: a Google Workspace account
+// of the domain, or an account of the Microsoft tenant that owns it. Anyone
+// can put any email on a personal account, so the email alone is not enough.
+func (s *Service) oidcDomain(ctx context.Context, provider string, token *oidc.IDToken) (*ent.SchoolDomainEntity, error) {
+	at := strings.LastIndexByte(token.Email, '@')
+	if at < 0 {
+		return nil, ErrOIDCDomainNotAllowed
+	}
+	name := token.Email[at+1:]
+
+	domain, err := s.dbDomain.GetSchoolDomain(ctx, provider, name)
+	if err != nil {
+		var notFound base.NotFoundError
+		if errors.As(err, &notFound) {
+			return nil, ErrOIDCDomainNotAllowed
+		}
+		return nil, err
+	}
+
+	var vouched bool
+	switch provider {
+	case ProviderGoogle:
+		vouched = token.EmailVerified && strings.EqualFold(token.HostedDomain, name)
+	case ProviderMicrosoft:
+		vouched = domain.TenantID != "" && token.TenantID == domain.TenantID
+	}
+	if !vouched {
+		return nil, ErrOIDCDomainNotAllowed
+	}
+	return domain, nil
+}
+
+// oidcTeacher returns the teacher of the account in token: the one linked to
+// it, else the one with its email, else a new one when the domain provisions
+// teachers. Only teachers of the domain's school sign in this way.
+func (s *Service) oidcTeacher(ctx context.Context, provider string, token *oidc.IDToken, domain *ent.SchoolDomainEntity) (*ent.TeacherEntity, error) {
+	var notFound base.NotFoundError
+
+	var teacher *ent.TeacherEntity
+	identity, err := s.dbOIDC.GetTeacherIdentity(ctx, provider, token.Subject)
+	switch {
+	case err == nil:
+		teacher, err = s.db.GetByIDTeacher(ctx, identity.TeacherID)
+	case errors.As(err, &notFound):
+		teacher, err = s.db.GetTeacherByEmail(ctx, token.Email)
+		if errors.As(err, &notFound) && domain.AutoProvision {
+			return s.provisionTeacher(ctx, provider, token, domain)
+		}
+	}
+	if errors.As(err, &notFound) {
+		return nil, ErrOIDCNoAccount
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if teacher.SchoolID != domain.SchoolID {
+		return nil, ErrOIDCDomainNotAllowed
+	}
+	return teacher, nil
+}
+
+// provisionTeacher creates the teacher of a new account in domain's school.
+// Their password is random, so they sign in with the provider until they
+// reset it.
+func (s *Service) provisionTeacher(ctx context.Context, provider string, token *oidc.IDToken, domain *ent.SchoolDomainEntity) (*ent.TeacherEntity, error) {
+	_, log := utils.LogSpanFromContext(ctx)
+
+	secret, err := oidc.NewVerifier()
+	if err != nil {
+		return nil, err
+	}
+	password, err := s.hasher.HashPassword(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	firstName, lastName := token.GivenName, token.FamilyName
+	if firstName == "" {
+		firstName, _, _ = strings.Cut(token.Email, "@")
+		if token.Name != "" {
+			firstName = token.Name
+		}
+	}
+	teacher, err := s.db.CreateTeacher(ctx, &entitiesdto.TeacherCreateRequest{
+		SchoolID:  domain.SchoolID,
+		PrefixID:  unspecifiedPrefixID,
+		GenderID:  unspecifiedGenderID,
+		FirstName: truncate(firstName, nameMaxLength),
+		LastName:  truncate(lastName, nameMaxLength),
+		Email:     token.Email,
+		Password:  password,
+		Role:      auth.RoleTeacher,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The provider checked the email already
+	if err := s.db.VerifyTeacherEmail(ctx, teacher.ID); err != nil {
+		return nil, err
+	}
+
+	log.Infof("security: teacher %s provisioned for %s account %s in school %s", teacher.ID, provider, token.Subject, domain.SchoolID)
+	return teacher, nil
+}
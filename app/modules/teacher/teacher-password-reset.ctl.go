@@ -0,0 +1,53 @@
+package teacher
+
+import (
+	"errors"
+
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/config/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+func (c *Controller) ForgotPasswordController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.password.forgot.ctl.start`)
+
+	var request ForgotPasswordServiceRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		base.ValidationFailed(ctx, err)
+		return
+	}
+	request.Lang = ctx.GetHeader("Accept-Language")
+
+	if err := c.svc.ForgotPasswordService(ctx.Request.Context(), &request); err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`teacher.password.forgot.ctl.end`)
+	base.Success(ctx, nil, i18n.PasswordResetSent)
+}
+
+func (c *Controller) ResetPasswordController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.password.reset.ctl.start`)
+
+	var request ResetPasswordServiceRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		base.ValidationFailed(ctx, err)
+		return
+	}
+
+	if err := c.svc.ResetPasswordService(ctx.Request.Context(), &request); err != nil {
+		if errors.Is(err, ErrInvalidLinkToken) {
+			base.BadRequest(ctx, i18n.LinkTokenInvalid, nil)
+			return
+		}
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`teacher.password.reset.ctl.end`)
+	base.Success(ctx, nil)
+}
@@ -0,0 +1,95 @@
+package teacher
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+)
+
+type ForgotPasswordServiceRequest struct {
+	Email string `json:"email" binding:"required,email"`
+
+	Lang string `json:"-"`
+}
+
+type ResetPasswordServiceRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"` // ตรวจตามนโยบายรหัสผ่าน
+}
+
+// ForgotPasswordService mails a password reset link. An unknown email is not
+// an error, so the endpoint does not reveal who has an account.
+func (s *Service) ForgotPasswordService(ctx context.Context, req *ForgotPasswordServiceRequest) error {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.password.forgot.start`)
+
+	teacher, err := s.db.GetTeacherByEmail(ctx, req.Email)
+	if err != nil {
+		var notFound base.NotFoundError
+		if errors.As(err, &notFound) {
+			span.AddEvent(`teacher.svc.password.forgot.unknown`)
+			return nil
+		}
+		log.Error(err)
+		return err
+	}
+
+	ttl := time.Duration(s.config.PasswordResetTTL) * time.Minute
+	if err := s.sendLink(ctx, teacher, passwordResetLink, ttl, req.Lang); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	span.AddEvent(`teacher.svc.password.forgot.end`)
+	return nil
+}
+
+// ResetPasswordService sets a new password with a reset token and logs the
+// teacher out everywhere, since whoever held the old password may be signed in.
+func (s *Service) ResetPasswordService(ctx context.Context, req *ResetPasswordServiceRequest) error {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.password.reset.start`)
+
+	// Check the policy first so a refused password leaves the link usable
+	hashed, err := s.hashNewPassword("password", req.Password)
+	if err != nil {
+		return err
+	}
+
+	token, err := s.consumeLinkToken(ctx, ent.TeacherTokenPasswordReset, req.Token)
+	if err != nil {
+		return err
+	}
+	if err := s.db.UpdateTeacherPassword(ctx, token.TeacherID, hashed); err != nil {
+		log.Error(err)
+		return err
+	}
+	if err := s.LogoutAllService(ctx, token.TeacherID); err != nil {
+		return err
+	}
+
+	span.AddEvent(`teacher.svc.password.reset.end`)
+	return nil
+}
+
+// consumeLinkToken uses up an emailed token, reporting an unknown, used or
+// expired one as ErrInvalidLinkToken.
+func (s *Service) consumeLinkToken(ctx context.Context, purpose, token string) (*ent.TeacherTokenEntity, error) {
+	_, log := utils.LogSpanFromContext(ctx)
+
+	consumed, err := s.dbMailToken.ConsumeTeacherToken(ctx, purpose, auth.HashOpaqueToken(token))
+	if err != nil {
+		var notFound base.NotFoundError
+		if errors.As(err, &notFound) {
+			return nil, ErrInvalidLinkToken
+		}
+		log.Error(err)
+		return nil, err
+	}
+	return consumed, nil
+}
@@ -0,0 +1,39 @@
+package teacher
+
+import (
+	"errors"
+
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/gin-gonic/gin"
+)
+
+func (c *Controller) ChangePasswordController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.password.change.ctl.start`)
+
+	var req ChangePasswordServiceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		base.ValidationFailed(ctx, err)
+		return
+	}
+	teacherID, sessionID, ok := currentSession(ctx)
+	if !ok {
+		return
+	}
+	req.TeacherID = teacherID
+	req.SessionID = sessionID
+	req.IP = ctx.ClientIP()
+
+	if err := c.svc.ChangePasswordService(ctx.Request.Context(), &req); err != nil {
+		if errors.Is(err, ErrInvalidCurrentPassword) {
+			base.InvalidField(ctx, "current_password", "invalid")
+			return
+		}
+		handleLoginError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`teacher.password.change.ctl.end`)
+	base.Success(ctx, nil)
+}
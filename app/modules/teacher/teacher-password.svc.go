@@ -0,0 +1,109 @@
+package teacher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/easy-attend-serviceV3/app/modules/lockout"
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/config"
+	"github.com/google/uuid"
+)
+
+type ChangePasswordServiceRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+
+	TeacherID uuid.UUID `json:"-"`
+	SessionID uuid.UUID `json:"-"`
+	IP        string    `json:"-"`
+}
+
+// newPasswordPolicy hashes with the Argon2id cost and checks new passwords
+// against the policy set in conf.
+func newPasswordPolicy(conf config.PasswordConfig) (*auth.PasswordHasher, *auth.PasswordPolicy, error) {
+	hasher := auth.NewPasswordHasherWithParams(uint32(conf.Memory), uint32(conf.Iterations), uint8(conf.Parallelism))
+	policy, err := auth.NewPasswordPolicy(conf.MinLength, conf.MaxLength, conf.CheckBreached, conf.BreachedFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("password policy: %w", err)
+	}
+	return hasher, policy, nil
+}
+
+// hashNewPassword checks a password chosen by the teacher against the policy
+// and hashes it.
+func (s *Service) hashNewPassword(field, password string) (string, error) {
+	if err := s.policy.Check(field, password); err != nil {
+		return "", err
+	}
+	return s.hasher.HashPassword(password)
+}
+
+// rehash replaces a stored hash made with bcrypt or outdated Argon2id
+// parameters, now that the password is at hand. Failing only costs the
+// upgrade, so it does not fail the login.
+func (s *Service) rehash(ctx context.Context, teacherID uuid.UUID, password, stored string) {
+	_, log := utils.LogSpanFromContext(ctx)
+
+	if !s.hasher.NeedsRehash(stored) {
+		return
+	}
+	hashed, err := s.hasher.HashPassword(password)
+	if err == nil {
+		err = s.db.UpdateTeacherPassword(ctx, teacherID, hashed)
+	}
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	log.Infof("security: password hash of %s upgraded", teacherID)
+}
+
+// ChangePasswordService sets a new password once the current one is verified,
+// and ends the teacher's other sessions. A wrong current password counts
+// towards the login lockout.
+func (s *Service) ChangePasswordService(ctx context.Context, req *ChangePasswordServiceRequest) error {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.password.change.start`)
+
+	teacher, err := s.db.GetByIDTeacher(ctx, req.TeacherID)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	emailKey, ipKey := lockout.EmailKey(teacher.Email), lockout.IPKey(req.IP)
+	if err := s.locks.CheckService(ctx, emailKey, ipKey); err != nil {
+		return err
+	}
+	valid, err := s.hasher.VerifyPassword(req.CurrentPassword, teacher.Password)
+	if err != nil {
+		log.Error(err)
+	}
+	if !valid {
+		if err := s.loginFailed(ctx, req.IP, emailKey, ipKey); !errors.Is(err, ErrInvalidCredentials) {
+			return err
+		}
+		return ErrInvalidCurrentPassword
+	}
+
+	hashed, err := s.hashNewPassword("new_password", req.NewPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.db.UpdateTeacherPassword(ctx, teacher.ID, hashed); err != nil {
+		log.With(slog.String(`teacher_id`, teacher.ID.String())).Error(err)
+		return err
+	}
+	log.Infof("security: password of %s changed", teacher.ID)
+
+	if err := s.logoutOthers(ctx, teacher.ID, req.SessionID); err != nil {
+		return err
+	}
+
+	span.AddEvent(`teacher.svc.password.change.end`)
+	return nil
+}
@@ -0,0 +1,70 @@
+package teacher
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/easy-attend-serviceV3/app/utils/base"
+)
+
+// Test_ChangePasswordService changes the password of a teacher signed in on
+// two devices from the first of them.
+func Test_ChangePasswordService(t *testing.T) {
+	const password = "correct horse battery"
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		current     string
+		next        string
+		wantErr     error
+		wantRule    string // of the ValidationError refusing next
+		wantChanged bool   // the teacher logs in with next, and their other session ends
+	}{
+		{name: "Test change", current: password, next: "staple battery horse", wantChanged: true},
+		{name: "Test wrong current password", current: "wrong horse battery", next: "staple battery horse", wantErr: ErrInvalidCurrentPassword},
+		{name: "Test too short", current: password, next: "short", wantRule: "min-length"},
+		{name: "Test breached", current: password, next: "password123", wantRule: "breached"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newMemoryEntities()
+			svc := newTestService(t, db)
+			teacher := db.addTeacher(t, svc, password)
+			current := sessionOf(t, svc, login(t, svc, teacher, password).AccessToken)
+			other := sessionOf(t, svc, login(t, svc, teacher, password).AccessToken)
+
+			err := svc.ChangePasswordService(ctx, &ChangePasswordServiceRequest{
+				CurrentPassword: tt.current,
+				NewPassword:     tt.next,
+				TeacherID:       teacher.ID,
+				SessionID:       current,
+			})
+			var invalid base.ValidationError
+			switch {
+			case tt.wantRule != "":
+				if !errors.As(err, &invalid) || invalid.Field != "new_password" || invalid.Rule != tt.wantRule {
+					t.Fatalf("ChangePasswordService() error = %v, want %s of new_password", err, tt.wantRule)
+				}
+			case !errors.Is(err, tt.wantErr):
+				t.Fatalf("ChangePasswordService() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !db.active(current) {
+				t.Error("the session changing the password ended")
+			}
+			if got := db.active(other); got == tt.wantChanged {
+				t.Errorf("other session active = %t, want %t", got, !tt.wantChanged)
+			}
+			want, refused := password, tt.next
+			if tt.wantChanged {
+				want, refused = tt.next, password
+			}
+			login(t, svc, teacher, want)
+			if _, err := svc.LoginService(ctx, &LoginServiceRequest{Email: teacher.Email, Password: refused}); !errors.Is(err, ErrInvalidCredentials) {
+				t.Errorf("login with %q: error = %v, want %v", refused, err, ErrInvalidCredentials)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package teacher
 
 import (
+	"errors"
+
 	"github.com/easy-attend-serviceV3/app/utils"
 	"github.com/easy-attend-serviceV3/app/utils/base"
 	"github.com/easy-attend-serviceV3/config/i18n"
@@ -24,7 +26,7 @@ func (c *Controller) RefreshController(ctx *gin.Context) {
 
 	var request RefreshControllerRequest
 	if err := ctx.ShouldBindJSON(&request); err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.ValidationFailed(ctx, err)
 		return
 	}
 
@@ -33,7 +35,11 @@ func (c *Controller) RefreshController(ctx *gin.Context) {
 		RefreshToken: request.RefreshToken,
 	})
 	if err != nil {
-		base.HandleError(ctx, err)
+		if errors.Is(err, ErrInvalidRefreshToken) {
+			base.Unauthorized(ctx, i18n.RefreshTokenInvalid, nil)
+			return
+		}
+		base.HandleCustomError(ctx, err)
 		return
 	}
 
@@ -47,4 +53,4 @@ func (c *Controller) RefreshController(ctx *gin.Context) {
 
 	span.AddEvent(`teacher.refresh.ctl.end`)
 	base.Success(ctx, resp)
-}
\ No newline at end of file
+}
@@ -2,11 +2,13 @@ package teacher
 
 import (
 	"context"
-	"errors"
 	"time"
 
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
 	"github.com/easy-attend-serviceV3/app/utils"
 	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type RefreshServiceRequest struct {
@@ -20,31 +22,104 @@ type RefreshServiceResponse struct {
 	ExpiresAt    time.Time `json:"expires_at"`
 }
 
+// RefreshService exchanges a refresh token for a new pair. Each refresh token
+// works once: presenting one that was already rotated means it was copied, so
+// every token of its family is revoked and the teacher has to log in again.
 func (s *Service) RefreshService(ctx context.Context, req *RefreshServiceRequest) (*RefreshServiceResponse, error) {
 	span, log := utils.LogSpanFromContext(ctx)
 	span.AddEvent(`teacher.svc.refresh.start`)
 
-	// Initialize token manager with config
-	tokenMgr := auth.NewTokenManagerWithConfig(
-		s.config.JWT.SecretKey,
-		time.Duration(s.config.JWT.AccessTokenExpiry)*time.Hour,
-		time.Duration(s.config.JWT.RefreshTokenExpiry)*time.Hour,
-	)
+	current, err := s.dbToken.GetRefreshTokenByHash(ctx, auth.HashOpaqueToken(req.RefreshToken))
+	if err != nil {
+		log.Error(err)
+		return nil, ErrInvalidRefreshToken
+	}
+	if current.RevokedAt != nil || !current.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if current.UsedAt != nil {
+		return nil, s.revokeFamily(ctx, current)
+	}
 
-	// Refresh the token
-	newTokens, err := tokenMgr.RefreshToken(req.RefreshToken)
+	teacher, err := s.db.GetByIDTeacher(ctx, current.TeacherID)
+	if err != nil {
+		log.Error(err)
+		return nil, ErrInvalidRefreshToken
+	}
+	// A role that started requiring MFA sends its teachers back to the login
+	// to enroll. This is checked before the rotation so a refused refresh
+	// leaves the token as it was.
+	missing, err := s.mfaMissing(ctx, teacher.ID)
 	if err != nil {
 		log.Error(err)
-		return nil, errors.New("invalid or expired refresh token")
+		return nil, err
+	}
+	if missing {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	token, hash, err := auth.NewOpaqueToken()
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	rotated, err := s.dbToken.RotateRefreshToken(ctx, current, &ent.RefreshTokenEntity{
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(s.tokens.RefreshExpiry()),
+	})
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	if !rotated {
+		// Another request used it first.
+		return nil, s.revokeFamily(ctx, current)
+	}
+
+	roles, permissions, err := s.grants(ctx, teacher.ID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	accessToken, expiresAt, err := s.tokens.GenerateAccessToken(
+		teacher.ID,
+		current.FamilyID,
+		teacher.SchoolID,
+		teacher.Email,
+		teacher.FirstName,
+		teacher.LastName,
+		"teacher",
+		roles,
+		permissions,
+	)
+	if err != nil {
+		log.Error(err)
+		return nil, err
 	}
 
 	response := &RefreshServiceResponse{
-		AccessToken:  newTokens.AccessToken,
-		RefreshToken: newTokens.RefreshToken,
-		TokenType:    newTokens.TokenType,
-		ExpiresAt:    newTokens.ExpiresAt,
+		AccessToken:  accessToken,
+		RefreshToken: token,
+		TokenType:    "Bearer",
+		ExpiresAt:    expiresAt,
 	}
 
 	span.AddEvent(`teacher.svc.refresh.end`)
 	return response, nil
-}
\ No newline at end of file
+}
+
+// revokeFamily handles a refresh token presented twice.
+func (s *Service) revokeFamily(ctx context.Context, reused *ent.RefreshTokenEntity) error {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.refresh.reused`, trace.WithAttributes(
+		attribute.String("teacher_id", reused.TeacherID.String()),
+		attribute.String("family_id", reused.FamilyID.String()),
+	))
+	log.Warnf("refresh token reused, revoking family %s of teacher %s", reused.FamilyID, reused.TeacherID)
+
+	if err := s.dbToken.RevokeRefreshTokenFamily(ctx, reused.FamilyID); err != nil {
+		log.Error(err)
+		return err
+	}
+	return ErrInvalidRefreshToken
+}
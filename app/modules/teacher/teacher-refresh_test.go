@@ -0,0 +1,118 @@
+package teacher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+)
+
+func Test_RefreshService(t *testing.T) {
+	const password = "correct horse battery"
+	ctx := context.Background()
+	refresh := func(svc *Service, token string) (*RefreshServiceResponse, error) {
+		return svc.RefreshService(ctx, &RefreshServiceRequest{RefreshToken: token})
+	}
+
+	tests := []struct {
+		name string
+		// run refreshes with the token of a login and returns the error of
+		// the refresh under test and the refresh token left to the teacher
+		run        func(t *testing.T, svc *Service, db *memoryEntities, token string) (string, error)
+		wantErr    error
+		wantActive bool // the login's session can still be used
+	}{
+		{
+			name: "Test rotate",
+			run: func(t *testing.T, svc *Service, db *memoryEntities, token string) (string, error) {
+				resp, err := refresh(svc, token)
+				if err != nil {
+					return "", err
+				}
+				if resp.RefreshToken == token {
+					t.Error("refresh handed back the same refresh token")
+				}
+				if _, err := svc.tokens.ValidateToken(resp.AccessToken); err != nil {
+					t.Errorf("refreshed access token is invalid: %v", err)
+				}
+				if _, err := refresh(svc, resp.RefreshToken); err != nil {
+					t.Errorf("rotated refresh token is refused: %v", err)
+				}
+				return resp.RefreshToken, nil
+			},
+			wantActive: true,
+		},
+		{
+			name: "Test reuse revokes family",
+			run: func(t *testing.T, svc *Service, db *memoryEntities, token string) (string, error) {
+				resp, err := refresh(svc, token)
+				if err != nil {
+					t.Fatal(err)
+				}
+				_, err = refresh(svc, token)
+				return resp.RefreshToken, err
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "Test expired",
+			run: func(t *testing.T, svc *Service, db *memoryEntities, token string) (string, error) {
+				current, _ := db.GetRefreshTokenByHash(ctx, auth.HashOpaqueToken(token))
+				for _, stored := range db.refresh {
+					if stored.ID == current.ID {
+						stored.ExpiresAt = time.Now().Add(-time.Minute)
+					}
+				}
+				_, err := refresh(svc, token)
+				return "", err
+			},
+			wantErr:    ErrInvalidRefreshToken,
+			wantActive: true,
+		},
+		{
+			name: "Test unknown",
+			run: func(t *testing.T, svc *Service, db *memoryEntities, token string) (string, error) {
+				_, err := refresh(svc, token+"x")
+				return token, err
+			},
+			wantErr:    ErrInvalidRefreshToken,
+			wantActive: true,
+		},
+		{
+			name: "Test after logout",
+			run: func(t *testing.T, svc *Service, db *memoryEntities, token string) (string, error) {
+				current, _ := db.GetRefreshTokenByHash(ctx, auth.HashOpaqueToken(token))
+				if err := svc.SessionDeleteService(ctx, current.TeacherID, current.FamilyID); err != nil {
+					t.Fatal(err)
+				}
+				_, err := refresh(svc, token)
+				return "", err
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newMemoryEntities()
+			svc := newTestService(t, db)
+			resp := login(t, svc, db.addTeacher(t, svc, password), password)
+			session := sessionOf(t, svc, resp.AccessToken)
+
+			left, err := tt.run(t, svc, db, resp.RefreshToken)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RefreshService() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := db.active(session); got != tt.wantActive {
+				t.Errorf("session active = %t, want %t", got, tt.wantActive)
+			}
+			// A revoked family takes the tokens rotated from it along
+			if left != "" && !tt.wantActive {
+				if _, err := refresh(svc, left); !errors.Is(err, ErrInvalidRefreshToken) {
+					t.Errorf("refresh token of the revoked family: error = %v, want %v", err, ErrInvalidRefreshToken)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package teacher
+
+import (
+	"errors"
+
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/config/i18n"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (c *Controller) RoleListController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.role.list.ctl.start`)
+
+	data, err := c.svc.RoleListService(ctx.Request.Context())
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`teacher.role.list.ctl.end`)
+	base.Success(ctx, data)
+}
+
+func (c *Controller) RoleUpdateController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.role.update.ctl.start`)
+
+	var req RoleUpdateServiceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		base.ValidationFailed(ctx, err)
+		return
+	}
+	actorID, err := auth.GetUserID(ctx)
+	if err != nil {
+		base.Unauthorized(ctx, i18n.Unauthorized, nil)
+		return
+	}
+	req.Name = ctx.Param("role")
+	req.ActorID = actorID
+
+	data, err := c.svc.RoleUpdateService(ctx.Request.Context(), &req)
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`teacher.role.update.ctl.end`)
+	base.Success(ctx, data)
+}
+
+func (c *Controller) TeacherRoleListController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.role.teacher.ctl.start`)
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		base.InvalidField(ctx, "id", "uuid")
+		return
+	}
+
+	data, err := c.svc.TeacherRoleListService(ctx.Request.Context(), id)
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`teacher.role.teacher.ctl.end`)
+	base.Success(ctx, data)
+}
+
+func (c *Controller) RoleAssignController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.role.assign.ctl.start`)
+
+	var req RoleAssignServiceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		base.ValidationFailed(ctx, err)
+		return
+	}
+	if !bindRoleActor(ctx, &req) {
+		return
+	}
+
+	if err := c.svc.RoleAssignService(ctx.Request.Context(), &req); err != nil {
+		handleRoleError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`teacher.role.assign.ctl.end`)
+	base.Success(ctx, nil)
+}
+
+func (c *Controller) RoleRevokeController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.role.revoke.ctl.start`)
+
+	req := RoleAssignServiceRequest{Role: ctx.Param("role")}
+	if !bindRoleActor(ctx, &req) {
+		return
+	}
+
+	if err := c.svc.RoleRevokeService(ctx.Request.Context(), &req); err != nil {
+		handleRoleError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`teacher.role.revoke.ctl.end`)
+	base.Success(ctx, nil)
+}
+
+// bindRoleActor fills the teacher from the path and the actor from the token.
+func bindRoleActor(ctx *gin.Context, req *RoleAssignServiceRequest) bool {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		base.InvalidField(ctx, "id", "uuid")
+		return false
+	}
+	actorID, err := auth.GetUserID(ctx)
+	if err != nil {
+		base.Unauthorized(ctx, i18n.Unauthorized, nil)
+		return false
+	}
+	req.ID = id
+	req.ActorID = actorID
+	req.ActorPermissions = auth.GetPermissions(ctx)
+	return true
+}
+
+func handleRoleError(ctx *gin.Context, err error) {
+	if errors.Is(err, ErrRoleNotGrantable) {
+		base.Forbidden(ctx, i18n.Forbidden, nil)
+		return
+	}
+	base.HandleCustomError(ctx, err)
+}
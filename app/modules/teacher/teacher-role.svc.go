@@ -0,0 +1,192 @@
+package teacher
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/google/uuid"
+)
+
+type RoleServiceResponse struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+	MFARequired bool     `json:"mfa_required"`
+}
+
+type RoleAssignServiceRequest struct {
+	ID   uuid.UUID `json:"-"`
+	Role string    `json:"role" binding:"required,max=50"`
+
+	ActorID          uuid.UUID `json:"-"`
+	ActorPermissions []string  `json:"-"`
+}
+
+type RoleUpdateServiceRequest struct {
+	Name        string `json:"-"`
+	MFARequired *bool  `json:"mfa_required" binding:"required"` // บังคับให้ผู้มีบทบาทนี้ใช้ MFA
+
+	ActorID uuid.UUID `json:"-"`
+}
+
+func toRoleResponse(role *ent.RoleEntity) *RoleServiceResponse {
+	return &RoleServiceResponse{
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: role.Permissions,
+		MFARequired: role.MFARequired,
+	}
+}
+
+func toRoleResponses(roles []*ent.RoleEntity) []*RoleServiceResponse {
+	resp := make([]*RoleServiceResponse, 0, len(roles))
+	for _, role := range roles {
+		resp = append(resp, toRoleResponse(role))
+	}
+	return resp
+}
+
+// RoleListService lists the roles that can be assigned.
+func (s *Service) RoleListService(ctx context.Context) ([]*RoleServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.role.list.start`)
+
+	roles, err := s.dbRole.GetListRole(ctx)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	span.AddEvent(`teacher.svc.role.list.end`)
+	return toRoleResponses(roles), nil
+}
+
+// RoleUpdateService sets whether teachers holding a role must log in with a
+// TOTP code. Those without one are asked to enroll at their next login, and
+// can no longer refresh their tokens until they have.
+func (s *Service) RoleUpdateService(ctx context.Context, req *RoleUpdateServiceRequest) (*RoleServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.role.update.start`)
+
+	role, err := s.dbRole.SetRoleMFARequired(ctx, req.Name, *req.MFARequired)
+	if err != nil {
+		log.With(slog.Any(`body`, req)).Error(err)
+		return nil, err
+	}
+	log.Infof("security: mfa_required of role %s set to %t by %s", role.Name, role.MFARequired, req.ActorID)
+
+	span.AddEvent(`teacher.svc.role.update.end`)
+	return toRoleResponse(role), nil
+}
+
+// TeacherRoleListService lists the roles of a teacher.
+func (s *Service) TeacherRoleListService(ctx context.Context, id uuid.UUID) ([]*RoleServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.role.teacher.start`)
+
+	if _, err := s.db.GetByIDTeacher(ctx, id); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	roles, err := s.dbRole.GetTeacherRoles(ctx, id)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	span.AddEvent(`teacher.svc.role.teacher.end`)
+	return toRoleResponses(roles), nil
+}
+
+// RoleAssignService grants req.Role to a teacher.
+func (s *Service) RoleAssignService(ctx context.Context, req *RoleAssignServiceRequest) error {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.role.assign.start`)
+
+	role, err := s.grantableRole(ctx, req)
+	if err != nil {
+		log.With(slog.Any(`body`, req)).Error(err)
+		return err
+	}
+	if err := s.dbRole.AssignTeacherRole(ctx, &ent.TeacherRoleEntity{
+		TeacherID: req.ID,
+		RoleID:    role.ID,
+		GrantedBy: &req.ActorID,
+	}); err != nil {
+		log.With(slog.Any(`body`, req)).Error(err)
+		return err
+	}
+	log.Infof("security: role %s granted to %s by %s", role.Name, req.ID, req.ActorID)
+
+	span.AddEvent(`teacher.svc.role.assign.end`)
+	return nil
+}
+
+// RoleRevokeService takes req.Role away from a teacher and ends their
+// sessions, so the permissions it carried do not outlive it in access tokens.
+func (s *Service) RoleRevokeService(ctx context.Context, req *RoleAssignServiceRequest) error {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.role.revoke.start`)
+
+	role, err := s.grantableRole(ctx, req)
+	if err != nil {
+		log.With(slog.Any(`body`, req)).Error(err)
+		return err
+	}
+	if err := s.dbRole.RevokeTeacherRole(ctx, req.ID, role.ID); err != nil {
+		log.With(slog.Any(`body`, req)).Error(err)
+		return err
+	}
+	log.Infof("security: role %s revoked from %s by %s", role.Name, req.ID, req.ActorID)
+
+	if err := s.LogoutAllService(ctx, req.ID); err != nil {
+		return err
+	}
+
+	span.AddEvent(`teacher.svc.role.revoke.end`)
+	return nil
+}
+
+// grantableRole looks up req.Role and checks the actor may hand it out: they
+// must hold every permission it grants, and unless they hold all permissions
+// the teacher must belong to their own school. A teacher of another school
+// is reported as not found.
+func (s *Service) grantableRole(ctx context.Context, req *RoleAssignServiceRequest) (*ent.RoleEntity, error) {
+	role, err := s.dbRole.GetRoleByName(ctx, req.Role)
+	if err != nil {
+		return nil, err
+	}
+	if !canGrant(req.ActorPermissions, role) {
+		return nil, ErrRoleNotGrantable
+	}
+
+	teacher, err := s.db.GetByIDTeacher(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !auth.HasPermission(req.ActorPermissions, auth.PermAll) {
+		actor, err := s.db.GetByIDTeacher(ctx, req.ActorID)
+		if err != nil {
+			return nil, err
+		}
+		if actor.SchoolID != teacher.SchoolID {
+			return nil, base.NotFoundError{Resource: "teacher", ID: req.ID.String()}
+		}
+	}
+	return role, nil
+}
+
+// canGrant reports whether an actor with permissions holds every permission
+// role grants.
+func canGrant(permissions []string, role *ent.RoleEntity) bool {
+	for _, perm := range role.Permissions {
+		if !auth.HasPermission(permissions, perm) {
+			return false
+		}
+	}
+	return true
+}
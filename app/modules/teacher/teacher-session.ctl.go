@@ -0,0 +1,128 @@
+package teacher
+
+import (
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/config/i18n"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SessionListControllerResponse struct {
+	ID         string `json:"id"`
+	Device     string `json:"device"`
+	IP         string `json:"ip"`
+	UserAgent  string `json:"user_agent"`
+	Current    bool   `json:"current"`
+	CreatedAt  int64  `json:"created_at"`
+	LastSeenAt int64  `json:"last_seen_at"`
+	ExpiresAt  int64  `json:"expires_at"`
+}
+
+func sessionClient(ctx *gin.Context, device string) SessionClient {
+	return SessionClient{
+		Device:    device,
+		IP:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	}
+}
+
+// currentSession returns the teacher and session of the access token.
+func currentSession(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	teacherID, err := auth.GetUserID(ctx)
+	if err != nil {
+		base.Unauthorized(ctx, i18n.Unauthorized, nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	sessionID, err := auth.GetSessionID(ctx)
+	if err != nil {
+		base.Unauthorized(ctx, i18n.TokenInvalid, nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return teacherID, sessionID, true
+}
+
+func (c *Controller) SessionListController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.session.list.ctl.start`)
+
+	teacherID, sessionID, ok := currentSession(ctx)
+	if !ok {
+		return
+	}
+
+	data, err := c.svc.SessionListService(ctx.Request.Context(), teacherID, sessionID)
+	if err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	resp := []*SessionListControllerResponse{}
+	if err := utils.CopyNTimeToUnix(&resp, data); err != nil {
+		base.InternalServerError(ctx, err.Error(), nil)
+		return
+	}
+
+	span.AddEvent(`teacher.session.list.ctl.end`)
+	base.Success(ctx, resp)
+}
+
+func (c *Controller) SessionDeleteController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.session.delete.ctl.start`)
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		base.InvalidField(ctx, "id", "uuid")
+		return
+	}
+	teacherID, _, ok := currentSession(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.svc.SessionDeleteService(ctx.Request.Context(), teacherID, id); err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`teacher.session.delete.ctl.end`)
+	base.Success(ctx, nil)
+}
+
+func (c *Controller) Logout(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.logout.ctl.start`)
+
+	teacherID, sessionID, ok := currentSession(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.svc.SessionDeleteService(ctx.Request.Context(), teacherID, sessionID); err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`teacher.logout.ctl.end`)
+	base.Success(ctx, nil)
+}
+
+func (c *Controller) LogoutAll(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.logout.all.ctl.start`)
+
+	teacherID, _, ok := currentSession(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.svc.LogoutAllService(ctx.Request.Context(), teacherID); err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`teacher.logout.all.ctl.end`)
+	base.Success(ctx, nil)
+}
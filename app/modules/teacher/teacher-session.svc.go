@@ -0,0 +1,109 @@
+package teacher
+
+import (
+	"context"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/google/uuid"
+)
+
+type SessionListServiceResponse struct {
+	ID         uuid.UUID `json:"id"`
+	Device     string    `json:"device"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	Current    bool      `json:"current"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// sessionStore answers the auth middleware from the database, noting the
+// session as seen each time the cache asks.
+type sessionStore struct {
+	svc *Service
+}
+
+func (s sessionStore) SessionActive(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	return s.svc.dbSession.TouchTeacherSession(ctx, sessionID)
+}
+
+// Sessions is the session check for auth.AuthMiddleware.
+func (s *Service) Sessions() auth.SessionStore {
+	return s.sessions
+}
+
+func (s *Service) SessionListService(ctx context.Context, teacherID, currentID uuid.UUID) ([]*SessionListServiceResponse, error) {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.session.list.start`)
+
+	sessions, err := s.dbSession.GetActiveTeacherSessions(ctx, teacherID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	resp := make([]*SessionListServiceResponse, 0, len(sessions))
+	for _, session := range sessions {
+		resp = append(resp, &SessionListServiceResponse{
+			ID:         session.ID,
+			Device:     session.Device,
+			IP:         session.IP,
+			UserAgent:  session.UserAgent,
+			Current:    session.ID == currentID,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+		})
+	}
+
+	span.AddEvent(`teacher.svc.session.list.end`)
+	return resp, nil
+}
+
+// SessionDeleteService ends one session of the teacher; logging out is
+// ending the current one.
+func (s *Service) SessionDeleteService(ctx context.Context, teacherID, sessionID uuid.UUID) error {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.session.delete.start`)
+
+	if err := s.dbSession.RevokeTeacherSession(ctx, teacherID, sessionID); err != nil {
+		log.Error(err)
+		return err
+	}
+	s.sessions.Forget(sessionID)
+
+	span.AddEvent(`teacher.svc.session.delete.end`)
+	return nil
+}
+
+// logoutOthers ends every session of the teacher but the current one.
+func (s *Service) logoutOthers(ctx context.Context, teacherID, currentID uuid.UUID) error {
+	_, log := utils.LogSpanFromContext(ctx)
+
+	revoked, err := s.dbSession.RevokeOtherTeacherSessions(ctx, teacherID, currentID)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	s.sessions.Forget(revoked...)
+	return nil
+}
+
+// LogoutAllService ends every session of the teacher.
+func (s *Service) LogoutAllService(ctx context.Context, teacherID uuid.UUID) error {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.logout.all.start`)
+
+	revoked, err := s.dbSession.RevokeTeacherSessions(ctx, teacherID)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	s.sessions.Forget(revoked...)
+
+	span.AddEvent(`teacher.svc.logout.all.end`)
+	return nil
+}
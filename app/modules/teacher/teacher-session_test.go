@@ -0,0 +1,123 @@
+package teacher
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Test_SessionRevocation signs a teacher in on two devices, ends sessions and
+// asks the auth middleware which access tokens it still takes. A session
+// revoked by this instance is refused at once; one revoked by another
+// instance once its cached answer expires.
+func Test_SessionRevocation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const password = "correct horse battery"
+	const ttl = 50 * time.Millisecond
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		// revoke ends sessions of the teacher signed in on phone and laptop
+		revoke     func(svc *Service, db *memoryEntities, teacher, phone, laptop uuid.UUID) error
+		notFound   bool // revoke is refused
+		wait       bool // let the cached answers expire before asking again
+		wantPhone  int
+		wantLaptop int
+	}{
+		{
+			name: "Test logout",
+			revoke: func(svc *Service, db *memoryEntities, teacher, phone, laptop uuid.UUID) error {
+				return svc.SessionDeleteService(ctx, teacher, phone)
+			},
+			wantPhone:  http.StatusUnauthorized,
+			wantLaptop: http.StatusOK,
+		},
+		{
+			name: "Test logout all",
+			revoke: func(svc *Service, db *memoryEntities, teacher, phone, laptop uuid.UUID) error {
+				return svc.LogoutAllService(ctx, teacher)
+			},
+			wantPhone:  http.StatusUnauthorized,
+			wantLaptop: http.StatusUnauthorized,
+		},
+		{
+			name: "Test revoke session of another teacher",
+			revoke: func(svc *Service, db *memoryEntities, teacher, phone, laptop uuid.UUID) error {
+				return svc.SessionDeleteService(ctx, uuid.New(), laptop)
+			},
+			notFound:   true,
+			wantPhone:  http.StatusOK,
+			wantLaptop: http.StatusOK,
+		},
+		{
+			name: "Test revoked elsewhere stays cached",
+			revoke: func(svc *Service, db *memoryEntities, teacher, phone, laptop uuid.UUID) error {
+				return db.RevokeTeacherSession(ctx, teacher, laptop)
+			},
+			wantPhone:  http.StatusOK,
+			wantLaptop: http.StatusOK,
+		},
+		{
+			name: "Test revoked elsewhere refused once cache expires",
+			revoke: func(svc *Service, db *memoryEntities, teacher, phone, laptop uuid.UUID) error {
+				return db.RevokeTeacherSession(ctx, teacher, laptop)
+			},
+			wait:       true,
+			wantPhone:  http.StatusOK,
+			wantLaptop: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newMemoryEntities()
+			svc := newTestService(t, db)
+			svc.sessions = auth.NewSessionCache(sessionStore{svc}, ttl)
+
+			r := gin.New()
+			r.GET("/teacher/me", auth.AuthMiddleware(svc.TokenManager(), svc.Sessions(), nil), func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+			get := func(accessToken string) int {
+				w := httptest.NewRecorder()
+				req := httptest.NewRequest(http.MethodGet, "/teacher/me", nil)
+				req.Header.Set("Authorization", "Bearer "+accessToken)
+				r.ServeHTTP(w, req)
+				return w.Code
+			}
+
+			teacher := db.addTeacher(t, svc, password)
+			phone, laptop := login(t, svc, teacher, password), login(t, svc, teacher, password)
+			// Both answers are cached from here on
+			for _, token := range []string{phone.AccessToken, laptop.AccessToken} {
+				if code := get(token); code != http.StatusOK {
+					t.Fatalf("status before revoking = %d, want %d", code, http.StatusOK)
+				}
+			}
+
+			err := tt.revoke(svc, db, teacher.ID, sessionOf(t, svc, phone.AccessToken), sessionOf(t, svc, laptop.AccessToken))
+			var notFound base.NotFoundError
+			if got := errors.As(err, &notFound); got != tt.notFound || err != nil && !got {
+				t.Fatalf("revoke error = %v, want not found %t", err, tt.notFound)
+			}
+			if tt.wait {
+				time.Sleep(2 * ttl)
+			}
+
+			if code := get(phone.AccessToken); code != tt.wantPhone {
+				t.Errorf("phone status = %d, want %d", code, tt.wantPhone)
+			}
+			if code := get(laptop.AccessToken); code != tt.wantLaptop {
+				t.Errorf("laptop status = %d, want %d", code, tt.wantLaptop)
+			}
+		})
+	}
+}
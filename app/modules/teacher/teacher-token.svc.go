@@ -0,0 +1,114 @@
+package teacher
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/config"
+	"github.com/google/uuid"
+)
+
+// developmentEnvs are the APP_ENV values that may sign with the default
+// HS256 secret.
+var developmentEnvs = []string{"local", "dev", "development"}
+
+// SessionClient describes where a session was started from.
+type SessionClient struct {
+	Device    string
+	IP        string
+	UserAgent string
+}
+
+// newTokenManager signs with the algorithm and keys set in conf. An HS256
+// secret that is empty, or the public default outside local development,
+// would let anyone sign tokens, so the service refuses to start with it.
+func newTokenManager(conf config.JWTConfig, env string) (*auth.TokenManager, error) {
+	keys := auth.NewSecretKeySet(conf.SecretKey)
+	if conf.Algorithm != "" && conf.Algorithm != auth.AlgHS256 {
+		var err error
+		if keys, err = auth.LoadKeySet(conf.Algorithm, conf.KeyDir, conf.KeyID); err != nil {
+			return nil, err
+		}
+	} else if conf.SecretKey == "" || (conf.SecretKey == config.DefaultJWTSecretKey && !slices.Contains(developmentEnvs, env)) {
+		return nil, ErrInsecureSecretKey
+	}
+	return auth.NewTokenManagerWithKeys(
+		keys,
+		conf.Issuer,
+		time.Duration(conf.AccessTokenExpiry)*time.Hour,
+		time.Duration(conf.RefreshTokenExpiry)*time.Hour,
+	), nil
+}
+
+// TokenManager issues and verifies the teachers' access tokens.
+func (s *Service) TokenManager() *auth.TokenManager {
+	return s.tokens
+}
+
+// grants returns the names of a teacher's roles and the union of their
+// permissions, as carried in access tokens.
+func (s *Service) grants(ctx context.Context, teacherID uuid.UUID) ([]string, []string, error) {
+	roles, err := s.dbRole.GetTeacherRoles(ctx, teacherID)
+	if err != nil {
+		return nil, nil, err
+	}
+	names := make([]string, 0, len(roles))
+	var permissions []string
+	for _, role := range roles {
+		names = append(names, role.Name)
+		for _, perm := range role.Permissions {
+			if !slices.Contains(permissions, perm) {
+				permissions = append(permissions, perm)
+			}
+		}
+	}
+	return names, permissions, nil
+}
+
+// issueTokens signs in teacher with a new session.
+func (s *Service) issueTokens(ctx context.Context, teacher *ent.TeacherEntity, client SessionClient) (*auth.TokenPair, error) {
+	roles, permissions, err := s.grants(ctx, teacher.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionID := uuid.New()
+	tokens, err := s.tokens.GenerateTokenPair(
+		teacher.ID,
+		sessionID,
+		teacher.SchoolID,
+		teacher.Email,
+		teacher.FirstName,
+		teacher.LastName,
+		"teacher",
+		roles,
+		permissions,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.dbSession.CreateTeacherSession(ctx, &ent.TeacherSessionEntity{
+		ID:        sessionID,
+		TeacherID: teacher.ID,
+		Device:    truncate(client.Device, 100),
+		IP:        truncate(client.IP, 45),
+		UserAgent: truncate(client.UserAgent, 512),
+	}, &ent.RefreshTokenEntity{
+		TokenHash: tokens.RefreshTokenHash,
+		ExpiresAt: tokens.RefreshExpiresAt,
+	}); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
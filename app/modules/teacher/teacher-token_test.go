@@ -0,0 +1,39 @@
+package teacher
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/config"
+)
+
+func Test_NewTokenManager(t *testing.T) {
+	tests := []struct {
+		name      string
+		algorithm string
+		secret    string
+		env       string
+		wantErr   error
+	}{
+		{"Test own secret", auth.AlgHS256, "a-secret-of-its-own", "production", nil},
+		{"Test default secret in development", auth.AlgHS256, config.DefaultJWTSecretKey, "development", nil},
+		{"Test default secret in local", "", config.DefaultJWTSecretKey, "local", nil},
+		{"Test default secret in production", auth.AlgHS256, config.DefaultJWTSecretKey, "production", ErrInsecureSecretKey},
+		{"Test default secret without algorithm", "", config.DefaultJWTSecretKey, "staging", ErrInsecureSecretKey},
+		{"Test empty secret", auth.AlgHS256, "", "development", ErrInsecureSecretKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTokenManager(config.JWTConfig{
+				SecretKey:          tt.secret,
+				Algorithm:          tt.algorithm,
+				AccessTokenExpiry:  1,
+				RefreshTokenExpiry: 1,
+			}, tt.env)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("newTokenManager() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package teacher
+
+import (
+	"errors"
+	"io"
+
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/config/i18n"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (c *Controller) UnlockController(ctx *gin.Context) {
+	span, _ := utils.LogSpanFromContext(ctx.Request.Context())
+	span.AddEvent(`teacher.unlock.ctl.start`)
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		base.InvalidField(ctx, "id", "uuid")
+		return
+	}
+
+	// The body is optional: without one only the email is unlocked
+	var req UnlockServiceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		base.ValidationFailed(ctx, err)
+		return
+	}
+
+	actorID, err := auth.GetUserID(ctx)
+	if err != nil {
+		base.Unauthorized(ctx, i18n.Unauthorized, nil)
+		return
+	}
+	req.ID = id
+	req.ActorID = actorID
+	req.ActorIP = ctx.ClientIP()
+
+	if err := c.svc.UnlockService(ctx.Request.Context(), &req); err != nil {
+		base.HandleCustomError(ctx, err)
+		return
+	}
+
+	span.AddEvent(`teacher.unlock.ctl.end`)
+	base.Success(ctx, nil)
+}
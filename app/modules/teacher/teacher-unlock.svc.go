@@ -0,0 +1,43 @@
+package teacher
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/easy-attend-serviceV3/app/modules/lockout"
+	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/google/uuid"
+)
+
+type UnlockServiceRequest struct {
+	ID uuid.UUID `json:"-"`
+	IP string    `json:"ip" binding:"omitempty,ip"` // also lift the lockout of this IP
+
+	ActorID uuid.UUID `json:"-"`
+	ActorIP string    `json:"-"`
+}
+
+// UnlockService lifts the login lockout of a teacher's email, and of req.IP
+// when given, on behalf of an administrator.
+func (s *Service) UnlockService(ctx context.Context, req *UnlockServiceRequest) error {
+	span, log := utils.LogSpanFromContext(ctx)
+	span.AddEvent(`teacher.svc.unlock.start`)
+
+	teacher, err := s.db.GetByIDTeacher(ctx, req.ID)
+	if err != nil {
+		log.With(slog.Any(`body`, req)).Error(err)
+		return err
+	}
+
+	keys := []lockout.Key{lockout.EmailKey(teacher.Email)}
+	if req.IP != "" {
+		keys = append(keys, lockout.IPKey(req.IP))
+	}
+	if err := s.locks.UnlockService(ctx, req.ActorID, req.ActorIP, keys...); err != nil {
+		log.With(slog.Any(`body`, req)).Error(err)
+		return err
+	}
+
+	span.AddEvent(`teacher.svc.unlock.end`)
+	return nil
+}
@@ -2,17 +2,17 @@ package teacher
 
 import (
 	"github.com/easy-attend-serviceV3/app/utils"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
 	"github.com/easy-attend-serviceV3/app/utils/base"
-	"github.com/easy-attend-serviceV3/config/i18n"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 type UpdateControllerRequest struct {
-	SchoolID    string `json:"school_id" binding:"required"`
-	ClassroomID string `json:"classroom_id"`
-	PrefixID    string `json:"prefix_id" binding:"required"`
-	GenderID    string `json:"gender_id" binding:"required"`
+	SchoolID    string `json:"school_id" binding:"required,uuid"`
+	ClassroomID string `json:"classroom_id" binding:"omitempty,uuid"`
+	PrefixID    string `json:"prefix_id" binding:"required,uuid"`
+	GenderID    string `json:"gender_id" binding:"required,uuid"`
 	FirstName   string `json:"first_name" binding:"required"`
 	LastName    string `json:"last_name" binding:"required"`
 	Email       string `json:"email" binding:"required,email"`
@@ -27,43 +27,42 @@ func (c *Controller) UpdateController(ctx *gin.Context) {
 	idParam := ctx.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.InvalidField(ctx, "id", "uuid")
+		return
+	}
+
+	ov, err := auth.NewOwnershipVerifier(ctx, c.access)
+	if err == nil {
+		err = ov.VerifyTeacherAccess(ctx.Request.Context(), id)
+	}
+	if err != nil {
+		base.HandleCustomError(ctx, err)
 		return
 	}
 
 	var request UpdateControllerRequest
 	if err := ctx.ShouldBindJSON(&request); err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.ValidationFailed(ctx, err)
 		return
 	}
 	span.AddEvent(`teacher.update.ctl.request`)
 
-	// Parse UUIDs
-	schoolID, err := uuid.Parse(request.SchoolID)
-	if err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
-		return
-	}
+	// The binding has checked the UUIDs
+	schoolID := uuid.MustParse(request.SchoolID)
 
 	var classroomIDPtr *uuid.UUID
 	if request.ClassroomID != "" {
-		classroomID, err := uuid.Parse(request.ClassroomID)
-		if err != nil {
-			base.BadRequest(ctx, i18n.BadRequest, nil)
-			return
-		}
+		classroomID := uuid.MustParse(request.ClassroomID)
 		classroomIDPtr = &classroomID
 	}
 
-	prefixID, err := uuid.Parse(request.PrefixID)
-	if err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
-		return
-	}
+	prefixID := uuid.MustParse(request.PrefixID)
+
+	genderID := uuid.MustParse(request.GenderID)
 
-	genderID, err := uuid.Parse(request.GenderID)
+	version, err := base.IfMatch(ctx)
 	if err != nil {
-		base.BadRequest(ctx, i18n.BadRequest, nil)
+		base.HandleCustomError(ctx, err)
 		return
 	}
 
@@ -77,8 +76,9 @@ func (c *Controller) UpdateController(ctx *gin.Context) {
 		LastName:    request.LastName,
 		Email:       request.Email,
 		Phone:       request.Phone,
+		Version:     version,
 	}); err != nil {
-		base.HandleError(ctx, err)
+		base.HandleCustomError(ctx, err)
 		return
 	}
 
@@ -3,6 +3,7 @@ package teacher
 import (
 	"context"
 	"log/slog"
+	"time"
 
 	entitiesdto "github.com/easy-attend-serviceV3/app/modules/entities/dto"
 	"github.com/easy-attend-serviceV3/app/utils"
@@ -19,6 +20,7 @@ type UpdateServiceRequest struct {
 	LastName    string     `json:"last_name"`
 	Email       string     `json:"email"`
 	Phone       string     `json:"phone"`
+	Version     *time.Time `json:"-"` // from If-Match; nil writes unconditionally
 }
 
 func (s *Service) UpdateService(ctx context.Context, req *UpdateServiceRequest) error {
@@ -35,7 +37,7 @@ func (s *Service) UpdateService(ctx context.Context, req *UpdateServiceRequest)
 		LastName:    req.LastName,
 		Email:       req.Email,
 		Phone:       req.Phone,
-	})
+	}, req.Version)
 	if err != nil {
 		log.With(slog.Any(`body`, req)).Error(err)
 		return err
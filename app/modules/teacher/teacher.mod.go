@@ -1,8 +1,14 @@
 package teacher
 
 import (
+	"time"
+
 	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+	"github.com/easy-attend-serviceV3/app/modules/lockout"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/oidc"
 	"github.com/easy-attend-serviceV3/config"
+	"github.com/easy-attend-serviceV3/internal/mailer"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
@@ -14,62 +20,148 @@ type Module struct {
 }
 type (
 	Service struct {
-		tracer      trace.Tracer
-		config      *config.Config
-		db          entitiesinf.TeacherEntity
-		dbSchool    entitiesinf.SchoolEntity
-		dbClassroom entitiesinf.ClassroomEntity
-		dbPrefix    entitiesinf.PrefixEntity
-		dbGender    entitiesinf.GenderEntity
+		tracer       trace.Tracer
+		config       *config.Config
+		db           entitiesinf.TeacherEntity
+		dbSchool     entitiesinf.SchoolEntity
+		dbClassroom  entitiesinf.ClassroomEntity
+		dbPrefix     entitiesinf.PrefixEntity
+		dbGender     entitiesinf.GenderEntity
+		dbToken      entitiesinf.RefreshTokenEntity
+		dbSession    entitiesinf.TeacherSessionEntity
+		dbMailToken  entitiesinf.TeacherTokenEntity
+		dbRole       entitiesinf.RoleEntity
+		dbMFA        entitiesinf.TeacherMFAEntity
+		dbDomain     entitiesinf.SchoolDomainEntity
+		dbOIDC       entitiesinf.OIDCEntity
+		dbInvitation entitiesinf.TeacherInvitationEntity
+		dbJoinCode   entitiesinf.SchoolJoinCodeEntity
+		mailer       mailer.Mailer
+		locks        *lockout.Service
+		sessions     *auth.SessionCache
+		tokens       *auth.TokenManager
+		hasher       *auth.PasswordHasher
+		policy       *auth.PasswordPolicy
+		oidc         map[string]*oidc.Provider
 	}
 	Controller struct {
 		tracer trace.Tracer
 		svc    *Service
+		access entitiesinf.AccessEntity
 	}
 )
 
 type Options struct {
-	tracer      trace.Tracer
-	config      *config.Config
-	db          entitiesinf.TeacherEntity
-	dbSchool    entitiesinf.SchoolEntity
-	dbClassroom entitiesinf.ClassroomEntity
-	dbPrefix    entitiesinf.PrefixEntity
-	dbGender    entitiesinf.GenderEntity
+	tracer       trace.Tracer
+	config       *config.Config
+	db           entitiesinf.TeacherEntity
+	dbSchool     entitiesinf.SchoolEntity
+	dbClassroom  entitiesinf.ClassroomEntity
+	dbPrefix     entitiesinf.PrefixEntity
+	dbGender     entitiesinf.GenderEntity
+	dbToken      entitiesinf.RefreshTokenEntity
+	dbSession    entitiesinf.TeacherSessionEntity
+	dbMailToken  entitiesinf.TeacherTokenEntity
+	dbRole       entitiesinf.RoleEntity
+	dbMFA        entitiesinf.TeacherMFAEntity
+	dbDomain     entitiesinf.SchoolDomainEntity
+	dbOIDC       entitiesinf.OIDCEntity
+	dbInvitation entitiesinf.TeacherInvitationEntity
+	dbJoinCode   entitiesinf.SchoolJoinCodeEntity
+	mailer       mailer.Mailer
+	locks        *lockout.Service
+}
+
+// Entities is the storage the teacher module works on, all of it served by
+// the entities module.
+type Entities interface {
+	entitiesinf.TeacherEntity
+	entitiesinf.SchoolEntity
+	entitiesinf.ClassroomEntity
+	entitiesinf.PrefixEntity
+	entitiesinf.GenderEntity
+	entitiesinf.RefreshTokenEntity
+	entitiesinf.TeacherSessionEntity
+	entitiesinf.TeacherTokenEntity
+	entitiesinf.RoleEntity
+	entitiesinf.TeacherMFAEntity
+	entitiesinf.SchoolDomainEntity
+	entitiesinf.OIDCEntity
+	entitiesinf.TeacherInvitationEntity
+	entitiesinf.SchoolJoinCodeEntity
+	entitiesinf.AccessEntity
 }
 
-func New(conf *config.Config, db entitiesinf.TeacherEntity, dbSchool entitiesinf.SchoolEntity, dbClassroom entitiesinf.ClassroomEntity, dbPrefix entitiesinf.PrefixEntity, dbGender entitiesinf.GenderEntity) *Module {
+func New(conf *config.Config, db Entities, mail mailer.Mailer, locks *lockout.Service) (*Module, error) {
 	tracer := otel.Tracer("easy-attend-serviceV3.modules.teacher")
-	svc := newService(&Options{
-		tracer:      tracer,
-		config:      conf,
-		db:          db,
-		dbSchool:    dbSchool,
-		dbClassroom: dbClassroom,
-		dbPrefix:    dbPrefix,
-		dbGender:    dbGender,
+	svc, err := newService(&Options{
+		tracer:       tracer,
+		config:       conf,
+		db:           db,
+		dbSchool:     db,
+		dbClassroom:  db,
+		dbPrefix:     db,
+		dbGender:     db,
+		dbToken:      db,
+		dbSession:    db,
+		dbMailToken:  db,
+		dbRole:       db,
+		dbMFA:        db,
+		dbDomain:     db,
+		dbOIDC:       db,
+		dbInvitation: db,
+		dbJoinCode:   db,
+		mailer:       mail,
+		locks:        locks,
 	})
+	if err != nil {
+		return nil, err
+	}
 	return &Module{
 		Svc: svc,
-		Ctl: newController(tracer, svc),
-	}
+		Ctl: newController(tracer, svc, db),
+	}, nil
 }
 
-func newService(opt *Options) *Service {
-	return &Service{
-		tracer:      opt.tracer,
-		config:      opt.config,
-		db:          opt.db,
-		dbSchool:    opt.dbSchool,
-		dbClassroom: opt.dbClassroom,
-		dbPrefix:    opt.dbPrefix,
-		dbGender:    opt.dbGender,
+func newService(opt *Options) (*Service, error) {
+	svc := &Service{
+		tracer:       opt.tracer,
+		config:       opt.config,
+		db:           opt.db,
+		dbSchool:     opt.dbSchool,
+		dbClassroom:  opt.dbClassroom,
+		dbPrefix:     opt.dbPrefix,
+		dbGender:     opt.dbGender,
+		dbToken:      opt.dbToken,
+		dbSession:    opt.dbSession,
+		dbMailToken:  opt.dbMailToken,
+		dbRole:       opt.dbRole,
+		dbMFA:        opt.dbMFA,
+		dbDomain:     opt.dbDomain,
+		dbOIDC:       opt.dbOIDC,
+		dbInvitation: opt.dbInvitation,
+		dbJoinCode:   opt.dbJoinCode,
+		mailer:       opt.mailer,
+		locks:        opt.locks,
+	}
+	tokens, err := newTokenManager(opt.config.JWT, opt.config.AppEnv)
+	if err != nil {
+		return nil, err
+	}
+	svc.tokens = tokens
+	svc.hasher, svc.policy, err = newPasswordPolicy(opt.config.Password)
+	if err != nil {
+		return nil, err
 	}
+	svc.oidc = newOIDCProviders(opt.config.AppURL, opt.config.Oidc)
+	svc.sessions = auth.NewSessionCache(sessionStore{svc}, time.Duration(opt.config.JWT.SessionCacheTTL)*time.Second)
+	return svc, nil
 }
 
-func newController(trace trace.Tracer, svc *Service) *Controller {
+func newController(trace trace.Tracer, svc *Service, access entitiesinf.AccessEntity) *Controller {
 	return &Controller{
 		tracer: trace,
 		svc:    svc,
+		access: access,
 	}
 }
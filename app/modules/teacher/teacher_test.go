@@ -0,0 +1,391 @@
+package teacher
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	entitiesdto "github.com/easy-attend-serviceV3/app/modules/entities/dto"
+	"github.com/easy-attend-serviceV3/app/modules/entities/ent"
+	"github.com/easy-attend-serviceV3/app/modules/lockout"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/config"
+	configDTO "github.com/easy-attend-serviceV3/internal/config/dto"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+// memoryEntities keeps the teacher module's storage in memory, answering as
+// the entities module's queries do. Storage a test does not reach is left
+// to the nil Entities, so reaching it panics.
+type memoryEntities struct {
+	Entities
+
+	teachers    map[uuid.UUID]*ent.TeacherEntity
+	classrooms  map[uuid.UUID]*ent.ClassroomEntity
+	roles       []*ent.RoleEntity    // every teacher holds them
+	registered  map[uuid.UUID]string // role each teacher registered with
+	sessions    map[uuid.UUID]*ent.TeacherSessionEntity
+	refresh     []*ent.RefreshTokenEntity
+	tokens      []*ent.TeacherTokenEntity
+	mfa         map[uuid.UUID]*ent.TeacherMFAEntity
+	recovery    []*ent.TeacherRecoveryCodeEntity
+	invitations []*ent.TeacherInvitationEntity
+}
+
+func newMemoryEntities() *memoryEntities {
+	return &memoryEntities{
+		teachers:   map[uuid.UUID]*ent.TeacherEntity{},
+		classrooms: map[uuid.UUID]*ent.ClassroomEntity{},
+		roles:      []*ent.RoleEntity{{Name: "teacher"}},
+		registered: map[uuid.UUID]string{},
+		sessions:   map[uuid.UUID]*ent.TeacherSessionEntity{},
+		mfa:        map[uuid.UUID]*ent.TeacherMFAEntity{},
+	}
+}
+
+// newTestService serves the teacher module from db, with a cheap password
+// hash and the lockout counted in memory.
+func newTestService(t *testing.T, db *memoryEntities) *Service {
+	t.Helper()
+	conf := config.App
+	conf.JWT.SecretKey = "a-secret-of-its-own"
+	conf.Password.Memory = 1024
+	conf.Password.Iterations = 1
+	conf.Password.Parallelism = 1
+	locks := lockout.New(&configDTO.Config[lockout.Config]{Val: &conf.Lockout}, nil, nil)
+
+	svc, err := newService(&Options{
+		tracer:       otel.Tracer("test"),
+		config:       &conf,
+		db:           db,
+		dbSchool:     db,
+		dbClassroom:  db,
+		dbPrefix:     db,
+		dbGender:     db,
+		dbToken:      db,
+		dbSession:    db,
+		dbMailToken:  db,
+		dbRole:       db,
+		dbMFA:        db,
+		dbDomain:     db,
+		dbOIDC:       db,
+		dbInvitation: db,
+		dbJoinCode:   db,
+		locks:        locks.Svc,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc
+}
+
+// addTeacher stores a teacher of a new school who logs in with password.
+func (m *memoryEntities) addTeacher(t *testing.T, svc *Service, password string) *ent.TeacherEntity {
+	t.Helper()
+	hashed, err := svc.hasher.HashPassword(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := uuid.New()
+	teacher := &ent.TeacherEntity{
+		ID:        id,
+		SchoolID:  uuid.New(),
+		FirstName: "Somchai",
+		LastName:  "Jaidee",
+		Email:     id.String() + "@school.ac.th",
+		Password:  hashed,
+	}
+	m.teachers[id] = teacher
+	return teacher
+}
+
+// active reports whether session id may still be used.
+func (m *memoryEntities) active(id uuid.UUID) bool {
+	ok, _ := m.TouchTeacherSession(context.Background(), id)
+	return ok
+}
+
+func (m *memoryEntities) CreateTeacher(_ context.Context, req *entitiesdto.TeacherCreateRequest) (*ent.TeacherEntity, error) {
+	teacher := &ent.TeacherEntity{
+		ID:          uuid.New(),
+		SchoolID:    req.SchoolID,
+		ClassroomID: req.ClassroomID,
+		PrefixID:    req.PrefixID,
+		GenderID:    req.GenderID,
+		FirstName:   req.FirstName,
+		LastName:    req.LastName,
+		Email:       req.Email,
+		Password:    req.Password,
+		Phone:       req.Phone,
+	}
+	if req.InvitationID != nil {
+		accepted := false
+		now := time.Now()
+		for _, i := range m.invitations {
+			if i.ID == *req.InvitationID && i.AcceptedAt == nil && i.ExpiresAt.After(now) {
+				i.AcceptedAt, i.AcceptedBy = &now, &teacher.ID
+				accepted = true
+			}
+		}
+		if !accepted {
+			return nil, base.NotFoundError{Resource: "teacher_invitation", ID: req.InvitationID.String()}
+		}
+	}
+	m.teachers[teacher.ID] = teacher
+	m.registered[teacher.ID] = req.Role
+	return teacher, nil
+}
+
+func (m *memoryEntities) GetByIDTeacher(_ context.Context, id uuid.UUID) (*ent.TeacherEntity, error) {
+	if teacher, ok := m.teachers[id]; ok {
+		copied := *teacher
+		return &copied, nil
+	}
+	return nil, base.NotFoundError{Resource: "teacher", ID: id.String()}
+}
+
+func (m *memoryEntities) GetTeacherByEmail(_ context.Context, email string) (*ent.TeacherEntity, error) {
+	for _, teacher := range m.teachers {
+		if strings.EqualFold(teacher.Email, email) {
+			copied := *teacher
+			return &copied, nil
+		}
+	}
+	return nil, base.NotFoundError{Resource: "teacher", ID: email}
+}
+
+func (m *memoryEntities) UpdateTeacherPassword(_ context.Context, id uuid.UUID, password string) error {
+	m.teachers[id].Password = password
+	return nil
+}
+
+func (m *memoryEntities) GetByIDSchool(_ context.Context, id uuid.UUID) (*ent.SchoolEntity, error) {
+	return &ent.SchoolEntity{ID: id, Name: "School"}, nil
+}
+
+func (m *memoryEntities) GetByIDPrefix(_ context.Context, id uuid.UUID) (*ent.PrefixEntity, error) {
+	return &ent.PrefixEntity{ID: id}, nil
+}
+
+func (m *memoryEntities) GetByIDGender(_ context.Context, id uuid.UUID) (*ent.GenderEntity, error) {
+	return &ent.GenderEntity{ID: id}, nil
+}
+
+func (m *memoryEntities) GetByIDClassroom(_ context.Context, id uuid.UUID) (*ent.ClassroomEntity, error) {
+	if classroom, ok := m.classrooms[id]; ok {
+		return classroom, nil
+	}
+	return nil, base.NotFoundError{Resource: "classroom", ID: id.String()}
+}
+
+func (m *memoryEntities) GetTeacherRoles(context.Context, uuid.UUID) ([]*ent.RoleEntity, error) {
+	return m.roles, nil
+}
+
+func (m *memoryEntities) CreateTeacherSession(_ context.Context, session *ent.TeacherSessionEntity, token *ent.RefreshTokenEntity) error {
+	session.ExpiresAt = token.ExpiresAt
+	token.ID = uuid.New()
+	token.FamilyID = session.ID
+	token.TeacherID = session.TeacherID
+	m.sessions[session.ID] = session
+	m.refresh = append(m.refresh, token)
+	return nil
+}
+
+func (m *memoryEntities) TouchTeacherSession(_ context.Context, id uuid.UUID) (bool, error) {
+	session, ok := m.sessions[id]
+	return ok && session.RevokedAt == nil && session.ExpiresAt.After(time.Now()), nil
+}
+
+func (m *memoryEntities) RevokeTeacherSession(_ context.Context, teacherID, id uuid.UUID) error {
+	if len(m.revoke(func(s *ent.TeacherSessionEntity) bool { return s.ID == id && s.TeacherID == teacherID })) == 0 {
+		return base.NotFoundError{Resource: "teacher_session", ID: id.String()}
+	}
+	return nil
+}
+
+func (m *memoryEntities) RevokeTeacherSessions(_ context.Context, teacherID uuid.UUID) ([]uuid.UUID, error) {
+	return m.revoke(func(s *ent.TeacherSessionEntity) bool { return s.TeacherID == teacherID }), nil
+}
+
+func (m *memoryEntities) RevokeOtherTeacherSessions(_ context.Context, teacherID, keepID uuid.UUID) ([]uuid.UUID, error) {
+	return m.revoke(func(s *ent.TeacherSessionEntity) bool { return s.TeacherID == teacherID && s.ID != keepID }), nil
+}
+
+// revoke revokes the active sessions matched by where together with their
+// refresh tokens.
+func (m *memoryEntities) revoke(where func(*ent.TeacherSessionEntity) bool) []uuid.UUID {
+	now := time.Now()
+	var ids []uuid.UUID
+	for _, session := range m.sessions {
+		if session.RevokedAt == nil && where(session) {
+			session.RevokedAt = &now
+			ids = append(ids, session.ID)
+		}
+	}
+	for _, token := range m.refresh {
+		for _, id := range ids {
+			if token.FamilyID == id && token.RevokedAt == nil {
+				token.RevokedAt = &now
+			}
+		}
+	}
+	return ids
+}
+
+func (m *memoryEntities) GetRefreshTokenByHash(_ context.Context, hash string) (*ent.RefreshTokenEntity, error) {
+	for _, token := range m.refresh {
+		if token.TokenHash == hash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, base.NotFoundError{Resource: "refresh_token"}
+}
+
+func (m *memoryEntities) RotateRefreshToken(_ context.Context, current, next *ent.RefreshTokenEntity) (bool, error) {
+	now := time.Now()
+	for _, token := range m.refresh {
+		if token.ID != current.ID || token.UsedAt != nil || token.RevokedAt != nil {
+			continue
+		}
+		token.UsedAt = &now
+		next.ID = uuid.New()
+		next.FamilyID = current.FamilyID
+		next.ParentID = &current.ID
+		next.TeacherID = current.TeacherID
+		m.refresh = append(m.refresh, next)
+		m.sessions[current.FamilyID].ExpiresAt = next.ExpiresAt
+		return true, nil
+	}
+	return false, nil
+}
+
+func (m *memoryEntities) RevokeRefreshTokenFamily(_ context.Context, familyID uuid.UUID) error {
+	m.revoke(func(s *ent.TeacherSessionEntity) bool { return s.ID == familyID })
+	return nil
+}
+
+func (m *memoryEntities) CreateTeacherToken(_ context.Context, token *ent.TeacherTokenEntity) error {
+	kept := m.tokens[:0]
+	for _, t := range m.tokens {
+		if t.TeacherID != token.TeacherID || t.Purpose != token.Purpose || t.UsedAt != nil {
+			kept = append(kept, t)
+		}
+	}
+	token.ID = uuid.New()
+	m.tokens = append(kept, token)
+	return nil
+}
+
+func (m *memoryEntities) GetTeacherToken(_ context.Context, purpose, hash string) (*ent.TeacherTokenEntity, error) {
+	if token := m.usableToken(purpose, hash); token != nil {
+		copied := *token
+		return &copied, nil
+	}
+	return nil, base.NotFoundError{Resource: "teacher_token"}
+}
+
+func (m *memoryEntities) ConsumeTeacherToken(_ context.Context, purpose, hash string) (*ent.TeacherTokenEntity, error) {
+	token := m.usableToken(purpose, hash)
+	if token == nil {
+		return nil, base.NotFoundError{Resource: "teacher_token"}
+	}
+	now := time.Now()
+	token.UsedAt = &now
+	copied := *token
+	return &copied, nil
+}
+
+// usableToken returns the unused, unexpired token with hash.
+func (m *memoryEntities) usableToken(purpose, hash string) *ent.TeacherTokenEntity {
+	for _, token := range m.tokens {
+		if token.TokenHash == hash && token.Purpose == purpose && token.UsedAt == nil && token.ExpiresAt.After(time.Now()) {
+			return token
+		}
+	}
+	return nil
+}
+
+func (m *memoryEntities) GetTeacherMFA(_ context.Context, teacherID uuid.UUID) (*ent.TeacherMFAEntity, error) {
+	if mfa, ok := m.mfa[teacherID]; ok {
+		copied := *mfa
+		return &copied, nil
+	}
+	return nil, base.NotFoundError{Resource: "teacher_mfa", ID: teacherID.String()}
+}
+
+func (m *memoryEntities) EnrollTeacherMFA(_ context.Context, mfa *ent.TeacherMFAEntity) error {
+	m.mfa[mfa.TeacherID] = &ent.TeacherMFAEntity{TeacherID: mfa.TeacherID, Secret: mfa.Secret}
+	return nil
+}
+
+func (m *memoryEntities) ConfirmTeacherMFA(_ context.Context, teacherID uuid.UUID, step int64, codes []*ent.TeacherRecoveryCodeEntity) error {
+	mfa, ok := m.mfa[teacherID]
+	if !ok || mfa.ConfirmedAt != nil {
+		return base.NotFoundError{Resource: "teacher_mfa", ID: teacherID.String()}
+	}
+	now := time.Now()
+	mfa.ConfirmedAt, mfa.LastStep = &now, step
+	for _, code := range codes {
+		code.TeacherID = teacherID
+	}
+	m.recovery = codes
+	return nil
+}
+
+func (m *memoryEntities) UseTeacherMFAStep(_ context.Context, teacherID uuid.UUID, step int64) error {
+	mfa, ok := m.mfa[teacherID]
+	if !ok || mfa.ConfirmedAt == nil || mfa.LastStep >= step {
+		return base.NotFoundError{Resource: "teacher_mfa", ID: teacherID.String()}
+	}
+	mfa.LastStep = step
+	return nil
+}
+
+func (m *memoryEntities) ConsumeRecoveryCode(_ context.Context, teacherID uuid.UUID, hash string) error {
+	for _, code := range m.recovery {
+		if code.TeacherID == teacherID && code.CodeHash == hash && code.UsedAt == nil {
+			now := time.Now()
+			code.UsedAt = &now
+			return nil
+		}
+	}
+	return base.NotFoundError{Resource: "teacher_recovery_code"}
+}
+
+func (m *memoryEntities) GetPendingTeacherInvitation(_ context.Context, hash string) (*ent.TeacherInvitationEntity, error) {
+	for _, i := range m.invitations {
+		if i.TokenHash == hash && i.AcceptedAt == nil && i.ExpiresAt.After(time.Now()) {
+			copied := *i
+			return &copied, nil
+		}
+	}
+	return nil, base.NotFoundError{Resource: "teacher_invitation"}
+}
+
+// login signs teacher in with password, failing the test unless that hands
+// out tokens.
+func login(t *testing.T, svc *Service, teacher *ent.TeacherEntity, password string) *LoginServiceResponse {
+	t.Helper()
+	resp, err := svc.LoginService(context.Background(), &LoginServiceRequest{Email: teacher.Email, Password: password})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.RefreshToken == "" {
+		t.Fatalf("login answered %+v, want tokens", resp)
+	}
+	return resp
+}
+
+// sessionOf returns the session an access token was issued for.
+func sessionOf(t *testing.T, svc *Service, accessToken string) uuid.UUID {
+	t.Helper()
+	claims, err := svc.tokens.ValidateToken(accessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return claims.SessionID
+}
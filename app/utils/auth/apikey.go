@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+)
+
+// APIKeyHeader carries an API key, accepted by AuthMiddleware in place of a
+// Bearer token.
+const APIKeyHeader = "X-API-Key"
+
+// UserTypeAPIKey is the user_type of requests authenticated by an API key;
+// their user_id is the key's ID.
+const UserTypeAPIKey = "api_key"
+
+// apiKeyTag starts every key so leaked keys are easy to recognise in logs and
+// secret scanners.
+const apiKeyTag = "eak_"
+
+// apiKeyPrefixLen is how much of a key is kept in the clear to tell keys
+// apart.
+const apiKeyPrefixLen = len(apiKeyTag) + 8
+
+// APIKeyScopes are the permissions an API key may carry. Keys serve a
+// school's integrations, so they cannot manage schools, accounts, roles or
+// other keys.
+var APIKeyScopes = []string{
+	PermSchoolRead,
+	PermClassroomRead,
+	PermClassroomWrite,
+	PermStudentRead,
+	PermStudentWrite,
+	PermAttendanceRead,
+	PermAttendanceWrite,
+	PermTeacherRead,
+}
+
+// APIKeyStore resolves the key of an X-API-Key header to the claims the
+// request runs with. An unknown, revoked or expired key is a
+// base.NotFoundError.
+type APIKeyStore interface {
+	APIKeyClaims(ctx context.Context, key string) (*TokenClaims, error)
+}
+
+// NewAPIKey generates an API key. The caller shows key once and stores prefix
+// and hash, the HashOpaqueToken of key.
+func NewAPIKey() (key, prefix, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	key = apiKeyTag + base64.RawURLEncoding.EncodeToString(b)
+	return key, key[:apiKeyPrefixLen], HashOpaqueToken(key), nil
+}
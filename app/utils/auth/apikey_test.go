@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/app/utils/tenant"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type keyStore map[string]*TokenClaims
+
+func (s keyStore) APIKeyClaims(_ context.Context, key string) (*TokenClaims, error) {
+	claims, ok := s[key]
+	if !ok {
+		return nil, base.NotFoundError{Resource: "api_key"}
+	}
+	return claims, nil
+}
+
+func Test_NewAPIKey(t *testing.T) {
+	key, prefix, hash, err := NewAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(key, apiKeyTag) || !strings.HasPrefix(key, prefix) || len(prefix) != apiKeyPrefixLen {
+		t.Errorf("key %q, prefix %q", key, prefix)
+	}
+	if hash != HashOpaqueToken(key) || strings.Contains(hash, key) {
+		t.Errorf("hash %q does not hash key", hash)
+	}
+	if other, _, _, _ := NewAPIKey(); other == key {
+		t.Error("keys repeat")
+	}
+}
+
+func Test_APIKeyAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	schoolID := uuid.New()
+	keys := keyStore{"eak_valid": {
+		UserID:      uuid.New(),
+		UserType:    UserTypeAPIKey,
+		SchoolID:    schoolID,
+		Permissions: []string{PermStudentRead},
+	}}
+
+	tests := []struct {
+		name    string
+		key     string
+		require string
+		want    int
+	}{
+		{"Test no credentials", "", PermStudentRead, http.StatusUnauthorized},
+		{"Test unknown key", "eak_unknown", PermStudentRead, http.StatusUnauthorized},
+		{"Test valid key", "eak_valid", PermStudentRead, http.StatusOK},
+		{"Test scope not granted", "eak_valid", PermStudentWrite, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var scoped uuid.UUID
+			r := gin.New()
+			r.GET("/", RequireAuth(nil, nil, keys), RequirePermission(tt.require), func(c *gin.Context) {
+				scoped, _ = tenant.School(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.key != "" {
+				req.Header.Set(APIKeyHeader, tt.key)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+			if w.Code == http.StatusOK && scoped != schoolID {
+				t.Errorf("school = %s, want %s", scoped, schoolID)
+			}
+		})
+	}
+}
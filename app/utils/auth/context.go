@@ -79,3 +79,29 @@ func MustGetUserID(c *gin.Context) uuid.UUID {
 	}
 	return userID
 }
+
+// GetSessionID extracts the session the access token was issued for
+func GetSessionID(c *gin.Context) (uuid.UUID, error) {
+	claims, exists := c.Get("user_claims")
+	if !exists {
+		return uuid.Nil, errors.New("user claims not found in context")
+	}
+	tokenClaims, ok := claims.(*TokenClaims)
+	if !ok || tokenClaims.SessionID == uuid.Nil {
+		return uuid.Nil, errors.New("session ID not found in context")
+	}
+	return tokenClaims.SessionID, nil
+}
+
+// GetSchoolID extracts the school the access token was issued for
+func GetSchoolID(c *gin.Context) (uuid.UUID, error) {
+	claims, exists := c.Get("user_claims")
+	if !exists {
+		return uuid.Nil, errors.New("user claims not found in context")
+	}
+	tokenClaims, ok := claims.(*TokenClaims)
+	if !ok || tokenClaims.SchoolID == uuid.Nil {
+		return uuid.Nil, errors.New("school ID not found in context")
+	}
+	return tokenClaims.SchoolID, nil
+}
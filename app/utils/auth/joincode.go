@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// joinCodeAlphabet leaves out letters and digits that are easily mistaken for
+// one another (0/O, 1/I/L), since join codes are read out and typed by hand.
+const joinCodeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+// joinCodeLen is the number of characters in a join code, shown in two
+// groups of four.
+const joinCodeLen = 8
+
+// NewJoinCode generates a school join code such as "K7QM-4TZP".
+func NewJoinCode() (string, error) {
+	var b strings.Builder
+	limit := big.NewInt(int64(len(joinCodeAlphabet)))
+	for i := range joinCodeLen {
+		if i == joinCodeLen/2 {
+			b.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate join code: %w", err)
+		}
+		b.WriteByte(joinCodeAlphabet[n.Int64()])
+	}
+	return b.String(), nil
+}
+
+// NormalizeJoinCode returns code the way NewJoinCode formats it, whatever its
+// case, spacing and dashes. It returns "" when code cannot be a join code.
+func NormalizeJoinCode(code string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(code) {
+		switch {
+		case r == '-' || r == ' ':
+			continue
+		case !strings.ContainsRune(joinCodeAlphabet, r):
+			return ""
+		}
+		if b.Len() == joinCodeLen/2 {
+			b.WriteByte('-')
+		}
+		b.WriteRune(r)
+	}
+	if b.Len() != joinCodeLen+1 {
+		return ""
+	}
+	return b.String()
+}
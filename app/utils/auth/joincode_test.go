@@ -0,0 +1,40 @@
+package auth
+
+import "testing"
+
+func Test_NewJoinCode(t *testing.T) {
+	code, err := NewJoinCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if NormalizeJoinCode(code) != code {
+		t.Errorf("code %q is not normalized", code)
+	}
+	if other, _ := NewJoinCode(); other == code {
+		t.Error("codes repeat")
+	}
+}
+
+func Test_NormalizeJoinCode(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want string
+	}{
+		{"Test formatted", "K7QM-4TZP", "K7QM-4TZP"},
+		{"Test lower case without dash", "k7qm4tzp", "K7QM-4TZP"},
+		{"Test spaces", " k7qm 4tzp ", "K7QM-4TZP"},
+		{"Test ambiguous letter", "K7QM-4TZO", ""},
+		{"Test too short", "K7QM-4TZ", ""},
+		{"Test too long", "K7QM-4TZPA", ""},
+		{"Test empty", "", ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := NormalizeJoinCode(tc.code); got != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
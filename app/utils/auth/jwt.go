@@ -8,9 +8,13 @@ import (
 	"github.com/google/uuid"
 )
 
+// DefaultIssuer is the iss claim when none is configured
+const DefaultIssuer = "easy-attend-service"
+
 // TokenManager handles simple token operations
 type TokenManager struct {
-	secretKey     string
+	keys          *KeySet
+	issuer        string
 	accessExpiry  time.Duration
 	refreshExpiry time.Duration
 }
@@ -22,89 +26,112 @@ type TokenClaims struct {
 	FirstName string    `json:"first_name"`
 	LastName  string    `json:"last_name"`
 	UserType  string    `json:"user_type"` // "teacher", "student", etc.
+	SessionID uuid.UUID `json:"sid"`
+	// SchoolID is the school the user's queries are limited to
+	SchoolID uuid.UUID `json:"school_id"`
+	// Roles and Permissions are as of when the token was issued; a change
+	// reaches the token at its next refresh.
+	Roles       []string `json:"roles,omitempty"`
+	Permissions []string `json:"perms,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// TokenPair represents access and refresh tokens
+// TokenPair represents access and refresh tokens. The refresh token is
+// opaque; the caller stores RefreshTokenHash so it can be rotated and revoked.
 type TokenPair struct {
-	AccessToken  string    `json:"access_token"`
-	RefreshToken string    `json:"refresh_token"`
-	ExpiresAt    time.Time `json:"expires_at"`
-	TokenType    string    `json:"token_type"`
+	AccessToken      string    `json:"access_token"`
+	RefreshToken     string    `json:"refresh_token"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	TokenType        string    `json:"token_type"`
+	RefreshTokenHash string    `json:"-"`
+	RefreshExpiresAt time.Time `json:"-"`
 }
 
-// NewTokenManager creates a new token manager
+// NewTokenManager creates a new HS256 token manager
 func NewTokenManager(secretKey string) *TokenManager {
-	return &TokenManager{
-		secretKey:     secretKey,
-		accessExpiry:  24 * time.Hour,     // 24 hours
-		refreshExpiry: 7 * 24 * time.Hour, // 7 days
-	}
+	return NewTokenManagerWithConfig(secretKey, 24*time.Hour, 7*24*time.Hour) // 24 hours, 7 days
 }
 
-// NewTokenManagerWithConfig creates a new token manager with custom durations
+// NewTokenManagerWithConfig creates a new HS256 token manager with custom durations
 func NewTokenManagerWithConfig(secretKey string, accessExpiry, refreshExpiry time.Duration) *TokenManager {
+	return NewTokenManagerWithKeys(NewSecretKeySet(secretKey), DefaultIssuer, accessExpiry, refreshExpiry)
+}
+
+// NewTokenManagerWithKeys creates a token manager signing with keys
+func NewTokenManagerWithKeys(keys *KeySet, issuer string, accessExpiry, refreshExpiry time.Duration) *TokenManager {
+	if issuer == "" {
+		issuer = DefaultIssuer
+	}
 	return &TokenManager{
-		secretKey:     secretKey,
+		keys:          keys,
+		issuer:        issuer,
 		accessExpiry:  accessExpiry,
 		refreshExpiry: refreshExpiry,
 	}
 }
 
-// GenerateTokenPair generates JWT access and refresh tokens
-func (tm *TokenManager) GenerateTokenPair(userID uuid.UUID, email, firstName, lastName, userType string) (*TokenPair, error) {
-	now := time.Now()
-
-	// Generate JWT access token
-	accessClaims := &TokenClaims{
-		UserID:    userID,
-		Email:     email,
-		FirstName: firstName,
-		LastName:  lastName,
-		UserType:  userType,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(tm.accessExpiry)),
-			IssuedAt:  jwt.NewNumericDate(now),
-			NotBefore: jwt.NewNumericDate(now),
-			Issuer:    "easy-attend-service",
-			Subject:   userID.String(),
-		},
+// GenerateTokenPair generates a JWT access token and an opaque refresh token
+func (tm *TokenManager) GenerateTokenPair(userID, sessionID, schoolID uuid.UUID, email, firstName, lastName, userType string, roles, permissions []string) (*TokenPair, error) {
+	accessToken, expiresAt, err := tm.GenerateAccessToken(userID, sessionID, schoolID, email, firstName, lastName, userType, roles, permissions)
+	if err != nil {
+		return nil, err
 	}
 
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessTokenString, err := accessToken.SignedString([]byte(tm.secretKey))
+	refreshToken, refreshTokenHash, err := NewOpaqueToken()
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign access token: %w", err)
+		return nil, err
 	}
 
-	// Generate JWT refresh token
-	refreshClaims := &TokenClaims{
-		UserID:    userID,
-		Email:     email,
-		FirstName: firstName,
-		LastName:  lastName,
-		UserType:  userType,
+	return &TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresAt:        expiresAt,
+		TokenType:        "Bearer",
+		RefreshTokenHash: refreshTokenHash,
+		RefreshExpiresAt: time.Now().Add(tm.refreshExpiry),
+	}, nil
+}
+
+// GenerateAccessToken generates a JWT access token for a session and returns
+// when it expires
+func (tm *TokenManager) GenerateAccessToken(userID, sessionID, schoolID uuid.UUID, email, firstName, lastName, userType string, roles, permissions []string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(tm.accessExpiry)
+
+	accessClaims := &TokenClaims{
+		UserID:      userID,
+		Email:       email,
+		FirstName:   firstName,
+		LastName:    lastName,
+		UserType:    userType,
+		SessionID:   sessionID,
+		SchoolID:    schoolID,
+		Roles:       roles,
+		Permissions: permissions,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(tm.refreshExpiry)),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
-			Issuer:    "easy-attend-service",
+			Issuer:    tm.issuer,
 			Subject:   userID.String(),
 		},
 	}
 
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshTokenString, err := refreshToken.SignedString([]byte(tm.secretKey))
+	accessTokenString, err := tm.keys.sign(accessClaims)
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign refresh token: %w", err)
+		return "", time.Time{}, fmt.Errorf("failed to sign access token: %w", err)
 	}
+	return accessTokenString, expiresAt, nil
+}
 
-	return &TokenPair{
-		AccessToken:  accessTokenString,
-		RefreshToken: refreshTokenString,
-		ExpiresAt:    now.Add(tm.accessExpiry),
-		TokenType:    "Bearer",
-	}, nil
+// RefreshExpiry is how long a refresh token stays valid when unused
+func (tm *TokenManager) RefreshExpiry() time.Duration {
+	return tm.refreshExpiry
+}
+
+// JWKS lists the public keys other services verify our tokens with
+func (tm *TokenManager) JWKS() JWKS {
+	return tm.keys.JWKS()
 }
 
 // ValidateToken validates a JWT token and returns claims
@@ -114,13 +141,10 @@ func (tm *TokenManager) ValidateToken(tokenString string) (*TokenClaims, error)
 	}
 
 	// Parse and validate the token
-	token, err := jwt.ParseWithClaims(tokenString, &TokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// Validate the signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(tm.secretKey), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &TokenClaims{}, tm.keys.verificationKey,
+		jwt.WithValidMethods([]string{tm.keys.Algorithm()}),
+		jwt.WithIssuer(tm.issuer),
+	)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token: %w", err)
@@ -139,21 +163,3 @@ func (tm *TokenManager) ValidateToken(tokenString string) (*TokenClaims, error)
 
 	return claims, nil
 }
-
-// RefreshToken generates a new access token from a valid refresh token
-func (tm *TokenManager) RefreshToken(refreshTokenString string) (*TokenPair, error) {
-	// Validate the refresh token
-	claims, err := tm.ValidateToken(refreshTokenString)
-	if err != nil {
-		return nil, fmt.Errorf("invalid refresh token: %w", err)
-	}
-
-	// Generate new token pair
-	return tm.GenerateTokenPair(
-		claims.UserID,
-		claims.Email,
-		claims.FirstName,
-		claims.LastName,
-		claims.UserType,
-	)
-}
@@ -0,0 +1,214 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Signing algorithms a KeySet can use.
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+type signingKey struct {
+	private any
+	public  any
+}
+
+// KeySet holds the keys tokens are signed and verified with. One key signs;
+// every key verifies, so tokens signed before a rotation stay valid until
+// they expire. Tokens carry the signing key's ID in their kid header.
+type KeySet struct {
+	method jwt.SigningMethod
+	active string
+	keys   map[string]signingKey
+}
+
+// NewSecretKeySet signs with HS256 and a shared secret. Only holders of the
+// secret can verify, so it publishes no JWKS.
+func NewSecretKeySet(secret string) *KeySet {
+	return &KeySet{
+		method: jwt.SigningMethodHS256,
+		keys:   map[string]signingKey{"": {private: []byte(secret), public: []byte(secret)}},
+	}
+}
+
+// LoadKeySet reads the PKCS#8 PEM private keys in dir for alg (RS256 or
+// EdDSA). Each file is named <kid>.pem. activeID picks the signing key; when
+// empty the last kid in lexical order signs, so keys named by date rotate by
+// adding a file.
+func LoadKeySet(alg, dir, activeID string) (*KeySet, error) {
+	var method jwt.SigningMethod
+	switch alg {
+	case AlgRS256:
+		method = jwt.SigningMethodRS256
+	case AlgEdDSA:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q", alg)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	ks := &KeySet{method: method, keys: make(map[string]signingKey, len(files))}
+	for _, file := range files {
+		kid := strings.TrimSuffix(filepath.Base(file), ".pem")
+		key, err := readPrivateKey(file, alg)
+		if err != nil {
+			return nil, err
+		}
+		ks.keys[kid] = key
+		ks.active = kid
+	}
+	if len(ks.keys) == 0 {
+		return nil, fmt.Errorf("no %s keys in %s", alg, dir)
+	}
+	if activeID != "" {
+		if _, ok := ks.keys[activeID]; !ok {
+			return nil, fmt.Errorf("signing key %q not found in %s", activeID, dir)
+		}
+		ks.active = activeID
+	}
+	return ks, nil
+}
+
+func readPrivateKey(file, alg string) (signingKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return signingKey{}, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return signingKey{}, fmt.Errorf("%s: no PEM block", file)
+	}
+	private, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return signingKey{}, fmt.Errorf("%s: %w", file, err)
+	}
+
+	switch key := private.(type) {
+	case *rsa.PrivateKey:
+		if alg == AlgRS256 {
+			return signingKey{private: key, public: &key.PublicKey}, nil
+		}
+	case ed25519.PrivateKey:
+		if alg == AlgEdDSA {
+			return signingKey{private: key, public: key.Public()}, nil
+		}
+	}
+	return signingKey{}, fmt.Errorf("%s: not a %s private key", file, alg)
+}
+
+// Algorithm is the JWS alg tokens are signed with.
+func (ks *KeySet) Algorithm() string {
+	return ks.method.Alg()
+}
+
+func (ks *KeySet) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.method, claims)
+	if ks.active != "" {
+		token.Header["kid"] = ks.active
+	}
+	return token.SignedString(ks.keys[ks.active].private)
+}
+
+// verificationKey is the jwt.Keyfunc for tokens of this set.
+func (ks *KeySet) verificationKey(token *jwt.Token) (any, error) {
+	if token.Method.Alg() != ks.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	return key.public, nil
+}
+
+// JWK is a public key in RFC 7517 form.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is the document served at /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS lists the public keys of the set, empty for a shared secret.
+func (ks *KeySet) JWKS() JWKS {
+	kids := make([]string, 0, len(ks.keys))
+	for kid := range ks.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	set := JWKS{Keys: []JWK{}}
+	for _, kid := range kids {
+		jwk := JWK{Kid: kid, Use: "sig", Alg: ks.method.Alg()}
+		switch key := ks.keys[kid].public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(key)
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// GenerateKey returns a new PKCS#8 PEM private key for alg (RS256 or EdDSA).
+func GenerateKey(alg string) ([]byte, error) {
+	var (
+		key crypto.Signer
+		err error
+	)
+	switch alg {
+	case AlgRS256:
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+	case AlgEdDSA:
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q", alg)
+	}
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func writeKey(t *testing.T, dir, alg, kid string) {
+	t.Helper()
+	key, err := GenerateKey(alg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, kid+".pem"), key, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_KeyRotation(t *testing.T) {
+	tests := []struct {
+		name    string
+		alg     string
+		wantKty string
+	}{
+		{"Test RS256", AlgRS256, "RSA"},
+		{"Test EdDSA", AlgEdDSA, "OKP"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeKey(t, dir, tt.alg, "2025-01")
+
+			keys, err := LoadKeySet(tt.alg, dir, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			old := NewTokenManagerWithKeys(keys, "", time.Hour, time.Hour)
+			oldToken, _, err := old.GenerateAccessToken(uuid.New(), uuid.New(), uuid.New(), "a@b.c", "A", "B", "teacher", nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Rotate: the new key signs, the old one still verifies.
+			writeKey(t, dir, tt.alg, "2025-02")
+			keys, err = LoadKeySet(tt.alg, dir, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			rotated := NewTokenManagerWithKeys(keys, "", time.Hour, time.Hour)
+			if _, err := rotated.ValidateToken(oldToken); err != nil {
+				t.Errorf("token of previous key rejected: %v", err)
+			}
+			newToken, _, err := rotated.GenerateAccessToken(uuid.New(), uuid.New(), uuid.New(), "a@b.c", "A", "B", "teacher", nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := old.ValidateToken(newToken); err == nil {
+				t.Error("token of unknown key accepted")
+			}
+			if _, err := NewTokenManager("secret").ValidateToken(newToken); err == nil {
+				t.Error("token accepted by HS256 manager")
+			}
+
+			jwks := rotated.JWKS()
+			if len(jwks.Keys) != 2 || jwks.Keys[0].Kid != "2025-01" || jwks.Keys[1].Kty != tt.wantKty || jwks.Keys[1].Alg != tt.alg {
+				t.Errorf("JWKS() = %+v", jwks)
+			}
+		})
+	}
+}
+
+func Test_SecretKeySet(t *testing.T) {
+	tm := NewTokenManager("secret")
+	token, _, err := tm.GenerateAccessToken(uuid.New(), uuid.New(), uuid.New(), "a@b.c", "A", "B", "teacher", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tm.ValidateToken(token); err != nil {
+		t.Errorf("ValidateToken() error = %v", err)
+	}
+	if _, err := NewTokenManager("other").ValidateToken(token); err == nil {
+		t.Error("token accepted with another secret")
+	}
+	if len(tm.JWKS().Keys) != 0 {
+		t.Error("shared secret published in JWKS")
+	}
+}
@@ -1,55 +1,30 @@
 package auth
 
 import (
+	"errors"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/app/utils/tenant"
+	"github.com/easy-attend-serviceV3/config/i18n"
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware provides JWT authentication middleware
-func AuthMiddleware(tokenManager *TokenManager) gin.HandlerFunc {
+// AuthMiddleware provides JWT authentication middleware. When sessions is
+// set, tokens must belong to a session it still reports active. When apiKeys
+// is set, an X-API-Key header is accepted in place of the Bearer token.
+func AuthMiddleware(tokenManager *TokenManager, sessions SessionStore, apiKeys APIKeyStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "Unauthorized",
-				"message": "Authorization header is required",
-			})
-			c.Abort()
-			return
-		}
-
-		// Check Bearer token format
-		parts := strings.SplitN(authHeader, " ", 2)
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "Unauthorized",
-				"message": "Invalid authorization header format",
-			})
-			c.Abort()
-			return
-		}
-
-		token := parts[1]
-		if token == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "Unauthorized",
-				"message": "Token is required",
-			})
-			c.Abort()
-			return
+		var claims *TokenClaims
+		if key := c.GetHeader(APIKeyHeader); key != "" && apiKeys != nil {
+			claims = apiKeyClaims(c, apiKeys, key)
+		} else {
+			claims = bearerClaims(c, tokenManager, sessions)
 		}
-
-		// Validate JWT token
-		claims, err := tokenManager.ValidateToken(token)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "Unauthorized",
-				"message": "Invalid or expired token",
-				"details": err.Error(),
-			})
+		if claims == nil {
 			c.Abort()
 			return
 		}
@@ -62,31 +37,99 @@ func AuthMiddleware(tokenManager *TokenManager) gin.HandlerFunc {
 		c.Set("user_last_name", claims.LastName)
 		c.Set("user_claims", claims)
 
+		// Limit the request's queries to the user's school; only a super
+		// admin sees every school
+		if HasPermission(claims.Permissions, PermAll) {
+			c.Request = c.Request.WithContext(tenant.Bypass(c.Request.Context()))
+		} else {
+			c.Request = c.Request.WithContext(tenant.WithSchool(c.Request.Context(), claims.SchoolID))
+		}
+
 		c.Next()
 	}
 }
 
-// RequireAuth is a helper function to create auth middleware
-func RequireAuth() gin.HandlerFunc {
-	tokenManager := NewTokenManager("your-secret-key-here") // TODO: Move to config
-	return AuthMiddleware(tokenManager)
+// bearerClaims validates the Bearer token of the request. It answers the
+// request and returns nil when the token is missing or no longer valid.
+func bearerClaims(c *gin.Context, tokenManager *TokenManager, sessions SessionStore) *TokenClaims {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		base.Unauthorized(c, i18n.TokenRequired, nil)
+		return nil
+	}
+
+	// Check Bearer token format
+	parts := strings.SplitN(authHeader, " ", 2)
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		base.Unauthorized(c, i18n.TokenMalformed, nil)
+		return nil
+	}
+
+	token := parts[1]
+	if token == "" {
+		base.Unauthorized(c, i18n.TokenRequired, nil)
+		return nil
+	}
+
+	// Validate JWT token
+	claims, err := tokenManager.ValidateToken(token)
+	if err != nil {
+		base.Unauthorized(c, i18n.TokenInvalid, nil)
+		return nil
+	}
+
+	// Reject tokens of sessions that were logged out
+	if sessions != nil {
+		active, err := sessions.SessionActive(c, claims.SessionID)
+		if err != nil {
+			base.HandleCustomError(c, err)
+			return nil
+		}
+		if !active {
+			base.Unauthorized(c, i18n.SessionRevoked, nil)
+			return nil
+		}
+	}
+	return claims
+}
+
+// apiKeyClaims resolves the API key of the request. It answers the request
+// and returns nil when the key is unknown, revoked or expired.
+func apiKeyClaims(c *gin.Context, apiKeys APIKeyStore, key string) *TokenClaims {
+	claims, err := apiKeys.APIKeyClaims(c.Request.Context(), key)
+	if err != nil {
+		var notFound base.NotFoundError
+		if errors.As(err, &notFound) {
+			base.Unauthorized(c, i18n.APIKeyInvalid, nil)
+		} else {
+			base.HandleCustomError(c, err)
+		}
+		return nil
+	}
+	return claims
+}
+
+// RequireAuth is a helper function to create auth middleware verifying tokens
+// issued by tokenManager, or API keys known to apiKeys
+func RequireAuth(tokenManager *TokenManager, sessions SessionStore, apiKeys APIKeyStore) gin.HandlerFunc {
+	return AuthMiddleware(tokenManager, sessions, apiKeys)
 }
 
 // RequireAuthWithConfig creates auth middleware with custom config
-func RequireAuthWithConfig(secretKey string, accessExpiry, refreshExpiry int) gin.HandlerFunc {
+func RequireAuthWithConfig(secretKey string, accessExpiry, refreshExpiry int, sessions SessionStore) gin.HandlerFunc {
 	tokenManager := NewTokenManagerWithConfig(
 		secretKey,
 		time.Duration(accessExpiry)*time.Hour,
 		time.Duration(refreshExpiry)*time.Hour,
 	)
-	return AuthMiddleware(tokenManager)
+	return AuthMiddleware(tokenManager, sessions, nil)
 }
 
-// RequireRole creates middleware that requires specific user role
-func RequireRole(tokenManager *TokenManager, requiredRole string) gin.HandlerFunc {
+// RequireRole creates middleware that requires the token to carry a role
+func RequireRole(tokenManager *TokenManager, sessions SessionStore, requiredRole string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// First run auth middleware
-		AuthMiddleware(tokenManager)(c)
+		AuthMiddleware(tokenManager, sessions, nil)(c)
 
 		// If auth middleware aborted, return
 		if c.IsAborted() {
@@ -94,12 +137,9 @@ func RequireRole(tokenManager *TokenManager, requiredRole string) gin.HandlerFun
 		}
 
 		// Check user role
-		userType, exists := c.Get("user_type")
-		if !exists || userType != requiredRole {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error":   "Forbidden",
-				"message": "Insufficient permissions",
-			})
+		claims, _ := c.Get("user_claims")
+		if tokenClaims, ok := claims.(*TokenClaims); !ok || !slices.Contains(tokenClaims.Roles, requiredRole) {
+			base.Forbidden(c, i18n.Forbidden, nil)
 			c.Abort()
 			return
 		}
@@ -107,3 +147,11 @@ func RequireRole(tokenManager *TokenManager, requiredRole string) gin.HandlerFun
 		c.Next()
 	}
 }
+
+// JWKSHandler serves the public keys of tokenManager as a JWK Set
+func JWKSHandler(tokenManager *TokenManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, tokenManager.JWKS())
+	}
+}
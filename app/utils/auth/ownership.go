@@ -2,19 +2,24 @@ package auth
 
 import (
 	"context"
-	"errors"
 
+	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+	"github.com/easy-attend-serviceV3/app/utils/base"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
-// OwnershipVerifier provides methods to verify user ownership of resources
+// OwnershipVerifier provides methods to verify user ownership of resources.
+// A row the teacher may not reach is reported as base.NotFoundError, the same
+// as a missing one, so IDs of other schools do not leak.
 type OwnershipVerifier struct {
 	teacherID uuid.UUID
+	all       bool // reaches every row its queries can: a super admin, or an API key acting for its whole school
+	db        entitiesinf.AccessEntity
 }
 
 // NewOwnershipVerifier creates a new ownership verifier for the authenticated user
-func NewOwnershipVerifier(c *gin.Context) (*OwnershipVerifier, error) {
+func NewOwnershipVerifier(c *gin.Context, db entitiesinf.AccessEntity) (*OwnershipVerifier, error) {
 	teacherID, err := GetUserID(c)
 	if err != nil {
 		return nil, err
@@ -22,6 +27,8 @@ func NewOwnershipVerifier(c *gin.Context) (*OwnershipVerifier, error) {
 
 	return &OwnershipVerifier{
 		teacherID: teacherID,
+		all:       HasPermission(GetPermissions(c), PermAll) || c.GetString("user_type") == UserTypeAPIKey,
+		db:        db,
 	}, nil
 }
 
@@ -30,51 +37,77 @@ func (ov *OwnershipVerifier) GetTeacherID() uuid.UUID {
 	return ov.teacherID
 }
 
-// VerifySchoolAccess checks if teacher has access to a specific school
-// This would typically check through teacher's school association
-func (ov *OwnershipVerifier) VerifySchoolAccess(ctx context.Context, schoolID uuid.UUID) error {
-	// Implementation would check if teacher belongs to this school
-	// For now, we'll implement a basic check
-	if schoolID == uuid.Nil {
-		return errors.New("invalid school ID")
+// ReachesAll reports whether the user reaches every row their queries can,
+// so lists need not be narrowed to the teacher's classrooms
+func (ov *OwnershipVerifier) ReachesAll() bool {
+	return ov.all
+}
+
+// verify runs check unless the teacher reaches everything.
+func (ov *OwnershipVerifier) verify(ctx context.Context, resource string, id uuid.UUID, check func(context.Context, uuid.UUID, uuid.UUID) (bool, error)) error {
+	if ov.all {
+		return nil
 	}
-	// TODO: Add actual database check
-	return nil
+	if id != uuid.Nil {
+		ok, err := check(ctx, ov.teacherID, id)
+		if err != nil || ok {
+			return err
+		}
+	}
+	return base.NotFoundError{Resource: resource, ID: id.String()}
+}
+
+// VerifySchoolAccess checks that the school is the teacher's own
+func (ov *OwnershipVerifier) VerifySchoolAccess(ctx context.Context, schoolID uuid.UUID) error {
+	return ov.verify(ctx, "school", schoolID, ov.db.TeacherCanAccessSchool)
+}
+
+// VerifyTeacherAccess checks that the other teacher works at the same school
+func (ov *OwnershipVerifier) VerifyTeacherAccess(ctx context.Context, teacherID uuid.UUID) error {
+	return ov.verify(ctx, "teacher", teacherID, ov.db.TeacherCanAccessTeacher)
 }
 
-// VerifyClassroomAccess checks if teacher has access to a specific classroom
-// This would typically check through classroom_members table
+// VerifyClassroomAccess checks that the teacher administers the classroom's
+// school, is assigned to it through classroom_teachers or stands in there
+// today with a full delegation
 func (ov *OwnershipVerifier) VerifyClassroomAccess(ctx context.Context, classroomID uuid.UUID) error {
-	// Implementation would check if teacher is assigned to this classroom
-	// For now, we'll implement a basic check
-	if classroomID == uuid.Nil {
-		return errors.New("invalid classroom ID")
-	}
-	// TODO: Add actual database check through classroom_members
-	return nil
+	return ov.verify(ctx, "classroom", classroomID, ov.db.TeacherCanAccessClassroom)
+}
+
+// VerifyClassroomManagement checks that the teacher administers the
+// classroom's school or is its homeroom teacher, who alone may change who
+// teaches or stands in there. A delegation never grants it.
+func (ov *OwnershipVerifier) VerifyClassroomManagement(ctx context.Context, classroomID uuid.UUID) error {
+	return ov.verify(ctx, "classroom", classroomID, ov.db.TeacherCanManageClassroom)
+}
+
+// VerifyClassroomMemberAccess checks that the membership is in a classroom
+// the teacher can reach
+func (ov *OwnershipVerifier) VerifyClassroomMemberAccess(ctx context.Context, memberID uuid.UUID) error {
+	return ov.verify(ctx, "classroom_member", memberID, ov.db.TeacherCanAccessClassroomMember)
 }
 
-// VerifyStudentAccess checks if teacher has access to a specific student
-// This would typically check through classroom membership
+// VerifyStudentAccess checks that the teacher administers the student's
+// school or the student is in one of the teacher's classrooms today
 func (ov *OwnershipVerifier) VerifyStudentAccess(ctx context.Context, studentID uuid.UUID) error {
-	// Implementation would check if student is in teacher's classrooms
-	// For now, we'll implement a basic check
-	if studentID == uuid.Nil {
-		return errors.New("invalid student ID")
-	}
-	// TODO: Add actual database check through classroom_members
-	return nil
+	return ov.verify(ctx, "student", studentID, ov.db.TeacherCanAccessStudent)
 }
 
-// VerifyAttendanceAccess checks if teacher has access to a specific attendance record
+// VerifyAttendanceAccess checks that the teacher can reach the attendance's
+// classroom or stands in there today with a delegation covering the
+// attendance's date
 func (ov *OwnershipVerifier) VerifyAttendanceAccess(ctx context.Context, attendanceID uuid.UUID) error {
-	// Implementation would check if attendance record belongs to teacher
-	// For now, we'll implement a basic check
-	if attendanceID == uuid.Nil {
-		return errors.New("invalid attendance ID")
-	}
-	// TODO: Add actual database check
-	return nil
+	return ov.verify(ctx, "attendance", attendanceID, ov.db.TeacherCanAccessAttendance)
+}
+
+// VerifyAttendanceMarking checks that the teacher may record attendance in
+// the classroom for date, YYYY-MM-DD or empty for today: they can reach it or
+// stand in there today with a delegation covering date, even one limited to
+// attendance
+func (ov *OwnershipVerifier) VerifyAttendanceMarking(ctx context.Context, classroomID uuid.UUID, date string) error {
+	return ov.verify(ctx, "classroom", classroomID, func(ctx context.Context, teacherID, classroomID uuid.UUID) (bool, error) {
+		return ov.db.TeacherCanMarkAttendance(ctx, teacherID, classroomID, date)
+	})
 }
 
 // CreateFilterRequest creates a filter request with teacher ID for services
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// stubAccess lets a teacher reach only the rows in its set.
+type stubAccess map[uuid.UUID]bool
+
+func (s stubAccess) can(_ context.Context, _, id uuid.UUID) (bool, error) { return s[id], nil }
+
+func (s stubAccess) TeacherCanAccessSchool(ctx context.Context, t, id uuid.UUID) (bool, error) {
+	return s.can(ctx, t, id)
+}
+func (s stubAccess) TeacherCanAccessTeacher(ctx context.Context, t, id uuid.UUID) (bool, error) {
+	return s.can(ctx, t, id)
+}
+func (s stubAccess) TeacherCanAccessClassroom(ctx context.Context, t, id uuid.UUID) (bool, error) {
+	return s.can(ctx, t, id)
+}
+func (s stubAccess) TeacherCanManageClassroom(ctx context.Context, t, id uuid.UUID) (bool, error) {
+	return s.can(ctx, t, id)
+}
+func (s stubAccess) TeacherCanAccessClassroomMember(ctx context.Context, t, id uuid.UUID) (bool, error) {
+	return s.can(ctx, t, id)
+}
+func (s stubAccess) TeacherCanAccessStudent(ctx context.Context, t, id uuid.UUID) (bool, error) {
+	return s.can(ctx, t, id)
+}
+func (s stubAccess) TeacherCanAccessAttendance(ctx context.Context, t, id uuid.UUID) (bool, error) {
+	return s.can(ctx, t, id)
+}
+
+func (s stubAccess) TeacherCanMarkAttendance(ctx context.Context, t, id uuid.UUID, _ string) (bool, error) {
+	return s.can(ctx, t, id)
+}
+
+var _ entitiesinf.AccessEntity = stubAccess(nil)
+
+func Test_OwnershipVerifier(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	own, other := uuid.New(), uuid.New()
+	access := stubAccess{own: true}
+
+	tests := []struct {
+		name     string
+		perms    []string
+		id       uuid.UUID
+		notFound bool
+	}{
+		{"Test own", []string{PermStudentRead}, own, false},
+		{"Test other school", []string{PermStudentRead}, other, true},
+		{"Test nil", []string{PermStudentRead}, uuid.Nil, true},
+		{"Test super admin", []string{PermAll}, other, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Set("user_id", uuid.New())
+			c.Set("user_email", "a@b.c")
+			c.Set("user_type", "teacher")
+			c.Set("user_claims", &TokenClaims{Permissions: tt.perms})
+
+			ov, err := NewOwnershipVerifier(c, access)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, verify := range []func(context.Context, uuid.UUID) error{
+				ov.VerifySchoolAccess, ov.VerifyTeacherAccess, ov.VerifyClassroomAccess, ov.VerifyClassroomManagement,
+				ov.VerifyClassroomMemberAccess, ov.VerifyStudentAccess, ov.VerifyAttendanceAccess,
+				func(ctx context.Context, id uuid.UUID) error { return ov.VerifyAttendanceMarking(ctx, id, "") },
+			} {
+				err := verify(context.Background(), tt.id)
+				var notFound base.NotFoundError
+				if errors.As(err, &notFound) != tt.notFound || (!tt.notFound && err != nil) {
+					t.Errorf("verify() error = %v, want not found %v", err, tt.notFound)
+				}
+			}
+		})
+	}
+}
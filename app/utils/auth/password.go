@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/easy-attend-serviceV3/app/utils/hashing"
 	"golang.org/x/crypto/argon2"
 )
 
@@ -30,6 +31,22 @@ func NewPasswordHasher() *PasswordHasher {
 	}
 }
 
+// NewPasswordHasherWithParams creates a password hasher with the given
+// Argon2id cost, memory being in KiB. Zero values keep the defaults.
+func NewPasswordHasherWithParams(memory, iterations uint32, parallelism uint8) *PasswordHasher {
+	p := NewPasswordHasher()
+	if memory > 0 {
+		p.memory = memory
+	}
+	if iterations > 0 {
+		p.iterations = iterations
+	}
+	if parallelism > 0 {
+		p.parallelism = parallelism
+	}
+	return p
+}
+
 // HashPassword hashes a password using Argon2id
 func (p *PasswordHasher) HashPassword(password string) (string, error) {
 	salt, err := p.generateRandomBytes(p.saltLength)
@@ -48,43 +65,81 @@ func (p *PasswordHasher) HashPassword(password string) (string, error) {
 	return encodedHash, nil
 }
 
-// VerifyPassword verifies a password against its hash
+// VerifyPassword verifies a password against its hash. Besides Argon2id it
+// accepts the bcrypt hashes of accounts created before Argon2id was adopted.
 func (p *PasswordHasher) VerifyPassword(password, encodedHash string) (bool, error) {
+	if isBcrypt(encodedHash) {
+		return hashing.CheckPasswordHash([]byte(encodedHash), []byte(password)), nil
+	}
+
+	params, salt, hash, err := decodeArgon2(encodedHash)
+	if err != nil {
+		return false, err
+	}
+	comparisonHash := argon2.IDKey([]byte(password), salt, params.iterations, params.memory, params.parallelism, params.keyLength)
+
+	return (subtle.ConstantTimeCompare(hash, comparisonHash) == 1), nil
+}
+
+// NeedsRehash reports whether encodedHash should be replaced by a hash of p,
+// because it is bcrypt or Argon2id with other parameters. Call it once the
+// password has been verified, while the plain password is at hand.
+func (p *PasswordHasher) NeedsRehash(encodedHash string) bool {
+	if isBcrypt(encodedHash) {
+		return true
+	}
+	params, salt, _, err := decodeArgon2(encodedHash)
+	if err != nil {
+		return false
+	}
+	return params.memory != p.memory ||
+		params.iterations != p.iterations ||
+		params.parallelism != p.parallelism ||
+		params.keyLength != p.keyLength ||
+		uint32(len(salt)) != p.saltLength
+}
+
+func isBcrypt(encodedHash string) bool {
+	return strings.HasPrefix(encodedHash, "$2a$") ||
+		strings.HasPrefix(encodedHash, "$2b$") ||
+		strings.HasPrefix(encodedHash, "$2y$")
+}
+
+// decodeArgon2 splits an encoded Argon2id hash into the parameters it was
+// made with, its salt and its key.
+func decodeArgon2(encodedHash string) (*PasswordHasher, []byte, []byte, error) {
 	vals := strings.Split(encodedHash, "$")
 	if len(vals) != 6 {
-		return false, fmt.Errorf("invalid hash format")
+		return nil, nil, nil, fmt.Errorf("invalid hash format")
 	}
 
 	var version int
 	_, err := fmt.Sscanf(vals[2], "v=%d", &version)
 	if err != nil {
-		return false, err
+		return nil, nil, nil, err
 	}
 	if version != argon2.Version {
-		return false, fmt.Errorf("incompatible version of argon2")
+		return nil, nil, nil, fmt.Errorf("incompatible version of argon2")
 	}
 
-	var memory, iterations uint32
-	var parallelism uint8
-	_, err = fmt.Sscanf(vals[3], "m=%d,t=%d,p=%d", &memory, &iterations, &parallelism)
+	params := &PasswordHasher{}
+	_, err = fmt.Sscanf(vals[3], "m=%d,t=%d,p=%d", &params.memory, &params.iterations, &params.parallelism)
 	if err != nil {
-		return false, err
+		return nil, nil, nil, err
 	}
 
 	salt, err := base64.RawStdEncoding.DecodeString(vals[4])
 	if err != nil {
-		return false, err
+		return nil, nil, nil, err
 	}
 
 	hash, err := base64.RawStdEncoding.DecodeString(vals[5])
 	if err != nil {
-		return false, err
+		return nil, nil, nil, err
 	}
-
-	keyLength := uint32(len(hash))
-	comparisonHash := argon2.IDKey([]byte(password), salt, iterations, memory, parallelism, keyLength)
-
-	return (subtle.ConstantTimeCompare(hash, comparisonHash) == 1), nil
+	params.saltLength = uint32(len(salt))
+	params.keyLength = uint32(len(hash))
+	return params, salt, hash, nil
 }
 
 // generateRandomBytes generates random bytes of specified length
@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/easy-attend-serviceV3/app/utils/base"
+)
+
+//go:embed breached-passwords.txt
+var breachedPasswords string
+
+// PasswordPolicy decides which new passwords are accepted. Existing passwords
+// are not checked, so tightening it only affects passwords set afterwards.
+type PasswordPolicy struct {
+	MinLength int
+	MaxLength int
+
+	breached map[string]struct{} // nil when breached passwords are allowed
+}
+
+// NewPasswordPolicy creates a policy on the length of passwords in
+// characters. With checkBreached it also refuses the passwords of the
+// embedded breach list and of extraFile, one per line, when set.
+func NewPasswordPolicy(minLength, maxLength int, checkBreached bool, extraFile string) (*PasswordPolicy, error) {
+	p := &PasswordPolicy{MinLength: minLength, MaxLength: maxLength}
+	if !checkBreached {
+		return p, nil
+	}
+
+	p.breached = make(map[string]struct{})
+	if err := p.addBreached(strings.NewReader(breachedPasswords)); err != nil {
+		return nil, err
+	}
+	if extraFile != "" {
+		f, err := os.Open(extraFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open breached password list: %w", err)
+		}
+		defer f.Close()
+		if err := p.addBreached(f); err != nil {
+			return nil, fmt.Errorf("failed to read breached password list: %w", err)
+		}
+	}
+	return p, nil
+}
+
+// addBreached adds the passwords of r, one per line. Blank lines and lines
+// starting with # are skipped.
+func (p *PasswordPolicy) addBreached(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p.breached[strings.ToLower(line)] = struct{}{}
+	}
+	return scanner.Err()
+}
+
+// Check returns a base.ValidationError on field when password breaks the
+// policy.
+func (p *PasswordPolicy) Check(field, password string) error {
+	n := utf8.RuneCountInString(password)
+	if p.MinLength > 0 && n < p.MinLength {
+		return base.ValidationError{Field: field, Rule: "min-length", Param: strconv.Itoa(p.MinLength)}
+	}
+	if p.MaxLength > 0 && n > p.MaxLength {
+		return base.ValidationError{Field: field, Rule: "max-length", Param: strconv.Itoa(p.MaxLength)}
+	}
+	if _, ok := p.breached[strings.ToLower(password)]; ok {
+		return base.ValidationError{Field: field, Rule: "breached"}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/app/utils/hashing"
+)
+
+func Test_PasswordRehash(t *testing.T) {
+	current := NewPasswordHasherWithParams(1024, 1, 1)
+	outdated := NewPasswordHasherWithParams(512, 1, 1)
+
+	argonCurrent, err := current.HashPassword("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	argonOutdated, err := outdated.HashPassword("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bcrypt, err := hashing.HashPassword("correct horse", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		hash       string
+		wantValid  bool
+		wantRehash bool
+	}{
+		{"Test current argon2id", argonCurrent, true, false},
+		{"Test outdated argon2id", argonOutdated, true, true},
+		{"Test legacy bcrypt", string(bcrypt), true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, err := current.VerifyPassword("correct horse", tt.hash)
+			if err != nil || valid != tt.wantValid {
+				t.Errorf("VerifyPassword() = %t, %v, want %t", valid, err, tt.wantValid)
+			}
+			if wrong, _ := current.VerifyPassword("wrong horse", tt.hash); wrong {
+				t.Error("VerifyPassword() accepted a wrong password")
+			}
+			if got := current.NeedsRehash(tt.hash); got != tt.wantRehash {
+				t.Errorf("NeedsRehash() = %t, want %t", got, tt.wantRehash)
+			}
+		})
+	}
+}
+
+func Test_PasswordPolicy(t *testing.T) {
+	policy, err := NewPasswordPolicy(8, 16, true, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		wantRule string
+	}{
+		{"Test ok", "tall-mango-river", ""},
+		{"Test too short", "short", "min-length"},
+		{"Test counts characters not bytes", "รหัสผ่านยาว", ""},
+		{"Test too long", "this-is-far-too-long", "max-length"},
+		{"Test breached", "password123", "breached"},
+		{"Test breached any case", "PassWord123", "breached"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check("password", tt.password)
+			var verr base.ValidationError
+			switch {
+			case tt.wantRule == "" && err != nil:
+				t.Errorf("Check() = %v, want nil", err)
+			case tt.wantRule != "" && (!errors.As(err, &verr) || verr.Rule != tt.wantRule):
+				t.Errorf("Check() = %v, want rule %s", err, tt.wantRule)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/easy-attend-serviceV3/app/utils/base"
+	"github.com/easy-attend-serviceV3/config/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// Permissions are "<resource>:<action>" and are granted through roles. A role
+// may hold "*" for everything or "<resource>:*" for every action on one
+// resource.
+const (
+	PermSchoolRead      = "school:read"
+	PermSchoolWrite     = "school:write"  // update a school
+	PermSchoolManage    = "school:manage" // create and delete schools
+	PermClassroomRead   = "classroom:read"
+	PermClassroomWrite  = "classroom:write"
+	PermStudentRead     = "student:read"
+	PermStudentWrite    = "student:write"
+	PermAttendanceRead  = "attendance:read"
+	PermAttendanceWrite = "attendance:write"
+	PermTeacherRead     = "teacher:read"
+	PermTeacherWrite    = "teacher:write"
+	PermRoleAssign      = "role:assign"
+	PermRoleManage      = "role:manage" // change what a role requires, such as MFA
+	PermAccountUnlock   = "account:unlock"
+	PermAPIKeyManage    = "api_key:manage"
+	PermAll             = "*"
+)
+
+// Roles seeded by the migrations.
+const (
+	RoleSuperAdmin  = "super_admin"
+	RoleSchoolAdmin = "school_admin"
+	RoleTeacher     = "teacher"
+	RoleStaff       = "staff"
+)
+
+// HasPermission reports whether granted covers perm.
+func HasPermission(granted []string, perm string) bool {
+	resource, _, _ := strings.Cut(perm, ":")
+	return slices.ContainsFunc(granted, func(g string) bool {
+		return g == perm || g == PermAll || g == resource+":*"
+	})
+}
+
+// GetPermissions returns the permissions carried by the access token.
+func GetPermissions(c *gin.Context) []string {
+	if claims, ok := c.Get("user_claims"); ok {
+		if tokenClaims, ok := claims.(*TokenClaims); ok {
+			return tokenClaims.Permissions
+		}
+	}
+	return nil
+}
+
+// RequirePermission rejects requests, already authenticated by
+// AuthMiddleware, whose token does not carry every one of perms.
+func RequirePermission(perms ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		granted := GetPermissions(c)
+		for _, perm := range perms {
+			if !HasPermission(granted, perm) {
+				base.Forbidden(c, i18n.Forbidden, nil)
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
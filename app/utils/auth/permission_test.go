@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Test_RequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		granted []string
+		require []string
+		want    int
+	}{
+		{"Test no claims", nil, []string{PermSchoolRead}, http.StatusForbidden},
+		{"Test exact", []string{PermSchoolRead}, []string{PermSchoolRead}, http.StatusOK},
+		{"Test other action", []string{PermSchoolRead}, []string{PermSchoolWrite}, http.StatusForbidden},
+		{"Test resource wildcard", []string{"classroom:*"}, []string{PermClassroomWrite}, http.StatusOK},
+		{"Test other resource wildcard", []string{"classroom:*"}, []string{PermSchoolManage}, http.StatusForbidden},
+		{"Test all", []string{PermAll}, []string{PermSchoolManage, PermRoleAssign}, http.StatusOK},
+		{"Test every one required", []string{PermStudentRead}, []string{PermStudentRead, PermStudentWrite}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/", func(c *gin.Context) {
+				if tt.granted != nil {
+					c.Set("user_claims", &TokenClaims{Permissions: tt.granted})
+				}
+			}, RequirePermission(tt.require...), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
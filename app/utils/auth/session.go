@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SessionStore reports whether a session may still be used.
+type SessionStore interface {
+	SessionActive(ctx context.Context, sessionID uuid.UUID) (bool, error)
+}
+
+type sessionEntry struct {
+	active  bool
+	expires time.Time
+}
+
+// SessionCache remembers SessionStore answers for ttl so the auth middleware
+// does not query the database on every request. A session revoked elsewhere
+// is therefore honoured within ttl; Forget applies a local revocation at once.
+type SessionCache struct {
+	store SessionStore
+	ttl   time.Duration
+
+	mu        sync.Mutex
+	entries   map[uuid.UUID]sessionEntry
+	nextSweep time.Time
+}
+
+var _ SessionStore = (*SessionCache)(nil)
+
+// NewSessionCache wraps store with a cache of ttl.
+func NewSessionCache(store SessionStore, ttl time.Duration) *SessionCache {
+	return &SessionCache{
+		store:   store,
+		ttl:     ttl,
+		entries: make(map[uuid.UUID]sessionEntry),
+	}
+}
+
+func (c *SessionCache) SessionActive(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[sessionID]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.active, nil
+	}
+
+	active, err := c.store.SessionActive(ctx, sessionID)
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !now.Before(c.nextSweep) {
+		for id, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, id)
+			}
+		}
+		c.nextSweep = now.Add(c.ttl)
+	}
+	c.entries[sessionID] = sessionEntry{active: active, expires: now.Add(c.ttl)}
+	return active, nil
+}
+
+// Forget drops the cached answers for sessionIDs.
+func (c *SessionCache) Forget(sessionIDs ...uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, id := range sessionIDs {
+		delete(c.entries, id)
+	}
+}
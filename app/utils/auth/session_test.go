@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type countingStore struct {
+	active map[uuid.UUID]bool
+	calls  int
+}
+
+func (s *countingStore) SessionActive(_ context.Context, id uuid.UUID) (bool, error) {
+	s.calls++
+	return s.active[id], nil
+}
+
+func Test_SessionCache(t *testing.T) {
+	id := uuid.New()
+	store := &countingStore{active: map[uuid.UUID]bool{id: true}}
+	cache := NewSessionCache(store, time.Minute)
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		before    func()
+		want      bool
+		wantCalls int
+	}{
+		{"Test miss", nil, true, 1},
+		{"Test hit", nil, true, 1},
+		{"Test revoked elsewhere stays cached", func() { store.active[id] = false }, true, 1},
+		{"Test forget", func() { cache.Forget(id) }, false, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.before != nil {
+				tt.before()
+			}
+			got, err := cache.SessionActive(ctx, id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want || store.calls != tt.wantCalls {
+				t.Errorf("SessionActive() = %v after %d calls, want %v after %d", got, store.calls, tt.want, tt.wantCalls)
+			}
+		})
+	}
+}
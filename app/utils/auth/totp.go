@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"fmt"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+// TOTP codes follow the defaults authenticator apps assume: SHA-1, 6 digits
+// and a 30 second period.
+const (
+	totpPeriod = 30
+	totpSkew   = 1 // steps accepted either side of now, for clock drift
+	totpQRSize = 256
+)
+
+// RecoveryCodeCount is how many recovery codes a teacher holds at a time.
+const RecoveryCodeCount = 10
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTPEnrollment is a new TOTP secret and the ways to add it to an
+// authenticator app.
+type TOTPEnrollment struct {
+	Secret          string
+	ProvisioningURI string
+	QRCode          []byte // PNG of ProvisioningURI
+}
+
+// NewTOTP generates a secret for account, shown as issuer in authenticator
+// apps.
+func NewTOTP(issuer, account string) (*TOTPEnrollment, error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      issuer,
+		AccountName: account,
+		Period:      totpPeriod,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate totp secret: %w", err)
+	}
+	return totpEnrollment(key)
+}
+
+func totpEnrollment(key *otp.Key) (*TOTPEnrollment, error) {
+	img, err := key.Image(totpQRSize, totpQRSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to draw totp qr code: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode totp qr code: %w", err)
+	}
+	return &TOTPEnrollment{
+		Secret:          key.Secret(),
+		ProvisioningURI: key.URL(),
+		QRCode:          buf.Bytes(),
+	}, nil
+}
+
+// VerifyTOTP checks code against secret at now and returns the time step it
+// was generated for. Callers reject steps at or before the last one used, so
+// a code cannot be replayed.
+func VerifyTOTP(secret, code string, now time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	opts := totp.ValidateOpts{Period: totpPeriod, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1}
+	step := now.Unix() / totpPeriod
+	for i := int64(-totpSkew); i <= totpSkew; i++ {
+		want, err := totp.GenerateCodeCustom(secret, time.Unix((step+i)*totpPeriod, 0), opts)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(want), []byte(code)) == 1 {
+			return step + i, true
+		}
+	}
+	return 0, false
+}
+
+// NewRecoveryCodes returns RecoveryCodeCount one-time codes, formatted
+// "xxxxx-xxxxx" for the teacher to write down, and the hashes to store.
+func NewRecoveryCodes() (codes, hashes []string, err error) {
+	for range RecoveryCodeCount {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		s := strings.ToLower(recoveryEncoding.EncodeToString(b))[:10]
+		codes = append(codes, s[:5]+"-"+s[5:])
+		hashes = append(hashes, HashRecoveryCode(s))
+	}
+	return codes, hashes, nil
+}
+
+// HashRecoveryCode is the lookup key stored for a recovery code. Case,
+// spaces and dashes are ignored, as people type codes back in many ways.
+func HashRecoveryCode(code string) string {
+	code = strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(code))
+	return HashOpaqueToken(code)
+}
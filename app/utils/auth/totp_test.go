@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+func Test_VerifyTOTP(t *testing.T) {
+	key, err := NewTOTP("Easy Attend", "teacher@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(key.ProvisioningURI, "otpauth://totp/") || !bytes.HasPrefix(key.QRCode, []byte("\x89PNG")) {
+		t.Fatalf("enrollment %q is not scannable", key.ProvisioningURI)
+	}
+
+	now := time.Unix(1_800_000_000, 0)
+	code := func(at time.Time) string {
+		c, err := totp.GenerateCodeCustom(key.Secret, at, totp.ValidateOpts{Period: totpPeriod, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name     string
+		code     string
+		wantStep int64
+		want     bool
+	}{
+		{"Test current", code(now), now.Unix() / totpPeriod, true},
+		{"Test previous step", code(now.Add(-totpPeriod * time.Second)), now.Unix()/totpPeriod - 1, true},
+		{"Test next step", code(now.Add(totpPeriod * time.Second)), now.Unix()/totpPeriod + 1, true},
+		{"Test too old", code(now.Add(-3 * totpPeriod * time.Second)), 0, false},
+		{"Test wrong", "000000", 0, false},
+		{"Test empty", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := VerifyTOTP(key.Secret, tt.code, now)
+			if ok != tt.want || step != tt.wantStep {
+				t.Errorf("VerifyTOTP() = %d, %t, want %d, %t", step, ok, tt.wantStep, tt.want)
+			}
+		})
+	}
+}
+
+func Test_RecoveryCodes(t *testing.T) {
+	codes, hashes, err := NewRecoveryCodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != RecoveryCodeCount || len(hashes) != RecoveryCodeCount {
+		t.Fatalf("got %d codes, %d hashes", len(codes), len(hashes))
+	}
+
+	tests := []struct {
+		name  string
+		typed string
+	}{
+		{"Test as shown", codes[0]},
+		{"Test upper case", strings.ToUpper(codes[0])},
+		{"Test without dash", strings.ReplaceAll(codes[0], "-", "")},
+		{"Test with spaces", strings.ReplaceAll(codes[0], "-", " ")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HashRecoveryCode(tt.typed); got != hashes[0] {
+				t.Errorf("HashRecoveryCode(%q) does not match the stored hash", tt.typed)
+			}
+		})
+	}
+}
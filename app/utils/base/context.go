@@ -120,6 +120,7 @@ func (c conventionalMarshallerFromPascal) MarshalJSON() ([]byte, error) {
 
 // JSON sends a JSON response with the given status code, message ID, data, and pagination information.
 // It supports localization of messages and custom parameter substitution.
+// Error statuses are sent as RFC 7807 problem documents when the request asks for them.
 func JSON(ctx *gin.Context, code int, msgID string, data any, paginate *ResponsePaginate, params ...map[string]string) error {
 	localizer := i18n.NewLocalizer(ci18n.Bundle, ctx.GetHeader("Accept-Language"))
 
@@ -130,6 +131,10 @@ func JSON(ctx *gin.Context, code int, msgID string, data any, paginate *Response
 	msg, err := localizer.Localize(&i18n.LocalizeConfig{MessageID: msgID, TemplateData: param})
 
 	if err != nil || msg == "" {
+		if code >= http.StatusBadRequest && wantsProblem(ctx) {
+			problemJSON(ctx, code, "", msgID, nil, data)
+			return nil
+		}
 		ctx.JSON(code, Response[any]{
 			ResponseStatus: &ResponseStatus{
 				Message: msgID,
@@ -141,6 +146,11 @@ func JSON(ctx *gin.Context, code int, msgID string, data any, paginate *Response
 		return nil
 	}
 
+	if code >= http.StatusBadRequest && wantsProblem(ctx) {
+		problemJSON(ctx, code, msgID, msg, nil, data)
+		return nil
+	}
+
 	ctx.JSON(code, conventionalMarshallerFromPascal{Response[any]{
 		ResponseStatus: &ResponseStatus{
 			Message: msg,
@@ -192,6 +202,21 @@ func Forbidden(ctx *gin.Context, message string, data any, params ...map[string]
 	return JSON(ctx, http.StatusForbidden, message, data, nil, params...)
 }
 
+// Conflict 409 clashes with the current state
+func Conflict(ctx *gin.Context, message string, data any, params ...map[string]string) error {
+	return JSON(ctx, http.StatusConflict, message, data, nil, params...)
+}
+
+// UnprocessableEntity 422 well-formed but unusable request
+func UnprocessableEntity(ctx *gin.Context, message string, data any, params ...map[string]string) error {
+	return JSON(ctx, http.StatusUnprocessableEntity, message, data, nil, params...)
+}
+
+// TooManyRequests 429 rate limited; set Retry-After before calling
+func TooManyRequests(ctx *gin.Context, message string, data any, params ...map[string]string) error {
+	return JSON(ctx, http.StatusTooManyRequests, message, data, nil, params...)
+}
+
 // ValidateFailed 412 Validate error
 func ValidateFailed(ctx *gin.Context, message string, data any, params ...map[string]string) error {
 	return JSON(ctx, http.StatusPreconditionFailed, message, data, nil, params...)
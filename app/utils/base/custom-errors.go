@@ -1,19 +1,27 @@
 package base
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
+	"github.com/easy-attend-serviceV3/config/i18n"
 	"github.com/gin-gonic/gin"
 )
 
-// ValidationError represents validation errors
+// ValidationError represents validation errors. With Rule set the message is
+// localized like a validator rule, as "validation-<Rule>" filled with Param.
 type ValidationError struct {
 	Field   string `json:"field"`
 	Message string `json:"message"`
+	Rule    string `json:"rule,omitempty"`
+	Param   string `json:"param,omitempty"`
 }
 
 func (e ValidationError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("validation error on field '%s': %s %s", e.Field, e.Rule, e.Param)
+	}
 	return fmt.Sprintf("validation error on field '%s': %s", e.Field, e.Message)
 }
 
@@ -37,54 +45,99 @@ func (e ConflictError) Error() string {
 	return fmt.Sprintf("%s already exists: %s", e.Resource, e.Value)
 }
 
+// PreconditionFailedError represents a conditional write against a row that
+// has changed since the client read it
+type PreconditionFailedError struct {
+	Resource string `json:"resource"`
+	ID       string `json:"id"`
+}
+
+func (e PreconditionFailedError) Error() string {
+	return fmt.Sprintf("%s was modified: %s", e.Resource, e.ID)
+}
+
+// PreconditionRequiredError represents a write sent without If-Match while
+// RequireIfMatch is enabled
+type PreconditionRequiredError struct{}
+
+func (e PreconditionRequiredError) Error() string {
+	return "If-Match header is required"
+}
+
 // HandleValidationError handles validation errors with proper HTTP status
 func HandleValidationError(ctx *gin.Context, err ValidationError) {
-	ctx.JSON(http.StatusBadRequest, gin.H{
-		"code":    "400",
-		"message": "Validation failed",
-		"error": gin.H{
-			"field":   err.Field,
-			"message": err.Message,
-		},
-		"data": nil,
-	})
+	localizer := newLocalizer(ctx)
+	fieldsJSON(ctx, localizer, []FieldError{err.fieldError(localizer)})
+}
+
+// ReferenceError represents a write pointing at a row that does not exist
+// (e.g. a student in an unknown classroom)
+type ReferenceError struct {
+	Resource  string `json:"resource"`
+	Reference string `json:"reference"`
+}
+
+func (e ReferenceError) Error() string {
+	return fmt.Sprintf("%s references a missing %s", e.Resource, e.Reference)
 }
 
 // HandleNotFoundError handles not found errors with proper HTTP status
 func HandleNotFoundError(ctx *gin.Context, err NotFoundError) {
-	ctx.JSON(http.StatusBadRequest, gin.H{
-		"code":    "400",
-		"message": "Resource not found",
-		"error": gin.H{
-			"resource": err.Resource,
-			"id":       err.ID,
-		},
-		"data": nil,
+	JSON(ctx, http.StatusNotFound, i18n.NotFound, nil, nil, map[string]string{
+		"Resource": err.Resource,
+		"ID":       err.ID,
 	})
 }
 
 // HandleConflictError handles conflict errors with proper HTTP status
 func HandleConflictError(ctx *gin.Context, err ConflictError) {
-	ctx.JSON(http.StatusConflict, gin.H{
-		"code":    "409",
-		"message": "Resource conflict",
-		"error": gin.H{
-			"resource": err.Resource,
-			"value":    err.Value,
-		},
-		"data": nil,
+	JSON(ctx, http.StatusConflict, i18n.Conflict, nil, nil, map[string]string{
+		"Resource": err.Resource,
+		"Value":    err.Value,
 	})
 }
 
+// HandleReferenceError handles dangling references with proper HTTP status
+func HandleReferenceError(ctx *gin.Context, err ReferenceError) {
+	JSON(ctx, http.StatusUnprocessableEntity, i18n.ReferenceInvalid, nil, nil, map[string]string{
+		"Resource":  err.Resource,
+		"Reference": err.Reference,
+	})
+}
+
+// HandlePreconditionFailedError handles stale conditional writes with proper HTTP status
+func HandlePreconditionFailedError(ctx *gin.Context, err PreconditionFailedError) {
+	JSON(ctx, http.StatusPreconditionFailed, i18n.PreconditionFailed, nil, nil)
+}
+
+// HandlePreconditionRequiredError handles writes missing If-Match with proper HTTP status
+func HandlePreconditionRequiredError(ctx *gin.Context, err PreconditionRequiredError) {
+	JSON(ctx, http.StatusPreconditionRequired, i18n.PreconditionRequired, nil, nil)
+}
+
 // Enhanced HandleError with custom error types
 func HandleCustomError(ctx *gin.Context, err error) {
-	switch e := err.(type) {
-	case ValidationError:
-		HandleValidationError(ctx, e)
-	case NotFoundError:
-		HandleNotFoundError(ctx, e)
-	case ConflictError:
-		HandleConflictError(ctx, e)
+	var (
+		validationErr ValidationError
+		notFoundErr   NotFoundError
+		conflictErr   ConflictError
+		referenceErr  ReferenceError
+		staleErr      PreconditionFailedError
+		missingErr    PreconditionRequiredError
+	)
+	switch {
+	case errors.As(err, &validationErr):
+		HandleValidationError(ctx, validationErr)
+	case errors.As(err, &notFoundErr):
+		HandleNotFoundError(ctx, notFoundErr)
+	case errors.As(err, &conflictErr):
+		HandleConflictError(ctx, conflictErr)
+	case errors.As(err, &referenceErr):
+		HandleReferenceError(ctx, referenceErr)
+	case errors.As(err, &staleErr):
+		HandlePreconditionFailedError(ctx, staleErr)
+	case errors.As(err, &missingErr):
+		HandlePreconditionRequiredError(ctx, missingErr)
 	default:
 		// Fallback to original HandleError
 		HandleError(ctx, err)
@@ -33,10 +33,6 @@ func NewInstant(db *bun.DB) *QueryInstant {
 	}
 }
 
-func (s *QueryInstant) APIKey(ctx context.Context, apiKey string) error {
-	return nil
-}
-
 func (s *QueryInstant) Exec(ctx context.Context, query string) error {
 	_, err := s.DB.Exec(query)
 	return err
@@ -0,0 +1,82 @@
+package base
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ETag is the strong entity tag of a row last written at updatedAt. Postgres
+// stores microseconds, so that is the precision the tag carries.
+func ETag(updatedAt time.Time) string {
+	return `"` + strconv.FormatInt(updatedAt.UnixMicro(), 36) + `"`
+}
+
+// parseETag reverses ETag. Weak tags are rejected since If-Match compares
+// strongly.
+func parseETag(tag string) (time.Time, bool) {
+	tag = strings.TrimSpace(tag)
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return time.Time{}, false
+	}
+	micro, err := strconv.ParseInt(tag[1:len(tag)-1], 36, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.UnixMicro(micro), true
+}
+
+// NotModified sets the ETag header for a row last written at updatedAt and,
+// when the request's If-None-Match already names it, answers 304 and reports
+// true so the caller can skip the body.
+func NotModified(ctx *gin.Context, updatedAt time.Time) bool {
+	etag := ETag(updatedAt)
+	ctx.Header("ETag", etag)
+
+	for _, tag := range strings.Split(ctx.GetHeader("If-None-Match"), ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			ctx.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// requireIfMatchKey holds in the gin context whether writes need If-Match.
+const requireIfMatchKey = "http_require_if_match"
+
+// RequireIfMatch sets whether conditional writes of the requests it handles
+// must carry If-Match; it is the HttpRequireIfMatch of the config.
+func RequireIfMatch(required bool) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Set(requireIfMatchKey, required)
+		ctx.Next()
+	}
+}
+
+// IfMatch returns the row version named by the request's If-Match header, to
+// be passed to the entity's conditional Update*/Delete*. It returns nil for an
+// unconditional write: no header, or "*". A tag this service could not have
+// issued never matches, so it fails the precondition outright.
+func IfMatch(ctx *gin.Context) (*time.Time, error) {
+	header := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	switch header {
+	case "":
+		if ctx.GetBool(requireIfMatchKey) {
+			return nil, PreconditionRequiredError{}
+		}
+		return nil, nil
+	case "*":
+		return nil, nil
+	}
+
+	version, ok := parseETag(header)
+	if !ok {
+		return nil, PreconditionFailedError{ID: ctx.Param("id")}
+	}
+	return &version, nil
+}
@@ -0,0 +1,91 @@
+package base
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Test_IfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	updatedAt := time.Date(2025, 3, 1, 8, 30, 0, 123456789, time.UTC)
+	version := updatedAt.Truncate(time.Microsecond)
+
+	tests := []struct {
+		name    string
+		header  string
+		require bool
+		want    *time.Time
+		wantErr error
+	}{
+		{"Test absent", "", false, nil, nil},
+		{"Test absent required", "", true, nil, PreconditionRequiredError{}},
+		{"Test any", "*", true, nil, nil},
+		{"Test tag", ETag(updatedAt), false, &version, nil},
+		{"Test weak tag", "W/" + ETag(updatedAt), false, nil, PreconditionFailedError{}},
+		{"Test garbage", `"not-a-version!"`, false, nil, PreconditionFailedError{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest(http.MethodPatch, "/", nil)
+			ctx.Request.Header.Set("If-Match", tt.header)
+			RequireIfMatch(tt.require)(ctx)
+
+			got, err := IfMatch(ctx)
+			if tt.wantErr != nil {
+				if reflect.TypeOf(err) != reflect.TypeOf(tt.wantErr) {
+					t.Fatalf("IfMatch() error = %v, want %T", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("IfMatch() error = %v", err)
+			}
+			if (got == nil) != (tt.want == nil) || got != nil && !got.Equal(*tt.want) {
+				t.Errorf("IfMatch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_NotModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	updatedAt := time.Date(2025, 3, 1, 8, 30, 0, 123456000, time.UTC)
+
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{"Test absent", "", false},
+		{"Test match", ETag(updatedAt), true},
+		{"Test weak match", "W/" + ETag(updatedAt), true},
+		{"Test list", `"other", ` + ETag(updatedAt), true},
+		{"Test stale", ETag(updatedAt.Add(-time.Second)), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			ctx.Request.Header.Set("If-None-Match", tt.header)
+
+			if got := NotModified(ctx, updatedAt); got != tt.want {
+				t.Errorf("NotModified() = %v, want %v", got, tt.want)
+			}
+			if w.Header().Get("ETag") != ETag(updatedAt) {
+				t.Errorf("ETag = %q, want %q", w.Header().Get("ETag"), ETag(updatedAt))
+			}
+			if tt.want && ctx.Writer.Status() != http.StatusNotModified {
+				t.Errorf("status = %d, want %d", ctx.Writer.Status(), http.StatusNotModified)
+			}
+		})
+	}
+}
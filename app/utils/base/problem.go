@@ -0,0 +1,81 @@
+package base
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ProblemContentType is the media type of RFC 7807 error documents.
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 error document. Errors and Data are extension
+// members carrying what the envelope would have sent.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	TraceID  string       `json:"trace_id,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+	Data     any          `json:"data,omitempty"`
+}
+
+// errorFormatKey holds the configured error format in the gin context.
+const errorFormatKey = "http_error_format"
+
+// ErrorFormat sets the format errors are sent in, "envelope" or "problem",
+// for the requests it handles; it is the HttpErrorFormat of the config.
+func ErrorFormat(format string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Set(errorFormatKey, format)
+		ctx.Next()
+	}
+}
+
+// wantsProblem reports whether errors for this request are sent as problem
+// documents: when the client asks for them in Accept, or when the configured
+// ErrorFormat is "problem".
+func wantsProblem(ctx *gin.Context) bool {
+	for _, accept := range strings.Split(ctx.GetHeader("Accept"), ",") {
+		if media, _, err := mime.ParseMediaType(strings.TrimSpace(accept)); err == nil && media == ProblemContentType {
+			return true
+		}
+	}
+	return ctx.GetString(errorFormatKey) == "problem"
+}
+
+// problemJSON writes an error as a problem document. msgID names the problem
+// type; it is empty when the detail is not a known message (a raw error).
+func problemJSON(ctx *gin.Context, code int, msgID, detail string, fields []FieldError, data any) {
+	problemType := "about:blank"
+	if msgID != "" {
+		problemType = "urn:problem-type:" + msgID
+	}
+	ctx.Header("Content-Type", ProblemContentType)
+	ctx.JSON(code, conventionalMarshallerFromPascal{Problem{
+		Type:     problemType,
+		Title:    http.StatusText(code),
+		Status:   code,
+		Detail:   detail,
+		Instance: ctx.Request.URL.Path,
+		TraceID:  traceID(ctx),
+		Errors:   fields,
+		Data:     data,
+	}})
+}
+
+// traceID matches the X-Trace-ID response header.
+func traceID(ctx *gin.Context) string {
+	if id := ctx.Writer.Header().Get("X-Trace-ID"); id != "" {
+		return id
+	}
+	if spanCtx := trace.SpanContextFromContext(ctx.Request.Context()); spanCtx.IsValid() {
+		return spanCtx.TraceID().String()
+	}
+	return ""
+}
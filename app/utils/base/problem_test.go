@@ -0,0 +1,91 @@
+package base
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/easy-attend-serviceV3/config/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+func Test_Problem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		accept      string
+		format      string
+		respond     func(ctx *gin.Context)
+		wantProblem bool
+		want        Problem
+	}{
+		{
+			name:    "Test envelope by default",
+			accept:  "application/json",
+			respond: func(ctx *gin.Context) { Unauthorized(ctx, i18n.Unauthorized, nil) },
+		},
+		{
+			name:        "Test accept header",
+			accept:      "application/json, application/problem+json;q=0.9",
+			respond:     func(ctx *gin.Context) { HandleCustomError(ctx, NotFoundError{Resource: "school", ID: "1"}) },
+			wantProblem: true,
+			want:        Problem{Type: "urn:problem-type:not-found", Title: "Not Found", Status: 404, Detail: "school not found", Instance: "/api/v1/school/1", TraceID: "trace"},
+		},
+		{
+			name:        "Test config",
+			format:      "problem",
+			respond:     func(ctx *gin.Context) { HandleError(ctx, http.ErrAbortHandler) },
+			wantProblem: true,
+			want:        Problem{Type: "about:blank", Title: "Internal Server Error", Status: 500, Detail: http.ErrAbortHandler.Error(), Instance: "/api/v1/school/1", TraceID: "trace"},
+		},
+		{
+			name:        "Test field errors",
+			accept:      ProblemContentType,
+			respond:     func(ctx *gin.Context) { InvalidField(ctx, "id", "uuid") },
+			wantProblem: true,
+			want:        Problem{Type: "urn:problem-type:validate-failed", Title: "Bad Request", Status: 400, Detail: "Validation failed", Instance: "/api/v1/school/1", TraceID: "trace"},
+		},
+		{
+			name:    "Test success stays envelope",
+			accept:  ProblemContentType,
+			respond: func(ctx *gin.Context) { Success(ctx, nil) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			_, router := gin.CreateTestContext(w)
+			router.GET("/api/v1/school/:id", ErrorFormat(tt.format), func(ctx *gin.Context) {
+				ctx.Header("X-Trace-ID", "trace")
+				tt.respond(ctx)
+			})
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/school/1", nil)
+			req.Header.Set("Accept", tt.accept)
+			router.ServeHTTP(w, req)
+
+			isProblem := w.Header().Get("Content-Type") == ProblemContentType
+			if isProblem != tt.wantProblem {
+				t.Fatalf("Content-Type = %q, want problem %v", w.Header().Get("Content-Type"), tt.wantProblem)
+			}
+			if !tt.wantProblem {
+				return
+			}
+
+			var got Problem
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if w.Code != tt.want.Status {
+				t.Errorf("status = %d, want %d", w.Code, tt.want.Status)
+			}
+			got.Errors = nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("problem = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -22,7 +22,15 @@ type ResponseErrorMessage struct {
 
 // ResponseStatus Response status
 type ResponseStatus struct {
-	Code    string `json:"code"`
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Errors  []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError One rejected request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
 	Message string `json:"message"`
 }
 
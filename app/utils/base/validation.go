@@ -0,0 +1,177 @@
+package base
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	msg "github.com/easy-attend-serviceV3/config/i18n"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+func init() {
+	// Report fields by the name the client sent rather than the Go field.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(fieldName)
+	}
+}
+
+func fieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "form", "uri"} {
+		name := strings.Split(field.Tag.Get(key), ",")[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// ValidationFailed responds 400 listing every field rejected by a failed
+// ShouldBind* call. Errors that cannot be tied to a field fall back to a plain
+// bad request.
+func ValidationFailed(ctx *gin.Context, err error) error {
+	localizer := newLocalizer(ctx)
+	fields := fieldErrors(localizer, err)
+	if len(fields) == 0 {
+		return BadRequest(ctx, msg.BadRequest, nil)
+	}
+	return fieldsJSON(ctx, localizer, fields)
+}
+
+// InvalidField responds 400 for a single field checked outside the validator,
+// such as a path parameter. param fills {{.Param}} in the rule's message.
+func InvalidField(ctx *gin.Context, field, rule string, param ...string) error {
+	localizer := newLocalizer(ctx)
+	p := ""
+	if len(param) > 0 {
+		p = param[0]
+	}
+	return fieldsJSON(ctx, localizer, []FieldError{newFieldError(localizer, field, rule, rule, p)})
+}
+
+func fieldsJSON(ctx *gin.Context, localizer *i18n.Localizer, fields []FieldError) error {
+	message := localize(localizer, msg.ValidateFailed, nil)
+	if wantsProblem(ctx) {
+		problemJSON(ctx, http.StatusBadRequest, msg.ValidateFailed, message, fields, nil)
+		return nil
+	}
+	ctx.JSON(http.StatusBadRequest, conventionalMarshallerFromPascal{Response[any]{
+		ResponseStatus: &ResponseStatus{
+			Code:    strconv.Itoa(http.StatusBadRequest),
+			Message: message,
+			Errors:  fields,
+		},
+	}})
+	return nil
+}
+
+func fieldErrors(localizer *i18n.Localizer, err error) []FieldError {
+	var (
+		verrs     validator.ValidationErrors
+		typeErr   *json.UnmarshalTypeError
+		syntaxErr *json.SyntaxError
+		custom    ValidationError
+	)
+	switch {
+	case errors.As(err, &verrs):
+		fields := make([]FieldError, 0, len(verrs))
+		for _, fe := range verrs {
+			key := fe.Tag()
+			switch key {
+			case "min", "max", "len":
+				if fe.Kind() == reflect.String || fe.Kind() == reflect.Slice {
+					key += "-length"
+				}
+			}
+			fields = append(fields, newFieldError(localizer, namespace(fe), fe.Tag(), key, fe.Param()))
+		}
+		return fields
+	case errors.As(err, &typeErr):
+		return []FieldError{newFieldError(localizer, typeErr.Field, "type", "type", jsonType(typeErr.Type))}
+	case errors.As(err, &syntaxErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return []FieldError{newFieldError(localizer, "body", "json", "json", "")}
+	case errors.As(err, &custom):
+		return []FieldError{custom.fieldError(localizer)}
+	}
+	return nil
+}
+
+// fieldError reports e like a validator rule when it names one.
+func (e ValidationError) fieldError(localizer *i18n.Localizer) FieldError {
+	if e.Rule != "" {
+		return newFieldError(localizer, e.Field, e.Rule, e.Rule, e.Param)
+	}
+	return FieldError{Field: e.Field, Rule: "invalid", Message: e.Message}
+}
+
+// newFieldError reports rule against field, with the message looked up as
+// "validation-<key>". key differs from rule where the wording depends on the
+// field kind, e.g. "min-length" for strings.
+func newFieldError(localizer *i18n.Localizer, field, rule, key, param string) FieldError {
+	data := map[string]string{"Field": field, "Param": param}
+	message, ok := tryLocalize(localizer, msg.ValidationRule+"-"+key, data)
+	if !ok {
+		message = localize(localizer, msg.ValidationInvalid, data)
+	}
+	return FieldError{Field: field, Rule: rule, Message: message}
+}
+
+// namespace drops the root struct name, leaving the client-facing path such
+// as "students[0].student_code".
+func namespace(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
+	}
+	return fe.Field()
+}
+
+func jsonType(t reflect.Type) string {
+	if t == nil {
+		return ""
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func newLocalizer(ctx *gin.Context) *i18n.Localizer {
+	return i18n.NewLocalizer(msg.Bundle, ctx.GetHeader("Accept-Language"))
+}
+
+func tryLocalize(localizer *i18n.Localizer, id string, data any) (string, bool) {
+	message, err := localizer.Localize(&i18n.LocalizeConfig{MessageID: id, TemplateData: data})
+	if err != nil || message == "" {
+		return "", false
+	}
+	return message, true
+}
+
+func localize(localizer *i18n.Localizer, id string, data any) string {
+	if message, ok := tryLocalize(localizer, id, data); ok {
+		return message
+	}
+	return id
+}
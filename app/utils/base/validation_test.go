@@ -0,0 +1,70 @@
+package base
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type validationRequest struct {
+	SchoolID string `json:"school_id" binding:"required,uuid"`
+	Name     string `json:"name" binding:"required,min=3"`
+	Age      int    `json:"age"`
+}
+
+func Test_ValidationFailed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		body     string
+		lang     string
+		want     []string // field:rule
+		wantText string
+	}{
+		{"Test rules", `{"school_id":"x","name":"ab"}`, "en", []string{"school_id:uuid", "name:min"}, "name must be at least 3 characters long"},
+		{"Test required", `{}`, "en", []string{"school_id:required", "name:required"}, "school_id is required"},
+		{"Test type", `{"school_id":"x","age":"x"}`, "en", []string{"age:type"}, "age must be a number"},
+		{"Test syntax", `{`, "en", []string{"body:json"}, "Request body must be valid JSON"},
+		{"Test thai", `{}`, "th", []string{"school_id:required", "name:required"}, "กรุณาระบุ school_id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			ctx.Request.Header.Set("Accept-Language", tt.lang)
+
+			var req validationRequest
+			err := ctx.ShouldBindJSON(&req)
+			if err == nil {
+				t.Fatal("ShouldBindJSON() succeeded, want error")
+			}
+			ValidationFailed(ctx, err)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+			}
+			var resp ResponseStatus
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, e := range resp.Errors {
+				got = append(got, e.Field+":"+e.Rule)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("errors = %v, want %v", got, tt.want)
+			}
+			if resp.Errors[0].Message != tt.wantText && resp.Errors[len(resp.Errors)-1].Message != tt.wantText {
+				t.Errorf("messages = %+v, want %q", resp.Errors, tt.wantText)
+			}
+		})
+	}
+}
@@ -0,0 +1,340 @@
+// Package oidc is the relying party side of OpenID Connect: the
+// authorization code flow with PKCE, state and nonce, and verification of ID
+// tokens against the provider's published keys.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// tenantPlaceholder stands for the tenant in the issuer of multi-tenant
+// providers, such as Microsoft's "organizations" endpoint.
+const tenantPlaceholder = "{tenantid}"
+
+// signingAlgs are the ID token algorithms Verify accepts.
+var signingAlgs = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// jwksMinRefresh limits how often an unknown key id refetches the key set.
+var jwksMinRefresh = time.Minute
+
+var (
+	ErrInvalidIDToken = errors.New("invalid id token")
+	ErrNonceMismatch  = errors.New("id token nonce does not match")
+)
+
+// Config describes a provider registered for this app. Field names follow
+// the env keys they are read from, e.g. OIDC_GOOGLE_CLIENT_ID.
+type Config struct {
+	Issuer       string
+	ClientId     string
+	ClientSecret string
+	RedirectUrl  string // page of the web app that receives the code
+	Scopes       string // space separated; "openid email profile" when empty
+}
+
+// Enabled reports whether the provider has been registered.
+func (c *Config) Enabled() bool {
+	return c != nil && c.Issuer != "" && c.ClientId != ""
+}
+
+// Discovery is the part of the provider's openid-configuration used here.
+type Discovery struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	SigningAlgs           []string `json:"id_token_signing_alg_values_supported"`
+}
+
+// IDToken holds the verified claims of an ID token.
+type IDToken struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	HostedDomain  string // Google Workspace domain of the account
+	TenantID      string // Microsoft Entra tenant of the account
+	GivenName     string
+	FamilyName    string
+	Name          string
+}
+
+// Provider talks to one OpenID provider. Discovery and keys are fetched on
+// first use and cached.
+type Provider struct {
+	conf   Config
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *Discovery
+	keys      map[string]any
+	fetchedAt time.Time
+}
+
+// NewProvider returns a provider for conf, calling it with client.
+func NewProvider(conf Config, client *http.Client) *Provider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Provider{conf: conf, client: client}
+}
+
+// NewVerifier returns a random PKCE code verifier, state or nonce.
+func NewVerifier() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate oidc verifier: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CodeChallenge is the S256 PKCE challenge of verifier.
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// Discover fetches the provider's openid-configuration, once.
+func (p *Provider) Discover(ctx context.Context) (*Discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.discover(ctx)
+}
+
+func (p *Provider) discover(ctx context.Context) (*Discovery, error) {
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+	var d Discovery
+	wellKnown := strings.TrimSuffix(p.conf.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := p.getJSON(ctx, wellKnown, &d); err != nil {
+		return nil, err
+	}
+	if strings.TrimSuffix(d.Issuer, "/") != strings.TrimSuffix(p.conf.Issuer, "/") && !strings.Contains(d.Issuer, tenantPlaceholder) {
+		return nil, fmt.Errorf("oidc discovery issuer %q does not match %q", d.Issuer, p.conf.Issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc discovery of %q is incomplete", p.conf.Issuer)
+	}
+	p.discovery = &d
+	return p.discovery, nil
+}
+
+// AuthCodeURL is where to send the user to sign in. state and nonce come
+// back in the redirect and the ID token; verifier is kept for Exchange.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	d, err := p.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	scopes := p.conf.Scopes
+	if scopes == "" {
+		scopes = "openid email profile"
+	}
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.conf.ClientId},
+		"redirect_uri":          {p.conf.RedirectUrl},
+		"scope":                 {scopes},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {CodeChallenge(verifier)},
+		"code_challenge_method": {"S256"},
+	}
+	sep := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return d.AuthorizationEndpoint + sep + q.Encode(), nil
+}
+
+// Exchange redeems an authorization code and returns the verified ID token.
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (*IDToken, error) {
+	d, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.conf.RedirectUrl},
+		"client_id":     {p.conf.ClientId},
+		"client_secret": {p.conf.ClientSecret},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var tokens struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := p.do(req, &tokens); err != nil {
+		return nil, err
+	}
+	if tokens.Error != "" {
+		return nil, fmt.Errorf("oidc token endpoint: %s: %s", tokens.Error, tokens.ErrorDescription)
+	}
+	if tokens.IDToken == "" {
+		return nil, fmt.Errorf("oidc token endpoint returned no id_token")
+	}
+	return p.Verify(ctx, tokens.IDToken, nonce)
+}
+
+// idClaims are the claims read from an ID token.
+type idClaims struct {
+	jwt.RegisteredClaims
+	Nonce         string `json:"nonce"`
+	AZP           string `json:"azp"`
+	Email         string `json:"email"`
+	EmailVerified any    `json:"email_verified"` // a bool, or the string "true" from some providers
+	PreferredName string `json:"preferred_username"`
+	HostedDomain  string `json:"hd"`
+	TenantID      string `json:"tid"`
+	GivenName     string `json:"given_name"`
+	FamilyName    string `json:"family_name"`
+	Name          string `json:"name"`
+}
+
+// Verify checks the signature of raw against the provider's keys, its
+// issuer, audience, expiry and nonce, and returns its claims.
+func (p *Provider) Verify(ctx context.Context, raw, nonce string) (*IDToken, error) {
+	d, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Only algorithms with keys in a JWKS; never "none" or HMAC
+	algs := slices.DeleteFunc(slices.Clone(d.SigningAlgs), func(alg string) bool {
+		return !slices.Contains(signingAlgs, alg)
+	})
+	if len(algs) == 0 {
+		algs = []string{"RS256"}
+	}
+
+	var claims idClaims
+	_, err = jwt.ParseWithClaims(raw, &claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.key(ctx, kid)
+	},
+		jwt.WithValidMethods(algs),
+		jwt.WithAudience(p.conf.ClientId),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIDToken, err)
+	}
+
+	// Multi-tenant issuers name the tenant the token was issued for, and
+	// Google also issues tokens with its bare host as issuer.
+	issuer := strings.ReplaceAll(d.Issuer, tenantPlaceholder, claims.TenantID)
+	if claims.Issuer != issuer && "https://"+claims.Issuer != issuer {
+		return nil, fmt.Errorf("%w: issuer %q", ErrInvalidIDToken, claims.Issuer)
+	}
+	if len(claims.Audience) > 1 && claims.AZP != p.conf.ClientId {
+		return nil, fmt.Errorf("%w: authorized party %q", ErrInvalidIDToken, claims.AZP)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: no subject", ErrInvalidIDToken)
+	}
+	if claims.Nonce != nonce {
+		return nil, ErrNonceMismatch
+	}
+
+	email := claims.Email
+	if email == "" {
+		email = claims.PreferredName
+	}
+	return &IDToken{
+		Subject:       claims.Subject,
+		Email:         strings.ToLower(email),
+		EmailVerified: claims.EmailVerified == true || claims.EmailVerified == "true",
+		HostedDomain:  claims.HostedDomain,
+		TenantID:      claims.TenantID,
+		GivenName:     claims.GivenName,
+		FamilyName:    claims.FamilyName,
+		Name:          claims.Name,
+	}, nil
+}
+
+// key returns the public key kid, refetching the key set when it is unknown
+// since providers rotate keys.
+func (p *Provider) key(ctx context.Context, kid string) (any, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+	if time.Since(p.fetchedAt) < jwksMinRefresh {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	d, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var set jwks
+	if err := p.getJSON(ctx, d.JWKSURI, &set); err != nil {
+		return nil, err
+	}
+	p.keys = set.publicKeys()
+	p.fetchedAt = time.Now()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+	// A set with a single key may leave kid out of tokens
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+func (p *Provider) getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	return p.do(req, v)
+}
+
+func (p *Provider) do(req *http.Request, v any) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	// The token endpoint answers errors with 400 and a JSON error body
+	if resp.StatusCode >= 300 && !(resp.StatusCode == http.StatusBadRequest && json.Valid(body)) {
+		return fmt.Errorf("oidc %s %s: %s", req.Method, req.URL.Redacted(), resp.Status)
+	}
+	return json.Unmarshal(body, v)
+}
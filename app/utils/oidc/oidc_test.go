@@ -0,0 +1,293 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// stubProvider is a local OpenID provider: it serves discovery, a key set
+// and a token endpoint that checks PKCE, and signs ID tokens with RS256.
+type stubProvider struct {
+	*httptest.Server
+	issuer string // issuer in discovery, may hold {tenantid}
+
+	mu    sync.Mutex
+	kid   string
+	key   *rsa.PrivateKey
+	codes map[string]stubCode
+}
+
+type stubCode struct {
+	challenge string
+	claims    jwt.MapClaims
+}
+
+func newStubProvider(t *testing.T, issuerPath string) *stubProvider {
+	t.Helper()
+	s := &stubProvider{codes: map[string]stubCode{}}
+	s.rotate(t)
+
+	mux := http.NewServeMux()
+	discovery := func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                                s.issuer,
+			"authorization_endpoint":                s.URL + "/authorize",
+			"token_endpoint":                        s.URL + "/token",
+			"jwks_uri":                              s.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256", "none"},
+		})
+	}
+	mux.HandleFunc("GET /.well-known/openid-configuration", discovery)
+	mux.HandleFunc("GET /organizations/v2.0/.well-known/openid-configuration", discovery)
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		pub := s.key.PublicKey
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": s.kid,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("POST /token", s.token)
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	s.issuer = s.URL + issuerPath
+	return s
+}
+
+// rotate replaces the signing key, as providers do from time to time.
+func (s *stubProvider) rotate(t *testing.T) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.key = key
+	s.kid = base64.RawURLEncoding.EncodeToString(key.N.Bytes()[:8])
+}
+
+// authorize plays the sign-in page: it checks the request built by
+// AuthCodeURL and returns a code for an ID token with claims.
+func (s *stubProvider) authorize(t *testing.T, authURL string, claims jwt.MapClaims) string {
+	t.Helper()
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if u.Path != "/authorize" || q.Get("response_type") != "code" || q.Get("client_id") != "client" ||
+		q.Get("state") == "" || q.Get("code_challenge_method") != "S256" {
+		t.Fatalf("unexpected authorization request %s", authURL)
+	}
+	full := jwt.MapClaims{
+		"iss":   s.issuer,
+		"aud":   "client",
+		"sub":   "subject-1",
+		"nonce": q.Get("nonce"),
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"email": "Teacher@School.ac.th",
+	}
+	for k, v := range claims {
+		if v == nil {
+			delete(full, k)
+			continue
+		}
+		full[k] = v
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	code := "code-" + q.Get("state")
+	s.codes[code] = stubCode{challenge: q.Get("code_challenge"), claims: full}
+	return code
+}
+
+func (s *stubProvider) token(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fail := func(code string) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": code})
+	}
+	if r.FormValue("client_id") != "client" || r.FormValue("client_secret") != "secret" {
+		fail("invalid_client")
+		return
+	}
+	issued, ok := s.codes[r.FormValue("code")]
+	delete(s.codes, r.FormValue("code"))
+	if !ok || r.FormValue("grant_type") != "authorization_code" ||
+		CodeChallenge(r.FormValue("code_verifier")) != issued.challenge {
+		fail("invalid_grant")
+		return
+	}
+
+	// An unknown_key claim signs with a key missing from the key set
+	key, kid := s.key, s.kid
+	if _, ok := issued.claims["unknown_key"]; ok {
+		key, _ = rsa.GenerateKey(rand.Reader, 2048)
+		kid = "unpublished"
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, issued.claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]string{"id_token": signed, "token_type": "Bearer"})
+}
+
+func (s *stubProvider) provider() *Provider {
+	return NewProvider(Config{
+		Issuer:       s.issuer,
+		ClientId:     "client",
+		ClientSecret: "secret",
+		RedirectUrl:  "https://app.example/oidc/callback",
+	}, s.Client())
+}
+
+// login runs the code flow against the stub and returns the verified token.
+func login(t *testing.T, s *stubProvider, p *Provider, claims jwt.MapClaims, verifier, nonce string) (*IDToken, error) {
+	t.Helper()
+	ctx := context.Background()
+	authURL, err := p.AuthCodeURL(ctx, "state-"+t.Name(), nonce, verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := s.authorize(t, authURL, claims)
+	return p.Exchange(ctx, code, verifier, nonce)
+}
+
+func Test_Provider(t *testing.T) {
+	s := newStubProvider(t, "")
+	p := s.provider()
+
+	verifier, err := NewVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-time.Hour).Unix()
+
+	tests := []struct {
+		name     string
+		claims   jwt.MapClaims
+		verifier string // sent at exchange; the one hashed into the challenge when empty
+		nonce    string // expected at exchange; the one sent when empty
+		wantErr  error
+	}{
+		{"Test valid login", jwt.MapClaims{"email_verified": true, "hd": "school.ac.th"}, "", "", nil},
+		{"Test wrong code verifier", nil, "other-verifier", "", errors.New("invalid_grant")},
+		{"Test nonce mismatch", nil, "", "other-nonce", ErrNonceMismatch},
+		{"Test wrong audience", jwt.MapClaims{"aud": "other-client"}, "", "", ErrInvalidIDToken},
+		{"Test wrong issuer", jwt.MapClaims{"iss": "https://evil.example"}, "", "", ErrInvalidIDToken},
+		{"Test expired", jwt.MapClaims{"iat": past - 60, "exp": past}, "", "", ErrInvalidIDToken},
+		{"Test no expiry", jwt.MapClaims{"exp": nil}, "", "", ErrInvalidIDToken},
+		{"Test no subject", jwt.MapClaims{"sub": ""}, "", "", ErrInvalidIDToken},
+		{"Test other authorized party", jwt.MapClaims{"aud": []string{"client", "other"}, "azp": "other"}, "", "", ErrInvalidIDToken},
+		{"Test unpublished key", jwt.MapClaims{"unknown_key": true}, "", "", ErrInvalidIDToken},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nonce, _ := NewVerifier()
+			ctx := context.Background()
+			authURL, err := p.AuthCodeURL(ctx, "state-"+tc.name, nonce, verifier)
+			if err != nil {
+				t.Fatal(err)
+			}
+			code := s.authorize(t, authURL, tc.claims)
+
+			sendVerifier, wantNonce := verifier, nonce
+			if tc.verifier != "" {
+				sendVerifier = tc.verifier
+			}
+			if tc.nonce != "" {
+				wantNonce = tc.nonce
+			}
+			tok, err := p.Exchange(ctx, code, sendVerifier, wantNonce)
+
+			switch {
+			case tc.wantErr == nil && err != nil:
+				t.Fatalf("unexpected error %v", err)
+			case tc.wantErr == nil:
+				if tok.Subject != "subject-1" || tok.Email != "teacher@school.ac.th" || !tok.EmailVerified || tok.HostedDomain != "school.ac.th" {
+					t.Errorf("unexpected token %+v", tok)
+				}
+			case err == nil:
+				t.Fatalf("expected error %v", tc.wantErr)
+			case !errors.Is(err, tc.wantErr) && !strings.Contains(err.Error(), tc.wantErr.Error()):
+				t.Errorf("expected error %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func Test_Provider_KeyRotation(t *testing.T) {
+	defer func(d time.Duration) { jwksMinRefresh = d }(jwksMinRefresh)
+	jwksMinRefresh = 0
+
+	s := newStubProvider(t, "")
+	p := s.provider()
+	verifier, _ := NewVerifier()
+
+	if _, err := login(t, s, p, nil, verifier, "nonce-1"); err != nil {
+		t.Fatal(err)
+	}
+	s.rotate(t)
+	if _, err := login(t, s, p, nil, verifier, "nonce-2"); err != nil {
+		t.Fatalf("token signed with the rotated key: %v", err)
+	}
+}
+
+func Test_Provider_TenantIssuer(t *testing.T) {
+	s := newStubProvider(t, "/{tenantid}/v2.0")
+	p := NewProvider(Config{
+		Issuer:       s.URL + "/organizations/v2.0",
+		ClientId:     "client",
+		ClientSecret: "secret",
+	}, s.Client())
+	verifier, _ := NewVerifier()
+
+	tests := []struct {
+		name    string
+		claims  jwt.MapClaims
+		wantErr bool
+	}{
+		{"Test issuer of the tenant", jwt.MapClaims{"iss": s.URL + "/tenant-a/v2.0", "tid": "tenant-a", "preferred_username": "t@school.ac.th"}, false},
+		{"Test issuer of another tenant", jwt.MapClaims{"iss": s.URL + "/tenant-b/v2.0", "tid": "tenant-a"}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tok, err := login(t, s, p, tc.claims, verifier, "nonce")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if err == nil && (tok.TenantID != "tenant-a" || tok.Email != "teacher@school.ac.th") {
+				t.Errorf("unexpected token %+v", tok)
+			}
+		})
+	}
+}
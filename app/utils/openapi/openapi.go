@@ -0,0 +1,345 @@
+// Package openapi builds an OpenAPI 3.1 document from the Gin route table and
+// the request/response structs bound by each controller.
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const Version = "3.1.0"
+
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Servers    []Server            `json:"servers,omitempty"`
+	Tags       []Tag               `json:"tags,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// PathItem maps a lower-case HTTP method to its operation.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	OperationID string                `json:"operationId"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Header struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+}
+
+// Spec describes what the route table cannot tell about an operation: the
+// structs bound by its controller and whether it needs a bearer token or API
+// key.
+type Spec struct {
+	Summary string
+	// Request is bound from the JSON body on POST/PUT/PATCH and from the
+	// query string otherwise.
+	Request   any
+	Response  any
+	Paginated bool
+	Public    bool
+	Hidden    bool
+	// Idempotent routes accept an Idempotency-Key header.
+	Idempotent bool
+}
+
+// Specs is keyed by "METHOD /path" relative to the base path, using Gin's
+// path syntax (":id"). The method "*" matches routes registered with Any.
+type Specs map[string]Spec
+
+const (
+	bearerAuth = "bearerAuth"
+	apiKeyAuth = "apiKeyAuth"
+)
+
+// Build generates the document for every route registered under basePath.
+// Routes without a Spec are still listed so nothing served goes undocumented.
+func Build(info Info, basePath string, routes gin.RoutesInfo, specs Specs) *Document {
+	g := newGenerator()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Servers: []Server{{URL: basePath}},
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			Schemas: g.schemas,
+			SecuritySchemes: map[string]SecurityScheme{
+				bearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+				apiKeyAuth: {Type: "apiKey", In: "header", Name: "X-API-Key"},
+			},
+		},
+	}
+	g.schemas["ResponseStatus"] = errorSchema()
+	g.schemas["ResponsePaginate"] = paginateSchema()
+	g.schemas["Problem"] = problemSchema()
+
+	sorted := append(gin.RoutesInfo(nil), routes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Path != sorted[j].Path {
+			return sorted[i].Path < sorted[j].Path
+		}
+		return sorted[i].Method < sorted[j].Method
+	})
+
+	tags := map[string]bool{}
+	for _, route := range sorted {
+		if !strings.HasPrefix(route.Path, basePath) {
+			continue
+		}
+		rel := strings.TrimPrefix(route.Path, basePath)
+		spec, ok := specs[route.Method+" "+rel]
+		if !ok {
+			spec = specs["* "+rel]
+		}
+		if spec.Hidden {
+			continue
+		}
+
+		op := g.operation(route, rel, spec)
+		for _, tag := range op.Tags {
+			tags[tag] = true
+		}
+
+		path := openAPIPath(rel)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = PathItem{}
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = op
+	}
+
+	for tag := range tags {
+		doc.Tags = append(doc.Tags, Tag{Name: tag})
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+
+	return doc
+}
+
+func (g *generator) operation(route gin.RouteInfo, rel string, spec Spec) *Operation {
+	pkg, method := handlerName(route.Handler)
+	op := &Operation{
+		Summary:     spec.Summary,
+		OperationID: route.Method + strings.NewReplacer("/", "_", ":", "", "-", "_").Replace(rel),
+		Responses:   map[string]Response{},
+	}
+	if pkg != "" {
+		op.Tags = []string{pkg}
+		op.OperationID = pkg + "." + method
+	}
+	if op.Summary == "" {
+		op.Summary = method
+	}
+
+	for _, segment := range strings.Split(rel, "/") {
+		if len(segment) < 2 || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+		param := Parameter{Name: segment[1:], In: "path", Required: true, Schema: &Schema{Type: "string"}}
+		if param.Name == "id" {
+			param.Schema.Format = "uuid"
+		}
+		op.Parameters = append(op.Parameters, param)
+	}
+
+	if spec.Request != nil {
+		switch route.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]MediaType{"application/json": {Schema: g.schemaOf(reflect.TypeOf(spec.Request))}},
+			}
+		default:
+			op.Parameters = append(op.Parameters, g.queryParameters(reflect.TypeOf(spec.Request))...)
+		}
+	}
+
+	success := &Schema{Type: "object", Properties: map[string]*Schema{
+		"code":    {Type: "string"},
+		"message": {Type: "string"},
+		"data":    {},
+	}, Required: []string{"code", "message"}}
+	if spec.Response != nil {
+		success.Properties["data"] = g.schemaOf(reflect.TypeOf(spec.Response))
+	}
+	if spec.Paginated {
+		success.Properties["data"] = &Schema{Type: "array", Items: success.Properties["data"]}
+		success.Properties["paginate"] = &Schema{Ref: ref("ResponsePaginate")}
+	}
+	op.Responses["200"] = Response{Description: "OK", Content: jsonContent(success)}
+
+	op.Responses["400"] = errorResponse(http.StatusBadRequest)
+	if !spec.Public {
+		op.Responses["401"] = errorResponse(http.StatusUnauthorized)
+		op.Security = []map[string][]string{{bearerAuth: {}}, {apiKeyAuth: {}}}
+	}
+	byID := len(op.Parameters) > 0 && op.Parameters[0].In == "path"
+	if byID {
+		op.Responses["404"] = errorResponse(http.StatusNotFound)
+	}
+	switch route.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		op.Responses["409"] = errorResponse(http.StatusConflict)
+		op.Responses["422"] = errorResponse(http.StatusUnprocessableEntity)
+	case http.MethodDelete:
+		op.Responses["409"] = errorResponse(http.StatusConflict)
+	}
+	if byID {
+		// Single rows carry an ETag for conditional requests.
+		switch route.Method {
+		case http.MethodGet:
+			op.Parameters = append(op.Parameters, Parameter{Name: "If-None-Match", In: "header", Schema: &Schema{Type: "string"}})
+			ok := op.Responses["200"]
+			ok.Headers = map[string]Header{"ETag": {Schema: &Schema{Type: "string"}}}
+			op.Responses["200"] = ok
+			op.Responses["304"] = Response{Description: http.StatusText(http.StatusNotModified)}
+		case http.MethodPatch, http.MethodPut, http.MethodDelete:
+			op.Parameters = append(op.Parameters, Parameter{Name: "If-Match", In: "header", Schema: &Schema{Type: "string"}})
+			op.Responses["412"] = errorResponse(http.StatusPreconditionFailed)
+			op.Responses["428"] = errorResponse(http.StatusPreconditionRequired)
+		}
+	}
+	if spec.Idempotent {
+		maxLength := 255
+		op.Parameters = append(op.Parameters, Parameter{Name: "Idempotency-Key", In: "header", Schema: &Schema{Type: "string", MaxLength: &maxLength}})
+		ok := op.Responses["200"]
+		ok.Headers = map[string]Header{"Idempotent-Replayed": {Schema: &Schema{Type: "boolean"}}}
+		op.Responses["200"] = ok
+	}
+	op.Responses["500"] = errorResponse(http.StatusInternalServerError)
+
+	return op
+}
+
+// handlerName splits a Gin handler name such as
+// "github.com/x/app/modules/teacher.(*Controller).Login-fm" into its package
+// ("teacher") and method ("Login").
+func handlerName(handler string) (string, string) {
+	handler = strings.TrimSuffix(handler, "-fm")
+	slash := strings.LastIndex(handler, "/")
+	name := handler[slash+1:]
+	dot := strings.Index(name, ".")
+	if dot < 0 {
+		return "", name
+	}
+	pkg := name[:dot]
+	method := name[strings.LastIndex(name, ".")+1:]
+	return pkg, method
+}
+
+// openAPIPath converts Gin path parameters (":id", "*path") to "{id}".
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if len(segment) > 1 && (segment[0] == ':' || segment[0] == '*') {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+func errorResponse(status int) Response {
+	content := jsonContent(&Schema{Ref: ref("ResponseStatus")})
+	content["application/problem+json"] = MediaType{Schema: &Schema{Ref: ref("Problem")}}
+	return Response{Description: http.StatusText(status), Content: content}
+}
+
+func errorSchema() *Schema {
+	return &Schema{Type: "object", Properties: map[string]*Schema{
+		"code":    {Type: "string"},
+		"message": {Type: "string"},
+		"data":    {Type: "null"},
+		"errors": {Type: "array", Items: &Schema{Type: "object", Properties: map[string]*Schema{
+			"field":   {Type: "string"},
+			"rule":    {Type: "string"},
+			"message": {Type: "string"},
+		}}},
+	}, Required: []string{"code", "message"}}
+}
+
+// problemSchema describes the RFC 7807 documents sent instead of
+// ResponseStatus when the client accepts application/problem+json.
+func problemSchema() *Schema {
+	schema := errorSchema()
+	return &Schema{Type: "object", Properties: map[string]*Schema{
+		"type":     {Type: "string", Format: "uri"},
+		"title":    {Type: "string"},
+		"status":   {Type: "integer", Format: "int64"},
+		"detail":   {Type: "string"},
+		"instance": {Type: "string", Format: "uri-reference"},
+		"trace_id": {Type: "string"},
+		"errors":   schema.Properties["errors"],
+	}, Required: []string{"type", "title", "status"}}
+}
+
+func paginateSchema() *Schema {
+	return &Schema{Type: "object", Properties: map[string]*Schema{
+		"page":  {Type: "integer", Format: "int64"},
+		"size":  {Type: "integer", Format: "int64"},
+		"total": {Type: "integer", Format: "int64"},
+	}}
+}
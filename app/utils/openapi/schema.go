@@ -0,0 +1,263 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 any                `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+}
+
+var (
+	uuidType    = reflect.TypeOf(uuid.UUID{})
+	timeType    = reflect.TypeOf(time.Time{})
+	rawJSONType = reflect.TypeOf(json.RawMessage{})
+	textType    = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+type generator struct {
+	schemas map[string]*Schema
+}
+
+func newGenerator() *generator {
+	return &generator{schemas: map[string]*Schema{}}
+}
+
+func ref(name string) string {
+	return "#/components/schemas/" + name
+}
+
+// schemaOf returns the schema for t. Named structs are registered as
+// components and returned as references.
+func (g *generator) schemaOf(t reflect.Type) *Schema {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		nullable = true
+	}
+
+	var schema *Schema
+	switch {
+	case t == uuidType:
+		schema = &Schema{Type: "string", Format: "uuid"}
+	case t == timeType:
+		schema = &Schema{Type: "string", Format: "date-time"}
+	case t == rawJSONType:
+		return &Schema{}
+	case t.Implements(textType):
+		// IDs such as ULIDs encode as text, not as their underlying array.
+		schema = &Schema{Type: "string"}
+	default:
+		switch t.Kind() {
+		case reflect.String:
+			schema = &Schema{Type: "string"}
+		case reflect.Bool:
+			schema = &Schema{Type: "boolean"}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			schema = &Schema{Type: "integer", Format: "int64"}
+		case reflect.Float32, reflect.Float64:
+			schema = &Schema{Type: "number"}
+		case reflect.Slice, reflect.Array:
+			schema = &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+		case reflect.Map:
+			schema = &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+		case reflect.Struct:
+			if t.Name() == "" {
+				schema = g.structSchema(t)
+				break
+			}
+			name := componentName(t)
+			if _, ok := g.schemas[name]; !ok {
+				// Register before filling so recursive types terminate.
+				g.schemas[name] = &Schema{}
+				*g.schemas[name] = *g.structSchema(t)
+			}
+			schema = &Schema{Ref: ref(name)}
+		default:
+			return &Schema{}
+		}
+	}
+
+	if nullable && schema.Ref == "" {
+		schema.Type = []string{schema.Type.(string), "null"}
+	}
+	return schema
+}
+
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	g.eachField(t, func(name string, field reflect.StructField, rules []string) {
+		prop := g.schemaOf(field.Type)
+		if applyRules(prop, rules) {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = prop
+	})
+	return schema
+}
+
+// queryParameters lists the fields of t as query string parameters.
+func (g *generator) queryParameters(t reflect.Type) []Parameter {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var params []Parameter
+	g.eachField(t, func(name string, field reflect.StructField, rules []string) {
+		if tag := field.Tag.Get("form"); tag != "" {
+			name = strings.Split(tag, ",")[0]
+		}
+		// Absent query parameters are simply omitted, never null.
+		ft := field.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		schema := g.schemaOf(ft)
+		if schema.Ref != "" {
+			return
+		}
+		params = append(params, Parameter{
+			Name:     name,
+			In:       "query",
+			Required: applyRules(schema, rules),
+			Schema:   schema,
+		})
+	})
+	return params
+}
+
+// eachField walks the JSON-visible fields of t, flattening embedded structs
+// the same way encoding/json does.
+func (g *generator) eachField(t reflect.Type, fn func(name string, field reflect.StructField, rules []string)) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.eachField(embedded, fn)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = snakeCase(field.Name)
+		}
+
+		var rules []string
+		if binding := field.Tag.Get("binding"); binding != "" {
+			rules = strings.Split(binding, ",")
+		}
+		fn(name, field, rules)
+	}
+}
+
+// applyRules maps validator binding rules onto schema keywords and reports
+// whether the field is required.
+func applyRules(schema *Schema, rules []string) bool {
+	required := false
+	for _, rule := range rules {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "required":
+			required = true
+		case "email", "uuid", "uri", "date", "date-time":
+			schema.Format = key
+		case "url":
+			schema.Format = "uri"
+		case "oneof":
+			for _, v := range strings.Fields(value) {
+				schema.Enum = append(schema.Enum, v)
+			}
+		case "min", "max", "len", "gte", "lte":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			if isType(schema, "string") {
+				length := int(n)
+				if key != "max" && key != "lte" {
+					schema.MinLength = &length
+				}
+				if key == "max" || key == "lte" || key == "len" {
+					schema.MaxLength = &length
+				}
+			} else if isType(schema, "integer") || isType(schema, "number") {
+				if key != "max" && key != "lte" {
+					schema.Minimum = &n
+				}
+				if key == "max" || key == "lte" || key == "len" {
+					schema.Maximum = &n
+				}
+			}
+		}
+	}
+	return required
+}
+
+func isType(schema *Schema, name string) bool {
+	switch t := schema.Type.(type) {
+	case string:
+		return t == name
+	case []string:
+		return len(t) > 0 && t[0] == name
+	}
+	return false
+}
+
+// componentName qualifies a type with its package so that identically named
+// controller structs ("CreateControllerRequest") stay distinct.
+func componentName(t reflect.Type) string {
+	return path.Base(t.PkgPath()) + "." + t.Name()
+}
+
+// snakeCase mirrors the response marshaller, which rewrites untagged Go
+// field names to snake_case.
+func snakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,37 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// SwaggerUI serves the Swagger UI bundled into the binary, so the docs page
+// works without reaching a CDN. The stock initializer is replaced with one
+// pointing at specURL.
+func SwaggerUI(specURL string) http.Handler {
+	initializer := fmt.Sprintf(`window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: %q,
+    dom_id: '#swagger-ui',
+    deepLinking: true,
+    persistAuthorization: true,
+    presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+    plugins: [SwaggerUIBundle.plugins.DownloadUrl],
+    layout: "StandaloneLayout"
+  });
+};
+`, specURL)
+	files := http.FileServer(http.FS(swaggerFiles.FS))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.TrimPrefix(r.URL.Path, "/") == "swagger-initializer.js" {
+			w.Header().Set("Content-Type", "application/javascript; charset=utf-8")
+			_, _ = w.Write([]byte(initializer))
+			return
+		}
+		files.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,39 @@
+// Package tenant carries the school a request acts for. The entities layer
+// reads it to scope every query on school-owned tables.
+package tenant
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type ctxKey struct{}
+
+type scope struct {
+	schoolID uuid.UUID
+	bypass   bool
+}
+
+// WithSchool limits queries made with the returned context to schoolID. A
+// uuid.Nil school matches no rows.
+func WithSchool(ctx context.Context, schoolID uuid.UUID) context.Context {
+	return context.WithValue(ctx, ctxKey{}, scope{schoolID: schoolID})
+}
+
+// Bypass lifts the school scope for queries made with the returned context.
+// It is for super admins only.
+func Bypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxKey{}, scope{bypass: true})
+}
+
+// School returns the school queries made with ctx are limited to. scoped is
+// false for a bypassed context and for one that never carried a tenant
+// (unauthenticated routes, console commands).
+func School(ctx context.Context) (schoolID uuid.UUID, scoped bool) {
+	s, ok := ctx.Value(ctxKey{}).(scope)
+	if !ok || s.bypass {
+		return uuid.Nil, false
+	}
+	return s.schoolID, true
+}
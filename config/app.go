@@ -3,7 +3,11 @@ package config
 import (
 	"github.com/easy-attend-serviceV3/app/modules/example"
 	exampletwo "github.com/easy-attend-serviceV3/app/modules/example-two"
+	"github.com/easy-attend-serviceV3/app/modules/idempotency"
+	"github.com/easy-attend-serviceV3/app/modules/lockout"
+	"github.com/easy-attend-serviceV3/app/utils/oidc"
 	"github.com/easy-attend-serviceV3/internal/log"
+	"github.com/easy-attend-serviceV3/internal/mailer"
 	"github.com/easy-attend-serviceV3/internal/otel/collector"
 )
 
@@ -12,22 +16,50 @@ type JWTConfig struct {
 	SecretKey            string
 	AccessTokenExpiry    int // in hours
 	RefreshTokenExpiry   int // in hours
+	SessionCacheTTL      int // in seconds a session check is cached by the auth middleware
+	Algorithm            string // HS256 (SecretKey), RS256 or EdDSA (keys in KeyDir)
+	KeyDir               string // PKCS#8 PEM private keys named <kid>.pem
+	KeyID                string // kid that signs; empty picks the last in KeyDir
 	Issuer              string
 }
 
+// PasswordConfig contains the password policy and hashing configuration
+type PasswordConfig struct {
+	MinLength     int
+	MaxLength     int
+	CheckBreached bool   // refuse passwords on the embedded list of breached passwords
+	BreachedFile  string // more breached passwords, one per line
+	Memory        int    // Argon2id memory in KiB; hashes made with other parameters are redone at login
+	Iterations    int
+	Parallelism   int
+}
+
+// OidcConfig contains the OpenID Connect providers teachers can sign in
+// with. A provider is offered once its ClientId is set.
+type OidcConfig struct {
+	Google    oidc.Config
+	Microsoft oidc.Config
+	StateTTL  int // in minutes a sign-in at the provider may take
+}
+
 // Config is a struct that contains all the configuration of the application.
 type Config struct {
 	Database Database
 	JWT      JWTConfig
+	Password PasswordConfig
 
 	AppName      string
+	AppURL       string // base URL of the web app, for links in emails
 	AppKey       string
 	AppEnv       string
 	AppEnvPrefix string
 	Debug        bool
 
-	Port           int
-	HttpJsonNaming string
+	Port            int
+	HttpJsonNaming  string
+	HttpErrorFormat string // "envelope" or "problem" (RFC 7807)
+
+	HttpRequireIfMatch bool // reject PATCH/DELETE without If-Match (428)
 
 	SslCaPath      string
 	SslPrivatePath string
@@ -38,33 +70,105 @@ type Config struct {
 	// Kafka dto.Kafka
 	Log log.Option
 
+	Idempotency idempotency.Config
+
+	Lockout lockout.Config
+
+	Mail             mailer.Config
+	PasswordResetTTL int // in minutes
+	EmailVerifyTTL   int // in hours
+	InvitationTTL    int // in hours
+	JoinCodeTTL      int // in days
+
+	MfaIssuer       string // name authenticator apps show for the account
+	MfaChallengeTTL int    // in minutes
+
+	StudentClassroomSync int // in minutes between syncs of students.classroom_id, 0 to turn them off
+
+	Oidc OidcConfig
+
 	Example example.Config
 
 	ExampleTwo exampletwo.Config
 }
 
+// DefaultJWTSecretKey is the HS256 secret App starts with, refused outside
+// local development as it is public.
+const DefaultJWTSecretKey = "your-super-secret-jwt-key-change-this-in-production"
+
 var App = Config{
 	Database: database,
 	// Kafka:    kafka,
 	JWT: JWTConfig{
-		SecretKey:            "your-super-secret-jwt-key-change-this-in-production",
+		SecretKey:            DefaultJWTSecretKey,
 		AccessTokenExpiry:    24,   // 24 hours
 		RefreshTokenExpiry:   168,  // 7 days (24 * 7)
+		SessionCacheTTL:      30,   // 30 seconds
+		Algorithm:            "HS256",
+		KeyDir:               "storage/jwt",
 		Issuer:              "easy-attend-service",
 	},
 
+	Password: PasswordConfig{
+		MinLength:     8,
+		MaxLength:     128,
+		CheckBreached: true,
+		Memory:        64 * 1024, // 64 MB
+		Iterations:    3,
+		Parallelism:   2,
+	},
+
 	AppName: "go_app",
+	AppURL:  "http://localhost:3000",
 	Port:    8080,
 	AppKey:  "secret",
 	AppEnv:  "development",
 	Debug:   false,
 
-	HttpJsonNaming: "snake_case",
+	HttpJsonNaming:  "snake_case",
+	HttpErrorFormat: "envelope",
+
+	HttpRequireIfMatch: false,
 
 	SslCaPath:      "mcop/cert/ca.pem",
 	SslPrivatePath: "mcop/cert/server.pem",
 	SslCertPath:    "mcop/cert/server-key.pem",
 
+	Idempotency: idempotency.Config{
+		TTL: 24, // 24 hours
+	},
+
+	Lockout: lockout.Config{
+		MaxAttempts:   5,
+		IpMaxAttempts: 20,
+		Window:        15, // 15 minutes
+		BaseDelay:     30, // 30 seconds, then 1, 2, 4 ... minutes
+		MaxDelay:      60, // 1 hour
+		Decay:         24, // 24 hours
+	},
+
+	Mail: mailer.Config{
+		Driver: "log",
+		From:   "Easy Attend <no-reply@easy-attend.local>",
+		Port:   587,
+		Dir:    "storage/mail",
+	},
+	PasswordResetTTL: 30,  // 30 minutes
+	EmailVerifyTTL:   48,  // 2 days
+	InvitationTTL:    168, // 7 days
+	JoinCodeTTL:      30,  // 30 days
+
+	MfaIssuer:       "Easy Attend",
+	MfaChallengeTTL: 5, // 5 minutes
+
+	StudentClassroomSync: 60, // 1 hour
+
+	Oidc: OidcConfig{
+		Google:    oidc.Config{Issuer: "https://accounts.google.com"},
+		Microsoft: oidc.Config{Issuer: "https://login.microsoftonline.com/organizations/v2.0"},
+		StateTTL:  10, // 10 minutes
+	},
+
 	Otel: collector.Config{
 		CollectorEndpoint: "",
 		LogMode:           "noop",
@@ -6,7 +6,43 @@ var (
 	BadRequest          = "bad-request"
 	Unauthorized        = "unauthorized"
 	Forbidden           = "forbidden"
-	ValidateFailed      = "validate-failed"
+	TokenRequired       = "token-required"
+	TokenMalformed      = "token-malformed"
+	TokenInvalid        = "token-invalid"
+
+	RefreshTokenInvalid = "refresh-token-invalid"
+	SessionRevoked      = "session-revoked"
+	APIKeyInvalid       = "api-key-invalid"
+	TooManyAttempts     = "too-many-attempts"
+	MFACodeInvalid      = "mfa-code-invalid"
+	MFAChallengeInvalid = "mfa-challenge-invalid"
+	MFARequired         = "mfa-required"
+	OIDCStateInvalid    = "oidc-state-invalid"
+	OIDCLoginFailed     = "oidc-login-failed"
+	OIDCDomainDenied    = "oidc-domain-denied"
+	OIDCNoAccount       = "oidc-no-account"
+
+	PasswordResetSent = "password-reset-sent"
+	LinkTokenInvalid  = "link-token-invalid"
+	InvitationInvalid = "invitation-invalid"
+	InvitationEmail   = "invitation-email"
+	JoinCodeInvalid   = "join-code-invalid"
+	ValidateFailed    = "validate-failed"
+	NotFound          = "not-found"
+	Conflict          = "conflict"
+	ReferenceInvalid  = "reference-invalid"
+	BulkInvalid       = "bulk-invalid"
+
+	PreconditionFailed   = "precondition-failed"
+	PreconditionRequired = "precondition-required"
+
+	IdempotencyKeyReused   = "idempotency-key-reused"
+	IdempotencyKeyInFlight = "idempotency-key-in-flight"
+
+	// Field errors are looked up as ValidationRule + "-" + rule, e.g.
+	// "validation-required", falling back to ValidationInvalid.
+	ValidationRule    = "validation"
+	ValidationInvalid = "validation-invalid"
 
 	ExampleMessageOK = "example-message-ok"
 )
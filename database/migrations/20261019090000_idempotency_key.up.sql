@@ -0,0 +1,24 @@
+CREATE TABLE idempotency_keys (
+    key          VARCHAR(320) NOT NULL,
+    fingerprint  CHAR(64)     NOT NULL,
+    status       SMALLINT     NOT NULL DEFAULT 0,
+    content_type VARCHAR(255) NULL,
+    body         BYTEA        NULL,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at   TIMESTAMPTZ  NOT NULL,
+    PRIMARY KEY (key)
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
+
+-- Add table comment
+COMMENT ON TABLE idempotency_keys IS 'ผลลัพธ์ของคำขอ POST ที่ส่งพร้อม Idempotency-Key';
+
+-- Add column comments
+COMMENT ON COLUMN idempotency_keys.key IS 'ผู้ใช้และ Idempotency-Key';
+COMMENT ON COLUMN idempotency_keys.fingerprint IS 'SHA-256 ของ method, path และ body';
+COMMENT ON COLUMN idempotency_keys.status IS 'HTTP status ของคำตอบ (0 = กำลังประมวลผล)';
+COMMENT ON COLUMN idempotency_keys.content_type IS 'Content-Type ของคำตอบ';
+COMMENT ON COLUMN idempotency_keys.body IS 'เนื้อหาคำตอบ';
+COMMENT ON COLUMN idempotency_keys.created_at IS 'วันที่สร้าง';
+COMMENT ON COLUMN idempotency_keys.expires_at IS 'วันที่หมดอายุ';
@@ -0,0 +1,30 @@
+CREATE TABLE refresh_tokens (
+    id         UUID        NOT NULL DEFAULT gen_random_uuid(),
+    family_id  UUID        NOT NULL,
+    parent_id  UUID        NULL,
+    teacher_id UUID        NOT NULL,
+    token_hash CHAR(64)    NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at    TIMESTAMPTZ NULL,
+    revoked_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    FOREIGN KEY (teacher_id) REFERENCES teachers(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX uq_refresh_tokens_token_hash ON refresh_tokens (token_hash);
+CREATE INDEX refresh_tokens_family_id_idx ON refresh_tokens (family_id);
+CREATE INDEX refresh_tokens_teacher_id_idx ON refresh_tokens (teacher_id);
+
+-- Add table comment
+COMMENT ON TABLE refresh_tokens IS 'refresh token ที่ออกให้ครู (เก็บเป็น hash)';
+
+-- Add column comments
+COMMENT ON COLUMN refresh_tokens.family_id IS 'กลุ่มของ token ที่หมุนมาจากการเข้าสู่ระบบครั้งเดียวกัน';
+COMMENT ON COLUMN refresh_tokens.parent_id IS 'token ก่อนหน้าที่ถูกหมุน';
+COMMENT ON COLUMN refresh_tokens.teacher_id IS 'รหัสครู';
+COMMENT ON COLUMN refresh_tokens.token_hash IS 'SHA-256 ของ token';
+COMMENT ON COLUMN refresh_tokens.expires_at IS 'วันที่หมดอายุ';
+COMMENT ON COLUMN refresh_tokens.used_at IS 'วันที่ถูกใช้หมุน token';
+COMMENT ON COLUMN refresh_tokens.revoked_at IS 'วันที่ถูกเพิกถอน';
+COMMENT ON COLUMN refresh_tokens.created_at IS 'วันที่สร้าง';
@@ -0,0 +1,38 @@
+CREATE TABLE teacher_sessions (
+    id           UUID         NOT NULL DEFAULT gen_random_uuid(),
+    teacher_id   UUID         NOT NULL,
+    device       VARCHAR(100) NULL,
+    ip           VARCHAR(45)  NULL,
+    user_agent   VARCHAR(512) NULL,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at   TIMESTAMPTZ  NOT NULL,
+    revoked_at   TIMESTAMPTZ  NULL,
+    PRIMARY KEY (id),
+    FOREIGN KEY (teacher_id) REFERENCES teachers(id) ON DELETE CASCADE
+);
+
+CREATE INDEX teacher_sessions_teacher_id_idx ON teacher_sessions (teacher_id);
+
+-- Every existing refresh token family becomes a session
+INSERT INTO teacher_sessions (id, teacher_id, created_at, last_seen_at, expires_at, revoked_at)
+SELECT family_id, (ARRAY_AGG(teacher_id))[1], MIN(created_at), MAX(created_at), MAX(expires_at), MAX(revoked_at)
+FROM refresh_tokens
+GROUP BY family_id;
+
+ALTER TABLE refresh_tokens
+    ADD CONSTRAINT refresh_tokens_family_id_fkey
+    FOREIGN KEY (family_id) REFERENCES teacher_sessions(id) ON DELETE CASCADE;
+
+-- Add table comment
+COMMENT ON TABLE teacher_sessions IS 'เซสชันการเข้าสู่ระบบของครู';
+
+-- Add column comments
+COMMENT ON COLUMN teacher_sessions.teacher_id IS 'รหัสครู';
+COMMENT ON COLUMN teacher_sessions.device IS 'ชื่ออุปกรณ์';
+COMMENT ON COLUMN teacher_sessions.ip IS 'IP ที่ใช้เข้าสู่ระบบ';
+COMMENT ON COLUMN teacher_sessions.user_agent IS 'User-Agent ที่ใช้เข้าสู่ระบบ';
+COMMENT ON COLUMN teacher_sessions.created_at IS 'วันที่เข้าสู่ระบบ';
+COMMENT ON COLUMN teacher_sessions.last_seen_at IS 'วันที่ใช้งานล่าสุด';
+COMMENT ON COLUMN teacher_sessions.expires_at IS 'วันที่หมดอายุ';
+COMMENT ON COLUMN teacher_sessions.revoked_at IS 'วันที่ออกจากระบบ';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS teacher_tokens;
+
+ALTER TABLE teachers DROP COLUMN IF EXISTS email_verified_at;
\ No newline at end of file
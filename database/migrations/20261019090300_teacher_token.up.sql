@@ -0,0 +1,29 @@
+ALTER TABLE teachers ADD COLUMN email_verified_at TIMESTAMPTZ NULL;
+
+COMMENT ON COLUMN teachers.email_verified_at IS 'วันที่ยืนยันอีเมล';
+
+CREATE TABLE teacher_tokens (
+    id         UUID        NOT NULL DEFAULT gen_random_uuid(),
+    teacher_id UUID        NOT NULL,
+    purpose    VARCHAR(32) NOT NULL,
+    token_hash CHAR(64)    NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at    TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    FOREIGN KEY (teacher_id) REFERENCES teachers(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX uq_teacher_tokens_token_hash ON teacher_tokens (token_hash);
+CREATE INDEX teacher_tokens_teacher_id_purpose_idx ON teacher_tokens (teacher_id, purpose);
+
+-- Add table comment
+COMMENT ON TABLE teacher_tokens IS 'token ใช้ครั้งเดียวที่ส่งทางอีเมล (รีเซ็ตรหัสผ่าน, ยืนยันอีเมล)';
+
+-- Add column comments
+COMMENT ON COLUMN teacher_tokens.teacher_id IS 'รหัสครู';
+COMMENT ON COLUMN teacher_tokens.purpose IS 'วัตถุประสงค์ (password_reset, email_verify)';
+COMMENT ON COLUMN teacher_tokens.token_hash IS 'SHA-256 ของ token';
+COMMENT ON COLUMN teacher_tokens.expires_at IS 'วันที่หมดอายุ';
+COMMENT ON COLUMN teacher_tokens.used_at IS 'วันที่ใช้ token';
+COMMENT ON COLUMN teacher_tokens.created_at IS 'วันที่สร้าง';
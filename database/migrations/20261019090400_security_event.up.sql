@@ -0,0 +1,24 @@
+CREATE TABLE security_events (
+    id         UUID         NOT NULL DEFAULT gen_random_uuid(),
+    event      VARCHAR(32)  NOT NULL,
+    subject    VARCHAR(320) NOT NULL,
+    actor_id   UUID         NULL,
+    ip         VARCHAR(45)  NULL,
+    detail     JSONB        NULL,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    FOREIGN KEY (actor_id) REFERENCES teachers(id) ON DELETE SET NULL
+);
+
+CREATE INDEX security_events_subject_created_at_idx ON security_events (subject, created_at);
+
+-- Add table comment
+COMMENT ON TABLE security_events IS 'บันทึกเหตุการณ์ด้านความปลอดภัย (ล็อกบัญชี, ปลดล็อก)';
+
+-- Add column comments
+COMMENT ON COLUMN security_events.event IS 'ประเภทเหตุการณ์ (lockout, unlock)';
+COMMENT ON COLUMN security_events.subject IS 'สิ่งที่เกี่ยวข้อง เช่น email:<อีเมล> หรือ ip:<ที่อยู่ IP>';
+COMMENT ON COLUMN security_events.actor_id IS 'รหัสครูผู้ดำเนินการ';
+COMMENT ON COLUMN security_events.ip IS 'IP ของผู้ร้องขอ';
+COMMENT ON COLUMN security_events.detail IS 'รายละเอียดเพิ่มเติม';
+COMMENT ON COLUMN security_events.created_at IS 'วันที่เกิดเหตุการณ์';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS teacher_roles;
+
+DROP TABLE IF EXISTS roles;
\ No newline at end of file
@@ -0,0 +1,51 @@
+CREATE TABLE roles (
+    id          UUID         NOT NULL DEFAULT gen_random_uuid(),
+    name        VARCHAR(50)  NOT NULL,
+    description VARCHAR(255) NULL,
+    permissions TEXT[]       NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX uq_roles_name ON roles (name);
+
+CREATE TABLE teacher_roles (
+    teacher_id UUID        NOT NULL,
+    role_id    UUID        NOT NULL,
+    granted_by UUID        NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (teacher_id, role_id),
+    FOREIGN KEY (teacher_id) REFERENCES teachers(id) ON DELETE CASCADE,
+    FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE,
+    FOREIGN KEY (granted_by) REFERENCES teachers(id) ON DELETE SET NULL
+);
+
+CREATE INDEX teacher_roles_role_id_idx ON teacher_roles (role_id);
+
+INSERT INTO roles (name, description, permissions) VALUES
+    ('super_admin', 'ผู้ดูแลระบบทั้งหมด', '{*}'),
+    ('school_admin', 'ผู้ดูแลโรงเรียน', '{school:read,school:write,classroom:*,student:*,attendance:*,teacher:*,role:assign,account:unlock}'),
+    ('teacher', 'ครู', '{school:read,classroom:read,classroom:write,student:read,student:write,attendance:read,attendance:write,teacher:read}'),
+    ('staff', 'เจ้าหน้าที่ (อ่านอย่างเดียว)', '{school:read,classroom:read,student:read,attendance:read,teacher:read}');
+
+-- Every existing teacher keeps what they could do before with the teacher
+-- role. Nobody is made super_admin here: the operator appoints the first
+-- with `console role-grant --email <email>`.
+INSERT INTO teacher_roles (teacher_id, role_id)
+SELECT t.id, r.id FROM teachers t JOIN roles r ON r.name = 'teacher';
+
+-- Add table comment
+COMMENT ON TABLE roles IS 'บทบาทและสิทธิ์การใช้งาน';
+COMMENT ON TABLE teacher_roles IS 'บทบาทของครู';
+
+-- Add column comments
+COMMENT ON COLUMN roles.name IS 'ชื่อบทบาท';
+COMMENT ON COLUMN roles.description IS 'คำอธิบาย';
+COMMENT ON COLUMN roles.permissions IS 'สิทธิ์ในรูปแบบ <resource>:<action>, <resource>:* หรือ *';
+COMMENT ON COLUMN roles.created_at IS 'วันที่สร้าง';
+COMMENT ON COLUMN roles.updated_at IS 'วันที่แก้ไขล่าสุด';
+COMMENT ON COLUMN teacher_roles.teacher_id IS 'รหัสครู';
+COMMENT ON COLUMN teacher_roles.role_id IS 'รหัสบทบาท';
+COMMENT ON COLUMN teacher_roles.granted_by IS 'รหัสครูผู้มอบบทบาท';
+COMMENT ON COLUMN teacher_roles.created_at IS 'วันที่มอบบทบาท';
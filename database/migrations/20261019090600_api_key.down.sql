@@ -0,0 +1,3 @@
+UPDATE roles SET permissions = array_remove(permissions, 'api_key:manage') WHERE name = 'school_admin';
+
+DROP TABLE IF EXISTS api_keys;
\ No newline at end of file
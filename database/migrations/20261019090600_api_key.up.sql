@@ -0,0 +1,38 @@
+CREATE TABLE api_keys (
+    id           UUID         NOT NULL DEFAULT gen_random_uuid(),
+    school_id    UUID         NOT NULL,
+    name         VARCHAR(100) NOT NULL,
+    prefix       VARCHAR(16)  NOT NULL,
+    key_hash     CHAR(64)     NOT NULL,
+    scopes       TEXT[]       NOT NULL DEFAULT '{}',
+    created_by   UUID         NULL,
+    last_used_at TIMESTAMPTZ  NULL,
+    expires_at   TIMESTAMPTZ  NULL,
+    revoked_at   TIMESTAMPTZ  NULL,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    FOREIGN KEY (school_id) REFERENCES schools(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES teachers(id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX uq_api_keys_key_hash ON api_keys (key_hash);
+CREATE INDEX api_keys_school_id_idx ON api_keys (school_id);
+
+UPDATE roles SET permissions = array_append(permissions, 'api_key:manage') WHERE name = 'school_admin';
+
+-- Add table comment
+COMMENT ON TABLE api_keys IS 'คีย์ API สำหรับระบบภายนอกของโรงเรียน';
+
+-- Add column comments
+COMMENT ON COLUMN api_keys.school_id IS 'รหัสโรงเรียนที่คีย์ใช้งานได้';
+COMMENT ON COLUMN api_keys.name IS 'ชื่อคีย์ เช่น ระบบทะเบียน, เครื่องสแกนประตู';
+COMMENT ON COLUMN api_keys.prefix IS 'ส่วนต้นของคีย์ สำหรับแยกแยะคีย์';
+COMMENT ON COLUMN api_keys.key_hash IS 'ค่าแฮช SHA-256 ของคีย์';
+COMMENT ON COLUMN api_keys.scopes IS 'สิทธิ์ของคีย์';
+COMMENT ON COLUMN api_keys.created_by IS 'รหัสครูผู้สร้างคีย์';
+COMMENT ON COLUMN api_keys.last_used_at IS 'วันที่ใช้งานล่าสุด';
+COMMENT ON COLUMN api_keys.expires_at IS 'วันหมดอายุ';
+COMMENT ON COLUMN api_keys.revoked_at IS 'วันที่เพิกถอน';
+COMMENT ON COLUMN api_keys.created_at IS 'วันที่สร้าง';
+COMMENT ON COLUMN api_keys.updated_at IS 'วันที่แก้ไขล่าสุด';
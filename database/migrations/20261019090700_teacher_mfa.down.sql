@@ -0,0 +1,5 @@
+ALTER TABLE roles DROP COLUMN IF EXISTS mfa_required;
+
+DROP TABLE IF EXISTS teacher_recovery_codes;
+
+DROP TABLE IF EXISTS teacher_mfa;
\ No newline at end of file
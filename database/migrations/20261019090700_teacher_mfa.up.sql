@@ -0,0 +1,41 @@
+CREATE TABLE teacher_mfa (
+    teacher_id   UUID        NOT NULL,
+    secret       VARCHAR(64) NOT NULL,
+    confirmed_at TIMESTAMPTZ NULL,
+    last_step    BIGINT      NOT NULL DEFAULT 0,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (teacher_id),
+    FOREIGN KEY (teacher_id) REFERENCES teachers(id) ON DELETE CASCADE
+);
+
+CREATE TABLE teacher_recovery_codes (
+    id         UUID        NOT NULL DEFAULT gen_random_uuid(),
+    teacher_id UUID        NOT NULL,
+    code_hash  CHAR(64)    NOT NULL,
+    used_at    TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    FOREIGN KEY (teacher_id) REFERENCES teachers(id) ON DELETE CASCADE
+);
+
+CREATE INDEX teacher_recovery_codes_teacher_id_idx ON teacher_recovery_codes (teacher_id);
+
+ALTER TABLE roles ADD COLUMN mfa_required BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Add table comment
+COMMENT ON TABLE teacher_mfa IS 'การยืนยันตัวตนสองขั้นตอน (TOTP) ของครู';
+COMMENT ON TABLE teacher_recovery_codes IS 'รหัสกู้คืนสำหรับใช้แทนรหัส TOTP';
+
+-- Add column comments
+COMMENT ON COLUMN teacher_mfa.teacher_id IS 'รหัสครู';
+COMMENT ON COLUMN teacher_mfa.secret IS 'secret ของ TOTP (base32)';
+COMMENT ON COLUMN teacher_mfa.confirmed_at IS 'วันที่ยืนยันการเปิดใช้งาน';
+COMMENT ON COLUMN teacher_mfa.last_step IS 'ช่วงเวลาของรหัสล่าสุดที่ใช้ ป้องกันการใช้รหัสซ้ำ';
+COMMENT ON COLUMN teacher_mfa.created_at IS 'วันที่สร้าง';
+COMMENT ON COLUMN teacher_mfa.updated_at IS 'วันที่แก้ไขล่าสุด';
+COMMENT ON COLUMN teacher_recovery_codes.teacher_id IS 'รหัสครู';
+COMMENT ON COLUMN teacher_recovery_codes.code_hash IS 'SHA-256 ของรหัสกู้คืน';
+COMMENT ON COLUMN teacher_recovery_codes.used_at IS 'วันที่ใช้รหัส';
+COMMENT ON COLUMN teacher_recovery_codes.created_at IS 'วันที่สร้าง';
+COMMENT ON COLUMN roles.mfa_required IS 'บังคับให้ผู้มีบทบาทนี้ใช้การยืนยันตัวตนสองขั้นตอน';
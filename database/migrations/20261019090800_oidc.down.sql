@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS oidc_states;
+
+DROP TABLE IF EXISTS teacher_identities;
+
+DROP TABLE IF EXISTS school_domains;
+
+DELETE FROM prefixes
+WHERE id = '5c0f8d1e-7a2b-4e63-9d41-0b8e6f3a2c17'
+  AND NOT EXISTS (SELECT 1 FROM teachers WHERE prefix_id = prefixes.id)
+  AND NOT EXISTS (SELECT 1 FROM students WHERE prefix_id = prefixes.id);
\ No newline at end of file
@@ -0,0 +1,72 @@
+CREATE TABLE school_domains (
+    id             UUID         NOT NULL DEFAULT gen_random_uuid(),
+    school_id      UUID         NOT NULL,
+    provider       VARCHAR(32)  NOT NULL,
+    domain         VARCHAR(255) NOT NULL,
+    tenant_id      VARCHAR(64)  NULL,
+    auto_provision BOOLEAN      NOT NULL DEFAULT FALSE,
+    created_by     UUID         NULL,
+    created_at     TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE (provider, domain),
+    FOREIGN KEY (school_id) REFERENCES schools(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES teachers(id) ON DELETE SET NULL
+);
+
+CREATE INDEX school_domains_school_id_idx ON school_domains (school_id);
+
+CREATE TABLE teacher_identities (
+    id            UUID         NOT NULL DEFAULT gen_random_uuid(),
+    teacher_id    UUID         NOT NULL,
+    provider      VARCHAR(32)  NOT NULL,
+    subject       VARCHAR(255) NOT NULL,
+    email         VARCHAR(255) NOT NULL,
+    last_login_at TIMESTAMPTZ  NULL,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE (provider, subject),
+    FOREIGN KEY (teacher_id) REFERENCES teachers(id) ON DELETE CASCADE
+);
+
+CREATE INDEX teacher_identities_teacher_id_idx ON teacher_identities (teacher_id);
+
+CREATE TABLE oidc_states (
+    state_hash    CHAR(64)     NOT NULL,
+    provider      VARCHAR(32)  NOT NULL,
+    nonce         VARCHAR(64)  NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    expires_at    TIMESTAMPTZ  NOT NULL,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (state_hash)
+);
+
+-- Teachers created at their first sign-in pick their prefix later
+INSERT INTO prefixes (id, name, created_at, updated_at, deleted_at) VALUES
+    ('5c0f8d1e-7a2b-4e63-9d41-0b8e6f3a2c17', 'ไม่ระบุ', NOW(), NULL, NULL)
+ON CONFLICT (id) DO NOTHING;
+
+-- Add table comment
+COMMENT ON TABLE school_domains IS 'โดเมนอีเมลของโรงเรียนที่ครูใช้เข้าสู่ระบบผ่าน OpenID Connect';
+COMMENT ON TABLE teacher_identities IS 'บัญชีของครูที่ผู้ให้บริการ OpenID Connect';
+COMMENT ON TABLE oidc_states IS 'การเข้าสู่ระบบผ่าน OpenID Connect ที่ยังไม่เสร็จ';
+
+-- Add column comments
+COMMENT ON COLUMN school_domains.school_id IS 'รหัสโรงเรียน';
+COMMENT ON COLUMN school_domains.provider IS 'ผู้ให้บริการ (google, microsoft)';
+COMMENT ON COLUMN school_domains.domain IS 'โดเมนอีเมล';
+COMMENT ON COLUMN school_domains.tenant_id IS 'รหัส tenant ของ Microsoft ที่เป็นเจ้าของโดเมน';
+COMMENT ON COLUMN school_domains.auto_provision IS 'สร้างบัญชีครูให้อัตโนมัติเมื่อเข้าสู่ระบบครั้งแรก';
+COMMENT ON COLUMN school_domains.created_by IS 'ครูที่เพิ่มโดเมน';
+COMMENT ON COLUMN school_domains.created_at IS 'วันที่สร้าง';
+COMMENT ON COLUMN teacher_identities.teacher_id IS 'รหัสครู';
+COMMENT ON COLUMN teacher_identities.provider IS 'ผู้ให้บริการ (google, microsoft)';
+COMMENT ON COLUMN teacher_identities.subject IS 'รหัสบัญชีที่ผู้ให้บริการ (sub)';
+COMMENT ON COLUMN teacher_identities.email IS 'อีเมลของบัญชีเมื่อเข้าสู่ระบบล่าสุด';
+COMMENT ON COLUMN teacher_identities.last_login_at IS 'วันที่เข้าสู่ระบบล่าสุด';
+COMMENT ON COLUMN teacher_identities.created_at IS 'วันที่เชื่อมบัญชี';
+COMMENT ON COLUMN oidc_states.state_hash IS 'SHA-256 ของ state ที่ส่งไปยังผู้ให้บริการ';
+COMMENT ON COLUMN oidc_states.provider IS 'ผู้ให้บริการ (google, microsoft)';
+COMMENT ON COLUMN oidc_states.nonce IS 'nonce ที่ต้องตรงกับ ID token';
+COMMENT ON COLUMN oidc_states.code_verifier IS 'PKCE code verifier';
+COMMENT ON COLUMN oidc_states.expires_at IS 'วันหมดอายุ';
+COMMENT ON COLUMN oidc_states.created_at IS 'วันที่สร้าง';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS school_join_codes;
+
+DROP TABLE IF EXISTS teacher_invitations;
\ No newline at end of file
@@ -0,0 +1,52 @@
+CREATE TABLE teacher_invitations (
+    id          UUID         NOT NULL DEFAULT gen_random_uuid(),
+    school_id   UUID         NOT NULL,
+    email       VARCHAR(100) NOT NULL,
+    role        VARCHAR(50)  NOT NULL,
+    token_hash  CHAR(64)     NOT NULL,
+    invited_by  UUID         NULL,
+    expires_at  TIMESTAMPTZ  NOT NULL,
+    accepted_at TIMESTAMPTZ  NULL,
+    accepted_by UUID         NULL,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    FOREIGN KEY (school_id) REFERENCES schools(id) ON DELETE CASCADE,
+    FOREIGN KEY (invited_by) REFERENCES teachers(id) ON DELETE SET NULL,
+    FOREIGN KEY (accepted_by) REFERENCES teachers(id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX uq_teacher_invitations_token_hash ON teacher_invitations (token_hash);
+CREATE INDEX teacher_invitations_school_id_idx ON teacher_invitations (school_id);
+
+CREATE TABLE school_join_codes (
+    school_id  UUID        NOT NULL,
+    code       VARCHAR(16) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_by UUID        NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (school_id),
+    FOREIGN KEY (school_id) REFERENCES schools(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES teachers(id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX uq_school_join_codes_code ON school_join_codes (code);
+
+-- Add table comment
+COMMENT ON TABLE teacher_invitations IS 'คำเชิญให้ครูสมัครเข้าโรงเรียน';
+COMMENT ON TABLE school_join_codes IS 'รหัสเข้าร่วมโรงเรียนสำหรับครูที่สมัครเอง';
+
+-- Add column comments
+COMMENT ON COLUMN teacher_invitations.school_id IS 'รหัสโรงเรียน';
+COMMENT ON COLUMN teacher_invitations.email IS 'อีเมลของครูที่ได้รับเชิญ';
+COMMENT ON COLUMN teacher_invitations.role IS 'บทบาทที่ครูได้รับเมื่อสมัคร';
+COMMENT ON COLUMN teacher_invitations.token_hash IS 'SHA-256 ของโทเค็นในลิงก์คำเชิญ';
+COMMENT ON COLUMN teacher_invitations.invited_by IS 'ครูที่ส่งคำเชิญ';
+COMMENT ON COLUMN teacher_invitations.expires_at IS 'วันหมดอายุ';
+COMMENT ON COLUMN teacher_invitations.accepted_at IS 'วันที่สมัครด้วยคำเชิญ';
+COMMENT ON COLUMN teacher_invitations.accepted_by IS 'ครูที่สมัครด้วยคำเชิญ';
+COMMENT ON COLUMN teacher_invitations.created_at IS 'วันที่สร้าง';
+COMMENT ON COLUMN school_join_codes.school_id IS 'รหัสโรงเรียน';
+COMMENT ON COLUMN school_join_codes.code IS 'รหัสเข้าร่วม เช่น K7QM-4TZP';
+COMMENT ON COLUMN school_join_codes.expires_at IS 'วันหมดอายุ';
+COMMENT ON COLUMN school_join_codes.created_by IS 'ครูที่สร้างรหัส';
+COMMENT ON COLUMN school_join_codes.created_at IS 'วันที่สร้าง';
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS classroom_teachers;
\ No newline at end of file
@@ -0,0 +1,37 @@
+CREATE TABLE classroom_teachers (
+    id           UUID        NOT NULL DEFAULT gen_random_uuid(),
+    classroom_id UUID        NOT NULL,
+    teacher_id   UUID        NOT NULL,
+    role         VARCHAR(20) NOT NULL DEFAULT 'subject',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    FOREIGN KEY (classroom_id) REFERENCES classrooms(id) ON DELETE CASCADE,
+    FOREIGN KEY (teacher_id) REFERENCES teachers(id) ON DELETE CASCADE,
+    CHECK (role IN ('homeroom', 'subject', 'assistant'))
+);
+
+CREATE UNIQUE INDEX uq_classroom_teachers_classroom_teacher ON classroom_teachers (classroom_id, teacher_id);
+CREATE INDEX classroom_teachers_teacher_id_idx ON classroom_teachers (teacher_id);
+
+-- Teachers keep the classrooms they had: their own classroom as homeroom
+-- teacher, and those they added students to as subject teacher
+INSERT INTO classroom_teachers (classroom_id, teacher_id, role)
+SELECT classroom_id, id, 'homeroom' FROM teachers
+WHERE classroom_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+INSERT INTO classroom_teachers (classroom_id, teacher_id, role)
+SELECT DISTINCT classroom_id, teacher_id, 'subject' FROM classroom_members
+WHERE classroom_id IS NOT NULL AND teacher_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+-- Add table comment
+COMMENT ON TABLE classroom_teachers IS 'ครูประจำห้องเรียนและครูผู้สอน';
+
+-- Add column comments
+COMMENT ON COLUMN classroom_teachers.classroom_id IS 'รหัสห้องเรียน';
+COMMENT ON COLUMN classroom_teachers.teacher_id IS 'รหัสครู';
+COMMENT ON COLUMN classroom_teachers.role IS 'บทบาทในห้องเรียน: homeroom ครูประจำชั้น, subject ครูผู้สอน, assistant ผู้ช่วยสอน';
+COMMENT ON COLUMN classroom_teachers.created_at IS 'วันที่สร้าง';
+COMMENT ON COLUMN classroom_teachers.updated_at IS 'วันที่แก้ไข';
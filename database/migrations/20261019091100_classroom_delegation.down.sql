@@ -0,0 +1,5 @@
+ALTER TABLE attendances
+    DROP COLUMN IF EXISTS on_behalf_of,
+    DROP COLUMN IF EXISTS marked_by;
+
+DROP TABLE IF EXISTS classroom_delegations;
\ No newline at end of file
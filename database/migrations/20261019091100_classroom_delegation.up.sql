@@ -0,0 +1,41 @@
+CREATE TABLE classroom_delegations (
+    id           UUID        NOT NULL DEFAULT gen_random_uuid(),
+    classroom_id UUID        NOT NULL,
+    delegator_id UUID        NOT NULL,
+    delegate_id  UUID        NOT NULL,
+    scope        VARCHAR(20) NOT NULL DEFAULT 'full',
+    starts_on    DATE        NOT NULL,
+    ends_on      DATE        NOT NULL,
+    created_by   UUID        NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    FOREIGN KEY (classroom_id) REFERENCES classrooms(id) ON DELETE CASCADE,
+    FOREIGN KEY (delegator_id) REFERENCES teachers(id) ON DELETE CASCADE,
+    FOREIGN KEY (delegate_id) REFERENCES teachers(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES teachers(id) ON DELETE SET NULL,
+    CHECK (scope IN ('full', 'attendance')),
+    CHECK (ends_on >= starts_on),
+    CHECK (delegate_id <> delegator_id)
+);
+
+CREATE INDEX classroom_delegations_classroom_id_idx ON classroom_delegations (classroom_id);
+CREATE INDEX classroom_delegations_delegate_id_ends_on_idx ON classroom_delegations (delegate_id, ends_on);
+
+ALTER TABLE attendances
+    ADD COLUMN marked_by UUID NULL REFERENCES teachers(id) ON DELETE SET NULL,
+    ADD COLUMN on_behalf_of UUID NULL REFERENCES teachers(id) ON DELETE SET NULL;
+
+-- Add table comment
+COMMENT ON TABLE classroom_delegations IS 'การมอบหมายครูสอนแทนในห้องเรียนตามช่วงวันที่';
+
+-- Add column comments
+COMMENT ON COLUMN classroom_delegations.classroom_id IS 'รหัสห้องเรียน';
+COMMENT ON COLUMN classroom_delegations.delegator_id IS 'ครูที่ถูกสอนแทน';
+COMMENT ON COLUMN classroom_delegations.delegate_id IS 'ครูที่สอนแทน';
+COMMENT ON COLUMN classroom_delegations.scope IS 'ขอบเขต: full เข้าถึงห้องเรียนทั้งหมด, attendance เช็คชื่อเท่านั้น';
+COMMENT ON COLUMN classroom_delegations.starts_on IS 'วันแรกที่สอนแทน';
+COMMENT ON COLUMN classroom_delegations.ends_on IS 'วันสุดท้ายที่สอนแทน หลังจากนี้หมดอายุเอง';
+COMMENT ON COLUMN classroom_delegations.created_by IS 'ครูที่สร้างการมอบหมาย';
+COMMENT ON COLUMN classroom_delegations.created_at IS 'วันที่สร้าง';
+COMMENT ON COLUMN attendances.marked_by IS 'ครูที่บันทึกการเช็คชื่อ';
+COMMENT ON COLUMN attendances.on_behalf_of IS 'ครูที่ถูกสอนแทน เมื่อบันทึกโดยครูสอนแทน';
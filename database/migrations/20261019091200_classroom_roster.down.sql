@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS attendances_classroom_id_date_session_idx;
+
+ALTER TABLE attendances
+    DROP COLUMN IF EXISTS session;
+
+ALTER TABLE classroom_members
+    DROP COLUMN IF EXISTS seat_no;
+
+ALTER TABLE students
+    DROP COLUMN IF EXISTS photo_url;
\ No newline at end of file
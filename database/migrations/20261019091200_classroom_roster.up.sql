@@ -0,0 +1,15 @@
+ALTER TABLE students
+    ADD COLUMN photo_url VARCHAR(500) NULL;
+
+ALTER TABLE classroom_members
+    ADD COLUMN seat_no INT NULL CHECK (seat_no > 0);
+
+ALTER TABLE attendances
+    ADD COLUMN session VARCHAR(20) NOT NULL DEFAULT '';
+
+CREATE INDEX attendances_classroom_id_date_session_idx ON attendances (classroom_id, date, session);
+
+-- Add column comments
+COMMENT ON COLUMN students.photo_url IS 'ลิงก์รูปนักเรียน';
+COMMENT ON COLUMN classroom_members.seat_no IS 'เลขที่ของนักเรียนในห้อง ใช้เรียงรายชื่อ';
+COMMENT ON COLUMN attendances.session IS 'คาบ/รอบการเช็คชื่อในวันนั้น ค่าว่างคือทั้งวัน';
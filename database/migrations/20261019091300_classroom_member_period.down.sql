@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS classroom_members_student_id_period_idx;
+DROP INDEX IF EXISTS classroom_members_classroom_id_period_idx;
+
+ALTER TABLE classroom_members
+    DROP CONSTRAINT IF EXISTS classroom_members_period_check,
+    DROP COLUMN IF EXISTS effective_to,
+    DROP COLUMN IF EXISTS effective_from;
\ No newline at end of file
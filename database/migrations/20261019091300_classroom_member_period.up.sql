@@ -0,0 +1,18 @@
+ALTER TABLE classroom_members
+    ADD COLUMN effective_from DATE NULL,
+    ADD COLUMN effective_to DATE NULL;
+
+-- Existing memberships start when they were created
+UPDATE classroom_members SET effective_from = COALESCE(created_at::date, CURRENT_DATE);
+
+ALTER TABLE classroom_members
+    ALTER COLUMN effective_from SET DEFAULT CURRENT_DATE,
+    ALTER COLUMN effective_from SET NOT NULL,
+    ADD CONSTRAINT classroom_members_period_check CHECK (effective_to IS NULL OR effective_to >= effective_from);
+
+CREATE INDEX classroom_members_classroom_id_period_idx ON classroom_members (classroom_id, effective_from, effective_to);
+CREATE INDEX classroom_members_student_id_period_idx ON classroom_members (student_id, effective_from, effective_to);
+
+-- Add column comments
+COMMENT ON COLUMN classroom_members.effective_from IS 'วันแรกที่นักเรียนอยู่ในห้องนี้';
+COMMENT ON COLUMN classroom_members.effective_to IS 'วันสุดท้ายที่นักเรียนอยู่ในห้องนี้ ค่าว่างคือยังอยู่';
@@ -0,0 +1,2 @@
+ALTER TABLE classroom_members
+    DROP CONSTRAINT IF EXISTS classroom_members_period_excl;
\ No newline at end of file
@@ -0,0 +1,49 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- Merge the overlapping memberships of a student in a classroom into the
+-- earliest of them, which is stretched to the latest end, so no day of a
+-- period is lost. ROWS ... 1 PRECEDING gives the furthest end reached by the
+-- memberships starting before each one; a membership starting after it
+-- begins a new group.
+CREATE TEMPORARY TABLE classroom_member_merges AS
+WITH reached AS (
+    SELECT id, student_id, classroom_id, effective_from, effective_to,
+           max(COALESCE(effective_to, 'infinity'::date)) OVER (
+               PARTITION BY student_id, classroom_id ORDER BY effective_from, id
+               ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING
+           ) AS reached_to
+    FROM classroom_members
+), grouped AS (
+    SELECT id, student_id, classroom_id, effective_from, effective_to,
+           count(*) FILTER (WHERE reached_to IS NULL OR reached_to < effective_from) OVER (
+               PARTITION BY student_id, classroom_id ORDER BY effective_from, id
+           ) AS period
+    FROM reached
+)
+SELECT id,
+       first_value(id) OVER (PARTITION BY student_id, classroom_id, period ORDER BY effective_from, id) AS keep_id,
+       NULLIF(max(COALESCE(effective_to, 'infinity'::date)) OVER (PARTITION BY student_id, classroom_id, period), 'infinity'::date) AS effective_to,
+       count(*) OVER (PARTITION BY student_id, classroom_id, period) AS members
+FROM grouped;
+
+UPDATE classroom_members cm
+SET effective_to = m.effective_to
+FROM classroom_member_merges m
+WHERE m.id = cm.id AND m.keep_id = cm.id AND m.members > 1;
+
+DELETE FROM classroom_members cm
+USING classroom_member_merges m
+WHERE m.id = cm.id AND m.keep_id <> cm.id;
+
+DROP TABLE classroom_member_merges;
+
+-- A student has at most one membership of a classroom on any day
+ALTER TABLE classroom_members
+    ADD CONSTRAINT classroom_members_period_excl EXCLUDE USING gist (
+        student_id WITH =,
+        classroom_id WITH =,
+        daterange(effective_from, effective_to, '[]') WITH &&
+    );
+
+-- Add constraint comment
+COMMENT ON CONSTRAINT classroom_members_period_excl ON classroom_members IS 'ช่วงวันที่ของนักเรียนในห้องเดียวกันต้องไม่ทับกัน';
@@ -33,10 +33,17 @@ func (l *ginLogger) Write(p []byte) (n int, err error) {
 func D(isHTTPS bool) func(_ *cobra.Command, _ []string) {
 	return func(_ *cobra.Command, _ []string) {
 		ctx, cancel := NotifyContext()
-		mod := modules.Get()
+		mod, err := modules.Get()
+		if err != nil {
+			log.With(log.Error(err)).Errf("Modules failed to initialize.")
+			os.Exit(1)
+		}
 		conf := mod.Conf.Svc.Config()
 
 		srv := serve(mod)
+		if conf.StudentClassroomSync > 0 {
+			go syncStudentClassrooms(ctx, mod.ENT.Svc, time.Duration(conf.StudentClassroomSync)*time.Minute)
+		}
 		log := log.With(log.String("gin", "logger"))
 
 		go func() {
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"context"
+	"time"
+
+	entitiesinf "github.com/easy-attend-serviceV3/app/modules/entities/inf"
+	"github.com/easy-attend-serviceV3/app/utils/tenant"
+	"github.com/easy-attend-serviceV3/internal/log"
+)
+
+// syncStudentClassrooms refreshes students.classroom_id now and then every
+// interval until ctx is done, so memberships dated ahead reach it on their
+// day. It works across every school.
+func syncStudentClassrooms(ctx context.Context, db entitiesinf.StudentClassroomEntity, every time.Duration) {
+	ctx = tenant.Bypass(ctx)
+	log := log.With(log.String("job", "student-classroom"))
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		n, err := db.SyncStudentClassrooms(ctx)
+		if err != nil {
+			log.With(log.Error(err)).Errf("Student classrooms failed to sync.")
+		} else if n > 0 {
+			log.Infof("Synced the classroom of %d students.", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
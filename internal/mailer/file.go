@@ -0,0 +1,54 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/easy-attend-serviceV3/internal/log"
+)
+
+// fileMailer writes each message to an .eml file instead of sending it.
+type fileMailer struct {
+	from string
+	dir  string
+}
+
+func (m *fileMailer) Send(_ context.Context, msg *Message) error {
+	now := time.Now()
+	data, err := compose(m.from, msg, now)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+	to, _ := addressOf(msg.To)
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102T150405.000000000"), strings.NewReplacer("@", "_at_", "/", "_").Replace(to))
+	return os.WriteFile(filepath.Join(m.dir, name), data, 0o644)
+}
+
+// logMailer only logs messages, for local development.
+type logMailer struct {
+	from string
+}
+
+func (m *logMailer) Send(ctx context.Context, msg *Message) error {
+	if _, err := compose(m.from, msg, time.Now()); err != nil {
+		return err
+	}
+	log.WithCtx(ctx).Infof("mail to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+func addressOf(s string) (string, error) {
+	addr, err := mail.ParseAddress(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid address %q: %w", s, err)
+	}
+	return addr.Address, nil
+}
@@ -0,0 +1,97 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+
+	configDTO "github.com/easy-attend-serviceV3/internal/config/dto"
+)
+
+// Drivers selectable with Config.Driver.
+const (
+	DriverSMTP = "smtp"
+	DriverFile = "file"
+	DriverLog  = "log"
+)
+
+type Config struct {
+	Driver   string // smtp, file or log
+	From     string
+	Host     string
+	Port     int
+	Username string
+	Password string
+	Dir      string // where the file driver writes .eml files
+}
+
+// Message is a plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends email.
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// New returns the mailer selected by conf.Driver. The log driver only prints
+// messages; it is the default so development needs no mail server.
+func New(conf *configDTO.Config[Config]) Mailer {
+	c := conf.Val
+	switch c.Driver {
+	case DriverSMTP:
+		return &smtpMailer{conf: c}
+	case DriverFile:
+		return &fileMailer{from: c.From, dir: c.Dir}
+	case DriverLog, "":
+		return &logMailer{from: c.From}
+	default:
+		panic(fmt.Sprintf("unknown mail driver %q", c.Driver))
+	}
+}
+
+// compose renders msg as an RFC 5322 message, quoted-printable so Thai text
+// survives any relay.
+func compose(from string, msg *Message, now time.Time) ([]byte, error) {
+	if _, err := mail.ParseAddress(msg.To); err != nil {
+		return nil, fmt.Errorf("invalid recipient %q: %w", msg.To, err)
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 {
+		domain = strings.Trim(from[at+1:], "> ")
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(id), domain)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	w := quotedprintable.NewWriter(&b)
+	if _, err := w.Write([]byte(strings.ReplaceAll(msg.Body, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
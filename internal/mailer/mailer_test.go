@@ -0,0 +1,64 @@
+package mailer
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_FileMailer(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     Message
+		wantErr bool
+	}{
+		{"Test ascii", Message{To: "Teacher <t@example.com>", Subject: "Reset your password", Body: "Hello\nhttps://x/y?token=abc"}, false},
+		{"Test thai", Message{To: "t@example.com", Subject: "ตั้งรหัสผ่านใหม่", Body: "สวัสดีคุณครู\nลิงก์"}, false},
+		{"Test bad recipient", Message{To: "not an address", Subject: "x", Body: "x"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			m := &fileMailer{from: "Easy Attend <no-reply@example.com>", dir: dir}
+			err := m.Send(context.Background(), &tt.msg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+			if len(files) != 1 {
+				t.Fatalf("wrote %d files, want 1", len(files))
+			}
+			f, err := os.Open(files[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			parsed, err := mail.ReadMessage(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+			if err != nil || subject != tt.msg.Subject {
+				t.Errorf("Subject = %q, want %q", subject, tt.msg.Subject)
+			}
+			body, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.ReplaceAll(string(body), "\r\n", "\n"); got != tt.msg.Body {
+				t.Errorf("Body = %q, want %q", got, tt.msg.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+type smtpMailer struct {
+	conf *Config
+}
+
+// Send delivers msg through the configured server, upgrading to TLS when the
+// server offers STARTTLS.
+func (m *smtpMailer) Send(ctx context.Context, msg *Message) error {
+	data, err := compose(m.conf.From, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(m.conf.Host, strconv.Itoa(m.conf.Port))
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	c, err := smtp.NewClient(conn, m.conf.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: m.conf.Host}); err != nil {
+			return err
+		}
+	}
+	if m.conf.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", m.conf.Username, m.conf.Password, m.conf.Host)); err != nil {
+			return err
+		}
+	}
+
+	from, err := addressOf(m.conf.From)
+	if err != nil {
+		return err
+	}
+	to, err := addressOf(msg.To)
+	if err != nil {
+		return err
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
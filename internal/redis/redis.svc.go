@@ -1,97 +1,100 @@
 package redis
 
-// import (
-// 	"context"
-// 	"fmt"
-// 	"sync"
+import (
+	"context"
+	"fmt"
+	"sync"
 
-// 	"github.com/easy-attend-serviceV3/internal/config/dto"
-// 	"github.com/jinzhu/copier"
-// 	"github.com/redis/go-redis/v9"
-// )
+	redisdto "github.com/easy-attend-serviceV3/internal/redis/dto"
+	"github.com/jinzhu/copier"
+	"github.com/redis/go-redis/v9"
+)
 
-// type RedisService struct {
-// 	rMap map[string]*JSONClient
-// 	mut  sync.RWMutex
-// }
+type RedisService struct {
+	rMap map[string]*JSONClient
+	mut  sync.RWMutex
+}
 
-// var defaultRDConfig = redis.Options{
-// 	MaxRetries: 1,
-// }
+var defaultRDConfig = redis.Options{
+	MaxRetries: 1,
+}
 
-// func newService(appEnv string, confService map[string]*dto.Option) *RedisService {
-// 	service := &RedisService{
-// 		rMap: make(map[string]*JSONClient),
-// 	}
-// 	if err := service.Register(context.Background(), appEnv, confService); err != nil {
-// 		panic(err)
-// 	}
-// 	return service
-// }
+func newService(appName string, confService map[string]*redisdto.Option) *RedisService {
+	service := &RedisService{
+		rMap: make(map[string]*JSONClient),
+	}
+	if err := service.Register(context.Background(), appName, confService); err != nil {
+		panic(err)
+	}
+	return service
+}
 
-// func (rds *RedisService) DB(name ...string) *JSONClient {
-// 	rds.mut.RLock()
-// 	defer rds.mut.RUnlock()
-// 	if rds.rMap == nil {
-// 		panic("redis not initialized")
-// 	}
-// 	if len(name) == 0 {
-// 		return rds.rMap[""]
-// 	}
+func (rds *RedisService) DB(name ...string) *JSONClient {
+	rds.mut.RLock()
+	defer rds.mut.RUnlock()
+	if rds.rMap == nil {
+		panic("redis not initialized")
+	}
+	if len(name) == 0 {
+		return rds.rMap[""]
+	}
 
-// 	db, ok := rds.rMap[name[0]]
-// 	if !ok {
-// 		panic("redis not initialized")
-// 	}
-// 	return db
-// }
+	db, ok := rds.rMap[name[0]]
+	if !ok {
+		panic("redis not initialized")
+	}
+	return db
+}
 
-// func (rds *RedisService) Register(ctx context.Context, appName string, opts map[string]*dto.Option) error {
-// 	rds.mut.Lock()
-// 	defer rds.mut.Unlock()
-// 	for key, opt := range opts {
-// 		optDef := withRDDefaultConf(appName, opt)
-// 		rd := redis.NewClient(optDef)
-// 		rdJson := newJSONClient(rd)
-// 		if err := rdJson.Ping(ctx).Err(); err != nil {
-// 			return fmt.Errorf("redis ping error: %w", err)
-// 		}
-// 		rds.rMap[key] = rdJson
-// 	}
-// 	return nil
-// }
+func (rds *RedisService) Register(ctx context.Context, appName string, opts map[string]*redisdto.Option) error {
+	rds.mut.Lock()
+	defer rds.mut.Unlock()
+	for key, opt := range opts {
+		if opt == nil || opt.Addr == "" {
+			continue // not configured
+		}
+		optDef := withRDDefaultConf(appName, opt)
+		rd := redis.NewClient(optDef)
+		rdJson := newJSONClient(rd)
+		if err := rdJson.Ping(ctx).Err(); err != nil {
+			return fmt.Errorf("redis ping error: %w", err)
+		}
+		rds.rMap[key] = rdJson
+	}
+	return nil
+}
 
-// func (rds *RedisService) Close(ctx context.Context, name string) error {
-// 	rds.mut.Lock()
-// 	defer rds.mut.Unlock()
-// 	if rds.rMap == nil {
-// 		return nil
-// 	}
-// 	rd, ok := rds.rMap[name]
-// 	if !ok {
-// 		return nil
-// 	}
-// 	if err := rd.Close(); err != nil {
-// 		return err
-// 	}
-// 	delete(rds.rMap, name)
-// 	return nil
-// }
+func (rds *RedisService) Close(ctx context.Context, name string) error {
+	rds.mut.Lock()
+	defer rds.mut.Unlock()
+	if rds.rMap == nil {
+		return nil
+	}
+	rd, ok := rds.rMap[name]
+	if !ok {
+		return nil
+	}
+	if err := rd.Close(); err != nil {
+		return err
+	}
+	delete(rds.rMap, name)
+	return nil
+}
 
-// func (rds *RedisService) close(ctx context.Context) error {
-// 	rds.mut.Lock()
-// 	defer rds.mut.Unlock()
-// 	for _, db := range rds.rMap {
-// 		if err := db.Close(); err != nil {
-// 			return err
-// 		}
-// 	}
-// 	return nil
-// }
+func (rds *RedisService) close(ctx context.Context) error {
+	rds.mut.Lock()
+	defer rds.mut.Unlock()
+	for _, db := range rds.rMap {
+		if err := db.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-// func withRDDefaultConf(appName string, opt *dto.Option) *redis.Options {
-// 	rOpt := defaultRDConfig
-// 	rOpt.ClientName = appName
-// 	copier.CopyWithOption(&rOpt, opt, copier.Option{IgnoreEmpty: true, DeepCopy: true})
-// 	return &rOpt
-// }
+func withRDDefaultConf(appName string, opt *redisdto.Option) *redis.Options {
+	rOpt := defaultRDConfig
+	rOpt.ClientName = appName
+	copier.CopyWithOption(&rOpt, opt, copier.Option{IgnoreEmpty: true, DeepCopy: true})
+	return &rOpt
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/easy-attend-serviceV3/app/modules"
+	"github.com/easy-attend-serviceV3/app/modules/idempotency"
 	"github.com/easy-attend-serviceV3/app/utils/auth"
 
 	"github.com/gin-gonic/gin"
@@ -16,13 +17,22 @@ func WarpH(router *gin.RouterGroup, prefix string, handler http.Handler) {
 
 func api(r *gin.RouterGroup, mod *modules.Modules) {
 	// Public routes (no authentication required)
-	r.POST("/teacher", mod.Teacher.Ctl.CreateController)       // Registration
+	r.POST("/teacher", mod.Idempotency.Ctl.Middleware, mod.Teacher.Ctl.CreateController) // Registration
 	r.POST("/teacher/login", mod.Teacher.Ctl.Login)            // Login
 	r.POST("/teacher/refresh", mod.Teacher.Ctl.RefreshController) // Refresh token
+	r.POST("/teacher/password/forgot", mod.Teacher.Ctl.ForgotPasswordController)
+	r.POST("/teacher/password/reset", mod.Teacher.Ctl.ResetPasswordController)
+	r.POST("/teacher/email/verify", mod.Teacher.Ctl.VerifyEmailController)
+	r.POST("/teacher/login/mfa", mod.Teacher.Ctl.LoginMFAController)
+	r.POST("/teacher/login/mfa/enroll", mod.Teacher.Ctl.LoginMFAEnrollController)
+	r.GET("/teacher/oidc/:provider/authorize", mod.Teacher.Ctl.OIDCAuthorizeController)
+	r.POST("/teacher/oidc/:provider/callback", mod.Teacher.Ctl.OIDCCallbackController)
 
-	// Protected routes (authentication required)
+	// Protected routes (authentication required), each declaring the
+	// permission it needs
+	perm := auth.RequirePermission
 	protected := r.Group("")
-	protected.Use(auth.RequireAuth())
+	protected.Use(auth.RequireAuth(mod.Teacher.Svc.TokenManager(), mod.Teacher.Svc.Sessions(), mod.APIKey.Svc.Keys()), mod.Idempotency.Ctl.Middleware)
 	{
 		// Example routes
 		protected.GET("/example/:id", mod.Example.Ctl.Get)
@@ -38,45 +48,96 @@ func api(r *gin.RouterGroup, mod *modules.Modules) {
 		protected.GET("/prefix/:id", mod.Prefix.Ctl.InfoController)
 
 		// School routes
-		protected.GET("/school", mod.School.Ctl.ListController)
-		protected.GET("/school/:id", mod.School.Ctl.InfoController)
-		protected.POST("/school", mod.School.Ctl.CreateController)
-		protected.PATCH("/school/:id", mod.School.Ctl.UpdateController)
-		protected.DELETE("/school/:id", mod.School.Ctl.DeleteController)
+		protected.GET("/school", perm(auth.PermSchoolRead), mod.School.Ctl.ListController)
+		protected.GET("/school/:id", perm(auth.PermSchoolRead), mod.School.Ctl.InfoController)
+		protected.POST("/school", perm(auth.PermSchoolManage), mod.School.Ctl.CreateController)
+		protected.PATCH("/school/:id", perm(auth.PermSchoolWrite), mod.School.Ctl.UpdateController)
+		protected.DELETE("/school/:id", perm(auth.PermSchoolManage), mod.School.Ctl.DeleteController)
+		protected.GET("/school/:id/sso-domain", perm(auth.PermSchoolRead), mod.School.Ctl.DomainListController)
+		protected.POST("/school/:id/sso-domain", perm(auth.PermSchoolWrite), mod.School.Ctl.DomainCreateController)
+		protected.DELETE("/school/:id/sso-domain/:domain_id", perm(auth.PermSchoolWrite), mod.School.Ctl.DomainDeleteController)
+		protected.GET("/school/:id/join-code", perm(auth.PermSchoolWrite), mod.School.Ctl.JoinCodeInfoController)
+		protected.POST("/school/:id/join-code", perm(auth.PermSchoolWrite), mod.School.Ctl.JoinCodeRotateController)
+		protected.DELETE("/school/:id/join-code", perm(auth.PermSchoolWrite), mod.School.Ctl.JoinCodeDeleteController)
+		protected.GET("/school/:id/invitation", perm(auth.PermTeacherWrite), mod.Teacher.Ctl.InvitationListController)
+		protected.POST("/school/:id/invitation", perm(auth.PermTeacherWrite), mod.Teacher.Ctl.InvitationCreateController)
+		protected.DELETE("/school/:id/invitation/:invitation_id", perm(auth.PermTeacherWrite), mod.Teacher.Ctl.InvitationDeleteController)
 
 		// Classroom routes
-		protected.GET("/classroom", mod.Classroom.Ctl.ListController)
-		protected.GET("/classroom/:id", mod.Classroom.Ctl.InfoController)
-		protected.POST("/classroom", mod.Classroom.Ctl.CreateController)
-		protected.PATCH("/classroom/:id", mod.Classroom.Ctl.UpdateController)
-		protected.DELETE("/classroom/:id", mod.Classroom.Ctl.DeleteController)
+		protected.GET("/classroom", perm(auth.PermClassroomRead), mod.Classroom.Ctl.ListController)
+		protected.GET("/classroom/:id", perm(auth.PermClassroomRead), mod.Classroom.Ctl.InfoController)
+		protected.POST("/classroom", perm(auth.PermClassroomWrite), mod.Classroom.Ctl.CreateController)
+		protected.PATCH("/classroom/:id", perm(auth.PermClassroomWrite), mod.Classroom.Ctl.UpdateController)
+		protected.DELETE("/classroom/:id", perm(auth.PermClassroomWrite), mod.Classroom.Ctl.DeleteController)
+		protected.GET("/classroom/:id/teachers", perm(auth.PermClassroomRead), mod.Classroom.Ctl.TeacherListController)
+		protected.POST("/classroom/:id/teachers", perm(auth.PermClassroomWrite), mod.Classroom.Ctl.TeacherCreateController)
+		protected.PATCH("/classroom/:id/teachers/:teacher_id", perm(auth.PermClassroomWrite), mod.Classroom.Ctl.TeacherUpdateController)
+		protected.DELETE("/classroom/:id/teachers/:teacher_id", perm(auth.PermClassroomWrite), mod.Classroom.Ctl.TeacherDeleteController)
+		protected.GET("/classroom/:id/delegations", perm(auth.PermClassroomRead), mod.Classroom.Ctl.DelegationListController)
+		protected.POST("/classroom/:id/delegations", perm(auth.PermClassroomWrite), mod.Classroom.Ctl.DelegationCreateController)
+		protected.DELETE("/classroom/:id/delegations/:delegation_id", perm(auth.PermClassroomWrite), mod.Classroom.Ctl.DelegationDeleteController)
+		protected.GET("/classroom/:id/roster", perm(auth.PermAttendanceRead), mod.Classroom.Ctl.RosterController)
+		protected.POST("/classroom/:id/members/bulk-add", perm(auth.PermClassroomWrite), mod.Classroom.Ctl.MemberBulkAddController)
+		protected.POST("/classroom/:id/members/bulk-remove", perm(auth.PermClassroomWrite), mod.Classroom.Ctl.MemberBulkRemoveController)
+		protected.POST("/classroom/:id/members/copy", perm(auth.PermClassroomWrite), mod.Classroom.Ctl.MemberCopyController)
 
 		// Classroom Member routes
-		protected.GET("/classroom-member", mod.ClassroomMember.Ctl.ListController)
-		protected.GET("/classroom-member/:id", mod.ClassroomMember.Ctl.InfoController)
-		protected.POST("/classroom-member", mod.ClassroomMember.Ctl.CreateController)
-		protected.PATCH("/classroom-member/:id", mod.ClassroomMember.Ctl.UpdateController)
-		protected.DELETE("/classroom-member/:id", mod.ClassroomMember.Ctl.DeleteController)
+		protected.GET("/classroom-member", perm(auth.PermClassroomRead), mod.ClassroomMember.Ctl.ListController)
+		protected.GET("/classroom-member/:id", perm(auth.PermClassroomRead), mod.ClassroomMember.Ctl.InfoController)
+		protected.POST("/classroom-member", perm(auth.PermClassroomWrite), mod.ClassroomMember.Ctl.CreateController)
+		protected.PATCH("/classroom-member/:id", perm(auth.PermClassroomWrite), mod.ClassroomMember.Ctl.UpdateController)
+		protected.DELETE("/classroom-member/:id", perm(auth.PermClassroomWrite), mod.ClassroomMember.Ctl.DeleteController)
+		protected.POST("/classroom-member/:id/transfer", perm(auth.PermClassroomWrite), mod.ClassroomMember.Ctl.TransferController)
 
 		// Student routes
-		protected.GET("/student", mod.Student.Ctl.ListController)
-		protected.GET("/student/:id", mod.Student.Ctl.InfoController)
-		protected.POST("/student", mod.Student.Ctl.CreateController)
-		protected.PATCH("/student/:id", mod.Student.Ctl.UpdateController)
-		protected.DELETE("/student/:id", mod.Student.Ctl.DeleteController)
+		protected.GET("/student", perm(auth.PermStudentRead), mod.Student.Ctl.ListController)
+		protected.GET("/student/:id", perm(auth.PermStudentRead), mod.Student.Ctl.InfoController)
+		protected.POST("/student", perm(auth.PermStudentWrite), mod.Student.Ctl.CreateController)
+		protected.PATCH("/student/:id", perm(auth.PermStudentWrite), mod.Student.Ctl.UpdateController)
+		protected.DELETE("/student/:id", perm(auth.PermStudentWrite), mod.Student.Ctl.DeleteController)
+
+		// Session routes
+		protected.POST("/teacher/email/resend", mod.Teacher.Ctl.ResendVerificationController)
+		protected.POST("/teacher/password", mod.Teacher.Ctl.ChangePasswordController)
+		protected.POST("/teacher/logout", mod.Teacher.Ctl.Logout)
+		protected.POST("/teacher/logout-all", mod.Teacher.Ctl.LogoutAll)
+		protected.GET("/teacher/sessions", mod.Teacher.Ctl.SessionListController)
+		protected.DELETE("/teacher/sessions/:id", mod.Teacher.Ctl.SessionDeleteController)
+
+		// MFA routes; secrets and recovery codes are never kept for replay
+		protected.GET("/teacher/mfa", mod.Teacher.Ctl.MFAStatusController)
+		protected.POST("/teacher/mfa/enroll", idempotency.Sensitive, mod.Teacher.Ctl.MFAEnrollController)
+		protected.POST("/teacher/mfa/confirm", idempotency.Sensitive, mod.Teacher.Ctl.MFAConfirmController)
+		protected.POST("/teacher/mfa/recovery-codes", idempotency.Sensitive, mod.Teacher.Ctl.MFARecoveryCodesController)
+		protected.POST("/teacher/mfa/disable", mod.Teacher.Ctl.MFADisableController)
 
 		// Teacher routes
-		protected.GET("/teacher", mod.Teacher.Ctl.ListController)
-		protected.GET("/teacher/:id", mod.Teacher.Ctl.InfoController)
-		protected.PATCH("/teacher/:id", mod.Teacher.Ctl.UpdateController)
-		protected.DELETE("/teacher/:id", mod.Teacher.Ctl.DeleteController)
+		protected.GET("/teacher", perm(auth.PermTeacherRead), mod.Teacher.Ctl.ListController)
+		protected.GET("/teacher/:id", perm(auth.PermTeacherRead), mod.Teacher.Ctl.InfoController)
+		protected.PATCH("/teacher/:id", perm(auth.PermTeacherWrite), mod.Teacher.Ctl.UpdateController)
+		protected.DELETE("/teacher/:id", perm(auth.PermTeacherWrite), mod.Teacher.Ctl.DeleteController)
+		protected.POST("/teacher/:id/unlock", perm(auth.PermAccountUnlock), mod.Teacher.Ctl.UnlockController)
+
+		// Role routes
+		protected.GET("/teacher/roles", perm(auth.PermRoleAssign), mod.Teacher.Ctl.RoleListController)
+		protected.PATCH("/teacher/roles/:role", perm(auth.PermRoleManage), mod.Teacher.Ctl.RoleUpdateController)
+		protected.GET("/teacher/:id/roles", perm(auth.PermRoleAssign), mod.Teacher.Ctl.TeacherRoleListController)
+		protected.POST("/teacher/:id/roles", perm(auth.PermRoleAssign), mod.Teacher.Ctl.RoleAssignController)
+		protected.DELETE("/teacher/:id/roles/:role", perm(auth.PermRoleAssign), mod.Teacher.Ctl.RoleRevokeController)
+
+		// API key routes
+		protected.GET("/api-key", perm(auth.PermAPIKeyManage), mod.APIKey.Ctl.ListController)
+		protected.GET("/api-key/:id", perm(auth.PermAPIKeyManage), mod.APIKey.Ctl.InfoController)
+		protected.POST("/api-key", perm(auth.PermAPIKeyManage), idempotency.Sensitive, mod.APIKey.Ctl.CreateController)
+		protected.PATCH("/api-key/:id", perm(auth.PermAPIKeyManage), mod.APIKey.Ctl.UpdateController)
+		protected.DELETE("/api-key/:id", perm(auth.PermAPIKeyManage), mod.APIKey.Ctl.RevokeController)
 
 		// Attendance routes
-		protected.GET("/attendance", mod.Attendance.Ctl.ListController)
-		protected.GET("/attendance/:id", mod.Attendance.Ctl.InfoController)
-		protected.POST("/attendance", mod.Attendance.Ctl.CreateController)
-		protected.PATCH("/attendance/:id", mod.Attendance.Ctl.UpdateController)
-		protected.DELETE("/attendance/:id", mod.Attendance.Ctl.DeleteController)
+		protected.GET("/attendance", perm(auth.PermAttendanceRead), mod.Attendance.Ctl.ListController)
+		protected.GET("/attendance/:id", perm(auth.PermAttendanceRead), mod.Attendance.Ctl.InfoController)
+		protected.POST("/attendance", perm(auth.PermAttendanceWrite), mod.Attendance.Ctl.CreateController)
+		protected.PATCH("/attendance/:id", perm(auth.PermAttendanceWrite), mod.Attendance.Ctl.UpdateController)
+		protected.DELETE("/attendance/:id", perm(auth.PermAttendanceWrite), mod.Attendance.Ctl.DeleteController)
 	}
 
 }
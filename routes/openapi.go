@@ -0,0 +1,169 @@
+package routes
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/easy-attend-serviceV3/app/modules"
+	apikey "github.com/easy-attend-serviceV3/app/modules/api_key"
+	"github.com/easy-attend-serviceV3/app/modules/attendance"
+	"github.com/easy-attend-serviceV3/app/modules/classroom"
+	classroommember "github.com/easy-attend-serviceV3/app/modules/classroom_member"
+	"github.com/easy-attend-serviceV3/app/modules/example"
+	"github.com/easy-attend-serviceV3/app/modules/gender"
+	"github.com/easy-attend-serviceV3/app/modules/prefix"
+	"github.com/easy-attend-serviceV3/app/modules/school"
+	"github.com/easy-attend-serviceV3/app/modules/student"
+	"github.com/easy-attend-serviceV3/app/modules/teacher"
+	"github.com/easy-attend-serviceV3/app/utils/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+const apiBasePath = "/api/v1"
+
+// apiSpecs documents the controllers registered in api. Routes missing here
+// still appear in the spec, only without request/response schemas.
+var apiSpecs = openapi.Specs{
+	"GET /openapi.json": {Hidden: true},
+	"* /docs/*w":        {Hidden: true},
+
+	"POST /teacher":         {Summary: "Register a teacher with an invitation token or a school join code", Request: teacher.CreateControllerRequest{}, Response: teacher.CreateControllerResponse{}, Public: true, Idempotent: true},
+	"POST /teacher/login":   {Summary: "Log in as a teacher; teachers with MFA get an mfa_token instead of tokens", Request: teacher.LoginServiceRequest{}, Response: teacher.LoginServiceResponse{}, Public: true},
+	"POST /teacher/refresh": {Summary: "Refresh a token pair", Request: teacher.RefreshControllerRequest{}, Response: teacher.RefreshControllerResponse{}, Public: true},
+
+	"GET /example/:id":   {Summary: "Get an example", Response: example.GetResponse{}},
+	"GET /example-http":  {Summary: "Call an upstream HTTP service"},
+	"POST /example":      {Summary: "Create an example", Request: example.CreateRequest{}, Response: example.CreateResponse{}, Idempotent: true},
+	"GET /gender":        {Summary: "List genders", Request: gender.ListControllerRequest{}, Response: gender.ListControllerResponse{}, Paginated: true},
+	"GET /gender/:id":    {Summary: "Get a gender", Response: gender.InfoControllerResponse{}},
+	"GET /prefix":        {Summary: "List name prefixes", Request: prefix.ListControllerRequest{}, Response: prefix.ListControllerResponse{}, Paginated: true},
+	"GET /prefix/:id":    {Summary: "Get a name prefix", Response: prefix.InfoControllerResponse{}},
+	"GET /school":        {Summary: "List schools", Request: school.ListControllerRequest{}, Response: school.ListControllerResponse{}, Paginated: true},
+	"GET /school/:id":    {Summary: "Get a school", Response: school.InfoControllerResponse{}},
+	"POST /school":       {Summary: "Create a school", Request: school.CreateControllerRequest{}, Idempotent: true},
+	"PATCH /school/:id":  {Summary: "Update a school", Request: school.UpdateControllerRequest{}},
+	"DELETE /school/:id": {Summary: "Delete a school"},
+
+	"GET /school/:id/sso-domain":                   {Summary: "List the email domains whose Google or Microsoft accounts can sign in", Response: []school.DomainServiceResponse{}},
+	"POST /school/:id/sso-domain":                  {Summary: "Trust an email domain for sign-in with Google or Microsoft", Request: school.DomainCreateServiceRequest{}, Response: school.DomainServiceResponse{}},
+	"DELETE /school/:id/sso-domain/:domain_id":     {Summary: "Stop trusting an email domain for sign-in"},
+	"GET /school/:id/join-code":                    {Summary: "Get the code teachers register in the school with", Response: school.JoinCodeServiceResponse{}},
+	"POST /school/:id/join-code":                   {Summary: "Replace the school's join code with a new one", Response: school.JoinCodeServiceResponse{}},
+	"DELETE /school/:id/join-code":                 {Summary: "Turn off registration with a join code"},
+	"GET /school/:id/invitation":                   {Summary: "List the teacher invitations of a school", Response: []teacher.InvitationServiceResponse{}},
+	"POST /school/:id/invitation":                  {Summary: "Invite a teacher to register in the school with a role", Request: teacher.InvitationCreateServiceRequest{}, Response: teacher.InvitationServiceResponse{}},
+	"DELETE /school/:id/invitation/:invitation_id": {Summary: "Withdraw a teacher invitation"},
+
+	"GET /classroom":                                   {Summary: "List classrooms", Request: classroom.ListControllerRequest{}, Response: classroom.ListControllerResponse{}, Paginated: true},
+	"GET /classroom/:id":                               {Summary: "Get a classroom", Response: classroom.InfoControllerResponse{}},
+	"POST /classroom":                                  {Summary: "Create a classroom", Request: classroom.CreateControllerRequest{}, Idempotent: true},
+	"PATCH /classroom/:id":                             {Summary: "Update a classroom", Request: classroom.UpdateControllerRequest{}},
+	"DELETE /classroom/:id":                            {Summary: "Delete a classroom"},
+	"GET /classroom/:id/teachers":                      {Summary: "List the teachers of a classroom", Response: []classroom.TeacherServiceResponse{}},
+	"POST /classroom/:id/teachers":                     {Summary: "Assign a teacher to a classroom", Request: classroom.TeacherCreateServiceRequest{}, Response: classroom.TeacherServiceResponse{}},
+	"PATCH /classroom/:id/teachers/:teacher_id":        {Summary: "Change a teacher's role in a classroom", Request: classroom.TeacherUpdateServiceRequest{}, Response: classroom.TeacherServiceResponse{}},
+	"DELETE /classroom/:id/teachers/:teacher_id":       {Summary: "Remove a teacher from a classroom"},
+	"GET /classroom/:id/delegations":                   {Summary: "List the substitute delegations of a classroom", Response: []classroom.DelegationServiceResponse{}},
+	"POST /classroom/:id/delegations":                  {Summary: "Let a substitute stand in for a teacher of a classroom for a date range", Request: classroom.DelegationCreateServiceRequest{}, Response: classroom.DelegationServiceResponse{}},
+	"DELETE /classroom/:id/delegations/:delegation_id": {Summary: "Revoke a substitute delegation"},
+	"GET /classroom/:id/roster":                        {Summary: "List the members of a classroom for roll call with their mark for a date and session", Request: classroom.RosterServiceRequest{}, Response: []classroom.RosterServiceResponse{}},
+	"POST /classroom/:id/members/bulk-add":             {Summary: "Add students to a classroom by ID or student code in one transaction, skipping current members", Request: classroom.MemberBulkServiceRequest{}, Response: []classroom.MemberBulkServiceResponse{}},
+	"POST /classroom/:id/members/bulk-remove":          {Summary: "Remove students from a classroom from a date in one transaction, skipping non-members", Request: classroom.MemberBulkServiceRequest{}, Response: []classroom.MemberBulkServiceResponse{}},
+	"POST /classroom/:id/members/copy":                 {Summary: "Copy the roster of another classroom of the school into this one for a new term", Request: classroom.MemberCopyServiceRequest{}, Response: []classroom.MemberBulkServiceResponse{}},
+
+	"GET /classroom-member":               {Summary: "List classroom members", Request: classroommember.ListServiceRequest{}, Response: []classroommember.ListServiceResponse{}},
+	"GET /classroom-member/:id":           {Summary: "Get a classroom member", Response: classroommember.InfoServiceResponse{}},
+	"POST /classroom-member":              {Summary: "Add a student to a classroom", Request: classroommember.CreateServiceRequest{}, Response: classroommember.CreateServiceResponse{}, Idempotent: true},
+	"PATCH /classroom-member/:id":         {Summary: "Update a classroom member", Request: classroommember.UpdateServiceRequest{}, Response: classroommember.UpdateServiceResponse{}},
+	"DELETE /classroom-member/:id":        {Summary: "Remove a classroom member", Response: classroommember.DeleteServiceResponse{}},
+	"POST /classroom-member/:id/transfer": {Summary: "Move a student to another classroom from a date, ending their current membership the day before", Request: classroommember.TransferServiceRequest{}, Response: classroommember.TransferServiceResponse{}},
+
+	"GET /student":        {Summary: "List students", Request: student.ListControllerRequest{}, Response: student.ListControllerResponse{}, Paginated: true},
+	"GET /student/:id":    {Summary: "Get a student", Response: student.InfoControllerResponse{}},
+	"POST /student":       {Summary: "Create a student", Request: student.CreateControllerRequest{}, Response: student.CreateControllerResponse{}, Idempotent: true},
+	"PATCH /student/:id":  {Summary: "Update a student", Request: student.UpdateControllerRequest{}},
+	"DELETE /student/:id": {Summary: "Delete a student"},
+
+	"POST /teacher/password/forgot": {Summary: "Email a password reset link", Request: teacher.ForgotPasswordServiceRequest{}, Public: true},
+	"POST /teacher/password/reset":  {Summary: "Set a new password with a reset link", Request: teacher.ResetPasswordServiceRequest{}, Public: true},
+	"POST /teacher/email/verify":    {Summary: "Verify the email with the link sent on registration", Request: teacher.VerifyEmailServiceRequest{}, Public: true},
+	"POST /teacher/email/resend":    {Summary: "Email a new verification link"},
+	"POST /teacher/password":        {Summary: "Change the password and end the other sessions", Request: teacher.ChangePasswordServiceRequest{}},
+	"POST /teacher/logout":          {Summary: "Log out of the current session"},
+	"POST /teacher/logout-all":      {Summary: "Log out of every session"},
+	"GET /teacher/sessions":         {Summary: "List active sessions", Response: []teacher.SessionListControllerResponse{}},
+	"DELETE /teacher/sessions/:id":  {Summary: "End a session"},
+
+	"POST /teacher/login/mfa":        {Summary: "Finish a login with a TOTP or recovery code", Request: teacher.LoginMFAServiceRequest{}, Response: teacher.LoginServiceResponse{}, Public: true},
+	"POST /teacher/login/mfa/enroll": {Summary: "Enroll an authenticator during a login that requires MFA", Request: teacher.LoginMFAEnrollServiceRequest{}, Response: teacher.MFAEnrollServiceResponse{}, Public: true},
+
+	"GET /teacher/oidc/:provider/authorize": {Summary: "Start a sign-in with a Google or Microsoft school account", Response: teacher.OIDCAuthorizeServiceResponse{}, Public: true},
+	"POST /teacher/oidc/:provider/callback": {Summary: "Finish a sign-in with the code and state the provider sent back", Request: teacher.OIDCCallbackServiceRequest{}, Response: teacher.LoginServiceResponse{}, Public: true},
+	"GET /teacher/mfa":                      {Summary: "Get the MFA status", Response: teacher.MFAStatusServiceResponse{}},
+	"POST /teacher/mfa/enroll":              {Summary: "Start enrolling an authenticator (secret, URI and QR PNG)", Response: teacher.MFAEnrollServiceResponse{}},
+	"POST /teacher/mfa/confirm":             {Summary: "Turn MFA on with the first code; returns the recovery codes", Request: teacher.MFACodeServiceRequest{}, Response: teacher.MFARecoveryCodesServiceResponse{}},
+	"POST /teacher/mfa/recovery-codes":      {Summary: "Replace the recovery codes", Request: teacher.MFACodeServiceRequest{}, Response: teacher.MFARecoveryCodesServiceResponse{}},
+	"POST /teacher/mfa/disable":             {Summary: "Turn MFA off", Request: teacher.MFACodeServiceRequest{}},
+
+	"GET /teacher":        {Summary: "List teachers", Request: teacher.ListControllerRequest{}, Response: teacher.ListControllerResponse{}, Paginated: true},
+	"GET /teacher/:id":    {Summary: "Get a teacher", Response: teacher.InfoControllerResponse{}},
+	"PATCH /teacher/:id":  {Summary: "Update a teacher", Request: teacher.UpdateControllerRequest{}},
+	"DELETE /teacher/:id": {Summary: "Delete a teacher"},
+
+	"POST /teacher/:id/unlock": {Summary: "Lift a login lockout (admin)", Request: teacher.UnlockServiceRequest{}},
+
+	"GET /teacher/roles":              {Summary: "List assignable roles", Response: []teacher.RoleServiceResponse{}},
+	"PATCH /teacher/roles/:role":      {Summary: "Require MFA for a role (super admin)", Request: teacher.RoleUpdateServiceRequest{}, Response: teacher.RoleServiceResponse{}},
+	"GET /teacher/:id/roles":          {Summary: "List a teacher's roles", Response: []teacher.RoleServiceResponse{}},
+	"POST /teacher/:id/roles":         {Summary: "Grant a role to a teacher", Request: teacher.RoleAssignServiceRequest{}, Idempotent: true},
+	"DELETE /teacher/:id/roles/:role": {Summary: "Revoke a role and end the teacher's sessions"},
+
+	"GET /api-key":        {Summary: "List the school's API keys", Response: []apikey.ServiceResponse{}},
+	"GET /api-key/:id":    {Summary: "Get an API key", Response: apikey.ServiceResponse{}},
+	"POST /api-key":       {Summary: "Create an API key; the key is only returned here", Request: apikey.CreateServiceRequest{}, Response: apikey.CreateServiceResponse{}, Idempotent: true},
+	"PATCH /api-key/:id":  {Summary: "Rename an API key or change its scopes or expiry", Request: apikey.UpdateServiceRequest{}, Response: apikey.ServiceResponse{}},
+	"DELETE /api-key/:id": {Summary: "Revoke an API key"},
+
+	"GET /attendance":        {Summary: "List attendance records", Request: attendance.ListServiceRequest{}, Response: []attendance.ListServiceResponse{}},
+	"GET /attendance/:id":    {Summary: "Get an attendance record", Response: attendance.InfoServiceResponse{}},
+	"POST /attendance":       {Summary: "Record attendance", Request: attendance.CreateServiceRequest{}, Response: attendance.CreateServiceResponse{}, Idempotent: true},
+	"PATCH /attendance/:id":  {Summary: "Update an attendance record", Request: attendance.UpdateServiceRequest{}, Response: attendance.UpdateServiceResponse{}},
+	"DELETE /attendance/:id": {Summary: "Delete an attendance record", Response: attendance.DeleteServiceResponse{}},
+}
+
+func apiInfo(mod *modules.Modules) openapi.Info {
+	version := mod.Conf.Svc.AppVersion()
+	if version == "" {
+		version = "dev"
+	}
+	return openapi.Info{
+		Title:       mod.Conf.Svc.AppName(),
+		Description: "Easy Attend service API",
+		Version:     version,
+	}
+}
+
+// OpenAPI builds the spec for the API routes on a throwaway engine, so it can
+// be produced without starting the server (see the `openapi` command).
+func OpenAPI(mod *modules.Modules) *openapi.Document {
+	app := gin.New()
+	api(app.Group(apiBasePath), mod)
+	return openapi.Build(apiInfo(mod), apiBasePath, app.Routes(), apiSpecs)
+}
+
+// docs serves the spec generated from the live route table plus the bundled
+// Swagger UI under <base>/docs/.
+func docs(app *gin.Engine, r *gin.RouterGroup, mod *modules.Modules) {
+	var (
+		once sync.Once
+		doc  *openapi.Document
+	)
+	r.GET("/openapi.json", func(ctx *gin.Context) {
+		once.Do(func() {
+			doc = openapi.Build(apiInfo(mod), apiBasePath, app.Routes(), apiSpecs)
+		})
+		ctx.JSON(http.StatusOK, doc)
+	})
+	WarpH(r, "/docs", openapi.SwaggerUI(r.BasePath()+"/openapi.json"))
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/easy-attend-serviceV3/app/modules"
+	"github.com/easy-attend-serviceV3/app/utils/auth"
+	"github.com/easy-attend-serviceV3/app/utils/base"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -16,7 +18,8 @@ func Router(app *gin.Engine, mod *modules.Modules) {
 		ctx.JSON(http.StatusOK, nil)
 	})
 
-	app.Use(otelgin.Middleware(mod.Conf.Svc.Config().AppName),
+	conf := mod.Conf.Svc.Config()
+	app.Use(otelgin.Middleware(conf.AppName),
 		// Middleware add trace id to response header
 		func(ctx *gin.Context) {
 			spanCtx := trace.SpanContextFromContext(ctx.Request.Context())
@@ -27,10 +30,13 @@ func Router(app *gin.Engine, mod *modules.Modules) {
 		},
 	)
 
+	app.Use(base.ErrorFormat(conf.HttpErrorFormat), base.RequireIfMatch(conf.HttpRequireIfMatch))
+
 	app.Use(cors.New(cors.Config{
 		AllowAllOrigins:        true,
 		AllowMethods:           []string{"*"},
 		AllowHeaders:           []string{"*"},
+		ExposeHeaders:          []string{"ETag", "X-Trace-ID", "Idempotent-Replayed", "Retry-After"},
 		AllowCredentials:       true,
 		AllowWildcard:          true,
 		AllowBrowserExtensions: true,
@@ -38,5 +44,10 @@ func Router(app *gin.Engine, mod *modules.Modules) {
 		AllowFiles:             false,
 	}))
 
-	api(app.Group("/api/v1"), mod)
+	// Public keys for verifying our access tokens
+	app.GET("/.well-known/jwks.json", auth.JWKSHandler(mod.Teacher.Svc.TokenManager()))
+
+	r := app.Group(apiBasePath)
+	api(r, mod)
+	docs(app, r, mod)
 }